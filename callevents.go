@@ -0,0 +1,254 @@
+package function
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CallEventType is the type of a CallEvent
+type CallEventType string
+
+const (
+	// CallStarted is emitted before a function is called
+	CallStarted CallEventType = "started"
+	// CallSucceeded is emitted after a function returned without an error
+	CallSucceeded CallEventType = "succeeded"
+	// CallFailed is emitted after a function returned an error
+	CallFailed CallEventType = "failed"
+)
+
+// Transports of CallEvent
+const (
+	TransportDirect   = "direct"
+	TransportCLI      = "cli"
+	TransportHTTP     = "http"
+	TransportHTMLForm = "htmlform"
+	TransportJobs     = "jobs"
+)
+
+// CallEvent describes a function call for audit trails.
+// Events are emitted to all sinks added with AddCallEventSink
+// by the command dispatchers of the cli package,
+// HTTPHandler, the htmlform package, the jobs package
+// and wrappers returned by WithCallEvents.
+type CallEvent struct {
+	Type CallEventType `json:"type"`
+	// Func is the name of the called function
+	Func string `json:"func"`
+	// Transport is the calling convention like TransportCLI
+	Transport string `json:"transport"`
+	// Command is the command of the CallInfo of the call context
+	Command string `json:"command,omitempty"`
+	// Start time of the call
+	Start time.Time `json:"start"`
+	// Duration of the call, zero for CallStarted
+	Duration time.Duration `json:"duration,omitempty"`
+	// Err returned by the function for CallFailed
+	Err error `json:"-"`
+}
+
+// CallEventSink receives the events of function calls.
+// HandleCallEvent is called synchronously by the calling goroutine
+// so implementations must be safe for concurrent use
+// and should hand off slow work like network requests.
+type CallEventSink interface {
+	HandleCallEvent(ctx context.Context, event *CallEvent)
+}
+
+// CallEventSinkFunc implements CallEventSink with a function
+type CallEventSinkFunc func(ctx context.Context, event *CallEvent)
+
+func (f CallEventSinkFunc) HandleCallEvent(ctx context.Context, event *CallEvent) {
+	f(ctx, event)
+}
+
+type callEventSinkEntry struct {
+	sink CallEventSink
+}
+
+var (
+	callEventSinksMtx sync.Mutex
+	callEventSinks    atomic.Pointer[[]*callEventSinkEntry]
+)
+
+// AddCallEventSink adds a sink receiving the events of all function calls
+// and returns a function that removes the sink again.
+func AddCallEventSink(sink CallEventSink) (remove func()) {
+	callEventSinksMtx.Lock()
+	defer callEventSinksMtx.Unlock()
+
+	entry := &callEventSinkEntry{sink}
+	var sinks []*callEventSinkEntry
+	if current := callEventSinks.Load(); current != nil {
+		sinks = slices.Clone(*current)
+	}
+	sinks = append(sinks, entry)
+	callEventSinks.Store(&sinks)
+
+	return func() {
+		callEventSinksMtx.Lock()
+		defer callEventSinksMtx.Unlock()
+
+		sinks := slices.DeleteFunc(slices.Clone(*callEventSinks.Load()), func(e *callEventSinkEntry) bool { return e == entry })
+		callEventSinks.Store(&sinks)
+	}
+}
+
+func noCallEventsEnd(error) {}
+
+// StartCallEvents emits a CallStarted event for a call
+// of the function funcName via transport and returns
+// a function that has to be called with the error result of the call
+// to emit a CallSucceeded or CallFailed event.
+// It does nothing if no sink was added with AddCallEventSink.
+func StartCallEvents(ctx context.Context, funcName, transport string) (end func(err error)) {
+	sinks := callEventSinks.Load()
+	if sinks == nil || len(*sinks) == 0 {
+		return noCallEventsEnd
+	}
+	event := CallEvent{
+		Type:      CallStarted,
+		Func:      funcName,
+		Transport: transport,
+		Start:     time.Now(),
+	}
+	if info := CallInfoFromContext(ctx); info != nil {
+		event.Command = info.Command
+	}
+	emitCallEvent(ctx, *sinks, event)
+
+	return func(err error) {
+		event.Duration = time.Since(event.Start)
+		event.Err = err
+		if err == nil {
+			event.Type = CallSucceeded
+		} else {
+			event.Type = CallFailed
+		}
+		emitCallEvent(ctx, *sinks, event)
+	}
+}
+
+// emitCallEvent passes a copy of event to every sink
+// so sinks can't change the event for other sinks.
+func emitCallEvent(ctx context.Context, sinks []*callEventSinkEntry, event CallEvent) {
+	for _, entry := range sinks {
+		e := event
+		entry.sink.HandleCallEvent(ctx, &e)
+	}
+}
+
+// CallEventLogSink returns a CallEventSink
+// that prints a line per event to logger.
+func CallEventLogSink(logger Logger) CallEventSink {
+	return CallEventSinkFunc(func(ctx context.Context, event *CallEvent) {
+		switch event.Type {
+		case CallStarted:
+			logger.Printf("call %s via %s started", event.Func, event.Transport)
+		case CallSucceeded:
+			logger.Printf("call %s via %s succeeded after %s", event.Func, event.Transport, event.Duration)
+		default:
+			logger.Printf("call %s via %s failed after %s: %s", event.Func, event.Transport, event.Duration, event.Err)
+		}
+	})
+}
+
+// CallEventJSONSink returns a CallEventSink
+// that writes every event as JSON object line to writer
+// with the error message of a CallFailed event as "error".
+func CallEventJSONSink(writer io.Writer) CallEventSink {
+	var mtx sync.Mutex
+	return CallEventSinkFunc(func(ctx context.Context, event *CallEvent) {
+		line := struct {
+			*CallEvent
+			Error string `json:"error,omitempty"`
+		}{CallEvent: event}
+		if event.Err != nil {
+			line.Error = event.Err.Error()
+		}
+		data, err := json.Marshal(line)
+		if err != nil {
+			return
+		}
+		mtx.Lock()
+		defer mtx.Unlock()
+		_, _ = writer.Write(append(data, '\n'))
+	})
+}
+
+// WithCallEvents returns a Wrapper that emits
+// the events of every call of f via TransportDirect.
+// The returned Wrapper also implements CallWithURLValuesWrapper
+// and ResultNamer if f implements them.
+func WithCallEvents(f Wrapper) Wrapper {
+	var (
+		w                      = callEventsWrapper{f}
+		urlValues, isURLValues = f.(CallWithURLValuesWrapper)
+		namer, isNamer         = f.(ResultNamer)
+	)
+	switch {
+	case isURLValues && isNamer:
+		return struct {
+			callEventsURLValuesWrapper
+			ResultNamer
+		}{callEventsURLValuesWrapper{w, urlValues}, namer}
+	case isURLValues:
+		return callEventsURLValuesWrapper{w, urlValues}
+	case isNamer:
+		return struct {
+			callEventsWrapper
+			ResultNamer
+		}{w, namer}
+	}
+	return w
+}
+
+type callEventsWrapper struct {
+	Wrapper
+}
+
+func (f callEventsWrapper) Call(ctx context.Context, args []any) (results []any, err error) {
+	end := StartCallEvents(ctx, f.Name(), TransportDirect)
+	results, err = f.Wrapper.Call(ctx, args)
+	end(err)
+	return results, err
+}
+
+func (f callEventsWrapper) CallWithStrings(ctx context.Context, args ...string) (results []any, err error) {
+	end := StartCallEvents(ctx, f.Name(), TransportDirect)
+	results, err = f.Wrapper.CallWithStrings(ctx, args...)
+	end(err)
+	return results, err
+}
+
+func (f callEventsWrapper) CallWithNamedStrings(ctx context.Context, args map[string]string) (results []any, err error) {
+	end := StartCallEvents(ctx, f.Name(), TransportDirect)
+	results, err = f.Wrapper.CallWithNamedStrings(ctx, args)
+	end(err)
+	return results, err
+}
+
+func (f callEventsWrapper) CallWithJSON(ctx context.Context, argsJSON []byte) (results []any, err error) {
+	end := StartCallEvents(ctx, f.Name(), TransportDirect)
+	results, err = f.Wrapper.CallWithJSON(ctx, argsJSON)
+	end(err)
+	return results, err
+}
+
+type callEventsURLValuesWrapper struct {
+	callEventsWrapper
+	urlValues CallWithURLValuesWrapper
+}
+
+func (f callEventsURLValuesWrapper) CallWithURLValues(ctx context.Context, args url.Values) (results []any, err error) {
+	end := StartCallEvents(ctx, f.Name(), TransportDirect)
+	results, err = f.urlValues.CallWithURLValues(ctx, args)
+	end(err)
+	return results, err
+}
@@ -0,0 +1,165 @@
+package function
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCallEvents(t *testing.T) {
+	var (
+		mtx    sync.Mutex
+		events []CallEvent
+	)
+	remove := AddCallEventSink(CallEventSinkFunc(func(ctx context.Context, event *CallEvent) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		events = append(events, *event)
+	}))
+	var jsonLines strings.Builder
+	removeJSON := AddCallEventSink(CallEventJSONSink(&jsonLines))
+
+	errFailed := errors.New("failed")
+	f := WithCallEvents(MustReflectWrapper(func(fail bool) error {
+		if fail {
+			return errFailed
+		}
+		return nil
+	}, "fail").WithName("Check"))
+	ctx := ContextWithCallInfo(context.Background(), CallInfo{Command: "check"})
+	_, _ = f.CallWithStrings(ctx, "false")
+	_, _ = f.Call(ctx, []any{true})
+
+	handler := HTTPHandler(nil, f, nil)
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	removeJSON()
+	remove()
+	// No events after removing the sinks
+	_, _ = f.Call(ctx, []any{false})
+
+	type summary struct {
+		Type      CallEventType
+		Transport string
+		Command   string
+		Err       error
+	}
+	var got []summary
+	for _, event := range events {
+		if event.Func != "Check" || event.Start.IsZero() || (event.Type == CallStarted) != (event.Duration == 0) {
+			t.Errorf("invalid event %#v", event)
+		}
+		got = append(got, summary{event.Type, event.Transport, event.Command, event.Err})
+	}
+	want := []summary{
+		{CallStarted, TransportDirect, "check", nil},
+		{CallSucceeded, TransportDirect, "check", nil},
+		{CallStarted, TransportDirect, "check", nil},
+		{CallFailed, TransportDirect, "check", errFailed},
+		{CallStarted, TransportHTTP, "", nil},
+		// The HTTP handler calls the direct events wrapper
+		{CallStarted, TransportDirect, "", nil},
+		{CallSucceeded, TransportDirect, "", nil},
+		{CallSucceeded, TransportHTTP, "", nil},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d events %v, want %d events %v", len(got), got, len(want), want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d is %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(jsonLines.String()), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("got %d JSON lines, want %d", len(lines), len(want))
+	}
+	if !strings.Contains(lines[3], `"type":"failed"`) || !strings.Contains(lines[3], `"error":"failed"`) {
+		t.Errorf("unexpected JSON line for failed call: %s", lines[3])
+	}
+}
+
+type testResultNamer struct{}
+
+func (testResultNamer) ResultNames() []string { return []string{"sum"} }
+
+type testURLValuesWrapper struct {
+	Wrapper
+}
+
+func (w testURLValuesWrapper) CallWithURLValues(ctx context.Context, args url.Values) ([]any, error) {
+	return w.CallWithStrings(ctx, args.Get("a"), args.Get("b"))
+}
+
+func TestWithCallEvents_optionalInterfaces(t *testing.T) {
+	var (
+		mtx    sync.Mutex
+		events []CallEventType
+	)
+	remove := AddCallEventSink(CallEventSinkFunc(func(ctx context.Context, event *CallEvent) {
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		events = append(events, event.Type)
+	}))
+	defer remove()
+
+	sum := MustReflectWrapper(func(a, b int) int { return a + b }, "a", "b")
+	tests := []struct {
+		name          string
+		wrapper       Wrapper
+		wantURLValues bool
+		wantNamer     bool
+	}{
+		{name: "Wrapper", wrapper: sum},
+		{name: "ResultNamer", wrapper: struct {
+			Wrapper
+			ResultNamer
+		}{sum, testResultNamer{}}, wantNamer: true},
+		{name: "CallWithURLValuesWrapper", wrapper: testURLValuesWrapper{sum}, wantURLValues: true},
+		{name: "both", wrapper: struct {
+			testURLValuesWrapper
+			ResultNamer
+		}{testURLValuesWrapper{sum}, testResultNamer{}}, wantURLValues: true, wantNamer: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := WithCallEvents(tt.wrapper)
+
+			_, isNamer := f.(ResultNamer)
+			if isNamer != tt.wantNamer {
+				t.Errorf("implements ResultNamer = %v, want %v", isNamer, tt.wantNamer)
+			}
+			wantNames := []string{"result0"}
+			if tt.wantNamer {
+				wantNames = []string{"sum"}
+			}
+			if got := resultNames(f, 1); !slices.Equal(got, wantNames) {
+				t.Errorf("resultNames() = %v, want %v", got, wantNames)
+			}
+
+			urlValues, isURLValues := f.(CallWithURLValuesWrapper)
+			if isURLValues != tt.wantURLValues {
+				t.Fatalf("implements CallWithURLValuesWrapper = %v, want %v", isURLValues, tt.wantURLValues)
+			}
+			if !isURLValues {
+				return
+			}
+			events = nil
+			results, err := urlValues.CallWithURLValues(context.Background(), url.Values{"a": {"1"}, "b": {"2"}})
+			if err != nil || !slices.Equal(results, []any{3}) {
+				t.Errorf("CallWithURLValues() = %v, %v, want [3]", results, err)
+			}
+			if want := []CallEventType{CallStarted, CallSucceeded}; !slices.Equal(events, want) {
+				t.Errorf("events %v, want %v", events, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package function
+
+import "context"
+
+type callInfoCtxKey struct{}
+
+// CallInfo describes a function call
+// so ResultsHandlers can label output, write per command files
+// or emit metrics without passing the information explicitly.
+// It is added to the context passed to the ResultsHandlers
+// by NewStringArgsFunc, NewNamedStringArgsFunc and NewJSONArgsFunc.
+type CallInfo struct {
+	// Name of the called function if the wrapper has a Name method
+	Name string
+	// Command that called the function including super commands
+	// separated by space, empty if not called by a command
+	Command string
+	// Args are the original string arguments of the call
+	Args []string
+	// NamedArgs are the original named string arguments of the call
+	NamedArgs map[string]string
+	// JSON is the original JSON of the arguments of the call
+	JSON []byte
+}
+
+// ContextWithCallInfo returns a context with info
+// that can be retrieved with CallInfoFromContext.
+// If info has no Command then the Command
+// of a CallInfo already in ctx is used,
+// so a command dispatcher can set the Command
+// before the function wrapper adds the arguments.
+func ContextWithCallInfo(ctx context.Context, info CallInfo) context.Context {
+	if parent := CallInfoFromContext(ctx); parent != nil && info.Command == "" {
+		info.Command = parent.Command
+	}
+	return context.WithValue(ctx, callInfoCtxKey{}, &info)
+}
+
+// CallInfoFromContext returns the CallInfo added
+// with ContextWithCallInfo to ctx or nil.
+func CallInfoFromContext(ctx context.Context) *CallInfo {
+	info, _ := ctx.Value(callInfoCtxKey{}).(*CallInfo)
+	return info
+}
+
+// contextWithCall returns ctx with the CallInfo
+// of a call of f with the passed arguments
+// if there are resultsHandlers to use it.
+func contextWithCall(ctx context.Context, f any, resultsHandlers []ResultsHandler, info CallInfo) context.Context {
+	if len(resultsHandlers) == 0 {
+		return ctx
+	}
+	if named, ok := f.(interface{ Name() string }); ok {
+		info.Name = named.Name()
+	}
+	return ContextWithCallInfo(ctx, info)
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// FileArgPrefix is the prefix of argument values
+// that are replaced with the content of the file
+// at the path following the prefix if enabled
+// with EnableFileArgs.
+// Use the prefix twice to pass a value beginning
+// with the prefix literally, so "@@me" becomes "@me".
+const FileArgPrefix = "@"
+
+// StdinArg is the argument value that is replaced
+// with the content read from stdin if enabled
+// with EnableStdinArg.
+const StdinArg = "-"
+
+// stdinArg holds the configuration for reading
+// an argument value from stdin.
+type stdinArg struct {
+	reader    io.Reader
+	trimSpace bool
+}
+
+// expandArgs returns args with argument values
+// replaced by their expanded form.
+// Flags are not expanded, but the values of flags
+// in the form --name=value are.
+func (cfg *dispatcherConfig) expandArgs(args []string) ([]string, error) {
+	if cfg.stdinArg == nil && !cfg.fileArgs {
+		return args, nil
+	}
+	expanded := make([]string, len(args))
+	stdinRead := false
+	for i, arg := range args {
+		prefix, value := "", arg
+		if isFlag(arg) {
+			name, v, hasValue := strings.Cut(arg, "=")
+			if !hasValue {
+				expanded[i] = arg
+				continue
+			}
+			prefix, value = name+"=", v
+		}
+		if cfg.fileArgs && strings.HasPrefix(value, FileArgPrefix) {
+			content, err := readFileArg(value)
+			if err != nil {
+				return nil, fmt.Errorf("argument %q: %w", arg, err)
+			}
+			expanded[i] = prefix + content
+			continue
+		}
+		if value == StdinArg && cfg.stdinArg != nil {
+			if stdinRead {
+				return nil, errors.New("stdin can only be used for one argument value")
+			}
+			stdinRead = true
+			content, err := cfg.stdinArg.read()
+			if err != nil {
+				return nil, err
+			}
+			value = content
+		}
+		expanded[i] = prefix + value
+	}
+	return expanded, nil
+}
+
+// readFileArg returns the content of the file
+// referenced by value with FileArgPrefix
+// or value without the first prefix
+// if the prefix is repeated.
+func readFileArg(value string) (string, error) {
+	path := strings.TrimPrefix(value, FileArgPrefix)
+	if strings.HasPrefix(path, FileArgPrefix) {
+		return path, nil
+	}
+	if path == "" {
+		return "", errors.New("missing file path after " + FileArgPrefix)
+	}
+	b, err := os.ReadFile(path) //#nosec G304 -- path passed by the user on the command line
+	if err != nil {
+		return "", fmt.Errorf("can't read argument value from file: %w", err)
+	}
+	return string(b), nil
+}
+
+func (s *stdinArg) read() (string, error) {
+	b, err := io.ReadAll(s.reader)
+	if err != nil {
+		return "", fmt.Errorf("can't read argument value from stdin: %w", err)
+	}
+	if s.trimSpace {
+		return strings.TrimSpace(string(b)), nil
+	}
+	return string(b), nil
+}
+
+func (cfg *dispatcherConfig) enableStdinArg(trimSpace bool) {
+	cfg.stdinArg = &stdinArg{reader: os.Stdin, trimSpace: trimSpace}
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/domonda/go-function"
+)
+
+func TestStringArgsDispatcher_EnableStdinArg(t *testing.T) {
+	var gotA, gotB string
+	disp := NewStringArgsDispatcher()
+	disp.MustAddCommand("cmd", "", function.MustReflectWrapper(
+		func(a, b string) { gotA, gotB = a, b },
+		"a", "b",
+	))
+	disp.EnableStdinArg(true)
+
+	disp.cfg.stdinArg.reader = strings.NewReader(" {\"x\":1}\n")
+	err := disp.Dispatch(context.Background(), "cmd", "A", "-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotA != "A" || gotB != `{"x":1}` {
+		t.Errorf("got a=%q b=%q", gotA, gotB)
+	}
+
+	disp.cfg.stdinArg.reader = strings.NewReader("stdin")
+	err = disp.Dispatch(context.Background(), "cmd", "--a=-", "--b", "B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotA != "stdin" || gotB != "B" {
+		t.Errorf("got a=%q b=%q", gotA, gotB)
+	}
+
+	err = disp.Dispatch(context.Background(), "cmd", "-", "-")
+	if err == nil {
+		t.Error("expected error for stdin used twice")
+	}
+}
+
+func TestStringArgsDispatcher_EnableFileArgs(t *testing.T) {
+	var gotA, gotB string
+	disp := NewStringArgsDispatcher()
+	disp.MustAddCommand("cmd", "", function.MustReflectWrapper(
+		func(a, b string) { gotA, gotB = a, b },
+		"a", "b",
+	))
+	disp.EnableFileArgs()
+
+	path := filepath.Join(t.TempDir(), "payload.json")
+	err := os.WriteFile(path, []byte(`{"x":1}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = disp.Dispatch(context.Background(), "cmd", "@"+path, "--b=@@me")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotA != `{"x":1}` || gotB != "@me" {
+		t.Errorf("got a=%q b=%q", gotA, gotB)
+	}
+
+	err = disp.Dispatch(context.Background(), "cmd", "@"+path+".missing", "B")
+	if err == nil {
+		t.Error("expected error for missing file")
+	}
+}
@@ -0,0 +1,134 @@
+// Package cobracli mounts the commands of the dispatchers
+// of the cli package into a github.com/spf13/cobra command tree.
+// It is a separate module so the cli package
+// does not depend on cobra.
+package cobracli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/domonda/go-function/cli"
+)
+
+// ToCobra returns a cobra root command with a sub command
+// for every command of the dispatcher.
+// The root command is named like the program of os.Args[0]
+// and runs the default command of the dispatcher if there is one.
+// Flag parsing, help and global flags are left to the dispatcher,
+// so commands behave the same as with the dispatcher.
+// Completion of flags and enumerable argument values is provided
+// via cobra's ValidArgsFunction.
+// To mount the commands into an existing cobra command tree
+// add the sub commands of the returned command
+// or the returned command itself with a different Use.
+func ToCobra(dispatcher cli.CommandDispatcher) *cobra.Command {
+	root := &cobra.Command{Use: filepath.Base(os.Args[0])}
+	for _, info := range cli.ListCommands(dispatcher) {
+		words := strings.Fields(info.Command)
+		parent := root
+		for _, word := range words {
+			parent = subCommand(parent, word)
+		}
+		cmd := parent
+		cmd.Short = info.Description
+		cmd.Hidden = info.Hidden
+		cmd.Example = strings.Join(info.Examples, "\n")
+		if len(words) > 0 {
+			cmd.Use = words[len(words)-1] + argsUsage(info.Args)
+		}
+		cmd.Args = cobra.ArbitraryArgs
+		cmd.DisableFlagParsing = true
+		cmd.ValidArgsFunction = completeArgs(info.Args)
+		cmd.RunE = func(c *cobra.Command, args []string) error {
+			c.SilenceUsage = true
+			return cli.DispatchCommandLine(c.Context(), dispatcher, append(slices.Clone(words), args...))
+		}
+	}
+	return root
+}
+
+// subCommand returns the sub command of parent
+// named word and adds it if it does not exist.
+func subCommand(parent *cobra.Command, word string) *cobra.Command {
+	for _, cmd := range parent.Commands() {
+		if cmd.Name() == word {
+			return cmd
+		}
+	}
+	cmd := &cobra.Command{Use: word}
+	parent.AddCommand(cmd)
+	return cmd
+}
+
+func argsUsage(args []cli.ArgInfo) string {
+	var b strings.Builder
+	for _, arg := range args {
+		if arg.Default != "" {
+			b.WriteString(" [" + arg.Name + "]")
+		} else {
+			b.WriteString(" <" + arg.Name + ">")
+		}
+	}
+	return b.String()
+}
+
+// completeArgs returns a cobra.ValidArgsFunction completing
+// the flags of args and their enumerable values
+// for flags and positional arguments.
+func completeArgs(args []cli.ArgInfo) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, passed []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if strings.HasPrefix(toComplete, "-") {
+			flags := []string{"--help"}
+			for _, arg := range args {
+				flags = append(flags, arg.Flag)
+			}
+			return flags, cobra.ShellCompDirectiveNoFileComp
+		}
+		numPositional := 0
+		for i := 0; i < len(passed); i++ {
+			if !strings.HasPrefix(passed[i], "--") {
+				numPositional++
+				continue
+			}
+			if strings.Contains(passed[i], "=") {
+				continue
+			}
+			arg := argByFlag(args, passed[i])
+			if arg == nil || isBool(arg) {
+				continue
+			}
+			if i == len(passed)-1 {
+				// Complete value of the flag
+				return arg.Values, cobra.ShellCompDirectiveNoFileComp
+			}
+			i++
+		}
+		if numPositional < len(args) && len(args[numPositional].Values) > 0 {
+			return args[numPositional].Values, cobra.ShellCompDirectiveNoFileComp
+		}
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+}
+
+func argByFlag(args []cli.ArgInfo, flag string) *cli.ArgInfo {
+	for i := range args {
+		if args[i].Flag == flag {
+			return &args[i]
+		}
+	}
+	return nil
+}
+
+func isBool(arg *cli.ArgInfo) bool {
+	t := arg.Type
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Bool
+}
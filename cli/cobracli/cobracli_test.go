@@ -0,0 +1,47 @@
+package cobracli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/domonda/go-function"
+	"github.com/domonda/go-function/cli"
+)
+
+func TestToCobra(t *testing.T) {
+	var migrated []string
+	tree := cli.NewCommandTree()
+	tree.MustAddCommand("db migrate", "Migrate the database", function.MustReflectWrapper(
+		func(env string, dryRun bool) { migrated = append(migrated, env) },
+		"env", "dryRun",
+	))
+	root := ToCobra(tree)
+
+	migrate, args, err := root.Find([]string{"db", "migrate"})
+	if err != nil || len(args) > 0 {
+		t.Fatalf("Find() = %v, %v", args, err)
+	}
+	if migrate.Short != "Migrate the database" || migrate.Use != "migrate <env> <dryRun>" {
+		t.Errorf("unexpected command: Use %q, Short %q", migrate.Use, migrate.Short)
+	}
+
+	root.SetArgs([]string{"db", "migrate", "--env=prod", "--dry-run"})
+	if err := root.ExecuteContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(migrated) != 1 || migrated[0] != "prod" {
+		t.Errorf("migrated %v, want [prod]", migrated)
+	}
+
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetArgs([]string{"__complete", "db", "migrate", "--"})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "--env\n--dry-run\n") {
+		t.Errorf("unexpected completions:\n%s", out.String())
+	}
+}
@@ -0,0 +1,30 @@
+module github.com/domonda/go-function/cli/cobracli
+
+go 1.23
+
+replace (
+	github.com/domonda/go-function => ../..
+	github.com/domonda/go-function/cli => ..
+)
+
+require (
+	github.com/domonda/go-function v0.0.0-00010101000000-000000000000 // replaced
+	github.com/domonda/go-function/cli v0.0.0-00010101000000-000000000000 // replaced
+)
+
+require github.com/spf13/cobra v1.8.1
+
+require (
+	github.com/fatih/color v1.17.0 // indirect
+	github.com/h2non/filetype v1.1.3 // indirect
+	github.com/inconshreveable/mousetrap v1.1.0 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/posener/complete/v2 v2.1.0 // indirect
+	github.com/posener/script v1.2.0 // indirect
+	github.com/spf13/pflag v1.0.5 // indirect
+	github.com/ungerik/go-httpx v0.0.0-20240110134719-544aadceddba // indirect
+	golang.org/x/sys v0.27.0 // indirect
+	golang.org/x/term v0.26.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/fatih/color"
+	"golang.org/x/term"
+)
+
+// NoColorFlag is the built-in global flag that disables colored output.
+// It is handled by all dispatchers unless a global flag
+// with the same name was added.
+const NoColorFlag = "no-color"
+
+// colorEnabled returns true if colored output should be written to w.
+// Colors are disabled by the dispatcher config, the NoColorFlag
+// passed to the command of ctx, the NO_COLOR
+// environment variable, or if w is not a terminal.
+func (cfg *dispatcherConfig) colorEnabled(ctx context.Context, w io.Writer) bool {
+	if cfg.noColor || noColorByFlag(ctx) || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(w)
+}
+
+// isTerminal returns true if w is a file connected to a terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd())) //#nosec G115 -- file descriptors fit into int
+}
+
+// color returns c if colored output is enabled for w,
+// else a copy of c with disabled colors.
+func (cfg *dispatcherConfig) color(ctx context.Context, c *color.Color, w io.Writer) *color.Color {
+	if cfg.colorEnabled(ctx, w) {
+		return c
+	}
+	disabled := *c
+	disabled.DisableColor()
+	return &disabled
+}
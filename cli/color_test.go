@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/domonda/go-function"
+)
+
+func TestStringArgsDispatcher_noColorFlag(t *testing.T) {
+	var (
+		got     []string
+		noColor []bool
+	)
+	disp := NewStringArgsDispatcher()
+	disp.MustAddCommand("cmd", "", function.MustReflectWrapper(func(ctx context.Context, a string) {
+		got = append(got, a)
+		noColor = append(noColor, noColorByFlag(ctx))
+	}, "ctx", "a"))
+
+	err := disp.Dispatch(context.Background(), "cmd", "--no-color", "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The flag only applies to the dispatched command
+	err = disp.Dispatch(context.Background(), "cmd", "y")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, []string{"x", "y"}) {
+		t.Errorf("got args %v", got)
+	}
+	if !slices.Equal(noColor, []bool{true, false}) {
+		t.Errorf("got no color flags %v, want [true false]", noColor)
+	}
+	if disp.cfg.noColor {
+		t.Error("--no-color changed the dispatcher config")
+	}
+}
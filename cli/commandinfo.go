@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/domonda/go-function"
+)
+
+// CommandInfo describes a command of a dispatcher
+// for integrations with other tools and frameworks.
+type CommandInfo struct {
+	// Command including super commands separated by space,
+	// empty for the default command.
+	Command     string
+	Description string
+	Func        function.Wrapper
+	Args        []ArgInfo // without a context argument
+	Hidden      bool
+	Deprecated  string // deprecation message, empty if not deprecated
+	Examples    []string
+}
+
+// ArgInfo describes an argument of a command function.
+type ArgInfo struct {
+	Name        string
+	Flag        string // kebab-case flag like --dry-run
+	Type        reflect.Type
+	Description string
+	Default     string   // empty if the argument has no default value
+	Values      []string // allowed values if they can be enumerated
+}
+
+// ListCommands returns information about all commands
+// of the dispatcher including hidden ones sorted by command.
+func ListCommands(dispatcher CommandSource) []CommandInfo {
+	list := dispatcher.commandList()
+	infos := make([]CommandInfo, len(list))
+	for i, cmd := range list {
+		infos[i] = cmd.info()
+	}
+	return infos
+}
+
+func (cmd *stringArgsCommand) info() CommandInfo {
+	f := cmd.commandFunc
+	argNames := positionalArgNames(f)
+	offset := f.NumArgs() - len(argNames)
+	descriptions := f.ArgDescriptions()
+	args := make([]ArgInfo, len(argNames))
+	for i, argName := range argNames {
+		args[i] = ArgInfo{
+			Name:    argName,
+			Flag:    "--" + kebabCase(argName),
+			Type:    f.ArgTypes()[offset+i],
+			Default: cmd.argDefaults[argName],
+			Values:  cmd.allowedArgValues(argName, f.ArgTypes()[offset+i]),
+		}
+		if offset+i < len(descriptions) {
+			args[i].Description = descriptions[offset+i]
+		}
+	}
+	return CommandInfo{
+		Command:     cmd.fullCommand,
+		Description: cmd.description,
+		Func:        f,
+		Args:        args,
+		Hidden:      cmd.hidden,
+		Deprecated:  cmd.deprecated,
+		Examples:    cmd.examples,
+	}
+}
+
+// DispatchCommandLine dispatches the command line commandAndArgs
+// with all features of the dispatcher like global flags and help.
+// It can be used to integrate a dispatcher into other
+// command line frameworks.
+func DispatchCommandLine(ctx context.Context, dispatcher CommandDispatcher, commandAndArgs []string) error {
+	return dispatcher.dispatchCommandLine(ctx, commandAndArgs)
+}
@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/domonda/go-function"
+)
+
+// StringArgsCommandEventLogger is a StringArgsCommandLogger
+// that receives events at the start and the end of every command.
+// Dispatchers call LogCommandStart and LogCommandEnd
+// instead of LogStringArgsCommand for loggers implementing this interface.
+type StringArgsCommandEventLogger interface {
+	StringArgsCommandLogger
+
+	LogCommandStart(event *CommandEvent)
+	LogCommandEnd(event *CommandEvent)
+}
+
+// CommandEvent describes the execution of a dispatched command.
+// End, Duration, Err and Results are only set for the end event.
+type CommandEvent struct {
+	Command  string // including super commands separated by space
+	Args     []string
+	Start    time.Time
+	End      time.Time
+	Duration time.Duration
+	Err      error
+	Results  []string // short summary of every result
+}
+
+type commandEventCtxKey struct{}
+
+// recordResults is a results handler that sets the Results
+// summary of the CommandEvent in the context if there is one.
+var recordResults function.ResultsHandlerFunc = func(ctx context.Context, results []any, resultErr error) error {
+	if event, ok := ctx.Value(commandEventCtxKey{}).(*CommandEvent); ok {
+		event.Results = make([]string, len(results))
+		for i, result := range results {
+			event.Results[i] = summarizeResult(result)
+		}
+	}
+	return nil
+}
+
+func withRecordResults(resultsHandlers []function.ResultsHandler) []function.ResultsHandler {
+	return append([]function.ResultsHandler{recordResults}, resultsHandlers...)
+}
+
+// MaxResultSummaryLen is the maximum number of characters
+// of a result summary of a CommandEvent.
+var MaxResultSummaryLen = 80
+
+// summarizeResult returns the type and length for collections
+// and the formatted value shortened to MaxResultSummaryLen otherwise.
+func summarizeResult(result any) string {
+	v := reflect.ValueOf(result)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return fmt.Sprintf("%T (len %d)", result, v.Len())
+	}
+	s := []rune(fmt.Sprint(result))
+	if len(s) > MaxResultSummaryLen {
+		return string(s[:MaxResultSummaryLen-1]) + "…"
+	}
+	return string(s)
+}
+
+// logCommand calls all loggers for a command execution.
+// It returns a context for the execution and a function
+// that has to be called with the command error after the execution.
+func (cfg *dispatcherConfig) logCommand(ctx context.Context, cmd *stringArgsCommand, args []string) (context.Context, func(error)) {
+	var event *CommandEvent
+	for _, logger := range cfg.loggers {
+		eventLogger, ok := logger.(StringArgsCommandEventLogger)
+		if !ok {
+			logger.LogStringArgsCommand(cmd.command, args)
+			continue
+		}
+		if event == nil {
+			event = &CommandEvent{Command: cmd.fullCommand, Args: args, Start: time.Now()}
+			ctx = context.WithValue(ctx, commandEventCtxKey{}, event)
+		}
+		eventLogger.LogCommandStart(event)
+	}
+	if event == nil {
+		return ctx, func(error) {}
+	}
+	return ctx, func(err error) {
+		event.End = time.Now()
+		event.Duration = event.End.Sub(event.Start)
+		event.Err = err
+		for _, logger := range cfg.loggers {
+			if eventLogger, ok := logger.(StringArgsCommandEventLogger); ok {
+				eventLogger.LogCommandEnd(event)
+			}
+		}
+	}
+}
+
+// NewTextCommandLogger returns a StringArgsCommandEventLogger
+// that writes one line of text per event to w.
+func NewTextCommandLogger(w io.Writer) StringArgsCommandEventLogger {
+	return &writerCommandLogger{w: w}
+}
+
+// NewJSONLinesCommandLogger returns a StringArgsCommandEventLogger
+// that writes one JSON object per event and line to w.
+func NewJSONLinesCommandLogger(w io.Writer) StringArgsCommandEventLogger {
+	return &writerCommandLogger{w: w, jsonLines: true}
+}
+
+// NewFileCommandLogger returns a StringArgsCommandEventLogger that
+// appends the events as text or JSON lines to the file at filePath.
+// The file is created if it does not exist
+// and only opened while an event is written.
+func NewFileCommandLogger(filePath string, jsonLines bool) StringArgsCommandEventLogger {
+	return &writerCommandLogger{w: appendFile(filePath), jsonLines: jsonLines}
+}
+
+// appendFile is an io.Writer that appends
+// every write to the file at its path.
+type appendFile string
+
+func (filePath appendFile) Write(p []byte) (n int, err error) {
+	f, err := os.OpenFile(string(filePath), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600) //#nosec G304 -- path set by the program
+	if err != nil {
+		return 0, err
+	}
+	n, err = f.Write(p)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return n, err
+}
+
+type writerCommandLogger struct {
+	mtx       sync.Mutex
+	w         io.Writer
+	jsonLines bool
+}
+
+// LogStringArgsCommand logs a start event.
+// Dispatchers call LogCommandStart instead.
+func (l *writerCommandLogger) LogStringArgsCommand(command string, args []string) {
+	l.LogCommandStart(&CommandEvent{Command: command, Args: args, Start: time.Now()})
+}
+
+func (l *writerCommandLogger) LogCommandStart(event *CommandEvent) {
+	l.write("start", event.Start, event)
+}
+
+func (l *writerCommandLogger) LogCommandEnd(event *CommandEvent) {
+	l.write("end", event.End, event)
+}
+
+type jsonCommandEvent struct {
+	Event    string    `json:"event"`
+	Time     time.Time `json:"time"`
+	Command  string    `json:"command"`
+	Args     []string  `json:"args"`
+	Duration string    `json:"duration,omitempty"`
+	Error    string    `json:"error,omitempty"`
+	Results  []string  `json:"results,omitempty"`
+}
+
+func (l *writerCommandLogger) write(eventName string, t time.Time, event *CommandEvent) {
+	var line []byte
+	if l.jsonLines {
+		e := jsonCommandEvent{
+			Event:   eventName,
+			Time:    t,
+			Command: event.Command,
+			Args:    event.Args,
+			Results: event.Results,
+		}
+		if eventName == "end" {
+			e.Duration = event.Duration.String()
+		}
+		if event.Err != nil {
+			e.Error = event.Err.Error()
+		}
+		line, _ = json.Marshal(e) //#nosec G104 -- can't fail for strings
+		line = append(line, '\n')
+	} else {
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s %s %q %q", t.Format(time.RFC3339Nano), eventName, event.Command, event.Args)
+		if eventName == "end" {
+			fmt.Fprintf(&b, " duration=%s", event.Duration)
+			if event.Err != nil {
+				fmt.Fprintf(&b, " error=%q", event.Err.Error())
+			}
+			if len(event.Results) > 0 {
+				fmt.Fprintf(&b, " results=%q", event.Results)
+			}
+		}
+		b.WriteByte('\n')
+		line = []byte(b.String())
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.w.Write(line) //#nosec G104 -- logging must not fail the command
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/domonda/go-function"
+)
+
+func TestNewJSONLinesCommandLogger(t *testing.T) {
+	var buf strings.Builder
+	var logged []string
+	disp := NewStringArgsDispatcher(
+		NewJSONLinesCommandLogger(&buf),
+		StringArgsCommandLoggerFunc(func(command string, args []string) { logged = append(logged, command) }),
+	)
+	disp.MustAddCommand("list", "", function.MustReflectWrapper(func(n int) []int { return make([]int, n) }, "n"))
+	disp.MustAddCommand("fail", "", function.MustReflectWrapper(func() error { return errors.New("failed") }))
+
+	disp.MustDispatch(context.Background(), "list", "3")
+	if err := disp.Dispatch(context.Background(), "fail"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	var events []jsonCommandEvent
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var event jsonCommandEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, event)
+	}
+	if len(events) != 4 {
+		t.Fatalf("logged %d events, want 4", len(events))
+	}
+	if e := events[0]; e.Event != "start" || e.Command != "list" || !slices.Equal(e.Args, []string{"3"}) {
+		t.Errorf("unexpected start event: %#v", e)
+	}
+	if e := events[1]; e.Event != "end" || e.Duration == "" || e.Error != "" || !slices.Equal(e.Results, []string{"[]int (len 3)"}) {
+		t.Errorf("unexpected end event: %#v", e)
+	}
+	if e := events[3]; e.Event != "end" || e.Command != "fail" || e.Error != "failed" {
+		t.Errorf("unexpected end event: %#v", e)
+	}
+	if want := []string{"list", "fail"}; !slices.Equal(logged, want) {
+		t.Errorf("simple logger logged %v, want %v", logged, want)
+	}
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/domonda/go-function"
+)
+
+// CommandOption configures a command.
+// Command options can be passed to AddCommand and
+// its variants together with the results handlers.
+// A CommandOption implements function.ResultsHandler
+// by returning the result error unchanged,
+// but it is not used as results handler of the command.
+type CommandOption interface {
+	function.ResultsHandler
+
+	applyToCommand(cmd *stringArgsCommand)
+}
+
+// commandOptionFunc implements CommandOption
+type commandOptionFunc func(cmd *stringArgsCommand)
+
+func (f commandOptionFunc) HandleResults(ctx context.Context, results []any, resultErr error) error {
+	return resultErr
+}
+
+func (f commandOptionFunc) applyToCommand(cmd *stringArgsCommand) {
+	f(cmd)
+}
+
+// splitCommandOptions separates the CommandOption
+// values from the results handlers.
+func splitCommandOptions(handlers []function.ResultsHandler) (resultsHandlers []function.ResultsHandler, options []CommandOption) {
+	for _, handler := range handlers {
+		if option, ok := handler.(CommandOption); ok {
+			options = append(options, option)
+		} else {
+			resultsHandlers = append(resultsHandlers, handler)
+		}
+	}
+	return resultsHandlers, options
+}
+
+// WithArgDefault returns a CommandOption that sets the default value
+// of the argument argName that is used if the argument
+// is not passed on the command line or found in
+// environment variables or the config file.
+// Usage output shows arguments with default values as [name:type=value].
+func WithArgDefault(argName, value string) CommandOption {
+	return commandOptionFunc(func(cmd *stringArgsCommand) {
+		if cmd.argDefaults == nil {
+			cmd.argDefaults = make(map[string]string)
+		}
+		cmd.argDefaults[argName] = value
+	})
+}
+
+// WithExample returns a CommandOption that adds an example
+// command line to the help output and generated documentation
+// of the command. Multiple examples can be added.
+func WithExample(example string) CommandOption {
+	return commandOptionFunc(func(cmd *stringArgsCommand) {
+		cmd.examples = append(cmd.examples, example)
+	})
+}
+
+// WithArgValues returns a CommandOption that restricts the argument
+// argName to the passed allowed values.
+// Other values are rejected with a UsageError
+// and the values are used for shell completion.
+func WithArgValues(argName string, values ...string) CommandOption {
+	return commandOptionFunc(func(cmd *stringArgsCommand) {
+		if cmd.argValues == nil {
+			cmd.argValues = make(map[string][]string)
+		}
+		cmd.argValues[argName] = values
+	})
+}
+
+// allowedArgValues returns the values registered with WithArgValues
+// for argName or the enumerable values of argType.
+func (cmd *stringArgsCommand) allowedArgValues(argName string, argType reflect.Type) []string {
+	if values, ok := cmd.argValues[argName]; ok {
+		return values
+	}
+	return argTypeValues(argType)
+}
+
+// checkArgValues returns a UsageError if a value of args
+// is not one of the values registered with WithArgValues.
+func (cmd *stringArgsCommand) checkArgValues(args map[string]string) error {
+	for _, argName := range positionalArgNames(cmd.commandFunc) {
+		allowed, ok := cmd.argValues[argName]
+		if !ok {
+			continue
+		}
+		if value, ok := args[argName]; ok && !slices.Contains(allowed, value) {
+			err := function.NewErrParseArgString(fmt.Errorf("invalid value %q, allowed are: %s", value, strings.Join(allowed, ", ")), cmd.commandFunc, argName)
+			return cmd.newUsageError(fmt.Errorf("command '%s': %w", cmd.command, err))
+		}
+	}
+	return nil
+}
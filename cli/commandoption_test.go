@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/domonda/go-function"
+)
+
+func TestWithArgDefault(t *testing.T) {
+	var (
+		gotQuery string
+		gotLimit int
+	)
+	disp := NewStringArgsDispatcher()
+	disp.MustAddCommand("search", "", function.MustReflectWrapper(
+		func(query string, limit int) { gotQuery, gotLimit = query, limit },
+		"query", "limit",
+	), WithArgDefault("limit", "50"))
+
+	err := disp.Dispatch(context.Background(), "search", "go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotQuery != "go" || gotLimit != 50 {
+		t.Errorf("got query=%q limit=%d", gotQuery, gotLimit)
+	}
+	err = disp.Dispatch(context.Background(), "search", "go", "10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotLimit != 10 {
+		t.Errorf("got limit=%d, want 10", gotLimit)
+	}
+
+	if got, want := positionalArgsString(disp.comm["search"].commandFunc, disp.comm["search"].argDefaults), "<query:string> [limit:int=50]"; got != want {
+		t.Errorf("positionalArgsString() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,509 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/domonda/go-function"
+)
+
+// CommandTree dispatches commands of arbitrary depth
+// like "myapp db migrations create <name>".
+// Every node of the tree can have a default command
+// that is called with the arguments following the
+// command words of the node, and any number of commands
+// and sub trees.
+// All nodes share the configuration of the root.
+type CommandTree struct {
+	path     string // command words separated by space
+	disp     *StringArgsDispatcher
+	children map[string]*CommandTree
+	cfg      *dispatcherConfig
+}
+
+// NewCommandTree returns a new empty CommandTree.
+func NewCommandTree(loggers ...StringArgsCommandLogger) *CommandTree {
+	return newCommandTree("", newDispatcherConfig(loggers))
+}
+
+func newCommandTree(path string, cfg *dispatcherConfig) *CommandTree {
+	return &CommandTree{
+		path:     path,
+		disp:     newStringArgsDispatcher(path, cfg),
+		children: make(map[string]*CommandTree),
+		cfg:      cfg,
+	}
+}
+
+// Sub returns the sub tree for the space separated
+// command words of path relative to the tree.
+// Missing nodes are created.
+func (tree *CommandTree) Sub(path string) (*CommandTree, error) {
+	node := tree
+	for _, word := range strings.Fields(path) {
+		if err := checkCommandChars(word); err != nil {
+			return nil, fmt.Errorf("Command '%s': %w", path, err)
+		}
+		if node.disp.HasCommnd(word) {
+			return nil, fmt.Errorf("Command '%s' already added as command and can't have sub commands", strings.TrimSpace(node.path+" "+word))
+		}
+		child, ok := node.children[word]
+		if !ok {
+			child = newCommandTree(strings.TrimSpace(node.path+" "+word), node.cfg)
+			node.children[word] = child
+		}
+		node = child
+	}
+	return node, nil
+}
+
+// MustSub calls Sub and panics on error.
+func (tree *CommandTree) MustSub(path string) *CommandTree {
+	sub, err := tree.Sub(path)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
+
+// AddCommand adds a command with the space separated command words
+// of path relative to the tree like "db migrations create".
+// If the last word of the path is the name of an existing
+// sub tree, then the command is added as default command of that sub tree.
+// An empty path adds the default command of the tree.
+func (tree *CommandTree) AddCommand(path, description string, commandFunc function.Wrapper, resultsHandlers ...function.ResultsHandler) error {
+	parent, command, err := tree.parentAndCommand(path)
+	if err != nil {
+		return err
+	}
+	if sub, ok := parent.children[command]; ok || command == DefaultCommand {
+		if command == DefaultCommand {
+			sub = parent
+		}
+		if sub.disp.HasDefaultCommnd() {
+			return fmt.Errorf("Command '%s' already added", sub.path)
+		}
+		return sub.disp.AddDefaultCommand(description, commandFunc, resultsHandlers...)
+	}
+	return parent.disp.AddCommand(command, description, commandFunc, resultsHandlers...)
+}
+
+// MustAddCommand calls AddCommand and panics on error.
+func (tree *CommandTree) MustAddCommand(path, description string, commandFunc function.Wrapper, resultsHandlers ...function.ResultsHandler) {
+	err := tree.AddCommand(path, description, commandFunc, resultsHandlers...)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// AddHiddenCommand adds a command like AddCommand
+// that is excluded from PrintCommands, completion,
+// and suggestions but can still be dispatched.
+func (tree *CommandTree) AddHiddenCommand(path, description string, commandFunc function.Wrapper, resultsHandlers ...function.ResultsHandler) error {
+	err := tree.AddCommand(path, description, commandFunc, resultsHandlers...)
+	if err != nil {
+		return err
+	}
+	cmd, _ := tree.command(path)
+	cmd.hidden = true
+	return nil
+}
+
+// MustAddHiddenCommand calls AddHiddenCommand and panics on error.
+func (tree *CommandTree) MustAddHiddenCommand(path, description string, commandFunc function.Wrapper, resultsHandlers ...function.ResultsHandler) {
+	err := tree.AddHiddenCommand(path, description, commandFunc, resultsHandlers...)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// parentAndCommand returns the node for all but the last
+// command word of path and the last command word.
+func (tree *CommandTree) parentAndCommand(path string) (parent *CommandTree, command string, err error) {
+	words := strings.Fields(path)
+	if len(words) == 0 {
+		return tree, DefaultCommand, nil
+	}
+	parent, err = tree.Sub(strings.Join(words[:len(words)-1], " "))
+	if err != nil {
+		return nil, "", err
+	}
+	command = words[len(words)-1]
+	if err := checkCommandChars(command); err != nil {
+		return nil, "", fmt.Errorf("Command '%s': %w", path, err)
+	}
+	return parent, command, nil
+}
+
+// command returns the command with the space separated
+// command words of path relative to the tree.
+func (tree *CommandTree) command(path string) (*stringArgsCommand, error) {
+	node, rest := tree.resolve(strings.Fields(path))
+	switch len(rest) {
+	case 0:
+		if cmd, ok := node.disp.comm[DefaultCommand]; ok {
+			return cmd, nil
+		}
+	case 1:
+		if cmd, ok := node.disp.comm[rest[0]]; ok && rest[0] != DefaultCommand {
+			return cmd, nil
+		}
+	}
+	return nil, withSuggestions(ErrCommandNotFound(strings.Join(strings.Fields(path), " ")), strings.Join(rest, " "), node.visibleCommands())
+}
+
+// resolve follows the sub trees for the passed words
+// and returns the deepest node and the remaining words.
+func (tree *CommandTree) resolve(words []string) (node *CommandTree, rest []string) {
+	node = tree
+	for len(words) > 0 {
+		child, ok := node.children[words[0]]
+		if !ok {
+			break
+		}
+		node = child
+		words = words[1:]
+	}
+	return node, words
+}
+
+// HasCommand returns true if there is a command
+// for the space separated command words of path.
+func (tree *CommandTree) HasCommand(path string) bool {
+	_, err := tree.command(path)
+	return err == nil
+}
+
+// Commands returns the sorted paths of all commands of the tree
+// including hidden commands. Default commands of sub trees
+// are returned as path of the sub tree.
+func (tree *CommandTree) Commands() []string {
+	var commands []string
+	tree.walk(func(node *CommandTree) {
+		for _, cmd := range node.disp.comm {
+			commands = append(commands, cmd.fullCommand)
+		}
+	})
+	slices.Sort(commands)
+	return commands
+}
+
+// walk calls visit for the tree and all sub trees
+// in order of their sorted command words.
+func (tree *CommandTree) walk(visit func(*CommandTree)) {
+	visit(tree)
+	for _, word := range slices.Sorted(maps.Keys(tree.children)) {
+		tree.children[word].walk(visit)
+	}
+}
+
+// visibleCommands returns the sorted command words of the
+// sub trees and not hidden commands of the node.
+func (tree *CommandTree) visibleCommands() []string {
+	commands := slices.Collect(maps.Keys(tree.children))
+	for _, command := range tree.disp.visibleCommands() {
+		if command != DefaultCommand {
+			commands = append(commands, command)
+		}
+	}
+	slices.Sort(commands)
+	return commands
+}
+
+// Dispatch resolves the command from the leading command words
+// of commandAndArgs and calls it with the following arguments.
+// If a command path resolves to a sub tree that was
+// not followed by a command of the sub tree,
+// then the default command of the sub tree is called.
+// The arguments "help" and "--help" print the help
+// of the tree, a sub tree, or a command.
+func (tree *CommandTree) Dispatch(ctx context.Context, commandAndArgs []string) (command string, err error) {
+	ctx, commandAndArgs, err = tree.cfg.withGlobalFlags(ctx, commandAndArgs)
+	if err != nil {
+		return "", err
+	}
+	if tree.cfg.printVersionIfRequested(ctx) {
+		return VersionCommand, nil
+	}
+	node, rest := tree.resolve(commandAndArgs)
+	if len(rest) > 0 {
+		word := rest[0]
+		switch {
+		case word != DefaultCommand && node.disp.HasCommnd(word):
+			return strings.TrimSpace(node.path + " " + word), node.disp.Dispatch(ctx, word, rest[1:]...)
+		case word == HelpCommand || isHelpFlag(word):
+			return node.path, node.printHelp(ctx, rest[1:])
+		case !isFlag(word) && !node.disp.HasDefaultCommnd():
+			notFound := strings.TrimSpace(node.path + " " + word)
+			if found, err := tree.cfg.runPlugin(ctx, strings.Fields(notFound), rest[1:]); found {
+				return notFound, err
+			}
+			return notFound, withSuggestions(ErrCommandNotFound(notFound), word, node.visibleCommands())
+		}
+	}
+	if !node.disp.HasDefaultCommnd() {
+		return node.path, ErrCommandNotFound(node.path)
+	}
+	return node.path, node.disp.Dispatch(ctx, DefaultCommand, rest...)
+}
+
+// MustDispatch calls Dispatch and panics on error.
+func (tree *CommandTree) MustDispatch(ctx context.Context, commandAndArgs []string) (command string) {
+	command, err := tree.Dispatch(ctx, commandAndArgs)
+	if err != nil {
+		panic(fmt.Errorf("MustDispatch(%v): %w", commandAndArgs, err))
+	}
+	return command
+}
+
+// printHelp prints the help of the command with
+// the command words of path relative to the tree,
+// or the help of the whole tree if path does not
+// resolve to a command.
+func (tree *CommandTree) printHelp(ctx context.Context, path []string) error {
+	node, rest := tree.resolve(path)
+	if len(rest) > 0 {
+		return tree.printCommandHelp(ctx, appName(), strings.Join(path, " "))
+	}
+	if node.disp.HasDefaultCommnd() {
+		err := node.disp.comm[DefaultCommand].printHelp(ctx, node.cfg, os.Stdout, appName())
+		if err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+	fmt.Println("Commands:")
+	node.printCommands(ctx, appName())
+	return node.cfg.printGlobalFlags(os.Stdout)
+}
+
+// PrintCommandHelp prints the usage, description,
+// and arguments of the command with the space separated
+// command words of path to os.Stdout.
+// Returns ErrCommandNotFound if there is no such command.
+func (tree *CommandTree) PrintCommandHelp(appName, path string) error {
+	return tree.printCommandHelp(context.Background(), appName, path)
+}
+
+func (tree *CommandTree) printCommandHelp(ctx context.Context, appName, path string) error {
+	cmd, err := tree.command(path)
+	if err != nil {
+		return err
+	}
+	err = cmd.printHelp(ctx, tree.cfg, os.Stdout, appName)
+	if err != nil {
+		return err
+	}
+	return tree.cfg.printGlobalFlags(os.Stdout)
+}
+
+// PrintCommands prints the usage of all not hidden
+// commands of the tree and its sub trees.
+func (tree *CommandTree) PrintCommands(appName string) {
+	tree.printCommands(context.Background(), appName)
+}
+
+func (tree *CommandTree) printCommands(ctx context.Context, appName string) {
+	tree.walk(func(node *CommandTree) {
+		for _, command := range node.disp.visibleCommands() {
+			cmd := node.disp.comm[command]
+			printCommandUsage(ctx, node.cfg, appName, cmd.fullCommand, cmd)
+		}
+	})
+}
+
+func (tree *CommandTree) completionTree() *completionNode {
+	node := &completionNode{path: tree.path, cmd: tree.disp.comm[DefaultCommand]}
+	for _, word := range tree.visibleCommands() {
+		if child, ok := tree.children[word]; ok {
+			node.children = append(node.children, child.completionTree())
+		} else {
+			cmd := tree.disp.comm[word]
+			node.children = append(node.children, &completionNode{path: cmd.fullCommand, cmd: cmd})
+		}
+	}
+	return node
+}
+
+// GenCompletionScript returns a completion script for the passed shell
+// that completes the commands of all levels, flags and
+// enumerable argument values of the tree
+// for the program name of os.Args[0].
+// Supported shells are listed in CompletionShells.
+func (tree *CommandTree) GenCompletionScript(shell string) (string, error) {
+	return genCompletionScript(shell, appName(), tree.completionTree())
+}
+
+// DeprecateCommand marks the command with the space
+// separated command words of path as deprecated.
+// See StringArgsDispatcher.DeprecateCommand
+func (tree *CommandTree) DeprecateCommand(path, message string) error {
+	cmd, err := tree.command(path)
+	if err != nil {
+		return err
+	}
+	cmd.deprecated = message
+	return nil
+}
+
+// SetOutputFormatHandler sets the results handler of the command
+// with the space separated command words of path
+// for an output format selected with the --output flag.
+// See StringArgsDispatcher.SetOutputFormatHandler
+func (tree *CommandTree) SetOutputFormatHandler(path, format string, handler function.ResultsHandler) error {
+	cmd, err := tree.command(path)
+	if err != nil {
+		return err
+	}
+	if cmd.outputHandlers == nil {
+		cmd.outputHandlers = make(map[string]function.ResultsHandler)
+	}
+	cmd.outputHandlers[format] = handler
+	return nil
+}
+
+// SetArgEnvVar sets the environment variable envVar
+// as source for the argument argName of all commands
+// when the argument is not passed on the command line.
+func (tree *CommandTree) SetArgEnvVar(argName, envVar string) {
+	tree.cfg.setArgEnvVar(argName, envVar)
+}
+
+// SetEnvVarPrefix enables environment variables as source
+// for all arguments not passed on the command line.
+// See StringArgsDispatcher.SetEnvVarPrefix
+func (tree *CommandTree) SetEnvVarPrefix(prefix string) {
+	tree.cfg.envVarPrefix = prefix
+}
+
+// EnablePrompting enables interactive prompting on os.Stdin
+// for required arguments that were not passed.
+// See StringArgsDispatcher.EnablePrompting
+func (tree *CommandTree) EnablePrompting() {
+	tree.cfg.enablePrompting(os.Stdin, os.Stderr)
+}
+
+// SetArgSensitive marks the argument argName as sensitive
+// so that its prompted input is not echoed to the terminal.
+// Has only an effect if prompting is enabled.
+func (tree *CommandTree) SetArgSensitive(argName string) {
+	tree.cfg.setArgSensitive(argName)
+}
+
+// WithConfigFile sets a YAML or JSON file that maps commands
+// to default values for their arguments.
+// Commands are keyed with their command words
+// separated by a space like "db migrations create".
+// See StringArgsDispatcher.WithConfigFile
+func (tree *CommandTree) WithConfigFile(path string) *CommandTree {
+	tree.cfg.configFile = configFileArgSource(path)
+	return tree
+}
+
+// EnableOutputFlag adds the global flag --output
+// that selects the results handler of the called command.
+// See StringArgsDispatcher.EnableOutputFlag
+func (tree *CommandTree) EnableOutputFlag() error {
+	return tree.cfg.enableOutputFlag()
+}
+
+// EnableStdinArg enables reading an argument value
+// from stdin if it is passed as "-".
+// See StringArgsDispatcher.EnableStdinArg
+func (tree *CommandTree) EnableStdinArg(trimSpace bool) {
+	tree.cfg.enableStdinArg(trimSpace)
+}
+
+// EnableFileArgs enables reading argument values
+// from files if they are passed as @path.
+// See StringArgsDispatcher.EnableFileArgs
+func (tree *CommandTree) EnableFileArgs() {
+	tree.cfg.fileArgs = true
+}
+
+// AddVersionCommand adds the command "version"
+// and the global flag --version that print
+// the version info of the program.
+// See StringArgsDispatcher.AddVersionCommand
+func (tree *CommandTree) AddVersionCommand(version, commit, date string) error {
+	info := NewVersionInfo(version, commit, date)
+	err := tree.AddCommand(VersionCommand, "Print version information", versionCommand(info), function.Println)
+	if err != nil {
+		return err
+	}
+	return tree.cfg.addVersionFlag(info)
+}
+
+// EnableTimeoutFlag adds the global flag --timeout
+// that sets a deadline on the context passed to the command function.
+// See StringArgsDispatcher.EnableTimeoutFlag
+func (tree *CommandTree) EnableTimeoutFlag(defaultTimeout time.Duration) error {
+	return tree.cfg.enableTimeoutFlag(defaultTimeout)
+}
+
+// EnableProgress renders the progress reported by command functions
+// on os.Stderr if it is a terminal.
+// See StringArgsDispatcher.EnableProgress
+func (tree *CommandTree) EnableProgress() {
+	tree.cfg.progress = true
+}
+
+// EnableDryRunFlag adds the global flag --dry-run
+// that marks the context passed to the command function
+// with function.ContextWithDryRun.
+// See StringArgsDispatcher.EnableDryRunFlag
+func (tree *CommandTree) EnableDryRunFlag(validateOnly bool) error {
+	return tree.cfg.enableDryRunFlag(validateOnly)
+}
+
+// EnablePager pipes the output of commands written to
+// function.OutputFromContext through a pager if it is longer than the terminal height.
+// See StringArgsDispatcher.EnablePager
+func (tree *CommandTree) EnablePager() {
+	tree.cfg.pager = true
+}
+
+// EnablePlugins enables git-style plugin executables
+// for commands that are not found.
+// See StringArgsDispatcher.EnablePlugins
+func (tree *CommandTree) EnablePlugins(prefix string) {
+	tree.cfg.enablePlugins(prefix)
+}
+
+// DisableColor disables colored output of the tree.
+// See StringArgsDispatcher.DisableColor
+func (tree *CommandTree) DisableColor() {
+	tree.cfg.noColor = true
+}
+
+// Use adds a hook that is called before every dispatched command.
+// See StringArgsDispatcher.Use
+func (tree *CommandTree) Use(before BeforeFunc) {
+	tree.cfg.before = append(tree.cfg.before, before)
+}
+
+// UseAfter adds a hook that is called after every dispatched command.
+// See StringArgsDispatcher.UseAfter
+func (tree *CommandTree) UseAfter(after AfterFunc) {
+	tree.cfg.after = append(tree.cfg.after, after)
+}
+
+// AddGlobalFlag adds a flag that can be passed
+// before or after the command words of any command.
+// See StringArgsDispatcher.AddGlobalFlag
+func (tree *CommandTree) AddGlobalFlag(name, description string, defaultValue any) error {
+	return tree.cfg.addGlobalFlag(name, description, defaultValue)
+}
+
+// MustAddGlobalFlag calls AddGlobalFlag and panics on error.
+func (tree *CommandTree) MustAddGlobalFlag(name, description string, defaultValue any) {
+	err := tree.AddGlobalFlag(name, description, defaultValue)
+	if err != nil {
+		panic(err)
+	}
+}
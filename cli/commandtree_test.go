@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/domonda/go-function"
+)
+
+func TestCommandTree_Dispatch(t *testing.T) {
+	var called []string
+	command := func(name string) function.Wrapper {
+		return function.MustReflectWrapper(
+			func(arg string) { called = append(called, name+":"+arg) },
+			"arg",
+		)
+	}
+	tree := NewCommandTree()
+	tree.MustAddCommand("", "", command("root"))
+	tree.MustAddCommand("db migrations create", "", command("create"))
+	tree.MustAddCommand("db migrations", "", command("migrations"))
+	tree.MustSub("db").MustAddCommand("status", "", command("status"))
+
+	if err := tree.AddCommand("db migrations create", "", command("again")); err == nil {
+		t.Error("expected error for duplicate command")
+	}
+	if _, err := tree.Sub("db status sub"); err == nil {
+		t.Error("expected error for sub tree of command")
+	}
+	if want := []string{"", "db migrations", "db migrations create", "db status"}; !slices.Equal(tree.Commands(), want) {
+		t.Errorf("Commands() = %q, want %q", tree.Commands(), want)
+	}
+
+	for _, commandAndArgs := range [][]string{
+		{"db", "migrations", "create", "A"},
+		{"db", "migrations", "B"},
+		{"db", "status", "--arg=C"},
+		{"D"},
+	} {
+		_, err := tree.Dispatch(context.Background(), commandAndArgs)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := []string{"create:A", "migrations:B", "status:C", "root:D"}; !slices.Equal(called, want) {
+		t.Errorf("called %v, want %v", called, want)
+	}
+
+	_, err := tree.Dispatch(context.Background(), []string{"db", "staus"})
+	if !IsErrCommandNotFound(err) || !slices.Equal(Suggestions(err), []string{"status"}) {
+		t.Errorf("expected ErrCommandNotFound with suggestion, got %v", err)
+	}
+
+	script, err := tree.GenCompletionScript("bash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(script, "'db'|'db migrations'|'db migrations create'|'db status'") {
+		t.Errorf("completion script is missing command paths:\n%s", script)
+	}
+}
+
+func TestCommandTree_ManifestJSON(t *testing.T) {
+	tree := NewCommandTree()
+	tree.MustAddCommand("db migrate", "Migrate the database", function.MustReflectWrapper(
+		func(ctx context.Context, env string, steps *int) {},
+		"ctx", "env", "steps",
+	), WithArgDefault("steps", "1"), WithArgValues("env", "dev", "prod"))
+	if err := tree.EnableDryRunFlag(false); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := tree.ManifestJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatal(err)
+	}
+	if len(manifest.GlobalFlags) != 1 || manifest.GlobalFlags[0].Flag != "--dry-run" || !manifest.GlobalFlags[0].IsBool {
+		t.Errorf("unexpected global flags: %#v", manifest.GlobalFlags)
+	}
+	if len(manifest.Commands) != 1 {
+		t.Fatalf("expected 1 command, got %d", len(manifest.Commands))
+	}
+	cmd := manifest.Commands[0]
+	if cmd.Command != "db migrate" || cmd.Description != "Migrate the database" || len(cmd.Args) != 2 {
+		t.Fatalf("unexpected command: %#v", cmd)
+	}
+	want := []ManifestArg{
+		{Name: "env", Flag: "--env", Type: "string", Values: []string{"dev", "prod"}},
+		{Name: "steps", Flag: "--steps", Type: "int", Default: "1"},
+	}
+	if !reflect.DeepEqual(cmd.Args, want) {
+		t.Errorf("args %#v, want %#v", cmd.Args, want)
+	}
+}
@@ -25,8 +25,8 @@ func (c completer) FlagGet(flag string) complete.Predictor  { return nil }
 func (c completer) ArgsGet() complete.Predictor             { return c }
 
 func (c completer) Predict(prefix string) (commands []string) {
-	for command := range c.comm {
-		if strings.HasPrefix(command, prefix) {
+	for command, cmd := range c.comm {
+		if !cmd.hidden && strings.HasPrefix(command, prefix) {
 			commands = append(commands, command)
 		}
 	}
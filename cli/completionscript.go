@@ -0,0 +1,298 @@
+package cli
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// CompletionShells are the shells supported by GenCompletionScript
+var CompletionShells = []string{"bash", "zsh", "fish"}
+
+// enumStringer is implemented by enum types
+// that can list the string values of all enum values.
+type enumStringer interface {
+	EnumStrings() []string
+}
+
+// completionNode is a command word of the completion tree
+// with optional sub commands and a command function.
+type completionNode struct {
+	path     string // command words separated by space
+	children []*completionNode
+	cmd      *stringArgsCommand // nil if node is not callable
+}
+
+// completionArg describes a command function argument
+// for completion as positional argument and as flag.
+type completionArg struct {
+	flag   string
+	isBool bool
+	values []string
+}
+
+// completionArgs returns the arguments of cmd that can be completed.
+func completionArgs(cmd *stringArgsCommand) []completionArg {
+	if cmd == nil {
+		return nil
+	}
+	f := cmd.commandFunc
+	argNames := positionalArgNames(f)
+	offset := f.NumArgs() - len(argNames)
+	args := make([]completionArg, len(argNames))
+	for i, argName := range argNames {
+		argType := f.ArgTypes()[offset+i]
+		args[i] = completionArg{
+			flag:   "--" + kebabCase(argName),
+			isBool: isBoolArg(f, argName),
+			values: cmd.allowedArgValues(argName, argType),
+		}
+	}
+	return args
+}
+
+// argTypeValues returns the possible string values
+// of an argument type if they can be enumerated.
+func argTypeValues(t reflect.Type) []string {
+	t = derefType(t)
+	if t.Kind() == reflect.Bool {
+		return []string{"true", "false"}
+	}
+	if e, ok := reflect.New(t).Elem().Interface().(enumStringer); ok {
+		return e.EnumStrings()
+	}
+	if e, ok := reflect.New(t).Interface().(enumStringer); ok {
+		return e.EnumStrings()
+	}
+	return nil
+}
+
+func (disp *StringArgsDispatcher) completionTree() *completionNode {
+	root := &completionNode{path: disp.superCommand, cmd: disp.comm[DefaultCommand]}
+	for _, command := range disp.visibleCommands() {
+		if command == DefaultCommand {
+			continue
+		}
+		root.children = append(root.children, &completionNode{
+			path: disp.comm[command].fullCommand,
+			cmd:  disp.comm[command],
+		})
+	}
+	return root
+}
+
+func (disp *SuperStringArgsDispatcher) completionTree() *completionNode {
+	root := &completionNode{}
+	for _, superCommand := range disp.Commands() {
+		sub := disp.sub[superCommand]
+		if superCommand == DefaultCommand {
+			root.cmd = sub.comm[DefaultCommand]
+			continue
+		}
+		root.children = append(root.children, sub.completionTree())
+	}
+	return root
+}
+
+// GenCompletionScript returns a completion script for the passed shell
+// that completes the commands, flags and enumerable argument values
+// of the dispatcher for the program name of os.Args[0].
+// Supported shells are listed in CompletionShells.
+func (disp *StringArgsDispatcher) GenCompletionScript(shell string) (string, error) {
+	return genCompletionScript(shell, appName(), disp.completionTree())
+}
+
+// GenCompletionScript returns a completion script for the passed shell
+// that completes the super commands, sub commands, flags and
+// enumerable argument values of the dispatcher
+// for the program name of os.Args[0].
+// Supported shells are listed in CompletionShells.
+func (disp *SuperStringArgsDispatcher) GenCompletionScript(shell string) (string, error) {
+	return genCompletionScript(shell, appName(), disp.completionTree())
+}
+
+func genCompletionScript(shell, appName string, root *completionNode) (string, error) {
+	var b strings.Builder
+	switch shell {
+	case "bash":
+		writeBashCompletion(&b, appName, root)
+	case "zsh":
+		fmt.Fprintf(&b, "#compdef %s\n\n", appName)
+		b.WriteString("autoload -U +X bashcompinit && bashcompinit\n\n")
+		writeBashCompletion(&b, appName, root)
+	case "fish":
+		writeFishCompletion(&b, appName, root)
+	default:
+		return "", fmt.Errorf("unsupported shell %q, supported are: %s", shell, strings.Join(CompletionShells, ", "))
+	}
+	return b.String(), nil
+}
+
+// walk calls visit for the node and all its descendants
+func (n *completionNode) walk(visit func(*completionNode)) {
+	visit(n)
+	for _, child := range n.children {
+		child.walk(visit)
+	}
+}
+
+func (n *completionNode) childNames() []string {
+	names := make([]string, len(n.children))
+	for i, child := range n.children {
+		names[i] = child.path[strings.LastIndexByte(child.path, ' ')+1:]
+	}
+	return names
+}
+
+// shellQuote returns s in single quotes for POSIX shells
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func shellFuncName(appName string) string {
+	return "_" + strings.Map(
+		func(r rune) rune {
+			if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+				return r
+			}
+			return '_'
+		},
+		appName,
+	) + "_complete"
+}
+
+func writeBashCompletion(b *strings.Builder, appName string, root *completionNode) {
+	var commandPaths []string
+	root.walk(func(n *completionNode) {
+		if n != root {
+			commandPaths = append(commandPaths, shellQuote(n.path))
+		}
+	})
+	funcName := shellFuncName(appName)
+
+	fmt.Fprintf(b, "# bash completion for %s\n", appName)
+	fmt.Fprintf(b, "%s() {\n", funcName)
+	b.WriteString("    local cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	b.WriteString("    local cmd=\"\" subcommands=\"\" flags=\"\" valueflags=\" \" positional=() i\n")
+	b.WriteString("    for ((i = 1; i < COMP_CWORD; i++)); do\n")
+	b.WriteString("        case \"${cmd:+$cmd }${COMP_WORDS[i]}\" in\n")
+	if len(commandPaths) > 0 {
+		fmt.Fprintf(b, "        %s) cmd=\"${cmd:+$cmd }${COMP_WORDS[i]}\" ;;\n", strings.Join(commandPaths, "|"))
+	}
+	b.WriteString("        *) break ;;\n")
+	b.WriteString("        esac\n")
+	b.WriteString("    done\n")
+	b.WriteString("    case \"$cmd\" in\n")
+	root.walk(func(n *completionNode) {
+		fmt.Fprintf(b, "    %s)\n", shellQuote(n.path))
+		if len(n.children) > 0 {
+			fmt.Fprintf(b, "        subcommands=%s\n", shellQuote(strings.Join(n.childNames(), " ")))
+		}
+		args := completionArgs(n.cmd)
+		if n.cmd != nil {
+			flags := []string{"--help"}
+			var valueFlags, positional []string
+			for _, arg := range args {
+				flags = append(flags, arg.flag)
+				if !arg.isBool {
+					valueFlags = append(valueFlags, arg.flag)
+				}
+				positional = append(positional, shellQuote(strings.Join(arg.values, " ")))
+			}
+			fmt.Fprintf(b, "        flags=%s\n", shellQuote(strings.Join(flags, " ")))
+			if len(valueFlags) > 0 {
+				fmt.Fprintf(b, "        valueflags=%s\n", shellQuote(" "+strings.Join(valueFlags, " ")+" "))
+			}
+			if len(positional) > 0 {
+				fmt.Fprintf(b, "        positional=(%s)\n", strings.Join(positional, " "))
+			}
+		}
+		hasFlagValues := slices.ContainsFunc(args, func(arg completionArg) bool { return !arg.isBool && len(arg.values) > 0 })
+		if hasFlagValues {
+			b.WriteString("        case \"$prev\" in\n")
+			for _, arg := range args {
+				if !arg.isBool && len(arg.values) > 0 {
+					fmt.Fprintf(b, "        %s) COMPREPLY=($(compgen -W %s -- \"$cur\")); return ;;\n", arg.flag, shellQuote(strings.Join(arg.values, " ")))
+				}
+			}
+			b.WriteString("        esac\n")
+		}
+		b.WriteString("        ;;\n")
+	})
+	b.WriteString("    esac\n")
+	b.WriteString("    if [[ \"$cur\" == -* ]]; then\n")
+	b.WriteString("        COMPREPLY=($(compgen -W \"$flags\" -- \"$cur\"))\n")
+	b.WriteString("        return\n")
+	b.WriteString("    fi\n")
+	b.WriteString("    local n=0\n")
+	b.WriteString("    for ((; i < COMP_CWORD; i++)); do\n")
+	b.WriteString("        case \"${COMP_WORDS[i]}\" in\n")
+	b.WriteString("        --) ;;\n")
+	b.WriteString("        -*) [[ \"$valueflags\" == *\" ${COMP_WORDS[i]} \"* ]] && ((i++)) ;;\n")
+	b.WriteString("        *) ((n++)) ;;\n")
+	b.WriteString("        esac\n")
+	b.WriteString("    done\n")
+	b.WriteString("    local words=\"${positional[n]}\"\n")
+	b.WriteString("    if ((n == 0)); then\n")
+	b.WriteString("        words=\"$subcommands $words\"\n")
+	b.WriteString("    fi\n")
+	b.WriteString("    COMPREPLY=($(compgen -W \"$words\" -- \"$cur\"))\n")
+	b.WriteString("}\n")
+	fmt.Fprintf(b, "complete -F %s %s\n", funcName, appName)
+}
+
+func writeFishCompletion(b *strings.Builder, appName string, root *completionNode) {
+	fmt.Fprintf(b, "# fish completion for %s\n", appName)
+	fmt.Fprintf(b, "complete -c %s -f\n", appName)
+	root.walk(func(n *completionNode) {
+		// Condition that the command words of n
+		// and none of its sub commands have been typed
+		var conditions []string
+		if n == root {
+			conditions = append(conditions, "__fish_use_subcommand")
+		} else {
+			for _, word := range strings.Fields(n.path) {
+				conditions = append(conditions, "__fish_seen_subcommand_from "+word)
+			}
+		}
+		if len(n.children) > 0 && n != root {
+			conditions = append(conditions, "not __fish_seen_subcommand_from "+strings.Join(n.childNames(), " "))
+		}
+		condition := shellQuote(strings.Join(conditions, "; and "))
+		childNames := n.childNames()
+		for i, child := range n.children {
+			name := childNames[i]
+			description := ""
+			if child.cmd != nil {
+				description = child.cmd.description
+			}
+			fmt.Fprintf(b, "complete -c %s -n %s -a %s", appName, condition, shellQuote(name))
+			if description != "" {
+				fmt.Fprintf(b, " -d %s", shellQuote(description))
+			}
+			b.WriteByte('\n')
+		}
+		if n.cmd == nil {
+			return
+		}
+		if n == root {
+			// Flags of the default command are only
+			// completed before any command was typed
+			condition = shellQuote("__fish_use_subcommand")
+		}
+		for _, arg := range completionArgs(n.cmd) {
+			fmt.Fprintf(b, "complete -c %s -n %s -l %s", appName, condition, arg.flag[2:])
+			if !arg.isBool {
+				b.WriteString(" -r")
+				if len(arg.values) > 0 {
+					fmt.Fprintf(b, " -a %s", shellQuote(strings.Join(arg.values, " ")))
+				}
+			}
+			b.WriteByte('\n')
+			if len(arg.values) > 0 {
+				fmt.Fprintf(b, "complete -c %s -n %s -a %s\n", appName, condition, shellQuote(strings.Join(arg.values, " ")))
+			}
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/domonda/go-function"
+)
+
+type testEnum string
+
+func (testEnum) EnumStrings() []string { return []string{"dev", "prod"} }
+
+func TestSuperStringArgsDispatcher_GenCompletionScript(t *testing.T) {
+	disp := NewSuperStringArgsDispatcher()
+	disp.MustAddSuperCommand("db").MustAddCommand("migrate", "Migrate the database", function.MustReflectWrapper(
+		func(env testEnum, dryRun bool) {},
+		"env", "dryRun",
+	))
+
+	for shell, wants := range map[string][]string{
+		"bash": {"'db'|'db migrate'", "subcommands='migrate'", "flags='--help --env --dry-run'", "--env) COMPREPLY=($(compgen -W 'dev prod'"},
+		"zsh":  {"#compdef", "bashcompinit"},
+		"fish": {"-a 'migrate' -d 'Migrate the database'", "-l env -r -a 'dev prod'", "-l dry-run\n"},
+	} {
+		t.Run(shell, func(t *testing.T) {
+			script, err := disp.GenCompletionScript(shell)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range wants {
+				if !strings.Contains(script, want) {
+					t.Errorf("%s script does not contain %q:\n%s", shell, want, script)
+				}
+			}
+		})
+	}
+
+	_, err := disp.GenCompletionScript("powershell")
+	if err == nil {
+		t.Error("expected error for unsupported shell")
+	}
+}
+
+func TestWithArgValues(t *testing.T) {
+	disp := NewStringArgsDispatcher()
+	disp.MustAddCommand("deploy", "", function.MustReflectWrapper(
+		func(env, version string) {},
+		"env", "version",
+	), WithArgValues("env", "dev", "staging", "prod"))
+
+	script, err := disp.GenCompletionScript("bash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "--env) COMPREPLY=($(compgen -W 'dev staging prod'"; !strings.Contains(script, want) {
+		t.Errorf("script does not contain %q:\n%s", want, script)
+	}
+
+	for args, wantErr := range map[string]bool{
+		"prod v1":                    false,
+		"--env=staging --version=v1": false,
+		"test v1":                    true,
+		"--env=test --version=v1":    true,
+	} {
+		err := disp.Dispatch(context.Background(), "deploy", strings.Fields(args)...)
+		if (err != nil) != wantErr {
+			t.Errorf("Dispatch(%q) error = %v, wantErr %v", args, err, wantErr)
+		}
+		var usageErr *UsageError
+		if wantErr && (!errors.As(err, &usageErr) || usageErr.ArgFormat != "one of dev, staging, prod") {
+			t.Errorf("Dispatch(%q) error = %#v, want UsageError for env", args, err)
+		}
+	}
+}
@@ -14,4 +14,8 @@ var (
 	// DescriptionColor is the color in which the
 	// command usage description will be printed on the screen.
 	DescriptionColor = color.New(color.FgCyan)
+
+	// DeprecatedColor is the color in which
+	// deprecation notices of commands will be printed.
+	DeprecatedColor = color.New(color.FgYellow)
 )
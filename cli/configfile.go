@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFileArgSource returns an argSource
+// reading argument values from the config file
+// at path which maps commands to their arguments:
+//
+//	deploy:
+//	  env: staging
+//	  replicas: 3
+//	"db migrate":
+//	  dryRun: true
+//
+// Commands of a SuperStringArgsDispatcher are keyed
+// with the super command and command separated by a space.
+// Files with the extension .json are parsed as JSON,
+// all other files as YAML.
+// A non existing config file is not an error.
+// The file is read only once, also by concurrently
+// dispatched commands like with DispatchEachLine.
+func configFileArgSource(path string) argSource {
+	load := sync.OnceValues(func() (map[string]map[string]string, error) {
+		return readConfigFile(path)
+	})
+	return func(_ context.Context, cmd *stringArgsCommand, argName string) (value string, ok bool, err error) {
+		commands, err := load()
+		if err != nil {
+			return "", false, err
+		}
+		value, ok = commands[cmd.fullCommand][argName]
+		return value, ok, nil
+	}
+}
+
+func readConfigFile(path string) (commands map[string]map[string]string, err error) {
+	data, err := os.ReadFile(path) //#nosec G304
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var values map[string]map[string]any
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &values)
+	} else {
+		err = yaml.Unmarshal(data, &values)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't parse config file %s: %w", path, err)
+	}
+	commands = make(map[string]map[string]string, len(values))
+	for command, args := range values {
+		commands[command] = make(map[string]string, len(args))
+		for argName, value := range args {
+			str, err := configValueString(value)
+			if err != nil {
+				return nil, fmt.Errorf("config file %s, command '%s', argument %s: %w", path, command, argName, err)
+			}
+			commands[command][argName] = str
+		}
+	}
+	return commands, nil
+}
+
+// configValueString returns strings as is
+// and all other values formatted as JSON
+// which can be scanned by function.ScanString.
+func configValueString(value any) (string, error) {
+	switch x := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return x, nil
+	}
+	j, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(j), nil
+}
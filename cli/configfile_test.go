@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/domonda/go-function"
+)
+
+func TestStringArgsDispatcher_WithConfigFile(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(configFile, []byte("cmd:\n  name: FromConfig\n  count: 3\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotName string
+	var gotCount int
+	disp := NewStringArgsDispatcher().WithConfigFile(configFile)
+	disp.MustAddCommand("cmd", "", function.MustReflectWrapper(
+		func(name string, count int) { gotName, gotCount = name, count },
+		"name", "count",
+	))
+	err = disp.Dispatch(context.Background(), "cmd", "--name=Explicit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotName != "Explicit" || gotCount != 3 {
+		t.Errorf("got name=%q count=%d", gotName, gotCount)
+	}
+}
+
+func TestStringArgsDispatcher_WithConfigFile_concurrent(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(configFile, []byte(`{"add": {"b": 10}}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sum atomic.Int64
+	disp := NewStringArgsDispatcher().WithConfigFile(configFile)
+	disp.MustAddCommand("add", "", function.MustReflectWrapper(
+		func(a, b int) { sum.Add(int64(a + b)) },
+		"a", "b",
+	))
+	// Wait until all workers dispatch a command
+	// so that they look up the config file concurrently
+	var (
+		arrived atomic.Int64
+		ready   = make(chan struct{})
+	)
+	disp.Use(func(ctx context.Context, command string, args []string) (context.Context, error) {
+		if arrived.Add(1) == 4 {
+			close(ready)
+		}
+		<-ready
+		return ctx, nil
+	})
+	input := strings.Repeat("1\n", 20)
+	err = disp.DispatchEachLine(context.Background(), "add", strings.NewReader(input), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sum.Load(); got != 20*11 {
+		t.Errorf("sum = %d, want %d", got, 20*11)
+	}
+
+	// The file was read only once
+	err = os.Remove(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = disp.Dispatch(context.Background(), "add", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sum.Load(); got != 21*11 {
+		t.Errorf("sum = %d, want %d", got, 21*11)
+	}
+}
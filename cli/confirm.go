@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// YesFlag is the built-in global flag that answers
+// all confirmation prompts of commands with yes.
+// It is handled by all dispatchers unless a global flag
+// with the same name was added.
+const YesFlag = "yes"
+
+// ErrNotConfirmed is returned when a command
+// with a confirmation prompt was not confirmed.
+var ErrNotConfirmed = errors.New("command not confirmed")
+
+// Confirm returns a CommandOption that asks the user for
+// confirmation with y/N before the command is called.
+// The message is used as format string for fmt.Sprintf
+// with the command argument values as arguments,
+// so "Delete user %s?" will print the first argument.
+// Pass the global flag --yes to skip the confirmation
+// for example in scripts.
+// If the confirmation is denied, then the command
+// returns ErrNotConfirmed.
+func Confirm(message string) CommandOption {
+	return commandOptionFunc(func(cmd *stringArgsCommand) {
+		cmd.confirm = message
+	})
+}
+
+// confirm asks the user for confirmation of cmd
+// with the argument values in order of the function arguments.
+func (cfg *dispatcherConfig) confirm(ctx context.Context, cmd *stringArgsCommand, argValues []string) error {
+	if cmd.confirm == "" || confirmedByFlag(ctx) {
+		return nil
+	}
+	confirmed, err := cfg.confirmer.confirm(formatConfirm(cmd.confirm, argValues))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return ErrNotConfirmed
+	}
+	return nil
+}
+
+// confirm asks the user to answer message with y/N.
+func (p *prompter) confirm(message string) (bool, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	_, err := fmt.Fprintf(p.out, "%s [y/N]: ", message)
+	if err != nil {
+		return false, err
+	}
+	line, err := p.in.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("can't read confirmation: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// formatConfirm formats message with as many
+// argValues as the message has formatting verbs.
+func formatConfirm(message string, argValues []string) string {
+	numVerbs := 0
+	for i := 0; i < len(message); i++ {
+		if message[i] != '%' {
+			continue
+		}
+		if i+1 < len(message) && message[i+1] == '%' {
+			i++
+			continue
+		}
+		numVerbs++
+	}
+	args := make([]any, numVerbs)
+	for i := range args {
+		if i < len(argValues) {
+			args[i] = argValues[i]
+		} else {
+			args[i] = ""
+		}
+	}
+	return fmt.Sprintf(message, args...)
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/domonda/go-function"
+)
+
+func TestConfirm(t *testing.T) {
+	var deleted []string
+	disp := NewStringArgsDispatcher()
+	disp.MustAddCommand("delete", "", function.MustReflectWrapper(
+		func(user string) { deleted = append(deleted, user) },
+		"user",
+	), Confirm("Delete user %s?"))
+	if len(disp.comm["delete"].resultsHandlers) != 0 {
+		t.Error("Confirm option must not be used as results handler")
+	}
+
+	var out strings.Builder
+	disp.cfg.confirmer = newPrompter(strings.NewReader("y\nn\n"), &out, nil)
+	for _, user := range []string{"alice", "bob"} {
+		err := disp.Dispatch(context.Background(), "delete", user)
+		if user == "bob" && !errors.Is(err, ErrNotConfirmed) {
+			t.Errorf("expected ErrNotConfirmed, got %v", err)
+		}
+	}
+	err := disp.Dispatch(context.Background(), "delete", "--yes", "carol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"alice", "carol"}; !slices.Equal(deleted, want) {
+		t.Errorf("deleted %v, want %v", deleted, want)
+	}
+	if want := "Delete user alice? [y/N]: Delete user bob? [y/N]: "; out.String() != want {
+		t.Errorf("prompted %q, want %q", out.String(), want)
+	}
+}
+
+func TestConfirm_concurrent(t *testing.T) {
+	var deleted atomic.Int64
+	disp := NewStringArgsDispatcher()
+	disp.MustAddCommand("delete", "", function.MustReflectWrapper(
+		func(user string) { deleted.Add(1) },
+		"user",
+	), Confirm("Delete user %s?"))
+	// Wait until all workers dispatch a command
+	// so that they ask for confirmation concurrently
+	var (
+		arrived atomic.Int64
+		ready   = make(chan struct{})
+	)
+	disp.Use(func(ctx context.Context, command string, args []string) (context.Context, error) {
+		if arrived.Add(1) == 3 {
+			close(ready)
+		}
+		<-ready
+		return ctx, nil
+	})
+
+	var out strings.Builder
+	disp.cfg.confirmer = newPrompter(strings.NewReader("y\ny\ny\n"), &out, nil)
+	err := disp.DispatchEachLine(context.Background(), "delete", strings.NewReader("alice\nbob\ncarol\n"), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted.Load() != 3 {
+		t.Errorf("deleted %d users, want 3", deleted.Load())
+	}
+	// Prompts are not interleaved
+	if got := strings.Count(out.String(), "? [y/N]: "); got != 3 {
+		t.Errorf("prompted %q", out.String())
+	}
+}
+
+func TestStringArgsDispatcher_EnablePrompting_confirmer(t *testing.T) {
+	disp := NewStringArgsDispatcher()
+	if disp.cfg.confirmer == nil {
+		t.Fatal("no confirmer configured")
+	}
+	disp.EnablePrompting()
+	if disp.cfg.confirmer != disp.cfg.prompter {
+		t.Error("confirmations must read from the prompter input")
+	}
+}
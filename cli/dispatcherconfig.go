@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// dispatcherConfig holds the configuration of a StringArgsDispatcher.
+// A SuperStringArgsDispatcher shares its config
+// with all its sub command dispatchers.
+type dispatcherConfig struct {
+	loggers       []StringArgsCommandLogger
+	argEnvVars    map[string]string
+	envVarPrefix  string
+	configFile    argSource
+	prompter      *prompter
+	sensitiveArgs map[string]bool
+	globalFlags   []*globalFlag
+	before        []BeforeFunc
+	after         []AfterFunc
+	noColor       bool
+	outputFlag    bool
+	stdinArg      *stdinArg
+	fileArgs      bool
+	confirmer     *prompter // prompter if prompting is enabled
+	versionInfo   *VersionInfo
+	timeoutFlag   bool
+	progress      bool
+	pager         bool
+	pluginPrefix  string // plugins disabled if empty
+
+	dryRunFlag         bool
+	dryRunValidateOnly bool // don't call command functions for dry runs
+}
+
+func newDispatcherConfig(loggers []StringArgsCommandLogger) *dispatcherConfig {
+	return &dispatcherConfig{
+		loggers:   loggers,
+		confirmer: newPrompter(os.Stdin, os.Stderr, nil),
+	}
+}
+
+// argSource returns a value for an argument
+// that was not passed on the command line.
+type argSource func(ctx context.Context, cmd *stringArgsCommand, argName string) (value string, ok bool, err error)
+
+// argSources returns the configured argument sources
+// in the order they are looked up.
+func (cfg *dispatcherConfig) argSources() []argSource {
+	sources := []argSource{
+		cfg.globalFlagArgSource,
+		cfg.envVarArgSource,
+	}
+	if cfg.configFile != nil {
+		sources = append(sources, cfg.configFile)
+	}
+	sources = append(sources, argDefaultSource)
+	if cfg.prompter != nil {
+		sources = append(sources, cfg.prompter.argSource)
+	}
+	return sources
+}
+
+// applyArgSources adds values from the argument sources
+// for all arguments of cmd that are not in named.
+func (cfg *dispatcherConfig) applyArgSources(ctx context.Context, cmd *stringArgsCommand, named map[string]string) error {
+	sources := cfg.argSources()
+	for _, argName := range positionalArgNames(cmd.commandFunc) {
+		if _, ok := named[argName]; ok {
+			continue
+		}
+		for _, source := range sources {
+			value, ok, err := source(ctx, cmd, argName)
+			if err != nil {
+				return err
+			}
+			if ok {
+				named[argName] = value
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// argDefaultSource returns the default value
+// of the argument set with WithArgDefault.
+func argDefaultSource(_ context.Context, cmd *stringArgsCommand, argName string) (value string, ok bool, err error) {
+	value, ok = cmd.argDefaults[argName]
+	return value, ok, nil
+}
+
+func (cfg *dispatcherConfig) setArgEnvVar(argName, envVar string) {
+	if cfg.argEnvVars == nil {
+		cfg.argEnvVars = make(map[string]string)
+	}
+	cfg.argEnvVars[argName] = envVar
+}
+
+func (cfg *dispatcherConfig) enablePrompting(in io.Reader, out io.Writer) {
+	if cfg.sensitiveArgs == nil {
+		cfg.sensitiveArgs = make(map[string]bool)
+	}
+	cfg.prompter = newPrompter(in, out, cfg.sensitiveArgs)
+	// Share the buffered input for confirmations
+	cfg.confirmer = cfg.prompter
+}
+
+func (cfg *dispatcherConfig) setArgSensitive(argName string) {
+	if cfg.sensitiveArgs == nil {
+		cfg.sensitiveArgs = make(map[string]bool)
+		if cfg.prompter != nil {
+			cfg.prompter.sensitive = cfg.sensitiveArgs
+		}
+	}
+	cfg.sensitiveArgs[argName] = true
+}
+
+func (cfg *dispatcherConfig) envVarArgSource(_ context.Context, _ *stringArgsCommand, argName string) (value string, ok bool, err error) {
+	if envVar, ok := cfg.argEnvVars[argName]; ok {
+		value, ok = os.LookupEnv(envVar)
+		return value, ok, nil
+	}
+	if cfg.envVarPrefix != "" {
+		value, ok = os.LookupEnv(cfg.envVarPrefix + upperSnakeCase(argName))
+		return value, ok, nil
+	}
+	return "", false, nil
+}
+
+// upperSnakeCase converts a camelCase name to UPPER_SNAKE_CASE
+// keeping acronyms together, so "userID" becomes "USER_ID"
+// and "httpURLPath" becomes "HTTP_URL_PATH".
+func upperSnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || nextLower && unicode.IsUpper(runes[i-1]) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
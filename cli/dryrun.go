@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/domonda/go-function"
+)
+
+// DryRunFlag is the name of the global flag
+// added by EnableDryRunFlag.
+const DryRunFlag = "dry-run"
+
+func (cfg *dispatcherConfig) enableDryRunFlag(validateOnly bool) error {
+	err := cfg.addGlobalFlag(DryRunFlag, "Rehearse the command without making changes", false)
+	if err != nil {
+		return err
+	}
+	cfg.dryRunFlag = true
+	cfg.dryRunValidateOnly = validateOnly
+	return nil
+}
+
+// withDryRun returns ctx marked with function.ContextWithDryRun
+// if the dry run flag is enabled and was passed.
+func (cfg *dispatcherConfig) withDryRun(ctx context.Context) context.Context {
+	if !cfg.dryRunFlag || !GlobalFlagBool(ctx, DryRunFlag) {
+		return ctx
+	}
+	return function.ContextWithDryRun(ctx)
+}
+
+// dryRunFuncs returns functions that only validate the arguments
+// of the command function without calling it.
+func (cmd *stringArgsCommand) dryRunFuncs() (function.StringArgsFunc, function.NamedStringArgsFunc) {
+	stringArgsFunc := func(ctx context.Context, args ...string) error {
+		return function.ValidateStrings(cmd.commandFunc, args...)
+	}
+	namedStringArgsFunc := func(ctx context.Context, args map[string]string) error {
+		return function.ValidateNamedStrings(cmd.commandFunc, args)
+	}
+	return stringArgsFunc, namedStringArgsFunc
+}
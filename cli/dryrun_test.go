@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/domonda/go-function"
+)
+
+func TestStringArgsDispatcher_EnableDryRunFlag(t *testing.T) {
+	var calls []bool
+	newDispatcher := func(validateOnly bool) *StringArgsDispatcher {
+		disp := NewStringArgsDispatcher()
+		disp.MustAddCommand("cmd", "", function.MustReflectWrapper(
+			func(ctx context.Context, n int) { calls = append(calls, function.IsDryRun(ctx)) },
+			"ctx", "n",
+		))
+		if err := disp.EnableDryRunFlag(validateOnly); err != nil {
+			t.Fatal(err)
+		}
+		return disp
+	}
+
+	disp := newDispatcher(false)
+	disp.MustDispatch(context.Background(), "cmd", "1")
+	disp.MustDispatch(context.Background(), "cmd", "--dry-run", "1")
+	if want := []bool{false, true}; !slices.Equal(calls, want) {
+		t.Errorf("dry run calls %v, want %v", calls, want)
+	}
+
+	calls = nil
+	disp = newDispatcher(true)
+	disp.MustDispatch(context.Background(), "cmd", "--dry-run", "--n=1")
+	if len(calls) > 0 {
+		t.Error("command function called for validate only dry run")
+	}
+	if err := disp.Dispatch(context.Background(), "cmd", "--dry-run", "x"); err == nil {
+		t.Error("expected validation error")
+	}
+}
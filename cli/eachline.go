@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DispatchEachLine dispatches commandAndArgs for every non empty line
+// read from input with the whitespace separated words of the line
+// appended as additional arguments, like xargs.
+// Up to concurrency commands are executed in parallel.
+// All command errors are returned joined in the order of their lines
+// prefixed with the line number.
+// No further lines are dispatched after ctx was canceled.
+func DispatchEachLine(ctx context.Context, dispatcher CommandDispatcher, commandAndArgs []string, input io.Reader, concurrency int) error {
+	return dispatchEachLine(ctx, input, concurrency, func(ctx context.Context, lineArgs []string) error {
+		return dispatcher.dispatchCommandLine(ctx, append(slices.Clip(commandAndArgs), lineArgs...))
+	})
+}
+
+// DispatchEachLine dispatches command for every non empty line
+// read from input with the whitespace separated words of the line
+// as arguments using up to concurrency parallel workers.
+// See the package function DispatchEachLine
+func (disp *StringArgsDispatcher) DispatchEachLine(ctx context.Context, command string, input io.Reader, concurrency int) error {
+	return dispatchEachLine(ctx, input, concurrency, func(ctx context.Context, lineArgs []string) error {
+		return disp.Dispatch(ctx, command, lineArgs...)
+	})
+}
+
+type lineError struct {
+	line int
+	err  error
+}
+
+func dispatchEachLine(ctx context.Context, input io.Reader, concurrency int, dispatch func(context.Context, []string) error) error {
+	type job struct {
+		line int
+		args []string
+	}
+	var (
+		jobs   = make(chan job)
+		wg     sync.WaitGroup
+		mtx    sync.Mutex
+		errs   []lineError
+		worker = func() {
+			defer wg.Done()
+			for j := range jobs {
+				err := dispatch(ctx, j.args)
+				if err != nil {
+					mtx.Lock()
+					errs = append(errs, lineError{j.line, err})
+					mtx.Unlock()
+				}
+			}
+		}
+	)
+	for range max(concurrency, 1) {
+		wg.Add(1)
+		go worker()
+	}
+
+	scanErr := func() error {
+		defer close(jobs)
+		scanner := bufio.NewScanner(input)
+		for line := 1; scanner.Scan(); line++ {
+			args := strings.Fields(scanner.Text())
+			if len(args) == 0 {
+				continue
+			}
+			select {
+			case jobs <- job{line, args}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return scanner.Err()
+	}()
+	wg.Wait()
+
+	slices.SortFunc(errs, func(a, b lineError) int { return a.line - b.line })
+	joined := make([]error, 0, len(errs)+1)
+	for _, e := range errs {
+		joined = append(joined, fmt.Errorf("line %d: %w", e.line, e.err))
+	}
+	if scanErr != nil {
+		joined = append(joined, scanErr)
+	}
+	return errors.Join(joined...)
+}
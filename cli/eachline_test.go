@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/domonda/go-function"
+)
+
+func TestStringArgsDispatcher_DispatchEachLine(t *testing.T) {
+	var (
+		mtx    sync.Mutex
+		greets []string
+	)
+	disp := NewStringArgsDispatcher()
+	disp.MustAddCommand("greet", "", function.MustReflectWrapper(
+		func(greeting, name string) error {
+			if name == "fail" {
+				return errors.New("failed")
+			}
+			mtx.Lock()
+			defer mtx.Unlock()
+			greets = append(greets, greeting+" "+name)
+			return nil
+		},
+		"greeting", "name",
+	))
+
+	input := "Hello Alice\n\nHi   Bob\nHello fail\nHey --unknown=x\n"
+	err := disp.DispatchEachLine(context.Background(), "greet", strings.NewReader(input), 3)
+	if err == nil {
+		t.Fatal("expected errors")
+	}
+	lines := strings.Split(err.Error(), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "line 4: ") || !strings.HasPrefix(lines[1], "line 5: ") {
+		t.Errorf("unexpected error: %s", err)
+	}
+	slices.Sort(greets)
+	if want := []string{"Hello Alice", "Hi Bob"}; !slices.Equal(greets, want) {
+		t.Errorf("greets %v, want %v", greets, want)
+	}
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 type ErrCommandNotFound string
@@ -26,3 +27,46 @@ func IsErrCommandNotFound(err error) bool {
 	)
 	return errors.As(err, &errCommandNotFound) || errors.As(err, &errSuperCommandNotFound)
 }
+
+// SuggestionsError wraps an ErrCommandNotFound or ErrSuperCommandNotFound
+// error with suggestions of similar existing commands.
+type SuggestionsError struct {
+	Err         error
+	Suggestions []string
+}
+
+func (e *SuggestionsError) Error() string {
+	quoted := make([]string, len(e.Suggestions))
+	for i, s := range e.Suggestions {
+		quoted[i] = "'" + s + "'"
+	}
+	if len(quoted) == 1 {
+		return fmt.Sprintf("%s, did you mean %s?", e.Err, quoted[0])
+	}
+	return fmt.Sprintf("%s, did you mean one of %s?", e.Err, strings.Join(quoted, ", "))
+}
+
+func (e *SuggestionsError) Unwrap() error {
+	return e.Err
+}
+
+// Suggestions returns the command suggestions
+// of a wrapped SuggestionsError or nil.
+func Suggestions(err error) []string {
+	var suggestionsErr *SuggestionsError
+	if errors.As(err, &suggestionsErr) {
+		return suggestionsErr.Suggestions
+	}
+	return nil
+}
+
+// withSuggestions wraps err with suggestions for command
+// from commands as SuggestionsError
+// or returns err unchanged if there are no similar commands.
+func withSuggestions(err error, command string, commands []string) error {
+	suggestions := suggestCommands(command, commands)
+	if len(suggestions) == 0 {
+		return err
+	}
+	return &SuggestionsError{Err: err, Suggestions: suggestions}
+}
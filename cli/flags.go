@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/domonda/go-function"
+)
+
+// EndOfFlags is the argument that stops flag parsing
+// so that all following arguments are used as positional
+// arguments even if they begin with "--".
+const EndOfFlags = "--"
+
+// isFlag returns true if arg has the form --name or --name=value
+func isFlag(arg string) bool {
+	return len(arg) > 2 && strings.HasPrefix(arg, "--")
+}
+
+// hasFlags returns true if any of the args is a flag
+// or the EndOfFlags marker.
+func hasFlags(args []string) bool {
+	for _, arg := range args {
+		if arg == EndOfFlags || isFlag(arg) {
+			return true
+		}
+	}
+	return false
+}
+
+// flagArgName returns the name of the function argument
+// matching the passed flag name.
+// A flag matches an argument if it has the identical name
+// or when the argument name in camelCase is written
+// as kebab-case flag name like --dry-run for dryRun.
+func flagArgName(f function.Description, flag string) (argName string, ok bool) {
+	normalized := strings.ReplaceAll(flag, "-", "")
+	for i, argName := range f.ArgNames() {
+		if i == 0 && f.ContextArg() {
+			continue
+		}
+		if argName == flag || strings.EqualFold(argName, normalized) {
+			return argName, true
+		}
+	}
+	return "", false
+}
+
+// isBoolArg returns true if the argument with argName
+// is of type bool or a pointer to bool.
+func isBoolArg(f function.Description, argName string) bool {
+	for i, name := range f.ArgNames() {
+		if name == argName {
+			return derefType(f.ArgTypes()[i]).Kind() == reflect.Bool
+		}
+	}
+	return false
+}
+
+// positionalArgNames returns the argument names of f
+// that can be passed as positional string arguments,
+// which means all names except the one of a context argument.
+func positionalArgNames(f function.Description) []string {
+	argNames := f.ArgNames()
+	if f.ContextArg() && len(argNames) > 0 {
+		return argNames[1:]
+	}
+	return argNames
+}
+
+// positionalArgsMap returns a map from the positional
+// argument names of f to the passed args.
+func positionalArgsMap(f function.Description, args []string) map[string]string {
+	m := make(map[string]string, len(args))
+	for i, argName := range positionalArgNames(f) {
+		if i < len(args) {
+			m[argName] = args[i]
+		}
+	}
+	return m
+}
+
+// parseFlags parses a mix of positional args and flags
+// of the form --name=value or --name value into a map
+// of function argument names to values.
+// Positional args fill the function arguments in order,
+// flags fill the arguments with the name of the flag.
+// Flags for bool arguments don't need a value,
+// --name is interpreted as --name=true.
+// All args after EndOfFlags are used as positional args.
+func parseFlags(f function.Description, args []string) (named map[string]string, err error) {
+	var (
+		positional []string
+		endOfFlags bool
+	)
+	named = make(map[string]string, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if endOfFlags || !isFlag(arg) {
+			if arg == EndOfFlags && !endOfFlags {
+				endOfFlags = true
+				continue
+			}
+			positional = append(positional, arg)
+			continue
+		}
+		flag, value, hasValue := strings.Cut(arg[2:], "=")
+		argName, ok := flagArgName(f, flag)
+		if !ok {
+			return nil, fmt.Errorf("unknown flag --%s", flag)
+		}
+		if !hasValue {
+			switch {
+			case isBoolArg(f, argName):
+				value = "true"
+			case i+1 >= len(args):
+				return nil, fmt.Errorf("missing value for flag --%s", flag)
+			default:
+				i++
+				value = args[i]
+			}
+		}
+		if _, exists := named[argName]; exists {
+			return nil, fmt.Errorf("flag --%s passed more than once", flag)
+		}
+		named[argName] = value
+	}
+
+	argNames := positionalArgNames(f)
+	if len(positional) > len(argNames) {
+		return nil, fmt.Errorf("%d positional arguments passed, but only %d expected", len(positional), len(argNames))
+	}
+	for i, value := range positional {
+		argName := argNames[i]
+		if _, exists := named[argName]; exists {
+			return nil, fmt.Errorf("argument %s passed as positional argument and as flag", argName)
+		}
+		named[argName] = value
+	}
+	return named, nil
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/domonda/go-function"
+)
+
+func Test_parseFlags(t *testing.T) {
+	f := function.MustReflectWrapper(
+		func(ctx context.Context, env string, dryRun bool, version int) {},
+		"ctx", "env", "dryRun", "version",
+	)
+	tests := []struct {
+		name    string
+		args    []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "empty", args: nil, want: map[string]string{}},
+		{name: "name=value", args: []string{"--env=prod"}, want: map[string]string{"env": "prod"}},
+		{name: "name value", args: []string{"--env", "prod"}, want: map[string]string{"env": "prod"}},
+		{name: "kebab-case", args: []string{"--dry-run=true", "--version", "42"}, want: map[string]string{"dryRun": "true", "version": "42"}},
+		{name: "exact camelCase", args: []string{"--dryRun=false"}, want: map[string]string{"dryRun": "false"}},
+		{name: "bool without value", args: []string{"--dry-run"}, want: map[string]string{"dryRun": "true"}},
+		{name: "positional and flags", args: []string{"prod", "--version=42", "--dry-run"}, want: map[string]string{"env": "prod", "dryRun": "true", "version": "42"}},
+		{name: "positional after flag", args: []string{"--version=42", "prod"}, want: map[string]string{"env": "prod", "version": "42"}},
+		{name: "end of flags", args: []string{"--version=42", "--", "--prod"}, want: map[string]string{"env": "--prod", "version": "42"}},
+		{name: "all positional", args: []string{"prod", "true", "42"}, want: map[string]string{"env": "prod", "dryRun": "true", "version": "42"}},
+
+		// Invalid:
+		{name: "unknown flag", args: []string{"--unknown=1"}, wantErr: true},
+		{name: "missing value", args: []string{"--env"}, wantErr: true},
+		{name: "duplicate", args: []string{"--env=a", "--env=b"}, wantErr: true},
+		{name: "too many positional", args: []string{"prod", "true", "42", "x"}, wantErr: true},
+		{name: "positional and flag for same arg", args: []string{"prod", "--env=dev"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFlags(f, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseFlags() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseFlags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStringArgsDispatcher_Dispatch_flags(t *testing.T) {
+	var gotA, gotB string
+	disp := NewStringArgsDispatcher()
+	disp.MustAddCommand("cmd", "", function.MustReflectWrapper(
+		func(a, b string) { gotA, gotB = a, b },
+		"a", "b",
+	))
+	err := disp.Dispatch(context.Background(), "cmd", "--b=B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotA != "" || gotB != "B" {
+		t.Errorf("got a=%q b=%q, want a=\"\" b=\"B\"", gotA, gotB)
+	}
+}
+
+func Test_upperSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"apiKey":      "API_KEY",
+		"userID":      "USER_ID",
+		"httpURLPath": "HTTP_URL_PATH",
+		"HTTPPort":    "HTTP_PORT",
+		"name":        "NAME",
+		"v2Name":      "V2_NAME",
+	}
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := upperSnakeCase(name); got != want {
+				t.Errorf("upperSnakeCase() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestStringArgsDispatcher_SetEnvVarPrefix(t *testing.T) {
+	t.Setenv("MYAPP_API_KEY", "secret")
+	var gotName, gotAPIKey string
+	disp := NewStringArgsDispatcher()
+	disp.SetEnvVarPrefix("MYAPP_")
+	disp.MustAddCommand("cmd", "", function.MustReflectWrapper(
+		func(name, apiKey string) { gotName, gotAPIKey = name, apiKey },
+		"name", "apiKey",
+	))
+	err := disp.Dispatch(context.Background(), "cmd", "Bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotName != "Bob" || gotAPIKey != "secret" {
+		t.Errorf("got name=%q apiKey=%q", gotName, gotAPIKey)
+	}
+}
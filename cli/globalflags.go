@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// globalFlag is a flag that can be passed
+// before or after any command.
+type globalFlag struct {
+	name         string
+	description  string
+	defaultValue string
+	isBool       bool
+}
+
+// matchesArg returns true if the flag name
+// is identical to argName or its kebab-case form.
+func (flag *globalFlag) matchesArg(argName string) bool {
+	return flag.name == argName || strings.EqualFold(strings.ReplaceAll(flag.name, "-", ""), argName)
+}
+
+type globalFlagsCtxKey struct{}
+
+// globalFlagValues are the values of the global flags
+// stored in the context of a dispatched command.
+type globalFlagValues struct {
+	values  map[string]string // including default values
+	passed  map[string]bool
+	yes     bool // built-in YesFlag
+	noColor bool // built-in NoColorFlag
+}
+
+// confirmedByFlag returns true if the built-in YesFlag was passed
+func confirmedByFlag(ctx context.Context) bool {
+	flags, _ := ctx.Value(globalFlagsCtxKey{}).(*globalFlagValues)
+	return flags != nil && flags.yes
+}
+
+// noColorByFlag returns true if the built-in NoColorFlag was passed
+func noColorByFlag(ctx context.Context) bool {
+	flags, _ := ctx.Value(globalFlagsCtxKey{}).(*globalFlagValues)
+	return flags != nil && flags.noColor
+}
+
+// GlobalFlag returns the value of the global flag with the passed name
+// from the context of a dispatched command.
+// If the flag was not passed on the command line,
+// then its default value is returned.
+// The result ok is false if there is no such global flag.
+func GlobalFlag(ctx context.Context, name string) (value string, ok bool) {
+	flags, _ := ctx.Value(globalFlagsCtxKey{}).(*globalFlagValues)
+	if flags == nil {
+		return "", false
+	}
+	value, ok = flags.values[name]
+	return value, ok
+}
+
+// GlobalFlagBool returns true if the global flag
+// with the passed name has the value "true".
+func GlobalFlagBool(ctx context.Context, name string) bool {
+	value, _ := GlobalFlag(ctx, name)
+	return value == "true"
+}
+
+func (cfg *dispatcherConfig) addGlobalFlag(name, description string, defaultValue any) error {
+	name = strings.TrimPrefix(name, "--")
+	if name == "" || strings.ContainsAny(name, "= \t") {
+		return fmt.Errorf("invalid global flag name: '%s'", name)
+	}
+	if cfg.globalFlag(name) != nil {
+		return fmt.Errorf("global flag --%s already added", name)
+	}
+	flag := &globalFlag{name: name, description: description}
+	switch v := defaultValue.(type) {
+	case nil:
+	case bool:
+		flag.isBool = true
+		if v {
+			flag.defaultValue = "true"
+		}
+	default:
+		flag.defaultValue = fmt.Sprint(v)
+	}
+	cfg.globalFlags = append(cfg.globalFlags, flag)
+	return nil
+}
+
+func (cfg *dispatcherConfig) globalFlag(name string) *globalFlag {
+	for _, flag := range cfg.globalFlags {
+		if flag.name == name {
+			return flag
+		}
+	}
+	return nil
+}
+
+// withGlobalFlags removes all global flags before EndOfFlags from args
+// and returns a context with their values
+// merged with global flags already in ctx.
+// The built-in NoColorFlag and YesFlag are also stored in the context.
+func (cfg *dispatcherConfig) withGlobalFlags(ctx context.Context, args []string) (context.Context, []string, error) {
+	flags := &globalFlagValues{
+		values: make(map[string]string, len(cfg.globalFlags)),
+		passed: make(map[string]bool),
+	}
+	if parent, _ := ctx.Value(globalFlagsCtxKey{}).(*globalFlagValues); parent != nil {
+		for name, value := range parent.values {
+			flags.values[name] = value
+		}
+		for name := range parent.passed {
+			flags.passed[name] = true
+		}
+		flags.yes = parent.yes
+		flags.noColor = parent.noColor
+	} else {
+		for _, flag := range cfg.globalFlags {
+			flags.values[flag.name] = flag.defaultValue
+		}
+	}
+
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == EndOfFlags {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		if !isFlag(arg) {
+			rest = append(rest, arg)
+			continue
+		}
+		name, value, hasValue := strings.Cut(arg[2:], "=")
+		flag := cfg.globalFlag(name)
+		if flag == nil && name == NoColorFlag {
+			noColor, err := strconv.ParseBool(cmp.Or(value, "true"))
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid value for flag --%s: %w", name, err)
+			}
+			flags.noColor = noColor
+			continue
+		}
+		if flag == nil && name == YesFlag {
+			yes, err := strconv.ParseBool(cmp.Or(value, "true"))
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid value for flag --%s: %w", name, err)
+			}
+			flags.yes = yes
+			continue
+		}
+		if flag == nil {
+			rest = append(rest, arg)
+			continue
+		}
+		if !hasValue {
+			switch {
+			case flag.isBool:
+				value = "true"
+			case i+1 >= len(args):
+				return nil, nil, fmt.Errorf("missing value for global flag --%s", name)
+			default:
+				i++
+				value = args[i]
+			}
+		}
+		flags.values[name] = value
+		flags.passed[name] = true
+	}
+	return context.WithValue(ctx, globalFlagsCtxKey{}, flags), rest, nil
+}
+
+// globalFlagArgSource binds the values of passed global flags
+// to command arguments with the same name.
+func (cfg *dispatcherConfig) globalFlagArgSource(ctx context.Context, _ *stringArgsCommand, argName string) (value string, ok bool, err error) {
+	flags, _ := ctx.Value(globalFlagsCtxKey{}).(*globalFlagValues)
+	if flags == nil {
+		return "", false, nil
+	}
+	for _, flag := range cfg.globalFlags {
+		if flags.passed[flag.name] && flag.matchesArg(argName) {
+			return flags.values[flag.name], true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// printGlobalFlags prints a table of the global flags to w
+func (cfg *dispatcherConfig) printGlobalFlags(w io.Writer) error {
+	if len(cfg.globalFlags) == 0 {
+		return nil
+	}
+	_, err := fmt.Fprint(w, "\nGlobal flags:\n")
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, flag := range cfg.globalFlags {
+		description := flag.description
+		if flag.defaultValue != "" {
+			description += fmt.Sprintf(" (default: %s)", flag.defaultValue)
+		}
+		fmt.Fprintf(tw, "  --%s\t%s\n", flag.name, strings.TrimSpace(description)) //#nosec G104 -- error returned by Flush
+	}
+	return tw.Flush()
+}
@@ -9,6 +9,8 @@ require github.com/domonda/go-function v0.0.0-00010101000000-000000000000 // rep
 require (
 	github.com/fatih/color v1.17.0
 	github.com/posener/complete/v2 v2.1.0
+	golang.org/x/term v0.26.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -17,5 +19,5 @@ require (
 	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/posener/script v1.2.0 // indirect
 	github.com/ungerik/go-httpx v0.0.0-20240110134719-544aadceddba // indirect
-	golang.org/x/sys v0.24.0 // indirect
+	golang.org/x/sys v0.27.0 // indirect
 )
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/domonda/go-function"
+)
+
+// HelpCommand is the command that prints the help
+// of the command passed as argument, like "myapp help deploy".
+// It is only handled if no command with that name was added.
+const HelpCommand = "help"
+
+// isHelpFlag returns true for the flags --help and -h
+func isHelpFlag(arg string) bool {
+	return arg == "--help" || arg == "-h"
+}
+
+// isHelpRequested returns true if args contain --help or -h
+// before EndOfFlags and f has no argument named help.
+func isHelpRequested(f function.Description, args []string) bool {
+	for _, arg := range args {
+		if arg == EndOfFlags {
+			return false
+		}
+		if isHelpFlag(arg) {
+			_, isArg := flagArgName(f, "help")
+			return !isArg
+		}
+	}
+	return false
+}
+
+// printDeprecationWarning prints the deprecation message of cmd to w
+func (cmd *stringArgsCommand) printDeprecationWarning(ctx context.Context, cfg *dispatcherConfig, w io.Writer) {
+	cfg.color(ctx, DeprecatedColor, w).Fprintf(w, "Warning: command '%s' is deprecated: %s\n", cmd.fullCommand, cmd.deprecated) //#nosec G104 -- best effort warning
+}
+
+// appName returns the base name of the running executable
+func appName() string {
+	return filepath.Base(os.Args[0])
+}
+
+// printHelp prints the usage, description,
+// and a table of the arguments of the command to w.
+func (cmd *stringArgsCommand) printHelp(ctx context.Context, cfg *dispatcherConfig, w io.Writer, appName string) error {
+	f := cmd.commandFunc
+	_, err := cfg.color(ctx, UsageColor, w).Fprintf(w, "Usage:\n  %s\n", cmd.usage(appName))
+	if err != nil {
+		return err
+	}
+	if cmd.description != "" {
+		_, err = cfg.color(ctx, DescriptionColor, w).Fprintf(w, "\n%s\n", cmd.description)
+		if err != nil {
+			return err
+		}
+	}
+	if cmd.deprecated != "" {
+		_, err = cfg.color(ctx, DeprecatedColor, w).Fprintf(w, "\nDeprecated: %s\n", cmd.deprecated)
+		if err != nil {
+			return err
+		}
+	}
+	if len(cmd.examples) > 0 {
+		_, err = fmt.Fprintf(w, "\nExamples:\n  %s\n", strings.Join(cmd.examples, "\n  "))
+		if err != nil {
+			return err
+		}
+	}
+	argNames := positionalArgNames(f)
+	if len(argNames) == 0 {
+		return nil
+	}
+	offset := f.NumArgs() - len(argNames)
+	argTypes := f.ArgTypes()[offset:]
+	argDescriptions := f.ArgDescriptions()
+
+	_, err = fmt.Fprint(w, "\nArguments:\n")
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if len(cmd.argDefaults) > 0 {
+		fmt.Fprint(tw, "  NAME\tFLAG\tTYPE\tDEFAULT\tDESCRIPTION\n") //#nosec G104 -- error returned by Flush
+	} else {
+		fmt.Fprint(tw, "  NAME\tFLAG\tTYPE\tDESCRIPTION\n") //#nosec G104 -- error returned by Flush
+	}
+	for i, argName := range argNames {
+		description := ""
+		if offset+i < len(argDescriptions) {
+			description = argDescriptions[offset+i]
+		}
+		if len(cmd.argDefaults) > 0 {
+			fmt.Fprintf(tw, "  %s\t--%s\t%s\t%s\t%s\n", argName, kebabCase(argName), derefType(argTypes[i]), cmd.argDefaults[argName], description) //#nosec G104 -- error returned by Flush
+		} else {
+			fmt.Fprintf(tw, "  %s\t--%s\t%s\t%s\n", argName, kebabCase(argName), derefType(argTypes[i]), description) //#nosec G104 -- error returned by Flush
+		}
+	}
+	err = tw.Flush()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(w, "\nArguments can be passed in order or as flags in the form --name=value\n")
+	return err
+}
+
+// usage returns the usage line of the command
+func (cmd *stringArgsCommand) usage(appName string) string {
+	usage := appName
+	if cmd.fullCommand != "" {
+		usage += " " + cmd.fullCommand
+	}
+	if args := positionalArgsString(cmd.commandFunc, cmd.argDefaults); args != "" {
+		usage += " " + args
+	}
+	return usage
+}
+
+// positionalArgsString returns the arguments of f
+// that can be passed on the command line as <name:type> list.
+// Arguments with a default value are formatted as [name:type=default].
+func positionalArgsString(f function.Description, argDefaults map[string]string) string {
+	var (
+		argNames = positionalArgNames(f)
+		offset   = f.NumArgs() - len(argNames)
+		argTypes = f.ArgTypes()
+		b        []byte
+	)
+	for i, argName := range argNames {
+		if i > 0 {
+			b = append(b, ' ')
+		}
+		if value, ok := argDefaults[argName]; ok {
+			b = fmt.Appendf(b, "[%s:%s=%s]", argName, derefType(argTypes[offset+i]), value)
+		} else {
+			b = fmt.Appendf(b, "<%s:%s>", argName, derefType(argTypes[offset+i]))
+		}
+	}
+	return string(b)
+}
+
+// kebabCase converts a camelCase name to kebab-case
+// as used for flag names, so "dryRun" becomes "dry-run".
+func kebabCase(name string) string {
+	return strings.ToLower(strings.ReplaceAll(upperSnakeCase(name), "_", "-"))
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/domonda/go-function"
+)
+
+func Test_stringArgsCommand_printHelp(t *testing.T) {
+	var called bool
+	disp := NewStringArgsDispatcher()
+	disp.MustAddCommand("deploy", "Deploys the app", function.MustReflectWrapper(
+		func(ctx context.Context, env string, dryRun bool) { called = true },
+		"ctx", "env", "dryRun",
+	))
+	cmd := disp.comm["deploy"]
+
+	if !isHelpRequested(cmd.commandFunc, []string{"prod", "--help"}) {
+		t.Error("--help not detected")
+	}
+	if isHelpRequested(cmd.commandFunc, []string{"--", "-h"}) {
+		t.Error("-h after -- must not be detected")
+	}
+
+	var b strings.Builder
+	err := cmd.printHelp(context.Background(), disp.cfg, &b, "myapp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"myapp deploy <env:string> <dryRun:bool>",
+		"Deploys the app",
+		"--dry-run",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("help output does not contain %q:\n%s", want, b.String())
+		}
+	}
+	if strings.Contains(b.String(), "ctx") {
+		t.Errorf("help output must not contain context argument:\n%s", b.String())
+	}
+
+	err = disp.Dispatch(context.Background(), "deploy", "--help")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if called {
+		t.Error("command must not be called for --help")
+	}
+}
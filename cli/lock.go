@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrLocked is returned when a command guarded by
+// WithLock or WithLockFile is already running.
+var ErrLocked = errors.New("command is locked")
+
+// Locker acquires an exclusive lock for a command.
+type Locker interface {
+	// TryLock acquires the lock without waiting
+	// and returns an error wrapping ErrLocked
+	// if the lock is already held.
+	// The returned unlock function releases the lock.
+	TryLock(ctx context.Context) (unlock func() error, err error)
+}
+
+// LockFile is a Locker that holds the lock as long as a file
+// at the path of the LockFile string exists.
+// The file contains the process ID of the lock holder.
+// If a process exits without unlocking, then the file
+// has to be removed manually.
+type LockFile string
+
+// TryLock implements Locker
+func (filePath LockFile) TryLock(ctx context.Context) (unlock func() error, err error) {
+	f, err := os.OpenFile(string(filePath), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600) //#nosec G304 -- path set by the program
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			holder, _ := os.ReadFile(string(filePath)) //#nosec G304 -- path set by the program
+			return nil, fmt.Errorf("%w by process %s (lock file %s)", ErrLocked, strings.TrimSpace(string(holder)), filePath)
+		}
+		return nil, err
+	}
+	_, err = f.WriteString(strconv.Itoa(os.Getpid()) + "\n")
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, errors.Join(err, os.Remove(string(filePath)))
+	}
+	return func() error { return os.Remove(string(filePath)) }, nil
+}
+
+// WithLock returns a CommandOption that guards the command with locker
+// so that concurrent invocations fail fast with an error wrapping ErrLocked
+// instead of running at the same time.
+func WithLock(locker Locker) CommandOption {
+	return commandOptionFunc(func(cmd *stringArgsCommand) {
+		cmd.locker = locker
+	})
+}
+
+// WithLockFile returns a CommandOption that guards the command
+// with the LockFile at filePath.
+// See WithLock
+func WithLockFile(filePath string) CommandOption {
+	return WithLock(LockFile(filePath))
+}
+
+// lock acquires the lock of cmd if it has a locker.
+// The returned unlock function must always be called.
+func (cmd *stringArgsCommand) lock(ctx context.Context) (unlock func() error, err error) {
+	if cmd.locker == nil {
+		return func() error { return nil }, nil
+	}
+	unlock, err = cmd.locker.TryLock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("command '%s': %w", cmd.fullCommand, err)
+	}
+	return unlock, nil
+}
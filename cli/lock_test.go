@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/domonda/go-function"
+)
+
+func TestWithLockFile(t *testing.T) {
+	lockFile := filepath.Join(t.TempDir(), "migrate.lock")
+	disp := NewStringArgsDispatcher()
+	var nestedErr error
+	disp.MustAddCommand("migrate", "", function.MustReflectWrapper(func(ctx context.Context) {
+		nestedErr = disp.Dispatch(ctx, "migrate")
+	}), WithLockFile(lockFile))
+
+	disp.MustDispatch(context.Background(), "migrate")
+	if !errors.Is(nestedErr, ErrLocked) {
+		t.Errorf("concurrent dispatch returned %v, want ErrLocked", nestedErr)
+	}
+	if _, err := os.Stat(lockFile); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("lock file not removed: %v", err)
+	}
+}
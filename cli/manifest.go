@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"encoding/json"
+)
+
+// Manifest is the machine-readable description
+// of the commands of a dispatcher returned as JSON
+// by the ManifestJSON methods of the dispatchers.
+type Manifest struct {
+	Name        string            `json:"name"`
+	Version     string            `json:"version,omitempty"`
+	GlobalFlags []ManifestFlag    `json:"globalFlags,omitempty"`
+	Commands    []ManifestCommand `json:"commands"`
+}
+
+// ManifestFlag describes a global flag in a Manifest.
+type ManifestFlag struct {
+	Flag        string `json:"flag"`
+	Description string `json:"description,omitempty"`
+	Default     string `json:"default,omitempty"`
+	IsBool      bool   `json:"isBool,omitempty"`
+}
+
+// ManifestCommand describes a command in a Manifest.
+type ManifestCommand struct {
+	// Command words separated by space,
+	// empty for the default command.
+	Command     string        `json:"command"`
+	Usage       string        `json:"usage"`
+	Description string        `json:"description,omitempty"`
+	Hidden      bool          `json:"hidden,omitempty"`
+	Deprecated  string        `json:"deprecated,omitempty"`
+	Examples    []string      `json:"examples,omitempty"`
+	Args        []ManifestArg `json:"args"`
+}
+
+// ManifestArg describes a command argument in a Manifest.
+type ManifestArg struct {
+	Name        string   `json:"name"`
+	Flag        string   `json:"flag"`
+	Type        string   `json:"type"`
+	Description string   `json:"description,omitempty"`
+	Default     string   `json:"default,omitempty"`
+	Values      []string `json:"values,omitempty"`
+}
+
+func newManifest(cfg *dispatcherConfig, source CommandSource) *Manifest {
+	appName := appName()
+	manifest := &Manifest{
+		Name:     appName,
+		Commands: []ManifestCommand{},
+	}
+	if cfg.versionInfo != nil {
+		manifest.Version = cfg.versionInfo.Version
+	}
+	for _, flag := range cfg.globalFlags {
+		manifest.GlobalFlags = append(manifest.GlobalFlags, ManifestFlag{
+			Flag:        "--" + flag.name,
+			Description: flag.description,
+			Default:     flag.defaultValue,
+			IsBool:      flag.isBool,
+		})
+	}
+	for _, cmd := range source.commandList() {
+		info := cmd.info()
+		command := ManifestCommand{
+			Command:     info.Command,
+			Usage:       cmd.usage(appName),
+			Description: info.Description,
+			Hidden:      info.Hidden,
+			Deprecated:  info.Deprecated,
+			Examples:    info.Examples,
+			Args:        make([]ManifestArg, len(info.Args)),
+		}
+		for i, arg := range info.Args {
+			command.Args[i] = ManifestArg{
+				Name:        arg.Name,
+				Flag:        arg.Flag,
+				Type:        derefType(arg.Type).String(),
+				Description: arg.Description,
+				Default:     arg.Default,
+				Values:      arg.Values,
+			}
+		}
+		manifest.Commands = append(manifest.Commands, command)
+	}
+	return manifest
+}
+
+// ManifestJSON returns the commands of the dispatcher with their
+// arguments and the global flags as indented JSON Manifest
+// for external tools like documentation generators.
+func (disp *StringArgsDispatcher) ManifestJSON() ([]byte, error) {
+	return json.MarshalIndent(newManifest(disp.cfg, disp), "", "  ")
+}
+
+// ManifestJSON returns the commands of the dispatcher as JSON Manifest.
+// See StringArgsDispatcher.ManifestJSON
+func (disp *SuperStringArgsDispatcher) ManifestJSON() ([]byte, error) {
+	return json.MarshalIndent(newManifest(disp.cfg, disp), "", "  ")
+}
+
+// ManifestJSON returns the commands of the tree as JSON Manifest.
+// See StringArgsDispatcher.ManifestJSON
+func (tree *CommandTree) ManifestJSON() ([]byte, error) {
+	return json.MarshalIndent(newManifest(tree.cfg, tree), "", "  ")
+}
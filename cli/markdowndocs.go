@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CommandSource is implemented by StringArgsDispatcher,
+// SuperStringArgsDispatcher, and CommandTree
+// to list their commands for documentation and tooling.
+type CommandSource interface {
+	// commandList returns all commands
+	// including hidden ones sorted by full command.
+	commandList() []*stringArgsCommand
+}
+
+func (disp *StringArgsDispatcher) commandList() []*stringArgsCommand {
+	list := make([]*stringArgsCommand, 0, len(disp.comm))
+	for _, command := range disp.Commands() {
+		list = append(list, disp.comm[command])
+	}
+	return list
+}
+
+func (disp *SuperStringArgsDispatcher) commandList() []*stringArgsCommand {
+	var list []*stringArgsCommand
+	for _, superCommand := range disp.Commands() {
+		list = append(list, disp.sub[superCommand].commandList()...)
+	}
+	return list
+}
+
+func (tree *CommandTree) commandList() []*stringArgsCommand {
+	var list []*stringArgsCommand
+	tree.walk(func(node *CommandTree) {
+		list = append(list, node.disp.commandList()...)
+	})
+	return list
+}
+
+// GenMarkdownDocs writes one markdown file per not hidden command
+// of the dispatcher to dir with the usage, description,
+// arguments, and examples of the command.
+// The files are named like the command with the
+// program name of os.Args[0] as prefix and all words
+// separated by underscores, like myapp_db_migrate.md.
+func GenMarkdownDocs(dispatcher CommandSource, dir string) error {
+	err := os.MkdirAll(dir, 0o755) //#nosec G301 -- documentation directory
+	if err != nil {
+		return err
+	}
+	appName := appName()
+	for _, cmd := range dispatcher.commandList() {
+		if cmd.hidden {
+			continue
+		}
+		filename := strings.Join(append([]string{appName}, strings.Fields(cmd.fullCommand)...), "_") + ".md"
+		err = os.WriteFile(filepath.Join(dir, filename), []byte(cmd.markdownDoc(appName)), 0o644) //#nosec G306 -- documentation is public
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markdownDoc returns the markdown documentation of cmd
+func (cmd *stringArgsCommand) markdownDoc(appName string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", strings.TrimSpace(appName+" "+cmd.fullCommand))
+	if cmd.description != "" {
+		fmt.Fprintf(&b, "%s\n\n", cmd.description)
+	}
+	if cmd.deprecated != "" {
+		fmt.Fprintf(&b, "**Deprecated:** %s\n\n", cmd.deprecated)
+	}
+	fmt.Fprintf(&b, "## Usage\n\n```\n%s\n```\n", cmd.usage(appName))
+
+	f := cmd.commandFunc
+	argNames := positionalArgNames(f)
+	if len(argNames) > 0 {
+		offset := f.NumArgs() - len(argNames)
+		argDescriptions := f.ArgDescriptions()
+		b.WriteString("\n## Arguments\n\n")
+		b.WriteString("| Name | Flag | Type | Default | Description |\n")
+		b.WriteString("|------|------|------|---------|-------------|\n")
+		for i, argName := range argNames {
+			description := ""
+			if offset+i < len(argDescriptions) {
+				description = argDescriptions[offset+i]
+			}
+			defaultValue := ""
+			if value, ok := cmd.argDefaults[argName]; ok {
+				defaultValue = "`" + value + "`"
+			}
+			fmt.Fprintf(&b, "| %s | `--%s` | `%s` | %s | %s |\n",
+				argName,
+				kebabCase(argName),
+				derefType(f.ArgTypes()[offset+i]),
+				markdownTableCell(defaultValue),
+				markdownTableCell(description),
+			)
+		}
+		b.WriteString("\nArguments can be passed in order or as flags in the form `--name=value`.\n")
+	}
+
+	if len(cmd.examples) > 0 {
+		b.WriteString("\n## Examples\n\n```\n")
+		for _, example := range cmd.examples {
+			fmt.Fprintf(&b, "%s\n", example)
+		}
+		b.WriteString("```\n")
+	}
+	return b.String()
+}
+
+// markdownTableCell escapes pipes and
+// replaces newlines for a markdown table cell.
+func markdownTableCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
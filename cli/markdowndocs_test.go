@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/domonda/go-function"
+)
+
+func TestGenMarkdownDocs(t *testing.T) {
+	disp := NewSuperStringArgsDispatcher()
+	db := disp.MustAddSuperCommand("db")
+	db.MustAddCommand("migrate", "Migrate the database", function.MustReflectWrapper(
+		func(env string, steps int) {},
+		"env", "steps",
+	), WithArgDefault("steps", "1"), WithExample("myapp db migrate prod --steps=3"))
+	db.MustAddHiddenCommand("debug", "", function.MustReflectWrapper(func() {}))
+
+	dir := t.TempDir()
+	err := GenMarkdownDocs(disp, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || !strings.HasSuffix(files[0], "_db_migrate.md") {
+		t.Fatalf("unexpected files: %v", files)
+	}
+	doc, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Migrate the database",
+		"db migrate <env:string> [steps:int=1]",
+		"| steps | `--steps` | `int` | `1` |  |",
+		"## Examples\n\n```\nmyapp db migrate prod --steps=3\n```",
+	} {
+		if !strings.Contains(string(doc), want) {
+			t.Errorf("doc does not contain %q:\n%s", want, doc)
+		}
+	}
+}
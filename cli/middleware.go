@@ -0,0 +1,34 @@
+package cli
+
+import "context"
+
+// BeforeFunc is called before every dispatched command
+// with the full command including super commands
+// and the command line arguments.
+// The returned context is passed to the command function
+// and following hooks. If an error is returned,
+// then the command is not called and the error is returned.
+type BeforeFunc func(ctx context.Context, command string, args []string) (context.Context, error)
+
+// AfterFunc is called after every dispatched command
+// with the context returned by the BeforeFunc hooks
+// and the error returned by the command.
+// The returned error replaces the error of the command,
+// so return cmdErr to keep it.
+type AfterFunc func(ctx context.Context, command string, args []string, cmdErr error) error
+
+// runHooks calls the before hooks, then call,
+// and then the after hooks in reverse order.
+func (cfg *dispatcherConfig) runHooks(ctx context.Context, command string, args []string, call func(context.Context) error) (err error) {
+	for _, before := range cfg.before {
+		ctx, err = before(ctx, command, args)
+		if err != nil {
+			return err
+		}
+	}
+	err = call(ctx)
+	for i := len(cfg.after) - 1; i >= 0; i-- {
+		err = cfg.after[i](ctx, command, args, err)
+	}
+	return err
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/domonda/go-function"
+)
+
+// OutputFlag is the name of the global flag
+// added by EnableOutputFlag to select the output format.
+const OutputFlag = "output"
+
+// OutputFormatHandlers are the results handlers used
+// for the output formats of the --output flag
+// if a command has no handler for the format set with
+// SetOutputFormatHandler.
+var OutputFormatHandlers = map[string]function.ResultsHandler{
+	"json":   PrintJSON,
+	"yaml":   PrintYAML,
+	"table":  function.PrintStructSliceAsTable,
+	"plain":  function.Println,
+	"ndjson": function.PrintNDJSON,
+}
+
+// PrintJSON prints every result as indented JSON
+// to os.Stdout or the output of function.ContextWithOutput
+var PrintJSON function.ResultsHandlerFunc = func(ctx context.Context, results []any, resultErr error) error {
+	if resultErr != nil {
+		return resultErr
+	}
+	for _, result := range results {
+		b, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(function.OutputFromContext(ctx), string(b))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintYAML prints every result as YAML document
+// to os.Stdout or the output of function.ContextWithOutput
+var PrintYAML function.ResultsHandlerFunc = func(ctx context.Context, results []any, resultErr error) error {
+	if resultErr != nil {
+		return resultErr
+	}
+	enc := yaml.NewEncoder(function.OutputFromContext(ctx))
+	enc.SetIndent(2)
+	for _, result := range results {
+		err := enc.Encode(result)
+		if err != nil {
+			return err
+		}
+	}
+	return enc.Close()
+}
+
+func (cfg *dispatcherConfig) enableOutputFlag() error {
+	err := cfg.addGlobalFlag(OutputFlag, "Output format: "+strings.Join(outputFormats(nil), ", "), "")
+	if err != nil {
+		return err
+	}
+	cfg.outputFlag = true
+	return nil
+}
+
+// outputFormats returns the sorted names of the OutputFormatHandlers
+// merged with the names of the passed command specific handlers.
+func outputFormats(cmdHandlers map[string]function.ResultsHandler) []string {
+	formats := make([]string, 0, len(OutputFormatHandlers)+len(cmdHandlers))
+	for format := range OutputFormatHandlers {
+		formats = append(formats, format)
+	}
+	for format := range cmdHandlers {
+		if _, exists := OutputFormatHandlers[format]; !exists {
+			formats = append(formats, format)
+		}
+	}
+	slices.Sort(formats)
+	return formats
+}
+
+// outputFormatHandler returns the results handler for the
+// output format selected by the --output flag in ctx.
+// The result ok is false if the flag is not enabled
+// or no output format was selected.
+func (cmd *stringArgsCommand) outputFormatHandler(ctx context.Context, cfg *dispatcherConfig) (handler function.ResultsHandler, ok bool, err error) {
+	if !cfg.outputFlag {
+		return nil, false, nil
+	}
+	format, _ := GlobalFlag(ctx, OutputFlag)
+	if format == "" {
+		return nil, false, nil
+	}
+	if handler, ok = cmd.outputHandlers[format]; ok {
+		return handler, true, nil
+	}
+	if handler, ok = OutputFormatHandlers[format]; ok {
+		return handler, true, nil
+	}
+	return nil, false, fmt.Errorf("unsupported output format %q, supported are: %s", format, strings.Join(outputFormats(cmd.outputHandlers), ", "))
+}
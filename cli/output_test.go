@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/domonda/go-function"
+)
+
+func TestStringArgsDispatcher_EnableOutputFlag(t *testing.T) {
+	var handled []string
+	handler := func(name string) function.ResultsHandlerFunc {
+		return func(ctx context.Context, results []any, resultErr error) error {
+			handled = append(handled, name)
+			return resultErr
+		}
+	}
+	disp := NewStringArgsDispatcher()
+	disp.MustAddCommand("cmd", "", function.MustReflectWrapper(func() string { return "result" }), handler("default"))
+	if err := disp.EnableOutputFlag(); err != nil {
+		t.Fatal(err)
+	}
+	if err := disp.SetOutputFormatHandler("cmd", "custom", handler("custom")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{nil, {"--output=custom"}} {
+		err := disp.Dispatch(context.Background(), "cmd", args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := []string{"default", "custom"}; !slices.Equal(handled, want) {
+		t.Errorf("handled by %v, want %v", handled, want)
+	}
+	if err := disp.Dispatch(context.Background(), "cmd", "--output", "xml"); err == nil {
+		t.Error("expected error for unsupported output format")
+	}
+}
+
+// lineNotifier sends every written line to lines
+type lineNotifier struct {
+	lines chan string
+}
+
+func (w lineNotifier) Write(p []byte) (int, error) {
+	w.lines <- string(p)
+	return len(p), nil
+}
+
+func TestStringArgsDispatcher_outputNDJSON(t *testing.T) {
+	// The command sends the next item after
+	// the previous one was printed, so buffering
+	// the output until the end would block
+	printed := make(chan string)
+	disp := NewStringArgsDispatcher()
+	disp.MustAddCommand("cmd", "", function.MustReflectWrapper(func() <-chan int {
+		items := make(chan int)
+		go func() {
+			defer close(items)
+			for i := range 3 {
+				items <- i
+				if i < 2 {
+					<-printed
+				}
+			}
+		}()
+		return items
+	}), function.Println)
+	if err := disp.EnableOutputFlag(); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		output = lineNotifier{lines: make(chan string)}
+		ctx    = function.ContextWithOutput(context.Background(), output)
+		done   = make(chan error)
+		got    []string
+	)
+	go func() { done <- disp.Dispatch(ctx, "cmd", "--output=ndjson") }()
+	for range 3 {
+		select {
+		case line := <-output.lines:
+			got = append(got, line)
+			if len(got) < 3 {
+				printed <- line
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for streamed line after %q", got)
+		}
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"0\n", "1\n", "2\n"}; !slices.Equal(got, want) {
+		t.Errorf("printed %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+
+	"github.com/domonda/go-function"
+)
+
+// DefaultPager is the pager command used
+// if the PAGER environment variable is not set.
+var DefaultPager = "less -FRX"
+
+// Paged returns a results handler that pipes the output
+// of handler through the pager of the PAGER environment variable
+// or DefaultPager if os.Stdout is a terminal
+// and the output has more lines than the terminal.
+// The handler has to write to function.OutputFromContext
+// like function.Println or PrintJSON.
+func Paged(handler function.ResultsHandler) function.ResultsHandlerFunc {
+	return func(ctx context.Context, results []any, resultErr error) error {
+		return withPager(ctx, func(ctx context.Context) error {
+			return handler.HandleResults(ctx, results, resultErr)
+		})
+	}
+}
+
+// paged calls call with withPager if the pager is enabled
+func (cfg *dispatcherConfig) paged(ctx context.Context, call func(context.Context) error) error {
+	if !cfg.pager {
+		return call(ctx)
+	}
+	return withPager(ctx, call)
+}
+
+// withPager calls call with a context whose function.OutputFromContext
+// is a pagerWriter if the output of ctx is a terminal.
+func withPager(ctx context.Context, call func(context.Context) error) error {
+	stdout, ok := function.OutputFromContext(ctx).(*os.File)
+	if !ok || !isTerminal(stdout) {
+		return call(ctx)
+	}
+	_, height, err := term.GetSize(int(stdout.Fd())) //#nosec G115 -- file descriptors fit into int
+	if err != nil {
+		return call(ctx)
+	}
+	w := newPagerWriter(stdout, height)
+	err = call(function.ContextWithOutput(ctx, w))
+	return errors.Join(err, w.Close())
+}
+
+// pagerWriter buffers the output written to it until
+// it has as many lines as the terminal height,
+// then it starts the pager and streams all output to it.
+// Output that fits into the terminal is written
+// to stdout when the pagerWriter is closed.
+type pagerWriter struct {
+	stdout *os.File
+	height int
+
+	mtx    sync.Mutex
+	buf    bytes.Buffer
+	lines  int
+	pager  *exec.Cmd
+	pipe   io.WriteCloser // stdin of pager
+	direct bool           // pager not found, write to stdout
+}
+
+func newPagerWriter(stdout *os.File, height int) *pagerWriter {
+	return &pagerWriter{stdout: stdout, height: height}
+}
+
+func (w *pagerWriter) Write(p []byte) (int, error) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	switch {
+	case w.pipe != nil:
+		// Writing fails after the user quit the pager,
+		// the remaining output is discarded
+		w.pipe.Write(p) //#nosec G104 -- see above
+		return len(p), nil
+	case w.direct:
+		return w.stdout.Write(p)
+	}
+	w.buf.Write(p)
+	w.lines += bytes.Count(p, []byte{'\n'})
+	if w.lines < w.height {
+		return len(p), nil
+	}
+	err := w.startPager()
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// startPager starts the pager and writes the buffered output to it
+// or to stdout if the pager command was not found.
+func (w *pagerWriter) startPager() error {
+	pager := strings.Fields(cmp.Or(os.Getenv("PAGER"), DefaultPager))
+	if len(pager) == 0 {
+		return w.writeDirect()
+	}
+	cmd := exec.Command(pager[0], pager[1:]...) //#nosec G204 -- pager configured by the user
+	cmd.Stdout = w.stdout
+	cmd.Stderr = os.Stderr
+	pipe, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	err = cmd.Start()
+	if errors.Is(err, exec.ErrNotFound) {
+		return w.writeDirect()
+	}
+	if err != nil {
+		return err
+	}
+	w.pager = cmd
+	w.pipe = pipe
+	w.buf.WriteTo(pipe) //#nosec G104 -- the user may have quit the pager already
+	return nil
+}
+
+// writeDirect writes the buffered output and all
+// following writes directly to stdout.
+func (w *pagerWriter) writeDirect() error {
+	w.direct = true
+	_, err := w.buf.WriteTo(w.stdout)
+	return err
+}
+
+// Close writes the buffered output to stdout
+// if the pager was not started,
+// else it closes the input of the pager
+// and waits until the user quits it.
+func (w *pagerWriter) Close() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if w.pipe == nil {
+		_, err := w.buf.WriteTo(w.stdout)
+		return err
+	}
+	w.pipe.Close() //#nosec G104 -- fails if the user quit the pager early
+	return w.pager.Wait()
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/domonda/go-function"
+)
+
+func TestPaged(t *testing.T) {
+	var called bool
+	handler := Paged(function.ResultsHandlerFunc(func(ctx context.Context, results []any, resultErr error) error {
+		called = true
+		return resultErr
+	}))
+	resultErr := errors.New("result error")
+	if err := handler(context.Background(), nil, resultErr); err != resultErr || !called {
+		t.Errorf("Paged handler returned %v, called %t", err, called)
+	}
+
+	// Output that is not a terminal is written directly
+	var output strings.Builder
+	ctx := function.ContextWithOutput(context.Background(), &output)
+	if err := Paged(function.Println)(ctx, []any{"a"}, nil); err != nil || output.String() != "a\n" {
+		t.Errorf("Paged(Println) wrote %q, %v", output.String(), err)
+	}
+}
+
+func Test_pagerWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		pager  string
+		writes []string
+		want   string
+	}{
+		{name: "fits terminal", pager: "sed s/^/>/", writes: []string{"a\n", "b\n"}, want: "a\nb\n"},
+		{name: "paged", pager: "sed s/^/>/", writes: []string{"a\n", "b\nc\n", "d\n"}, want: ">a\n>b\n>c\n>d\n"},
+		{name: "pager not found", pager: "pager-that-does-not-exist", writes: []string{"a\n", "b\nc\n", "d\n"}, want: "a\nb\nc\nd\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PAGER", tt.pager)
+			f, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			w := newPagerWriter(f, 3)
+			for _, s := range tt.writes {
+				if _, err := io.WriteString(w, s); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if written, _ := os.ReadFile(f.Name()); string(written) != tt.want {
+				t.Errorf("wrote %q, want %q", written, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+func (cfg *dispatcherConfig) enablePlugins(prefix string) {
+	cfg.pluginPrefix = cmp.Or(prefix, appName())
+}
+
+// runPlugin runs the executable named like the plugin prefix
+// and the command words joined with dashes with args
+// if plugins are enabled and the executable is found on PATH.
+// The result found is false if no plugin was run.
+func (cfg *dispatcherConfig) runPlugin(ctx context.Context, words []string, args []string) (found bool, err error) {
+	if cfg.pluginPrefix == "" || len(words) == 0 {
+		return false, nil
+	}
+	path, err := exec.LookPath(cfg.pluginPrefix + "-" + strings.Join(words, "-"))
+	if err != nil {
+		return false, nil
+	}
+	cmd := exec.CommandContext(ctx, path, args...) //#nosec G204 -- plugins are executables installed by the user
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return true, cmd.Run()
+}
@@ -0,0 +1,40 @@
+//go:build unix
+
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStringArgsDispatcher_EnablePlugins(t *testing.T) {
+	dir := t.TempDir()
+	outFile := filepath.Join(dir, "out.txt")
+	script := "#!/bin/sh\necho \"$@\" > " + outFile + "\nexit 3\n"
+	err := os.WriteFile(filepath.Join(dir, "myapp-hello"), []byte(script), 0o755) //#nosec G306 -- executable test script
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	disp := NewStringArgsDispatcher()
+	if err := disp.Dispatch(context.Background(), "hello", "world"); !IsErrCommandNotFound(err) {
+		t.Errorf("expected command not found without plugins, got %v", err)
+	}
+
+	disp.EnablePlugins("myapp")
+	err = disp.Dispatch(context.Background(), "hello", "big", "world")
+	if ExitCode(err) != 3 {
+		t.Errorf("expected plugin exit code 3, got %d for %v", ExitCode(err), err)
+	}
+	out, _ := os.ReadFile(outFile)
+	if strings.TrimSpace(string(out)) != "big world" {
+		t.Errorf("plugin called with %q", out)
+	}
+	if err := disp.Dispatch(context.Background(), "bye"); !IsErrCommandNotFound(err) {
+		t.Errorf("expected command not found for missing plugin, got %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProgressReporter is used by command functions
+// to report the progress of long-running work.
+// Use Progress to get the reporter from the context
+// of a dispatched command.
+type ProgressReporter interface {
+	// SetTotal sets the total number of steps.
+	// A total of zero means unknown.
+	SetTotal(total int64)
+	// Add adds n completed steps.
+	Add(n int64)
+	// SetMessage sets a message describing the current step.
+	SetMessage(message string)
+}
+
+type progressCtxKey struct{}
+
+// ContextWithProgressReporter returns a context with the passed
+// ProgressReporter that will be returned by Progress.
+func ContextWithProgressReporter(ctx context.Context, reporter ProgressReporter) context.Context {
+	return context.WithValue(ctx, progressCtxKey{}, reporter)
+}
+
+// Progress returns the ProgressReporter of the context
+// or a reporter that discards all progress if there is none.
+// The result is never nil.
+func Progress(ctx context.Context) ProgressReporter {
+	if reporter, ok := ctx.Value(progressCtxKey{}).(ProgressReporter); ok {
+		return reporter
+	}
+	return discardProgress{}
+}
+
+type discardProgress struct{}
+
+func (discardProgress) SetTotal(int64)    {}
+func (discardProgress) Add(int64)         {}
+func (discardProgress) SetMessage(string) {}
+
+// ProgressBarWidth is the number of characters
+// of the bar rendered by TerminalProgress.
+var ProgressBarWidth = 30
+
+var spinnerFrames = []string{"|", "/", "-", `\`}
+
+// TerminalProgress is a ProgressReporter that renders
+// a spinner for an unknown total or a bar for a known total
+// into a single terminal line.
+// Nothing is rendered before the first progress was reported.
+type TerminalProgress struct {
+	w        io.Writer
+	interval time.Duration
+
+	mtx      sync.Mutex
+	total    int64
+	current  int64
+	message  string
+	reported bool
+	rendered bool
+	frame    int
+	stop     chan struct{}
+	stopped  chan struct{}
+}
+
+// NewTerminalProgress returns a TerminalProgress rendering to w
+// that has to be started with Start and stopped with Stop.
+func NewTerminalProgress(w io.Writer) *TerminalProgress {
+	return &TerminalProgress{w: w, interval: 100 * time.Millisecond}
+}
+
+// SetTotal implements ProgressReporter
+func (p *TerminalProgress) SetTotal(total int64) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.total = total
+	p.reported = true
+}
+
+// Add implements ProgressReporter
+func (p *TerminalProgress) Add(n int64) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.current += n
+	p.reported = true
+}
+
+// SetMessage implements ProgressReporter
+func (p *TerminalProgress) SetMessage(message string) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.message = message
+	p.reported = true
+}
+
+// Start starts rendering the progress in a background goroutine.
+func (p *TerminalProgress) Start() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.stop != nil {
+		return
+	}
+	p.stop = make(chan struct{})
+	p.stopped = make(chan struct{})
+	go func() {
+		defer close(p.stopped)
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				p.mtx.Lock()
+				p.render()
+				p.mtx.Unlock()
+			}
+		}
+	}()
+}
+
+// Stop stops rendering and clears the progress line.
+func (p *TerminalProgress) Stop() {
+	p.mtx.Lock()
+	stop, stopped := p.stop, p.stopped
+	p.stop = nil
+	p.mtx.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-stopped
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.rendered {
+		fmt.Fprint(p.w, "\r\033[K") //#nosec G104 -- progress output is best effort
+		p.rendered = false
+	}
+}
+
+// render writes the current progress line.
+// p.mtx must be locked.
+func (p *TerminalProgress) render() {
+	if !p.reported {
+		return
+	}
+	fmt.Fprint(p.w, "\r\033[K"+p.line()) //#nosec G104 -- progress output is best effort
+	p.rendered = true
+	p.frame++
+}
+
+// line returns the progress line without control characters.
+// p.mtx must be locked.
+func (p *TerminalProgress) line() string {
+	var b strings.Builder
+	if p.total > 0 {
+		done := min(int(p.current*int64(ProgressBarWidth)/p.total), ProgressBarWidth)
+		fmt.Fprintf(&b, "[%s%s] %d/%d", strings.Repeat("=", done), strings.Repeat(" ", ProgressBarWidth-done), p.current, p.total)
+	} else {
+		b.WriteString(spinnerFrames[p.frame%len(spinnerFrames)])
+		if p.current > 0 {
+			fmt.Fprintf(&b, " %d", p.current)
+		}
+	}
+	if p.message != "" {
+		b.WriteByte(' ')
+		b.WriteString(p.message)
+	}
+	return b.String()
+}
+
+// withProgress returns a context with a TerminalProgress
+// rendering to os.Stderr if progress reporting is enabled
+// and os.Stderr is a terminal.
+// The returned stop function must always be called.
+func (cfg *dispatcherConfig) withProgress(ctx context.Context) (context.Context, func()) {
+	if !cfg.progress || !isTerminal(os.Stderr) {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Value(progressCtxKey{}).(ProgressReporter); ok {
+		return ctx, func() {}
+	}
+	progress := NewTerminalProgress(os.Stderr)
+	progress.Start()
+	return ContextWithProgressReporter(ctx, progress), progress.Stop
+}
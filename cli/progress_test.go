@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTerminalProgress(t *testing.T) {
+	if _, ok := Progress(context.Background()).(discardProgress); !ok {
+		t.Error("expected discarding progress reporter without reporter in context")
+	}
+
+	var buf strings.Builder
+	p := NewTerminalProgress(&buf)
+	p.render()
+	if buf.Len() > 0 {
+		t.Errorf("rendered %q before progress was reported", buf.String())
+	}
+	p.SetMessage("loading")
+	p.Add(2)
+	if got, want := p.line(), "| 2 loading"; got != want {
+		t.Errorf("spinner line %q, want %q", got, want)
+	}
+	p.SetTotal(4)
+	if got, want := p.line(), "["+strings.Repeat("=", ProgressBarWidth/2)+strings.Repeat(" ", ProgressBarWidth/2)+"] 2/4 loading"; got != want {
+		t.Errorf("bar line %q, want %q", got, want)
+	}
+	p.render()
+	if !strings.HasSuffix(buf.String(), "2/4 loading") {
+		t.Errorf("rendered %q", buf.String())
+	}
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// prompter asks the user for argument values
+// that were not passed on the command line.
+// Prompts of concurrently dispatched commands
+// are asked one after the other.
+type prompter struct {
+	mtx       sync.Mutex
+	in        *bufio.Reader
+	inFile    *os.File // used for hidden input if it is a terminal
+	out       io.Writer
+	sensitive map[string]bool
+}
+
+func newPrompter(in io.Reader, out io.Writer, sensitive map[string]bool) *prompter {
+	p := &prompter{
+		in:        bufio.NewReader(in),
+		out:       out,
+		sensitive: sensitive,
+	}
+	p.inFile, _ = in.(*os.File)
+	return p
+}
+
+// argSource prompts for required arguments of cmd.
+// Input for sensitive arguments is not echoed
+// if the input is a terminal.
+func (p *prompter) argSource(_ context.Context, cmd *stringArgsCommand, argName string) (value string, ok bool, err error) {
+	f := cmd.commandFunc
+	index := -1
+	for i, name := range f.ArgNames() {
+		if name == argName {
+			index = i
+			break
+		}
+	}
+	if index == -1 || !isRequiredArgType(f.ArgTypes()[index]) {
+		return "", false, nil
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	prompt := argName
+	if descriptions := f.ArgDescriptions(); index < len(descriptions) && descriptions[index] != "" {
+		prompt = descriptions[index] + " (" + argName + ")"
+	}
+	_, err = fmt.Fprintf(p.out, "%s <%s>: ", prompt, derefType(f.ArgTypes()[index]))
+	if err != nil {
+		return "", false, err
+	}
+
+	if p.sensitive[argName] && p.inFile != nil && term.IsTerminal(int(p.inFile.Fd())) { //#nosec G115
+		b, err := term.ReadPassword(int(p.inFile.Fd())) //#nosec G115
+		fmt.Fprintln(p.out)                             //#nosec G104 -- ReadPassword swallows the newline
+		if err != nil {
+			return "", false, err
+		}
+		return string(b), true, nil
+	}
+
+	line, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", false, fmt.Errorf("can't read value for argument %s: %w", argName, err)
+	}
+	return strings.TrimRight(line, "\r\n"), true, nil
+}
+
+// isRequiredArgType returns false for argument types
+// where the zero value is a valid choice,
+// like pointers, slices, maps, bools, and nullable types.
+func isRequiredArgType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Bool, reflect.Interface:
+		return false
+	}
+	return !t.Implements(reflect.TypeFor[interface{ IsNull() bool }]())
+}
@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/domonda/go-function"
+)
+
+func TestStringArgsDispatcher_prompting(t *testing.T) {
+	var gotName string
+	var gotAge int
+	var gotNick *string
+	disp := NewStringArgsDispatcher()
+	var prompts strings.Builder
+	disp.cfg.enablePrompting(strings.NewReader("Alice\n42\n"), &prompts)
+	disp.MustAddCommand("cmd", "", function.MustReflectWrapper(
+		func(name string, age int, nick *string) { gotName, gotAge, gotNick = name, age, nick },
+		"name", "age", "nick",
+	))
+	err := disp.Dispatch(context.Background(), "cmd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotName != "Alice" || gotAge != 42 || gotNick != nil {
+		t.Errorf("got name=%q age=%d nick=%v", gotName, gotAge, gotNick)
+	}
+	if want := "name <string>: age <int>: "; prompts.String() != want {
+		t.Errorf("prompts = %q, want %q", prompts.String(), want)
+	}
+}
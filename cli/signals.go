@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// ShutdownGracePeriod is the time DispatchWithSignals
+// waits for a canceled command to return
+// before the process is forcefully exited.
+var ShutdownGracePeriod = 10 * time.Second
+
+// exit is os.Exit, replaceable for tests
+var exit = os.Exit
+
+// CommandDispatcher is implemented by StringArgsDispatcher,
+// SuperStringArgsDispatcher, and CommandTree
+// to dispatch a command line.
+type CommandDispatcher interface {
+	CommandSource
+
+	dispatchCommandLine(ctx context.Context, commandAndArgs []string) error
+}
+
+func (disp *StringArgsDispatcher) dispatchCommandLine(ctx context.Context, commandAndArgs []string) error {
+	_, err := disp.DispatchCombinedCommandAndArgs(ctx, commandAndArgs)
+	return err
+}
+
+func (disp *SuperStringArgsDispatcher) dispatchCommandLine(ctx context.Context, commandAndArgs []string) error {
+	_, _, err := disp.DispatchCombinedCommandAndArgs(ctx, commandAndArgs)
+	return err
+}
+
+func (tree *CommandTree) dispatchCommandLine(ctx context.Context, commandAndArgs []string) error {
+	_, err := tree.Dispatch(ctx, commandAndArgs)
+	return err
+}
+
+// DispatchWithSignals dispatches the command line commandAndArgs
+// with a context that is canceled on SIGINT or SIGTERM.
+// After the first signal the command has ShutdownGracePeriod
+// to return, if it does not, or if a second signal is received,
+// then the process is exited with the exit code 128 + signal number.
+func DispatchWithSignals(dispatcher CommandDispatcher, commandAndArgs []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	result := make(chan error, 1)
+	go func() {
+		result <- dispatcher.dispatchCommandLine(ctx, commandAndArgs)
+	}()
+
+	var sig os.Signal
+	select {
+	case err := <-result:
+		return err
+	case sig = <-signals:
+	}
+	fmt.Fprintf(os.Stderr, "\nReceived %s, shutting down (send again to force exit)\n", sig) //#nosec G104 -- best effort message
+	cancel()
+
+	timeout := time.NewTimer(ShutdownGracePeriod)
+	defer timeout.Stop()
+	select {
+	case err := <-result:
+		return err
+	case sig = <-signals:
+		fmt.Fprintf(os.Stderr, "Received %s again, forcing exit\n", sig) //#nosec G104 -- best effort message
+	case <-timeout.C:
+		fmt.Fprintf(os.Stderr, "Command did not return within %s, forcing exit\n", ShutdownGracePeriod) //#nosec G104 -- best effort message
+	}
+	exit(exitCodeForSignal(sig))
+	return ctx.Err() // only reached if exit was replaced
+}
+
+// exitCodeForSignal returns the conventional
+// exit code 128 + signal number for sig.
+func exitCodeForSignal(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return ExitCodeError
+}
@@ -0,0 +1,37 @@
+//go:build unix
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/domonda/go-function"
+)
+
+func TestDispatchWithSignals(t *testing.T) {
+	disp := NewStringArgsDispatcher()
+	disp.MustAddCommand("wait", "", function.MustReflectWrapper(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+	disp.MustAddCommand("fast", "", function.MustReflectWrapper(func() {}))
+
+	err := DispatchWithSignals(disp, []string{"fast"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		syscall.Kill(os.Getpid(), syscall.SIGINT) //#nosec G104
+	}()
+	err = DispatchWithSignals(disp, []string{"wait"})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -2,24 +2,147 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"maps"
+	"os"
 	"reflect"
 	"slices"
 	"sort"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/domonda/go-function"
 )
 
 type stringArgsCommand struct {
-	command         string
-	description     string
-	commandFunc     function.Wrapper
-	stringArgsFunc  function.StringArgsFunc
-	resultsHandlers []function.ResultsHandler
+	command             string
+	fullCommand         string // including super commands separated by space
+	description         string
+	commandFunc         function.Wrapper
+	stringArgsFunc      function.StringArgsFunc
+	namedStringArgsFunc function.NamedStringArgsFunc
+	resultsHandlers     []function.ResultsHandler
+	hidden              bool   // excluded from PrintCommands and completion
+	deprecated          string // deprecation message, empty if not deprecated
+	outputHandlers      map[string]function.ResultsHandler
+	confirm             string // confirmation message format
+	argDefaults         map[string]string
+	examples            []string
+	locker              Locker
+	argValues           map[string][]string // allowed values per argument
+}
+
+func newStringArgsCommand(superCommand, command, description string, commandFunc function.Wrapper, resultsHandlers []function.ResultsHandler) *stringArgsCommand {
+	resultsHandlers, options := splitCommandOptions(resultsHandlers)
+	cmd := &stringArgsCommand{
+		command:             command,
+		fullCommand:         strings.TrimSpace(superCommand + " " + command),
+		description:         description,
+		commandFunc:         commandFunc,
+		stringArgsFunc:      function.NewStringArgsFunc(commandFunc, withRecordResults(resultsHandlers)...),
+		namedStringArgsFunc: function.NewNamedStringArgsFunc(commandFunc, withRecordResults(resultsHandlers)...),
+		resultsHandlers:     resultsHandlers,
+	}
+	for _, option := range options {
+		option.applyToCommand(cmd)
+	}
+	return cmd
+}
+
+// dispatch calls the command function with positional args
+// or with named args if the args are passed as flags
+// in the form --name=value or --name value.
+// Arguments that were not passed are looked up
+// from the argument sources of the dispatcher config.
+func (cmd *stringArgsCommand) dispatch(ctx context.Context, cfg *dispatcherConfig, args []string) (err error) {
+	ctx = function.ContextWithCallInfo(ctx, function.CallInfo{Command: cmd.fullCommand})
+	unlock, err := cmd.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if unlockErr := unlock(); unlockErr != nil {
+			err = errors.Join(err, unlockErr)
+		}
+	}()
+
+	stringArgsFunc, namedStringArgsFunc := cmd.stringArgsFunc, cmd.namedStringArgsFunc
+	outputHandler, ok, err := cmd.outputFormatHandler(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("command '%s': %w", cmd.command, err)
+	}
+	if ok {
+		stringArgsFunc = function.NewStringArgsFunc(cmd.commandFunc, recordResults, outputHandler)
+		namedStringArgsFunc = function.NewNamedStringArgsFunc(cmd.commandFunc, recordResults, outputHandler)
+	}
+	stringArgsFunc, namedStringArgsFunc = cmd.withCallEvents(stringArgsFunc, namedStringArgsFunc)
+	if cfg.dryRunValidateOnly && function.IsDryRun(ctx) {
+		stringArgsFunc, namedStringArgsFunc = cmd.dryRunFuncs()
+	}
+
+	args, err = cfg.expandArgs(args)
+	if err != nil {
+		return fmt.Errorf("command '%s': %w", cmd.command, err)
+	}
+
+	positionalOnly := !hasFlags(args)
+	if positionalOnly && len(args) >= len(positionalArgNames(cmd.commandFunc)) {
+		err = cmd.checkArgValues(positionalArgsMap(cmd.commandFunc, args))
+		if err != nil {
+			return err
+		}
+		err = cfg.confirm(ctx, cmd, args)
+		if err != nil {
+			return err
+		}
+		return cmd.wrapParseArgError(stringArgsFunc(ctx, args...))
+	}
+	named, err := parseFlags(cmd.commandFunc, args)
+	if err != nil {
+		return cmd.newUsageError(fmt.Errorf("command '%s': %w", cmd.command, err))
+	}
+	numPassed := len(named)
+	err = cfg.applyArgSources(ctx, cmd, named)
+	if err != nil {
+		return fmt.Errorf("command '%s': %w", cmd.command, err)
+	}
+	err = cmd.checkArgValues(named)
+	if err != nil {
+		return err
+	}
+	argValues := make([]string, 0, len(named))
+	for _, argName := range positionalArgNames(cmd.commandFunc) {
+		argValues = append(argValues, named[argName])
+	}
+	err = cfg.confirm(ctx, cmd, argValues)
+	if err != nil {
+		return err
+	}
+	if positionalOnly && len(named) == numPassed {
+		// No flags and nothing added from argument sources
+		return cmd.wrapParseArgError(stringArgsFunc(ctx, args...))
+	}
+	return cmd.wrapParseArgError(namedStringArgsFunc(ctx, named))
+}
+
+// withCallEvents returns the passed functions wrapped
+// to emit the function.CallEvent of the command function calls.
+func (cmd *stringArgsCommand) withCallEvents(stringArgsFunc function.StringArgsFunc, namedStringArgsFunc function.NamedStringArgsFunc) (function.StringArgsFunc, function.NamedStringArgsFunc) {
+	name := cmd.commandFunc.Name()
+	return func(ctx context.Context, args ...string) error {
+			end := function.StartCallEvents(ctx, name, function.TransportCLI)
+			err := stringArgsFunc(ctx, args...)
+			end(err)
+			return err
+		}, func(ctx context.Context, args map[string]string) error {
+			end := function.StartCallEvents(ctx, name, function.TransportCLI)
+			err := namedStringArgsFunc(ctx, args)
+			end(err)
+			return err
+		}
 }
 
 func checkCommandChars(command string) error {
@@ -46,17 +169,74 @@ func (f StringArgsCommandLoggerFunc) LogStringArgsCommand(command string, args [
 }
 
 type StringArgsDispatcher struct {
-	comm    map[string]*stringArgsCommand
-	loggers []StringArgsCommandLogger
+	superCommand string
+	comm         map[string]*stringArgsCommand
+	cfg          *dispatcherConfig
 }
 
 func NewStringArgsDispatcher(loggers ...StringArgsCommandLogger) *StringArgsDispatcher {
+	return newStringArgsDispatcher("", newDispatcherConfig(loggers))
+}
+
+func newStringArgsDispatcher(superCommand string, cfg *dispatcherConfig) *StringArgsDispatcher {
 	return &StringArgsDispatcher{
-		comm:    make(map[string]*stringArgsCommand),
-		loggers: loggers,
+		superCommand: superCommand,
+		comm:         make(map[string]*stringArgsCommand),
+		cfg:          cfg,
 	}
 }
 
+// SetArgEnvVar sets the environment variable envVar
+// as source for the argument argName of all commands
+// when the argument is not passed on the command line.
+func (disp *StringArgsDispatcher) SetArgEnvVar(argName, envVar string) {
+	disp.cfg.setArgEnvVar(argName, envVar)
+}
+
+// SetEnvVarPrefix enables environment variables as source
+// for all arguments not passed on the command line.
+// The environment variable name is the prefix followed by
+// the argument name in UPPER_SNAKE_CASE,
+// so with the prefix "MYAPP_" the argument apiKey
+// is read from the environment variable MYAPP_API_KEY.
+// Names set with SetArgEnvVar have precedence.
+func (disp *StringArgsDispatcher) SetEnvVarPrefix(prefix string) {
+	disp.cfg.envVarPrefix = prefix
+}
+
+// EnablePrompting enables interactive prompting on os.Stdin
+// for required arguments that were neither passed on the command line
+// nor found in environment variables or the config file.
+// Arguments of pointer, slice, map, bool, interface, and
+// nullable types are not required and never prompted for.
+// The argument description is used as prompt text.
+func (disp *StringArgsDispatcher) EnablePrompting() {
+	disp.cfg.enablePrompting(os.Stdin, os.Stderr)
+}
+
+// SetArgSensitive marks the argument argName as sensitive
+// so that its prompted input is not echoed to the terminal.
+// Has only an effect if prompting is enabled.
+func (disp *StringArgsDispatcher) SetArgSensitive(argName string) {
+	disp.cfg.setArgSensitive(argName)
+}
+
+// WithConfigFile sets a YAML or JSON file that maps commands
+// to default values for their arguments.
+// Arguments passed on the command line or found
+// in environment variables have precedence.
+// The file is read when the first command needs it,
+// a non existing file is not an error.
+// Example YAML:
+//
+//	deploy:
+//	  env: staging
+//	  replicas: 3
+func (disp *StringArgsDispatcher) WithConfigFile(path string) *StringArgsDispatcher {
+	disp.cfg.configFile = configFileArgSource(path)
+	return disp
+}
+
 func (disp *StringArgsDispatcher) AddCommand(command, description string, commandFunc function.Wrapper, resultsHandlers ...function.ResultsHandler) error {
 	if _, exists := disp.comm[command]; exists {
 		return fmt.Errorf("Command '%s' already added", command)
@@ -64,16 +244,31 @@ func (disp *StringArgsDispatcher) AddCommand(command, description string, comman
 	if err := checkCommandChars(command); err != nil {
 		return fmt.Errorf("Command '%s' returned: %w", command, err)
 	}
-	disp.comm[command] = &stringArgsCommand{
-		command:         command,
-		description:     description,
-		commandFunc:     commandFunc,
-		stringArgsFunc:  function.NewStringArgsFunc(commandFunc, resultsHandlers...),
-		resultsHandlers: resultsHandlers,
+	disp.comm[command] = newStringArgsCommand(disp.superCommand, command, description, commandFunc, resultsHandlers)
+	return nil
+}
+
+// AddHiddenCommand adds a command like AddCommand
+// that is excluded from PrintCommands, completion,
+// and suggestions but can still be dispatched.
+// Useful for internal or debug commands.
+func (disp *StringArgsDispatcher) AddHiddenCommand(command, description string, commandFunc function.Wrapper, resultsHandlers ...function.ResultsHandler) error {
+	err := disp.AddCommand(command, description, commandFunc, resultsHandlers...)
+	if err != nil {
+		return err
 	}
+	disp.comm[command].hidden = true
 	return nil
 }
 
+// MustAddHiddenCommand calls AddHiddenCommand and panics on error.
+func (disp *StringArgsDispatcher) MustAddHiddenCommand(command, description string, commandFunc function.Wrapper, resultsHandlers ...function.ResultsHandler) {
+	err := disp.AddHiddenCommand(command, description, commandFunc, resultsHandlers...)
+	if err != nil {
+		panic(err)
+	}
+}
+
 func (disp *StringArgsDispatcher) MustAddCommand(command, description string, commandFunc function.Wrapper, resultsHandlers ...function.ResultsHandler) {
 	err := disp.AddCommand(command, description, commandFunc, resultsHandlers...)
 	if err != nil {
@@ -82,13 +277,7 @@ func (disp *StringArgsDispatcher) MustAddCommand(command, description string, co
 }
 
 func (disp *StringArgsDispatcher) AddDefaultCommand(description string, commandFunc function.Wrapper, resultsHandlers ...function.ResultsHandler) error {
-	disp.comm[DefaultCommand] = &stringArgsCommand{
-		command:         DefaultCommand,
-		description:     description,
-		commandFunc:     commandFunc,
-		stringArgsFunc:  function.NewStringArgsFunc(commandFunc, resultsHandlers...),
-		resultsHandlers: resultsHandlers,
-	}
+	disp.comm[DefaultCommand] = newStringArgsCommand(disp.superCommand, DefaultCommand, description, commandFunc, resultsHandlers)
 	return nil
 }
 
@@ -113,15 +302,228 @@ func (disp *StringArgsDispatcher) Commands() []string {
 	return slices.Sorted(maps.Keys(disp.comm))
 }
 
-func (disp *StringArgsDispatcher) Dispatch(ctx context.Context, command string, args ...string) error {
+// EnableOutputFlag adds the global flag --output (see OutputFlag)
+// that selects the results handler of the called command
+// by an output format name like json, yaml, table, or plain.
+// The default handlers of the formats are defined in OutputFormatHandlers
+// and can be overridden per command with SetOutputFormatHandler.
+// Without the flag the results handlers passed to AddCommand are used.
+func (disp *StringArgsDispatcher) EnableOutputFlag() error {
+	return disp.cfg.enableOutputFlag()
+}
+
+// SetOutputFormatHandler sets the results handler of a command
+// for an output format selected with the --output flag.
+// See EnableOutputFlag
+func (disp *StringArgsDispatcher) SetOutputFormatHandler(command, format string, handler function.ResultsHandler) error {
 	cmd, found := disp.comm[command]
 	if !found {
 		return ErrCommandNotFound(command)
 	}
-	for _, logger := range disp.loggers {
-		logger.LogStringArgsCommand(command, args)
+	if cmd.outputHandlers == nil {
+		cmd.outputHandlers = make(map[string]function.ResultsHandler)
+	}
+	cmd.outputHandlers[format] = handler
+	return nil
+}
+
+// EnableStdinArg enables reading an argument value from stdin
+// if it is passed as "-" (see StdinArg) so that commands
+// can be used in shell pipelines like:
+//
+//	cat payload.json | myapp send -
+//
+// Only one argument value per command can be read from stdin.
+// If trimSpace is true, then leading and trailing
+// white space is removed from the read content.
+func (disp *StringArgsDispatcher) EnableStdinArg(trimSpace bool) {
+	disp.cfg.enableStdinArg(trimSpace)
+}
+
+// EnableFileArgs enables reading argument values from files
+// if they are passed as @path (see FileArgPrefix),
+// so that large JSON arguments can be passed as files like:
+//
+//	myapp import @customers.json
+//
+// A value beginning with @@ is passed with the first @ removed.
+func (disp *StringArgsDispatcher) EnableFileArgs() {
+	disp.cfg.fileArgs = true
+}
+
+// AddVersionCommand adds the command "version" (see VersionCommand)
+// and the global flag --version (see VersionFlag)
+// that print the version info of the program.
+// Empty values for version, commit, and date are read
+// from the build info embedded by the Go toolchain.
+// Use EnableOutputFlag to print the version info as JSON or YAML.
+func (disp *StringArgsDispatcher) AddVersionCommand(version, commit, date string) error {
+	info := NewVersionInfo(version, commit, date)
+	err := disp.AddCommand(VersionCommand, "Print version information", versionCommand(info), function.Println)
+	if err != nil {
+		return err
 	}
-	return cmd.stringArgsFunc(ctx, args...)
+	return disp.cfg.addVersionFlag(info)
+}
+
+// EnableTimeoutFlag adds the global flag --timeout (see TimeoutFlag)
+// that sets a deadline on the context passed to the command function.
+// The flag value is parsed with time.ParseDuration like 30s or 5m.
+// A defaultTimeout greater zero is used if the flag is not passed.
+func (disp *StringArgsDispatcher) EnableTimeoutFlag(defaultTimeout time.Duration) error {
+	return disp.cfg.enableTimeoutFlag(defaultTimeout)
+}
+
+// EnableProgress renders the progress reported by command functions
+// via the ProgressReporter returned by Progress as spinner or bar
+// on os.Stderr if it is a terminal.
+func (disp *StringArgsDispatcher) EnableProgress() {
+	disp.cfg.progress = true
+}
+
+// EnableDryRunFlag adds the global flag --dry-run (see DryRunFlag)
+// that marks the context passed to the command function
+// with function.ContextWithDryRun so commands can check
+// function.IsDryRun and skip making changes.
+// If validateOnly is true, then command functions are not called
+// for dry runs, instead only their arguments are validated.
+func (disp *StringArgsDispatcher) EnableDryRunFlag(validateOnly bool) error {
+	return disp.cfg.enableDryRunFlag(validateOnly)
+}
+
+// EnablePager pipes the output of commands written to
+// function.OutputFromContext by the results handlers
+// through the pager of the PAGER environment variable or DefaultPager
+// if os.Stdout is a terminal and the output has more lines
+// than the terminal. The output is buffered until it has more lines
+// than the terminal or the command returns.
+// Use Paged to page only the output of specific results handlers.
+func (disp *StringArgsDispatcher) EnablePager() {
+	disp.cfg.pager = true
+}
+
+// EnablePlugins enables git-style plugins:
+// If a command is not found, then an executable named like
+// the prefix and the command words joined with dashes,
+// like myapp-deploy for the command deploy,
+// is looked up on PATH and run with the remaining arguments.
+// An empty prefix uses the program name of os.Args[0].
+func (disp *StringArgsDispatcher) EnablePlugins(prefix string) {
+	disp.cfg.enablePlugins(prefix)
+}
+
+// DisableColor disables colored output of the dispatcher.
+// Colors are also disabled by the NO_COLOR environment variable,
+// if the output is not a terminal,
+// or with the global flag --no-color (see NoColorFlag).
+func (disp *StringArgsDispatcher) DisableColor() {
+	disp.cfg.noColor = true
+}
+
+// Use adds a hook that is called before every dispatched command,
+// for example to check authorization or to setup resources.
+// Hooks are called in the order they were added
+// after the command loggers.
+func (disp *StringArgsDispatcher) Use(before BeforeFunc) {
+	disp.cfg.before = append(disp.cfg.before, before)
+}
+
+// UseAfter adds a hook that is called after every dispatched command,
+// for example for telemetry or to teardown resources.
+// Hooks are called in reverse order they were added.
+func (disp *StringArgsDispatcher) UseAfter(after AfterFunc) {
+	disp.cfg.after = append(disp.cfg.after, after)
+}
+
+// AddGlobalFlag adds a flag that can be passed before the command
+// or between the arguments of any command like --verbose or --timeout=10s.
+// The flag name is used without the "--" prefix.
+// If defaultValue is a bool, then the flag can be passed without a value.
+// The value of the flag is available to commands via GlobalFlag(ctx, name)
+// and is passed to command function arguments with the same name
+// in camelCase, so --dry-run is bound to an argument dryRun.
+func (disp *StringArgsDispatcher) AddGlobalFlag(name, description string, defaultValue any) error {
+	return disp.cfg.addGlobalFlag(name, description, defaultValue)
+}
+
+// MustAddGlobalFlag calls AddGlobalFlag and panics on error.
+func (disp *StringArgsDispatcher) MustAddGlobalFlag(name, description string, defaultValue any) {
+	err := disp.AddGlobalFlag(name, description, defaultValue)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// DeprecateCommand marks a command as deprecated.
+// A warning with the message is printed to os.Stderr
+// every time the command is dispatched
+// and the command is marked as deprecated in the help output.
+// The message should tell the user what to use instead.
+func (disp *StringArgsDispatcher) DeprecateCommand(command, message string) error {
+	cmd, found := disp.comm[command]
+	if !found {
+		return ErrCommandNotFound(command)
+	}
+	cmd.deprecated = message
+	return nil
+}
+
+// visibleCommands returns the sorted commands
+// that are not hidden.
+func (disp *StringArgsDispatcher) visibleCommands() []string {
+	var commands []string
+	for _, command := range disp.Commands() {
+		if !disp.comm[command].hidden {
+			commands = append(commands, command)
+		}
+	}
+	return commands
+}
+
+func (disp *StringArgsDispatcher) Dispatch(ctx context.Context, command string, args ...string) error {
+	ctx, args, err := disp.cfg.withGlobalFlags(ctx, args)
+	if err != nil {
+		return err
+	}
+	if disp.cfg.printVersionIfRequested(ctx) {
+		return nil
+	}
+	ctx, cancel, err := disp.cfg.withTimeout(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	ctx = disp.cfg.withDryRun(ctx)
+	ctx, stopProgress := disp.cfg.withProgress(ctx)
+	defer stopProgress()
+	cmd, found := disp.comm[command]
+	if !found {
+		if command == HelpCommand || isHelpFlag(command) {
+			if len(args) == 0 {
+				disp.printCommands(ctx, appName())
+				return nil
+			}
+			return disp.printCommandHelp(ctx, appName(), args[0])
+		}
+		if found, err := disp.cfg.runPlugin(ctx, append(strings.Fields(disp.superCommand), command), args); found {
+			return err
+		}
+		return withSuggestions(ErrCommandNotFound(command), command, disp.visibleCommands())
+	}
+	if isHelpRequested(cmd.commandFunc, args) {
+		return disp.printCommandHelp(ctx, appName(), command)
+	}
+	if cmd.deprecated != "" {
+		cmd.printDeprecationWarning(ctx, disp.cfg, os.Stderr)
+	}
+	ctx, logEnd := disp.cfg.logCommand(ctx, cmd, args)
+	err = disp.cfg.runHooks(ctx, cmd.fullCommand, args, func(ctx context.Context) error {
+		return disp.cfg.paged(ctx, func(ctx context.Context) error {
+			return cmd.dispatch(ctx, disp.cfg, args)
+		})
+	})
+	logEnd(err)
+	return err
 }
 
 func (disp *StringArgsDispatcher) MustDispatch(ctx context.Context, command string, args ...string) {
@@ -146,6 +548,13 @@ func (disp *StringArgsDispatcher) DispatchCombinedCommandAndArgs(ctx context.Con
 	if len(commandAndArgs) == 0 {
 		return DefaultCommand, disp.DispatchDefaultCommand()
 	}
+	ctx, commandAndArgs, err = disp.cfg.withGlobalFlags(ctx, commandAndArgs)
+	if err != nil {
+		return "", err
+	}
+	if len(commandAndArgs) == 0 {
+		return DefaultCommand, disp.Dispatch(ctx, DefaultCommand)
+	}
 	command = commandAndArgs[0]
 	args := commandAndArgs[1:]
 	return command, disp.Dispatch(ctx, command, args...)
@@ -160,33 +569,75 @@ func (disp *StringArgsDispatcher) MustDispatchCombinedCommandAndArgs(ctx context
 }
 
 func (disp *StringArgsDispatcher) PrintCommands(appName string) {
+	disp.printCommands(context.Background(), appName)
+}
+
+// printCommands prints the usage of all not hidden commands
+// with colors as configured for the dispatched command of ctx.
+func (disp *StringArgsDispatcher) printCommands(ctx context.Context, appName string) {
 	list := make([]*stringArgsCommand, 0, len(disp.comm))
 	for _, cmd := range disp.comm {
-		list = append(list, cmd)
+		if !cmd.hidden {
+			list = append(list, cmd)
+		}
 	}
 	sort.Slice(list, func(i, j int) bool {
 		return list[i].command < list[j].command
 	})
 
 	for _, cmd := range list {
-		UsageColor.Printf("  %s %s %s\n", appName, cmd.command, functionArgsString(cmd.commandFunc))
-		if cmd.description != "" {
-			DescriptionColor.Printf("      %s\n", cmd.description)
-		}
-		hasAnyArgDesc := false
-		for _, desc := range cmd.commandFunc.ArgDescriptions() {
-			if desc != "" {
-				hasAnyArgDesc = true
-				break
-			}
+		printCommandUsage(ctx, disp.cfg, appName, cmd.command, cmd)
+	}
+}
+
+// printCommandUsage prints the usage line of cmd with its description
+// and argument descriptions to os.Stdout as used by PrintCommands.
+func printCommandUsage(ctx context.Context, cfg *dispatcherConfig, appName, command string, cmd *stringArgsCommand) {
+	usageColor := cfg.color(ctx, UsageColor, os.Stdout)
+	descriptionColor := cfg.color(ctx, DescriptionColor, os.Stdout)
+	deprecatedColor := cfg.color(ctx, DeprecatedColor, os.Stdout)
+
+	usageColor.Printf("  %s %s %s\n", appName, command, functionArgsString(cmd.commandFunc, cmd.argDefaults))
+	if cmd.description != "" {
+		descriptionColor.Printf("      %s\n", cmd.description)
+	}
+	if cmd.deprecated != "" {
+		deprecatedColor.Printf("      Deprecated: %s\n", cmd.deprecated)
+	}
+	hasAnyArgDesc := false
+	for _, desc := range cmd.commandFunc.ArgDescriptions() {
+		if desc != "" {
+			hasAnyArgDesc = true
+			break
 		}
-		if hasAnyArgDesc {
-			for i, desc := range cmd.commandFunc.ArgDescriptions() {
-				DescriptionColor.Printf("          <%s:%s> %s\n", cmd.commandFunc.ArgNames()[i], derefType(cmd.commandFunc.ArgTypes()[i]), desc)
-			}
+	}
+	if hasAnyArgDesc {
+		for i, desc := range cmd.commandFunc.ArgDescriptions() {
+			descriptionColor.Printf("          <%s:%s> %s\n", cmd.commandFunc.ArgNames()[i], derefType(cmd.commandFunc.ArgTypes()[i]), desc)
 		}
-		DescriptionColor.Println()
 	}
+	descriptionColor.Println()
+}
+
+// PrintCommandHelp prints the usage, description,
+// and arguments of a single command to os.Stdout.
+// Returns ErrCommandNotFound if there is no such command.
+func (disp *StringArgsDispatcher) PrintCommandHelp(appName, command string) error {
+	return disp.printCommandHelp(context.Background(), appName, command)
+}
+
+// printCommandHelp prints the help of command with colors
+// as configured for the dispatched command of ctx.
+func (disp *StringArgsDispatcher) printCommandHelp(ctx context.Context, appName, command string) error {
+	cmd, found := disp.comm[command]
+	if !found {
+		return withSuggestions(ErrCommandNotFound(command), command, disp.visibleCommands())
+	}
+	err := cmd.printHelp(ctx, disp.cfg, os.Stdout, appName)
+	if err != nil {
+		return err
+	}
+	return disp.cfg.printGlobalFlags(os.Stdout)
 }
 
 func (disp *StringArgsDispatcher) PrintCommandsUsageIntro(appName string, output io.Writer) {
@@ -197,7 +648,7 @@ func (disp *StringArgsDispatcher) PrintCommandsUsageIntro(appName string, output
 	}
 }
 
-func functionArgsString(f function.Wrapper) string {
+func functionArgsString(f function.Wrapper, argDefaults map[string]string) string {
 	b := strings.Builder{}
 	argNames := f.ArgNames()
 	argTypes := f.ArgTypes()
@@ -205,7 +656,11 @@ func functionArgsString(f function.Wrapper) string {
 		if i > 0 {
 			b.WriteByte(' ')
 		}
-		fmt.Fprintf(&b, "<%s:%s>", argNames[i], derefType(argTypes[i]))
+		if value, ok := argDefaults[argNames[i]]; ok {
+			fmt.Fprintf(&b, "[%s:%s=%s]", argNames[i], derefType(argTypes[i]), value)
+		} else {
+			fmt.Fprintf(&b, "<%s:%s>", argNames[i], derefType(argTypes[i]))
+		}
 	}
 	return b.String()
 }
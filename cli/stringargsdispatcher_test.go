@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/domonda/go-function"
+)
+
+func TestStringArgsDispatcher_CallInfo(t *testing.T) {
+	var got []function.CallInfo
+	handler := function.ResultsHandlerFunc(func(ctx context.Context, results []any, resultErr error) error {
+		if info := function.CallInfoFromContext(ctx); info != nil {
+			got = append(got, *info)
+		}
+		return resultErr
+	})
+	disp := NewStringArgsDispatcher()
+	disp.MustAddCommand("greet", "", function.MustReflectWrapper(
+		func(name, greeting string) string { return greeting + " " + name },
+		"name", "greeting",
+	).WithName("Greet"), handler)
+	err := disp.Dispatch(context.Background(), "greet", "World", "Hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = disp.Dispatch(context.Background(), "greet", "--greeting=Hi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []function.CallInfo{
+		{Name: "Greet", Command: "greet", Args: []string{"World", "Hello"}},
+		{Name: "Greet", Command: "greet", NamedArgs: map[string]string{"greeting": "Hi"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got CallInfo %#v, want %#v", got, want)
+	}
+}
+
+func TestStringArgsDispatcher_CallEvents(t *testing.T) {
+	var events []function.CallEvent
+	remove := function.AddCallEventSink(function.CallEventSinkFunc(func(ctx context.Context, event *function.CallEvent) {
+		events = append(events, *event)
+	}))
+	defer remove()
+
+	disp := NewStringArgsDispatcher()
+	disp.MustAddCommand("fail", "", function.MustReflectWrapper(
+		func() error { return errors.New("failed") },
+	).WithName("Fail"))
+	err := disp.Dispatch(context.Background(), "fail")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	for i, wantType := range []function.CallEventType{function.CallStarted, function.CallFailed} {
+		event := events[i]
+		if event.Type != wantType || event.Func != "Fail" || event.Transport != function.TransportCLI || event.Command != "fail" {
+			t.Errorf("unexpected event %d: %#v", i, event)
+		}
+	}
+}
+
+func TestStringArgsDispatcher_MustAddHiddenCommand(t *testing.T) {
+	var called bool
+	disp := NewStringArgsDispatcher()
+	disp.MustAddCommand("deploy", "", function.MustReflectWrapper(func() {}))
+	disp.MustAddHiddenCommand("debug", "", function.MustReflectWrapper(func() { called = true }))
+
+	if got := disp.visibleCommands(); !slices.Equal(got, []string{"deploy"}) {
+		t.Errorf("visibleCommands() = %v", got)
+	}
+	script, err := disp.GenCompletionScript("bash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(script, "debug") {
+		t.Errorf("completion script contains hidden command:\n%s", script)
+	}
+	err = disp.Dispatch(context.Background(), "debug")
+	if err != nil || !called {
+		t.Errorf("hidden command not dispatched, error: %v", err)
+	}
+}
+
+func TestStringArgsDispatcher_DeprecateCommand(t *testing.T) {
+	disp := NewStringArgsDispatcher()
+	disp.MustAddCommand("deploy", "", function.MustReflectWrapper(func() {}))
+	if err := disp.DeprecateCommand("unknown", "use deploy"); !IsErrCommandNotFound(err) {
+		t.Errorf("expected ErrCommandNotFound, got %v", err)
+	}
+	if err := disp.DeprecateCommand("deploy", "use release instead"); err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	err := disp.comm["deploy"].printHelp(context.Background(), disp.cfg, &b, "myapp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "Deprecated: use release instead") {
+		t.Errorf("help output does not contain deprecation notice:\n%s", b.String())
+	}
+}
+
+func TestStringArgsDispatcher_AddGlobalFlag(t *testing.T) {
+	var (
+		gotEnv     string
+		gotDryRun  bool
+		gotVerbose bool
+		gotFormat  string
+	)
+	disp := NewStringArgsDispatcher()
+	disp.MustAddGlobalFlag("verbose", "Verbose output", false)
+	disp.MustAddGlobalFlag("dry-run", "Don't change anything", false)
+	disp.MustAddGlobalFlag("format", "Output format", "text")
+	disp.MustAddCommand("deploy", "", function.MustReflectWrapper(
+		func(ctx context.Context, env string, dryRun bool) {
+			gotEnv, gotDryRun = env, dryRun
+			gotVerbose = GlobalFlagBool(ctx, "verbose")
+			gotFormat, _ = GlobalFlag(ctx, "format")
+		},
+		"ctx", "env", "dryRun",
+	))
+	if err := disp.AddGlobalFlag("verbose", "", false); err == nil {
+		t.Error("expected error for duplicate global flag")
+	}
+
+	_, err := disp.DispatchCombinedCommandAndArgs(context.Background(), []string{"--verbose", "deploy", "prod", "--dry-run"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotEnv != "prod" || !gotDryRun || !gotVerbose || gotFormat != "text" {
+		t.Errorf("got env=%q dryRun=%t verbose=%t format=%q", gotEnv, gotDryRun, gotVerbose, gotFormat)
+	}
+
+	err = disp.Dispatch(context.Background(), "deploy", "--format", "json", "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotEnv != "dev" || gotDryRun || gotVerbose || gotFormat != "json" {
+		t.Errorf("got env=%q dryRun=%t verbose=%t format=%q", gotEnv, gotDryRun, gotVerbose, gotFormat)
+	}
+}
+
+func TestStringArgsDispatcher_Use(t *testing.T) {
+	type ctxKey struct{}
+	var calls []string
+	disp := NewStringArgsDispatcher()
+	disp.MustAddCommand("cmd", "", function.MustReflectWrapper(func(ctx context.Context) {
+		calls = append(calls, "cmd:"+ctx.Value(ctxKey{}).(string))
+	}))
+	disp.Use(func(ctx context.Context, command string, args []string) (context.Context, error) {
+		calls = append(calls, "before:"+command)
+		return context.WithValue(ctx, ctxKey{}, "value"), nil
+	})
+	disp.UseAfter(func(ctx context.Context, command string, args []string, cmdErr error) error {
+		calls = append(calls, "after:"+command)
+		return cmdErr
+	})
+
+	err := disp.Dispatch(context.Background(), "cmd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"before:cmd", "cmd:value", "after:cmd"}; !slices.Equal(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+
+	calls = nil
+	disp.Use(func(ctx context.Context, command string, args []string) (context.Context, error) {
+		return ctx, errors.New("not authorized")
+	})
+	err = disp.Dispatch(context.Background(), "cmd")
+	if err == nil || err.Error() != "not authorized" {
+		t.Errorf("expected error from before hook, got %v", err)
+	}
+	if want := []string{"before:cmd"}; !slices.Equal(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
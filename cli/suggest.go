@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"slices"
+	"strings"
+)
+
+// suggestCommands returns the commands that are similar to command
+// by having command as prefix or a small edit distance to it,
+// sorted by similarity.
+func suggestCommands(command string, commands []string) []string {
+	if command == "" {
+		return nil
+	}
+	type suggestion struct {
+		command  string
+		distance int
+	}
+	var (
+		maxDistance = max(2, len(command)/3)
+		suggestions []suggestion
+	)
+	for _, c := range commands {
+		if c == "" || c == command {
+			continue
+		}
+		distance := editDistance(strings.ToLower(command), strings.ToLower(c))
+		if strings.HasPrefix(c, command) {
+			distance = 0
+		}
+		if distance <= maxDistance {
+			suggestions = append(suggestions, suggestion{command: c, distance: distance})
+		}
+	}
+	slices.SortStableFunc(suggestions, func(a, b suggestion) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+		return strings.Compare(a.command, b.command)
+	})
+	result := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		result[i] = s.command
+	}
+	return result
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
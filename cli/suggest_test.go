@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/domonda/go-function"
+)
+
+func TestStringArgsDispatcher_Dispatch_suggestions(t *testing.T) {
+	disp := NewStringArgsDispatcher()
+	for _, command := range []string{"deploy", "destroy", "status"} {
+		disp.MustAddCommand(command, "", function.MustReflectWrapper(func() {}))
+	}
+	tests := map[string][]string{
+		"deplyo": {"deploy"},
+		"de":     {"deploy", "destroy"},
+		"stats":  {"status"},
+		"xyz":    nil,
+	}
+	for command, want := range tests {
+		t.Run(command, func(t *testing.T) {
+			err := disp.Dispatch(context.Background(), command)
+			if !IsErrCommandNotFound(err) {
+				t.Fatalf("expected ErrCommandNotFound, got %v", err)
+			}
+			if got := Suggestions(err); !slices.Equal(got, want) {
+				t.Errorf("Suggestions() = %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -5,24 +5,195 @@ import (
 	"fmt"
 	"io"
 	"maps"
+	"os"
 	"slices"
 	"sort"
+	"time"
 
 	"github.com/domonda/go-function"
 )
 
 type SuperStringArgsDispatcher struct {
-	sub     map[string]*StringArgsDispatcher
-	loggers []StringArgsCommandLogger
+	sub map[string]*StringArgsDispatcher
+	// cfg is shared with all sub command dispatchers
+	cfg *dispatcherConfig
 }
 
 func NewSuperStringArgsDispatcher(loggers ...StringArgsCommandLogger) *SuperStringArgsDispatcher {
 	return &SuperStringArgsDispatcher{
-		sub:     make(map[string]*StringArgsDispatcher),
-		loggers: loggers,
+		sub: make(map[string]*StringArgsDispatcher),
+		cfg: newDispatcherConfig(loggers),
 	}
 }
 
+// SetArgEnvVar sets the environment variable envVar
+// as source for the argument argName of all commands
+// when the argument is not passed on the command line.
+func (disp *SuperStringArgsDispatcher) SetArgEnvVar(argName, envVar string) {
+	disp.cfg.setArgEnvVar(argName, envVar)
+}
+
+// SetEnvVarPrefix enables environment variables as source
+// for all arguments not passed on the command line.
+// See StringArgsDispatcher.SetEnvVarPrefix
+func (disp *SuperStringArgsDispatcher) SetEnvVarPrefix(prefix string) {
+	disp.cfg.envVarPrefix = prefix
+}
+
+// EnablePrompting enables interactive prompting on os.Stdin
+// for required arguments that were not passed.
+// See StringArgsDispatcher.EnablePrompting
+func (disp *SuperStringArgsDispatcher) EnablePrompting() {
+	disp.cfg.enablePrompting(os.Stdin, os.Stderr)
+}
+
+// SetArgSensitive marks the argument argName as sensitive
+// so that its prompted input is not echoed to the terminal.
+// Has only an effect if prompting is enabled.
+func (disp *SuperStringArgsDispatcher) SetArgSensitive(argName string) {
+	disp.cfg.setArgSensitive(argName)
+}
+
+// WithConfigFile sets a YAML or JSON file that maps commands
+// to default values for their arguments.
+// Commands are keyed with the super command and command
+// separated by a space like "db migrate".
+// See StringArgsDispatcher.WithConfigFile
+func (disp *SuperStringArgsDispatcher) WithConfigFile(path string) *SuperStringArgsDispatcher {
+	disp.cfg.configFile = configFileArgSource(path)
+	return disp
+}
+
+// EnableOutputFlag adds the global flag --output
+// that selects the results handler of the called command.
+// See StringArgsDispatcher.EnableOutputFlag
+func (disp *SuperStringArgsDispatcher) EnableOutputFlag() error {
+	return disp.cfg.enableOutputFlag()
+}
+
+// SetOutputFormatHandler sets the results handler of a command
+// of a super command for an output format selected with the --output flag.
+// See StringArgsDispatcher.SetOutputFormatHandler
+func (disp *SuperStringArgsDispatcher) SetOutputFormatHandler(superCommand, command, format string, handler function.ResultsHandler) error {
+	sub, ok := disp.sub[superCommand]
+	if !ok {
+		return ErrSuperCommandNotFound(superCommand)
+	}
+	return sub.SetOutputFormatHandler(command, format, handler)
+}
+
+// EnableStdinArg enables reading an argument value
+// from stdin if it is passed as "-".
+// See StringArgsDispatcher.EnableStdinArg
+func (disp *SuperStringArgsDispatcher) EnableStdinArg(trimSpace bool) {
+	disp.cfg.enableStdinArg(trimSpace)
+}
+
+// EnableFileArgs enables reading argument values
+// from files if they are passed as @path.
+// See StringArgsDispatcher.EnableFileArgs
+func (disp *SuperStringArgsDispatcher) EnableFileArgs() {
+	disp.cfg.fileArgs = true
+}
+
+// AddVersionCommand adds the super command "version"
+// and the global flag --version that print
+// the version info of the program.
+// See StringArgsDispatcher.AddVersionCommand
+func (disp *SuperStringArgsDispatcher) AddVersionCommand(version, commit, date string) error {
+	sub, err := disp.AddSuperCommand(VersionCommand)
+	if err != nil {
+		return err
+	}
+	info := NewVersionInfo(version, commit, date)
+	err = sub.AddDefaultCommand("Print version information", versionCommand(info), function.Println)
+	if err != nil {
+		return err
+	}
+	return disp.cfg.addVersionFlag(info)
+}
+
+// EnableTimeoutFlag adds the global flag --timeout
+// that sets a deadline on the context passed to the command function.
+// See StringArgsDispatcher.EnableTimeoutFlag
+func (disp *SuperStringArgsDispatcher) EnableTimeoutFlag(defaultTimeout time.Duration) error {
+	return disp.cfg.enableTimeoutFlag(defaultTimeout)
+}
+
+// EnableProgress renders the progress reported by command functions
+// on os.Stderr if it is a terminal.
+// See StringArgsDispatcher.EnableProgress
+func (disp *SuperStringArgsDispatcher) EnableProgress() {
+	disp.cfg.progress = true
+}
+
+// EnableDryRunFlag adds the global flag --dry-run
+// that marks the context passed to the command function
+// with function.ContextWithDryRun.
+// See StringArgsDispatcher.EnableDryRunFlag
+func (disp *SuperStringArgsDispatcher) EnableDryRunFlag(validateOnly bool) error {
+	return disp.cfg.enableDryRunFlag(validateOnly)
+}
+
+// EnablePager pipes the output of commands written to
+// function.OutputFromContext through a pager if it is longer than the terminal height.
+// See StringArgsDispatcher.EnablePager
+func (disp *SuperStringArgsDispatcher) EnablePager() {
+	disp.cfg.pager = true
+}
+
+// EnablePlugins enables git-style plugin executables
+// for commands that are not found.
+// See StringArgsDispatcher.EnablePlugins
+func (disp *SuperStringArgsDispatcher) EnablePlugins(prefix string) {
+	disp.cfg.enablePlugins(prefix)
+}
+
+// DisableColor disables colored output of the dispatcher.
+// See StringArgsDispatcher.DisableColor
+func (disp *SuperStringArgsDispatcher) DisableColor() {
+	disp.cfg.noColor = true
+}
+
+// Use adds a hook that is called before every dispatched command.
+// See StringArgsDispatcher.Use
+func (disp *SuperStringArgsDispatcher) Use(before BeforeFunc) {
+	disp.cfg.before = append(disp.cfg.before, before)
+}
+
+// UseAfter adds a hook that is called after every dispatched command.
+// See StringArgsDispatcher.UseAfter
+func (disp *SuperStringArgsDispatcher) UseAfter(after AfterFunc) {
+	disp.cfg.after = append(disp.cfg.after, after)
+}
+
+// AddGlobalFlag adds a flag that can be passed
+// before the super command or between the arguments of any command.
+// See StringArgsDispatcher.AddGlobalFlag
+func (disp *SuperStringArgsDispatcher) AddGlobalFlag(name, description string, defaultValue any) error {
+	return disp.cfg.addGlobalFlag(name, description, defaultValue)
+}
+
+// MustAddGlobalFlag calls AddGlobalFlag and panics on error.
+func (disp *SuperStringArgsDispatcher) MustAddGlobalFlag(name, description string, defaultValue any) {
+	err := disp.AddGlobalFlag(name, description, defaultValue)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// DeprecateCommand marks a command of a super command as deprecated.
+// Pass DefaultCommand as command to deprecate the
+// default command of the super command.
+// See StringArgsDispatcher.DeprecateCommand
+func (disp *SuperStringArgsDispatcher) DeprecateCommand(superCommand, command, message string) error {
+	sub, ok := disp.sub[superCommand]
+	if !ok {
+		return ErrSuperCommandNotFound(superCommand)
+	}
+	return sub.DeprecateCommand(command, message)
+}
+
 func (disp *SuperStringArgsDispatcher) AddSuperCommand(superCommand string) (subDisp *StringArgsDispatcher, err error) {
 	if superCommand != "" {
 		if err := checkCommandChars(superCommand); err != nil {
@@ -32,7 +203,7 @@ func (disp *SuperStringArgsDispatcher) AddSuperCommand(superCommand string) (sub
 	if _, exists := disp.sub[superCommand]; exists {
 		return nil, fmt.Errorf("super command already added: '%s'", superCommand)
 	}
-	subDisp = NewStringArgsDispatcher(disp.loggers...)
+	subDisp = newStringArgsDispatcher(superCommand, disp.cfg)
 	disp.sub[superCommand] = subDisp
 	return subDisp, nil
 }
@@ -90,7 +261,14 @@ func (disp *SuperStringArgsDispatcher) SubCommands(superCommand string) []string
 func (disp *SuperStringArgsDispatcher) Dispatch(ctx context.Context, superCommand, command string, args ...string) error {
 	sub, ok := disp.sub[superCommand]
 	if !ok {
-		return ErrSuperCommandNotFound(superCommand)
+		pluginArgs := args
+		if command != DefaultCommand {
+			pluginArgs = append([]string{command}, args...)
+		}
+		if found, err := disp.cfg.runPlugin(ctx, []string{superCommand}, pluginArgs); found {
+			return err
+		}
+		return withSuggestions(ErrSuperCommandNotFound(superCommand), superCommand, disp.Commands())
 	}
 	return sub.Dispatch(ctx, command, args...)
 }
@@ -114,6 +292,18 @@ func (disp *SuperStringArgsDispatcher) MustDispatchDefaultCommand() {
 }
 
 func (disp *SuperStringArgsDispatcher) DispatchCombinedCommandAndArgs(ctx context.Context, commandAndArgs []string) (superCommand, command string, err error) {
+	ctx, commandAndArgs, err = disp.cfg.withGlobalFlags(ctx, commandAndArgs)
+	if err != nil {
+		return "", "", err
+	}
+	if disp.cfg.printVersionIfRequested(ctx) {
+		return VersionCommand, DefaultCommand, nil
+	}
+	if len(commandAndArgs) > 0 {
+		if _, ok := disp.sub[commandAndArgs[0]]; !ok && (commandAndArgs[0] == HelpCommand || isHelpFlag(commandAndArgs[0])) {
+			return commandAndArgs[0], DefaultCommand, disp.printHelp(ctx, commandAndArgs[1:])
+		}
+	}
 	var args []string
 	switch len(commandAndArgs) {
 	case 0:
@@ -131,6 +321,9 @@ func (disp *SuperStringArgsDispatcher) DispatchCombinedCommandAndArgs(ctx contex
 		} else {
 			command = commandAndArgs[1]
 			args = commandAndArgs[2:]
+			if ok && isHelpFlag(command) && !sub.HasCommnd(command) {
+				return superCommand, command, disp.printCommandHelp(ctx, appName(), superCommand, "")
+			}
 		}
 	}
 	return superCommand, command, disp.Dispatch(ctx, superCommand, command, args...)
@@ -145,6 +338,10 @@ func (disp *SuperStringArgsDispatcher) MustDispatchCombinedCommandAndArgs(ctx co
 }
 
 func (disp *SuperStringArgsDispatcher) PrintCommands(appName string) {
+	disp.printCommands(context.Background(), appName)
+}
+
+func (disp *SuperStringArgsDispatcher) printCommands(ctx context.Context, appName string) {
 	type superCmd struct {
 		super string
 		cmd   *stringArgsCommand
@@ -153,7 +350,9 @@ func (disp *SuperStringArgsDispatcher) PrintCommands(appName string) {
 	var list []superCmd
 	for super, sub := range disp.sub {
 		for _, cmd := range sub.comm {
-			list = append(list, superCmd{super: super, cmd: cmd})
+			if !cmd.hidden {
+				list = append(list, superCmd{super: super, cmd: cmd})
+			}
 		}
 	}
 	sort.Slice(list, func(i, j int) bool {
@@ -169,25 +368,57 @@ func (disp *SuperStringArgsDispatcher) PrintCommands(appName string) {
 		if cmd.command != DefaultCommand {
 			command += " " + cmd.command
 		}
+		printCommandUsage(ctx, disp.cfg, appName, command, cmd)
+	}
+}
 
-		UsageColor.Printf("  %s %s %s\n", appName, command, functionArgsString(cmd.commandFunc))
-		if cmd.description != "" {
-			DescriptionColor.Printf("      %s\n", cmd.description)
-		}
-		hasAnyArgDesc := false
-		for _, desc := range cmd.commandFunc.ArgDescriptions() {
-			if desc != "" {
-				hasAnyArgDesc = true
-				break
-			}
+// printHelp prints the help for the super command
+// and optional command passed as superCommandAndCommand
+// or all commands if superCommandAndCommand is empty.
+func (disp *SuperStringArgsDispatcher) printHelp(ctx context.Context, superCommandAndCommand []string) error {
+	switch len(superCommandAndCommand) {
+	case 0:
+		disp.printCommands(ctx, appName())
+		return nil
+	case 1:
+		return disp.printCommandHelp(ctx, appName(), superCommandAndCommand[0], "")
+	default:
+		return disp.printCommandHelp(ctx, appName(), superCommandAndCommand[0], superCommandAndCommand[1])
+	}
+}
+
+// PrintCommandHelp prints the usage, description,
+// and arguments of a single command to os.Stdout.
+// If command is empty, then the help of the default command
+// of the super command is printed, or if it has no default command,
+// the help of all its commands.
+// Returns ErrSuperCommandNotFound or ErrCommandNotFound
+// if there is no such command.
+func (disp *SuperStringArgsDispatcher) PrintCommandHelp(appName, superCommand, command string) error {
+	return disp.printCommandHelp(context.Background(), appName, superCommand, command)
+}
+
+func (disp *SuperStringArgsDispatcher) printCommandHelp(ctx context.Context, appName, superCommand, command string) error {
+	sub, ok := disp.sub[superCommand]
+	if !ok {
+		return withSuggestions(ErrSuperCommandNotFound(superCommand), superCommand, disp.Commands())
+	}
+	if command != "" {
+		return sub.printCommandHelp(ctx, appName, command)
+	}
+	if sub.HasDefaultCommnd() {
+		return sub.printCommandHelp(ctx, appName, DefaultCommand)
+	}
+	for i, command := range sub.visibleCommands() {
+		if i > 0 {
+			fmt.Println()
 		}
-		if hasAnyArgDesc {
-			for i, desc := range cmd.commandFunc.ArgDescriptions() {
-				DescriptionColor.Printf("          <%s:%s> %s\n", cmd.commandFunc.ArgNames()[i], derefType(cmd.commandFunc.ArgTypes()[i]), desc)
-			}
+		err := sub.comm[command].printHelp(ctx, disp.cfg, os.Stdout, appName)
+		if err != nil {
+			return err
 		}
-		DescriptionColor.Println()
 	}
+	return disp.cfg.printGlobalFlags(os.Stdout)
 }
 
 func (disp *SuperStringArgsDispatcher) PrintCommandsUsageIntro(appName string, output io.Writer) {
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TimeoutFlag is the name of the global flag
+// added by EnableTimeoutFlag.
+const TimeoutFlag = "timeout"
+
+func (cfg *dispatcherConfig) enableTimeoutFlag(defaultTimeout time.Duration) error {
+	var defaultValue any
+	if defaultTimeout > 0 {
+		defaultValue = defaultTimeout
+	}
+	err := cfg.addGlobalFlag(TimeoutFlag, "Timeout of the command like 30s or 5m", defaultValue)
+	if err != nil {
+		return err
+	}
+	cfg.timeoutFlag = true
+	return nil
+}
+
+// withTimeout returns ctx with the deadline of the
+// timeout passed with TimeoutFlag if the flag is enabled.
+// The returned cancel function must always be called.
+func (cfg *dispatcherConfig) withTimeout(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	if !cfg.timeoutFlag {
+		return ctx, func() {}, nil
+	}
+	value, _ := GlobalFlag(ctx, TimeoutFlag)
+	if value == "" {
+		return ctx, func() {}, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return ctx, func() {}, fmt.Errorf("invalid value for flag --%s: %w", TimeoutFlag, err)
+	}
+	if timeout <= 0 {
+		return ctx, func() {}, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, cancel, nil
+}
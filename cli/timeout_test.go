@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/domonda/go-function"
+)
+
+func TestStringArgsDispatcher_EnableTimeoutFlag(t *testing.T) {
+	var deadline time.Duration
+	disp := NewStringArgsDispatcher()
+	disp.MustAddCommand("cmd", "", function.MustReflectWrapper(func(ctx context.Context) {
+		if d, ok := ctx.Deadline(); ok {
+			deadline = time.Until(d)
+		}
+	}))
+	if err := disp.EnableTimeoutFlag(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		args []string
+		want time.Duration
+	}{
+		{args: nil, want: time.Hour},
+		{args: []string{"--timeout=1m"}, want: time.Minute},
+		{args: []string{"--timeout", "0"}, want: 0},
+	} {
+		deadline = 0
+		err := disp.Dispatch(context.Background(), "cmd", tt.args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if deadline > tt.want || deadline < tt.want-time.Second {
+			t.Errorf("deadline in %s with args %v, want %s", deadline, tt.args, tt.want)
+		}
+	}
+	if err := disp.Dispatch(context.Background(), "cmd", "--timeout=soon"); err == nil {
+		t.Error("expected error for invalid timeout")
+	}
+}
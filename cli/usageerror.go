@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/domonda/go-function"
+)
+
+const (
+	// ExitCodeError is the exit code used by ExitOnError
+	// for errors returned by commands.
+	ExitCodeError = 1
+
+	// ExitCodeUsage is the exit code used by ExitOnError
+	// for wrong command line usage like unknown commands
+	// or arguments that can't be parsed.
+	ExitCodeUsage = 2
+)
+
+// UsageError is returned by dispatchers when the
+// command line arguments could not be parsed
+// for the arguments of the command function.
+type UsageError struct {
+	Err error
+	// Usage line of the command
+	Usage string
+	// Name, type, description, and accepted format
+	// of the failing argument.
+	// Empty if the error is not specific to an argument.
+	ArgName        string
+	ArgType        string
+	ArgDescription string
+	ArgFormat      string
+}
+
+func (e *UsageError) Error() string {
+	if e.ArgName == "" {
+		return e.Err.Error()
+	}
+	expected := fmt.Sprintf("<%s:%s>", e.ArgName, e.ArgType)
+	if e.ArgFormat != "" {
+		expected += " " + e.ArgFormat
+	}
+	return fmt.Sprintf("%s (expected %s)", e.Err, expected)
+}
+
+func (e *UsageError) Unwrap() error {
+	return e.Err
+}
+
+// WriteDetails writes the usage line and the failing
+// argument to w as used by ExitOnError.
+func (e *UsageError) WriteDetails(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "Usage:\n  %s\n", e.Usage)
+	if err != nil || e.ArgName == "" {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "Argument:\n  <%s:%s> %s\n", e.ArgName, e.ArgType, e.ArgDescription)
+	if err != nil || e.ArgFormat == "" {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "Format:\n  %s\n", e.ArgFormat)
+	return err
+}
+
+// newUsageError returns a UsageError for cmd with err
+// that contains the details of the failing argument
+// if err wraps a function.ErrParseArgString.
+func (cmd *stringArgsCommand) newUsageError(err error) error {
+	usageErr := &UsageError{
+		Err:   err,
+		Usage: cmd.usage(appName()),
+	}
+	var parseErr function.ErrParseArgString
+	if errors.As(err, &parseErr) {
+		f := cmd.commandFunc
+		for i, argName := range f.ArgNames() {
+			if argName != parseErr.Arg {
+				continue
+			}
+			usageErr.ArgName = argName
+			usageErr.ArgType = derefType(f.ArgTypes()[i]).String()
+			usageErr.ArgFormat = argFormat(f.ArgTypes()[i])
+			if values, ok := cmd.argValues[argName]; ok {
+				usageErr.ArgFormat = "one of " + strings.Join(values, ", ")
+			}
+			if descriptions := f.ArgDescriptions(); i < len(descriptions) {
+				usageErr.ArgDescription = descriptions[i]
+			}
+			break
+		}
+	}
+	return usageErr
+}
+
+// argFormat returns a description of the string format
+// accepted for arguments of type t with examples
+// or an empty string if t has no well known format.
+func argFormat(t reflect.Type) string {
+	t = derefType(t)
+	if values := argTypeValues(t); len(values) > 0 {
+		return "one of " + strings.Join(values, ", ")
+	}
+	switch t {
+	case reflect.TypeOf(time.Duration(0)):
+		return "duration like 90s, 5m, or 1h30m"
+	case reflect.TypeOf(time.Time{}):
+		return "date like 2024-12-31, date and time like 2024-12-31 23:59:59, or RFC 3339 like 2024-12-31T23:59:59Z"
+	}
+	if t.Implements(typeOfTextUnmarshaler) || reflect.PointerTo(t).Implements(typeOfTextUnmarshaler) {
+		return ""
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "integer like 42 or -1"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "unsigned integer like 42"
+	case reflect.Float32, reflect.Float64:
+		return "number like 3.14"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "" // bytes are passed as string
+		}
+		format := "list like [a,b,c] or a single element"
+		if elemFormat := argFormat(t.Elem()); elemFormat != "" {
+			format += " of " + elemFormat
+		}
+		return format
+	case reflect.Struct, reflect.Map:
+		return `JSON object like {"key":"value"}`
+	}
+	return ""
+}
+
+var typeOfTextUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// wrapParseArgError returns a UsageError for err
+// if it wraps a function.ErrParseArgString,
+// else err unchanged.
+func (cmd *stringArgsCommand) wrapParseArgError(err error) error {
+	if !errors.As(err, new(function.ErrParseArgString)) {
+		return err
+	}
+	return cmd.newUsageError(fmt.Errorf("command '%s': %w", cmd.command, err))
+}
+
+// ExitCode returns the process exit code for err:
+// 0 for nil, the exit code of a failed plugin or other
+// exec.ExitError, ExitCodeUsage for a UsageError or
+// a command not found error, else ExitCodeError.
+func ExitCode(err error) int {
+	var (
+		usageErr *UsageError
+		exitErr  *exec.ExitError
+	)
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitErr) && exitErr.ExitCode() > 0:
+		return exitErr.ExitCode()
+	case errors.As(err, &usageErr) || IsErrCommandNotFound(err):
+		return ExitCodeUsage
+	default:
+		return ExitCodeError
+	}
+}
+
+// ExitOnError does nothing if err is nil,
+// else it prints the error to os.Stderr
+// and exits the process with ExitCode(err).
+// For a UsageError the usage line of the command
+// and the failing argument are printed as well.
+func ExitOnError(err error) {
+	if err == nil {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "Error:", err) //#nosec G104 -- exiting anyway
+	var usageErr *UsageError
+	if errors.As(err, &usageErr) {
+		usageErr.WriteDetails(os.Stderr) //#nosec G104 -- exiting anyway
+	}
+	os.Exit(ExitCode(err))
+}
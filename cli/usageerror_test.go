@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/domonda/go-function"
+)
+
+func TestStringArgsDispatcher_Dispatch_usageError(t *testing.T) {
+	disp := NewStringArgsDispatcher()
+	disp.MustAddCommand("search", "", function.MustReflectWrapper(
+		func(query string, limit int) {},
+		"query", "limit",
+	))
+	for _, args := range [][]string{
+		{"go", "many"},
+		{"go", "--limit=many"},
+		{"go", "--unknown"},
+	} {
+		err := disp.Dispatch(context.Background(), "search", args...)
+		var usageErr *UsageError
+		if !errors.As(err, &usageErr) {
+			t.Fatalf("expected UsageError for %v, got %v", args, err)
+		}
+		if ExitCode(err) != ExitCodeUsage {
+			t.Errorf("ExitCode() = %d, want %d", ExitCode(err), ExitCodeUsage)
+		}
+		if strings.HasPrefix(args[1], "--limit") || args[1] == "many" {
+			if usageErr.ArgName != "limit" || usageErr.ArgType != "int" {
+				t.Errorf("got arg %s of type %s", usageErr.ArgName, usageErr.ArgType)
+			}
+			if !strings.HasSuffix(err.Error(), "(expected <limit:int> integer like 42 or -1)") {
+				t.Errorf("got error %q", err)
+			}
+		}
+		if !strings.HasSuffix(usageErr.Usage, "search <query:string> <limit:int>") {
+			t.Errorf("got usage %q", usageErr.Usage)
+		}
+	}
+}
+
+func Test_argFormat(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{value: "", want: ""},
+		{value: true, want: "one of true, false"},
+		{value: time.Second, want: "duration like 90s, 5m, or 1h30m"},
+		{value: new(uint8), want: "unsigned integer like 42"},
+		{value: []float64{}, want: "list like [a,b,c] or a single element of number like 3.14"},
+		{value: []byte{}, want: ""},
+		{value: testEnum(""), want: "one of dev, prod"},
+	}
+	for _, tt := range tests {
+		if got := argFormat(reflect.TypeOf(tt.value)); got != tt.want {
+			t.Errorf("argFormat(%T) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+	if got := argFormat(reflect.TypeOf(time.Time{})); !strings.HasPrefix(got, "date like ") {
+		t.Errorf("argFormat(time.Time) = %q", got)
+	}
+}
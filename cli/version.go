@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/domonda/go-function"
+)
+
+const (
+	// VersionCommand is the command added by AddVersionCommand
+	VersionCommand = "version"
+
+	// VersionFlag is the global flag added by AddVersionCommand
+	// that prints the version info instead of calling a command.
+	VersionFlag = "version"
+)
+
+// VersionInfo holds the version information of a program.
+type VersionInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	Date      string `json:"date,omitempty"`
+	GoVersion string `json:"goVersion"`
+	Platform  string `json:"platform"`
+}
+
+// NewVersionInfo returns a VersionInfo with the passed values.
+// Empty values are read from the build info embedded
+// by the Go toolchain if available.
+func NewVersionInfo(version, commit, date string) *VersionInfo {
+	info := &VersionInfo{
+		Version:   version,
+		Commit:    commit,
+		Date:      date,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "" && buildInfo.Main.Version != "" {
+			info.Version = buildInfo.Main.Version
+		}
+		for _, setting := range buildInfo.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.Date == "":
+				info.Date = setting.Value
+			}
+		}
+	}
+	if info.Version == "" {
+		info.Version = "(devel)"
+	}
+	return info
+}
+
+// String returns the version info formatted in multiple lines
+func (info *VersionInfo) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Version:    %s\n", info.Version)
+	if info.Commit != "" {
+		fmt.Fprintf(&b, "Commit:     %s\n", info.Commit)
+	}
+	if info.Date != "" {
+		fmt.Fprintf(&b, "Date:       %s\n", info.Date)
+	}
+	fmt.Fprintf(&b, "Go version: %s\n", info.GoVersion)
+	fmt.Fprintf(&b, "Platform:   %s", info.Platform)
+	return b.String()
+}
+
+// versionCommand returns the function of the version command
+func versionCommand(info *VersionInfo) function.Wrapper {
+	return function.MustReflectWrapper(func() *VersionInfo { return info })
+}
+
+// addVersionFlag adds the global VersionFlag that
+// dispatches to the version command of disp.
+func (cfg *dispatcherConfig) addVersionFlag(info *VersionInfo) error {
+	err := cfg.addGlobalFlag(VersionFlag, "Print version information", false)
+	if err != nil {
+		return err
+	}
+	cfg.versionInfo = info
+	return nil
+}
+
+// printVersionIfRequested prints the version info
+// if the VersionFlag was passed and returns true in that case.
+func (cfg *dispatcherConfig) printVersionIfRequested(ctx context.Context) bool {
+	if cfg.versionInfo == nil || !GlobalFlagBool(ctx, VersionFlag) {
+		return false
+	}
+	fmt.Println(cfg.versionInfo)
+	return true
+}
@@ -0,0 +1,24 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewVersionInfo(t *testing.T) {
+	info := NewVersionInfo("v1.2.3", "abc123", "")
+	if info.Version != "v1.2.3" || info.Commit != "abc123" || info.GoVersion == "" {
+		t.Errorf("unexpected version info: %#v", info)
+	}
+	if !strings.HasPrefix(info.String(), "Version:    v1.2.3\nCommit:     abc123\n") {
+		t.Errorf("unexpected version info string:\n%s", info)
+	}
+
+	disp := NewStringArgsDispatcher()
+	if err := disp.AddVersionCommand("", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if !disp.HasCommnd(VersionCommand) || disp.cfg.versionInfo.Version == "" {
+		t.Error("version command not added")
+	}
+}
@@ -1,39 +1,105 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 
 	"github.com/domonda/go-function/cmd/gen-func-wrappers/gen"
 )
 
 var (
-	// genFilename   string
-	// namePrefix    string
-	// exportedFuncs bool
+	exportedFuncs  bool
+	namePrefix     string
+	genFile        bool
+	genTests       bool
+	openAPI        bool
+	typeScript     bool
+	docs           bool
+	cliApp         string
 	replaceForJSON string
+	jsonNaming     string
+	configFile     string
+	exclude        string
+	watch          bool
+	orphans        bool
+	fix            bool
 	verbose        bool
 	printOnly      bool
+	report         string
 	printHelp      bool
 )
 
 func main() {
-	// flag.BoolVar(&exportedFuncs, "exported", false, "generate function.Wrapper implementation types exported package functions")
-	// flag.StringVar(&genFilename, "genfile", "generated.go", "name of the file to be generated")
-	// flag.StringVar(&namePrefix, "prefix", "Func", "prefix for function type names in the same package")
+	flag.BoolVar(&exportedFuncs, "exported", false, "generate function.Wrapper implementation types for all exported package functions into "+gen.ExportedFuncsFilename)
+	flag.StringVar(&namePrefix, "prefix", "Func", "prefix for the generated type names of the -exported mode")
+	flag.BoolVar(&genFile, "genfile", false, "write generated wrapper types into "+gen.GeneratedFilename+" per package instead of the files declaring the wrappers")
+	flag.BoolVar(&genTests, "gentests", false, "write tests of the generated function wrappers calling them with zero value arguments into a _test.go file per package")
+	flag.BoolVar(&openAPI, "openapi", false, "write an OpenAPI specification of the wrappers per package into "+gen.OpenAPIFilename+" instead of generating wrappers")
+	flag.BoolVar(&typeScript, "typescript", false, "write TypeScript types and fetch based client functions of the wrappers per package into "+gen.TypeScriptFilename+" instead of generating wrappers")
+	flag.BoolVar(&docs, "docs", false, "write a markdown documentation of the wrapped functions per package into "+gen.DocsFilename+" instead of generating wrappers")
+	flag.StringVar(&cliApp, "cli", "", "write the main package of a CLI with the given app name calling the exported wrappers of a package into cmd/<app>/main.go of the module root instead of generating wrappers")
 	flag.StringVar(&replaceForJSON, "replaceForJSON", "", "comma separated list of InterfaceType:ImplementationType used for JSON unmarshalling")
+	flag.StringVar(&jsonNaming, "jsonNaming", "", "naming of the JSON fields of arguments for CallWithJSON: camelCase, snake_case, verbatim (default: exported argument names matched case insensitive)")
+	flag.StringVar(&configFile, "config", "", "configuration file to use instead of searching for "+gen.ConfigFilename+" in the target directory and its parents up to the module root")
+	flag.StringVar(&exclude, "exclude", "", "comma separated list of file path patterns of files and directories to skip in addition to vendor, testdata and the ignore list of the configuration, patterns with a slash are relative to the working directory")
+	flag.BoolVar(&orphans, "orphans", false, "report generated wrappers of functions or interfaces that no longer exist without writing files")
+	flag.BoolVar(&fix, "fix", false, "remove generated wrappers of functions or interfaces that no longer exist")
+	flag.BoolVar(&watch, "watch", false, "keep running and regenerate the wrappers of packages with changed files")
 	flag.BoolVar(&verbose, "verbose", false, "prints information of what's happening")
 	flag.BoolVar(&printOnly, "print", false, "prints to stdout instead of writing files")
+	flag.StringVar(&report, "report", "", "prints a report of the processed files and wrappers to stdout in the given format: json")
 	flag.BoolVar(&printHelp, "help", false, "prints this help output")
 	flag.Parse()
 	if printHelp {
 		flag.PrintDefaults()
 		os.Exit(2)
 	}
+	if watch && (exportedFuncs || printOnly) {
+		fmt.Fprintln(os.Stderr, "gen-func-wrappers error: -watch can't be used with -exported or -print")
+		os.Exit(2)
+	}
+	if openAPI && (exportedFuncs || watch || orphans) {
+		fmt.Fprintln(os.Stderr, "gen-func-wrappers error: -openapi can't be used with -exported, -watch or -orphans")
+		os.Exit(2)
+	}
+	if typeScript && (exportedFuncs || openAPI || watch || orphans) {
+		fmt.Fprintln(os.Stderr, "gen-func-wrappers error: -typescript can't be used with -exported, -openapi, -watch or -orphans")
+		os.Exit(2)
+	}
+	if docs && (exportedFuncs || openAPI || typeScript || watch || orphans) {
+		fmt.Fprintln(os.Stderr, "gen-func-wrappers error: -docs can't be used with -exported, -openapi, -typescript, -watch or -orphans")
+		os.Exit(2)
+	}
+	if cliApp != "" && (exportedFuncs || openAPI || typeScript || docs || watch || orphans) {
+		fmt.Fprintln(os.Stderr, "gen-func-wrappers error: -cli can't be used with -exported, -openapi, -typescript, -docs, -watch or -orphans")
+		os.Exit(2)
+	}
+	if genTests && exportedFuncs {
+		fmt.Fprintln(os.Stderr, "gen-func-wrappers error: -gentests can't be used with -exported")
+		os.Exit(2)
+	}
+	if report != "" && report != "json" {
+		fmt.Fprintf(os.Stderr, "gen-func-wrappers error: invalid -report format %q, only json is supported\n", report)
+		os.Exit(2)
+	}
+	if report != "" && (exportedFuncs || openAPI || typeScript || docs || cliApp != "" || watch || printOnly || verbose) {
+		fmt.Fprintln(os.Stderr, "gen-func-wrappers error: -report can't be used with -exported, -openapi, -typescript, -docs, -cli, -watch, -print or -verbose")
+		os.Exit(2)
+	}
+	reportOrphans := orphans && !fix
+	if reportOrphans && (exportedFuncs || watch) {
+		fmt.Fprintln(os.Stderr, "gen-func-wrappers error: -orphans can't be used with -exported or -watch")
+		os.Exit(2)
+	}
 
 	var (
 		args     = flag.Args()
@@ -59,7 +125,34 @@ func main() {
 		os.Exit(2)
 	}
 
+	var config *gen.Config
+	if configFile != "" {
+		config, err = gen.LoadConfig(configFile)
+	} else {
+		configDir := strings.TrimSuffix(filePath, "...")
+		if !info.IsDir() {
+			configDir = filepath.Dir(configDir)
+		}
+		config, err = gen.FindConfig(configDir)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gen-func-wrappers error:", err)
+		os.Exit(2)
+	}
+	if config != nil && verbose {
+		fmt.Println("using configuration", filepath.Join(config.Dir, gen.ConfigFilename))
+	}
+
+	// Command line flags override the configuration
 	jsonTypeReplacements := make(map[string]string)
+	if config != nil {
+		for typ, jsonType := range config.ReplaceForJSON {
+			jsonTypeReplacements[typ] = jsonType
+		}
+		if jsonNaming == "" {
+			jsonNaming = string(config.JSONNaming)
+		}
+	}
 	if replaceForJSON != "" {
 		for _, repl := range strings.Split(replaceForJSON, ",") {
 			types := strings.Split(repl, ":")
@@ -71,20 +164,113 @@ func main() {
 		}
 	}
 
-	// TODO replace hard coded prefix with config option and auto-detection
-	localImportPrefixes := []string{"github.com/domonda/"}
+	jsonFieldNaming, err := gen.ParseJSONFieldNaming(jsonNaming)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gen-func-wrappers error:", err)
+		os.Exit(2)
+	}
+	jsonOptions := gen.JSONOptions{
+		TypeReplacements: jsonTypeReplacements,
+		FieldNaming:      jsonFieldNaming,
+	}
+
+	excluded := &gen.Config{Dir: cwd}
+	if exclude != "" {
+		excluded.Ignore = strings.Split(exclude, ",")
+		for _, pattern := range excluded.Ignore {
+			if _, err = filepath.Match(pattern, ""); err != nil {
+				fmt.Fprintf(os.Stderr, "gen-func-wrappers error: invalid -exclude pattern %q: %s\n", pattern, err)
+				os.Exit(2)
+			}
+		}
+	}
+	ignored := func(path string) bool {
+		return config.IsIgnored(path) || excluded.IsIgnored(path)
+	}
+
+	// Without configured prefixes the local imports
+	// are detected per module of the generated packages
+	var localImportPrefixes []string
+	if config != nil {
+		localImportPrefixes = config.LocalImportPrefixes
+	}
 
 	var printOnlyWriter io.Writer
-	if printOnly {
+	switch {
+	case reportOrphans:
+		printOnlyWriter = io.Discard
+	case printOnly:
 		printOnlyWriter = os.Stdout
 	}
-	if info.IsDir() {
-		err = gen.RewriteDir(filePath, verbose, printOnlyWriter, jsonTypeReplacements, localImportPrefixes)
-	} else {
-		err = gen.RewriteFile(filePath, verbose, printOnlyWriter, jsonTypeReplacements, localImportPrefixes)
+	generator := &gen.Generator{
+		GenFile:             genFile,
+		GenTests:            genTests,
+		RemoveOrphans:       fix,
+		JSONOptions:         jsonOptions,
+		LocalImportPrefixes: localImportPrefixes,
+		Ignored:             ignored,
+		Verbose:             verbose,
+		PrintOnly:           printOnlyWriter,
+	}
+	switch {
+	case exportedFuncs:
+		if !info.IsDir() || strings.HasSuffix(filePath, "...") {
+			fmt.Fprintln(os.Stderr, "gen-func-wrappers error: -exported needs a single package directory")
+			os.Exit(2)
+		}
+		err = gen.PackageFunctions(filePath, gen.ExportedFuncsFilename, namePrefix, verbose, printOnlyWriter, jsonOptions, nil, localImportPrefixes)
+	case cliApp != "":
+		if !info.IsDir() || strings.HasSuffix(filePath, "...") {
+			fmt.Fprintln(os.Stderr, "gen-func-wrappers error: -cli needs a single package directory")
+			os.Exit(2)
+		}
+		err = gen.WriteCLI(filePath, cliApp, verbose, printOnlyWriter, localImportPrefixes, ignored)
+	case openAPI:
+		err = gen.WriteOpenAPI(filePath, verbose, printOnlyWriter, jsonOptions, ignored)
+	case typeScript:
+		err = gen.WriteTypeScript(filePath, verbose, printOnlyWriter, jsonOptions, ignored)
+	case docs:
+		err = gen.WriteDocs(filePath, verbose, printOnlyWriter, ignored)
+	default:
+		var result *gen.Result
+		result, err = generator.Generate(filePath)
+		if report == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if e := encoder.Encode(result); e != nil {
+				fmt.Fprintln(os.Stderr, "gen-func-wrappers error:", e)
+				os.Exit(2)
+			}
+		}
+	}
+	if reportOrphans && errors.Is(err, gen.ErrOrphanedWrapper) {
+		if report != "" {
+			// Keep stdout parseable, the orphans are part of the report
+			fmt.Fprintln(os.Stderr, err)
+		} else {
+			fmt.Println(err)
+		}
+		os.Exit(1)
 	}
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "gen-func-wrappers error:", err)
-		os.Exit(2)
+		if !watch {
+			os.Exit(2)
+		}
+	}
+
+	if watch {
+		watchPath := filePath
+		if !info.IsDir() {
+			watchPath = filepath.Dir(filePath)
+		}
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer cancel()
+		fmt.Println("watching", watchPath, "for changes, press Ctrl+C to stop")
+		err = generator.Watch(ctx, watchPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "gen-func-wrappers error:", err)
+			os.Exit(2)
+		}
 	}
 }
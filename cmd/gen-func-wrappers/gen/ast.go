@@ -2,6 +2,7 @@ package gen
 
 import (
 	"go/ast"
+	"regexp"
 	"strings"
 
 	"github.com/ungerik/go-astvisit"
@@ -32,8 +33,11 @@ func funcDeclArgDescriptions(funcDecl *ast.FuncDecl) (descriptions []string) {
 			if funcDecl.Doc != nil {
 				label := " " + name.Name + ": "
 				for _, comment := range funcDecl.Doc.List {
-					if labelPos := strings.Index(comment.Text, label); labelPos != -1 {
-						description = strings.TrimSpace(comment.Text[labelPos+len(label):])
+					// gofmt indents the lines of argument descriptions
+					// formatted as code block with a tab
+					text := strings.ReplaceAll(comment.Text, "\t", " ")
+					if labelPos := strings.Index(text, label); labelPos != -1 {
+						description = strings.TrimSpace(text[labelPos+len(label):])
 						break
 					}
 				}
@@ -56,3 +60,53 @@ func funcTypeResultTypes(funcType *ast.FuncType, exportedNameQualifyer string) (
 	}
 	return types
 }
+
+func funcTypeParamNames(funcType *ast.FuncType) (names []string) {
+	if funcType.TypeParams == nil {
+		return nil
+	}
+	for _, field := range funcType.TypeParams.List {
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+	}
+	return names
+}
+
+var typeNameRegexp = regexp.MustCompile(`\w+(\.\w+)?`)
+
+// instantiateTypeParams replaces the type parameter names
+// in typeStr with their type arguments from instances.
+// Type parameter names qualified with exportedNameQualifyer
+// as returned by funcTypeArgTypes are also replaced.
+func instantiateTypeParams(typeStr, exportedNameQualifyer string, instances map[string]string) string {
+	return typeNameRegexp.ReplaceAllStringFunc(typeStr, func(name string) string {
+		if exportedNameQualifyer != "" {
+			if unqualified, ok := strings.CutPrefix(name, exportedNameQualifyer+"."); ok {
+				if typeArg, ok := instances[unqualified]; ok {
+					return typeArg
+				}
+			}
+		}
+		if typeArg, ok := instances[name]; ok {
+			return typeArg
+		}
+		return name
+	})
+}
+
+// wrappedFuncString returns the string of a wrapped function expression
+// including the type arguments of a generic function instantiation.
+func wrappedFuncString(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.IndexExpr:
+		return astvisit.ExprString(e.X) + "[" + astvisit.ExprString(e.Index) + "]"
+	case *ast.IndexListExpr:
+		typeArgs := make([]string, len(e.Indices))
+		for i, index := range e.Indices {
+			typeArgs[i] = astvisit.ExprString(index)
+		}
+		return astvisit.ExprString(e.X) + "[" + strings.Join(typeArgs, ", ") + "]"
+	}
+	return astvisit.ExprString(expr)
+}
@@ -0,0 +1,66 @@
+package gen
+
+import "testing"
+
+func Test_instantiateTypeParams(t *testing.T) {
+	instances := map[string]string{"T": "int", "K": "pkg.Key", "V": "[]T"}
+	tests := []struct {
+		name                  string
+		typeStr               string
+		exportedNameQualifyer string
+		want                  string
+	}{
+		{name: "param", typeStr: "T", want: "int"},
+		{name: "no param", typeStr: "string", want: "string"},
+		{name: "slice", typeStr: "[]T", want: "[]int"},
+		{name: "variadic", typeStr: "...T", want: "...int"},
+		{name: "map", typeStr: "map[K]V", want: "map[pkg.Key][]T"},
+		{name: "qualified param", typeStr: "map[other.K]other.V", exportedNameQualifyer: "other", want: "map[pkg.Key][]T"},
+		{name: "other package", typeStr: "other.T", want: "other.T"},
+		{name: "longer name", typeStr: "TT", want: "TT"},
+		{name: "func", typeStr: "(ctx context.Context, t T) (K, error)", want: "(ctx context.Context, t int) (pkg.Key, error)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := instantiateTypeParams(tt.typeStr, tt.exportedNameQualifyer, instances); got != tt.want {
+				t.Errorf("instantiateTypeParams(%q) = %q, want %q", tt.typeStr, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_wrapper_WrappedFuncTypeArgs(t *testing.T) {
+	tests := []struct {
+		wrappedFunc  string
+		wantPkg      string
+		wantFunc     string
+		wantTypeArgs []string
+	}{
+		{wrappedFunc: "MyFunc", wantFunc: "MyFunc"},
+		{wrappedFunc: "pkg.MyFunc", wantPkg: "pkg", wantFunc: "MyFunc"},
+		{wrappedFunc: "MyFunc[int]", wantFunc: "MyFunc", wantTypeArgs: []string{"int"}},
+		{wrappedFunc: "MyFunc[other.Type]", wantFunc: "MyFunc", wantTypeArgs: []string{"other.Type"}},
+		{wrappedFunc: "pkg.MyFunc[int, map[string][]byte]", wantPkg: "pkg", wantFunc: "MyFunc", wantTypeArgs: []string{"int", "map[string][]byte"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wrappedFunc, func(t *testing.T) {
+			w := &wrapper{WrappedFunc: tt.wrappedFunc}
+			gotPkg, gotFunc := w.WrappedFuncPkgAndFuncName()
+			if gotPkg != tt.wantPkg || gotFunc != tt.wantFunc {
+				t.Errorf("WrappedFuncPkgAndFuncName() = %q, %q, want %q, %q", gotPkg, gotFunc, tt.wantPkg, tt.wantFunc)
+			}
+			gotTypeArgs, err := w.WrappedFuncTypeArgs()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(gotTypeArgs) != len(tt.wantTypeArgs) {
+				t.Fatalf("WrappedFuncTypeArgs() = %#v, want %#v", gotTypeArgs, tt.wantTypeArgs)
+			}
+			for i := range gotTypeArgs {
+				if gotTypeArgs[i] != tt.wantTypeArgs[i] {
+					t.Errorf("WrappedFuncTypeArgs() = %#v, want %#v", gotTypeArgs, tt.wantTypeArgs)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package gen
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// importedLoadMode is the packages.LoadMode for imported packages.
+// Only the file names and the names of their imported packages are needed
+// because the files are parsed on demand when a function
+// or interface of the package is referenced.
+const importedLoadMode = packages.NeedName | packages.NeedFiles | packages.NeedModule | packages.NeedImports | packages.NeedDeps
+
+// packageCache caches the parsed exported functions and interfaces
+// of imported packages so that packages imported by many files
+// are only located and parsed once per generator run.
+// It is safe for concurrent use.
+type packageCache struct {
+	mtx      sync.Mutex
+	packages map[string]*cachedPackage // by package ID
+}
+
+type cachedPackage struct {
+	pkg   *packages.Package // loaded with importedLoadMode
+	once  sync.Once
+	funcs packageFuncs
+	err   error
+}
+
+func newPackageCache() *packageCache {
+	return &packageCache{
+		packages: make(map[string]*cachedPackage),
+	}
+}
+
+// imported returns the cached packages for the passed
+// package IDs of imports of a package in pkgDir.
+// Packages that are not cached yet are loaded with a single packages.Load call.
+func (c *packageCache) imported(pkgDir string, ids []string) (map[string]*cachedPackage, error) {
+	result := make(map[string]*cachedPackage, len(ids))
+	var missing []string
+	c.mtx.Lock()
+	for _, id := range ids {
+		if cached, ok := c.packages[id]; ok {
+			result[id] = cached
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	c.mtx.Unlock()
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	config := &packages.Config{Mode: importedLoadMode, Dir: pkgDir}
+	loaded, err := packages.Load(config, missing...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for _, pkg := range loaded {
+		// Another goroutine might have loaded
+		// the same package in the meantime
+		cached, ok := c.packages[pkg.ID]
+		if !ok {
+			cached = &cachedPackage{pkg: pkg}
+			c.packages[pkg.ID] = cached
+		}
+		result[pkg.ID] = cached
+	}
+	return result, nil
+}
+
+// exportedFuncs returns the exported functions and interfaces
+// of the imported package parsed from its Go files.
+func (pkg *cachedPackage) exportedFuncs() (packageFuncs, error) {
+	pkg.once.Do(func() {
+		if len(pkg.pkg.Errors) > 0 {
+			pkg.err = pkg.pkg.Errors[0]
+			return
+		}
+		pkg.funcs = packageFuncs{
+			PkgPath:       pkg.pkg.PkgPath,
+			PkgName:       pkg.pkg.Name,
+			Funcs:         make(map[string]funcDeclInFile),
+			Interfaces:    make(map[string]interfaceInFile),
+			ExportedNames: make(map[string]struct{}),
+		}
+		// Every package uses its own token.FileSet
+		// because the positions of imported declarations
+		// are never used for rewriting files
+		fset := token.NewFileSet()
+		importNames := make(map[string]string, len(pkg.pkg.Imports))
+		for path, imported := range pkg.pkg.Imports {
+			importNames[path] = imported.Name
+		}
+		for _, filename := range pkg.pkg.GoFiles {
+			file, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+			if err != nil {
+				pkg.err = err
+				return
+			}
+			f := &ParsedFile{File: file, ImportNames: importNames}
+			addInterfaces(f, true, pkg.funcs.Interfaces)
+			addExportedNames(f.File, pkg.funcs.ExportedNames)
+			for _, decl := range f.Decls {
+				funcDecl, ok := decl.(*ast.FuncDecl)
+				if ok && funcDecl.Recv == nil && funcDecl.Name.IsExported() {
+					pkg.funcs.Funcs[funcDecl.Name.Name] = funcDeclInFile{
+						Decl: funcDecl,
+						File: f,
+					}
+				}
+			}
+		}
+	})
+	return pkg.funcs, pkg.err
+}
@@ -0,0 +1,237 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ungerik/go-astvisit"
+	"golang.org/x/tools/go/packages"
+)
+
+// CLIFilename is the name of the file written by WriteCLI
+// into the directory cmd/<app> of the module root.
+const CLIFilename = "main.go"
+
+// cliCommand is a command of the CLI written by WriteCLI.
+type cliCommand struct {
+	Name        string
+	Description string
+	VarName     string // of the wrapper in the wrapped package
+}
+
+// WriteCLI writes the main package of a command line application
+// named appName to the file cmd/<appName>/main.go of the module root
+// of the package in pkgDir.
+// Every exported function wrapper variable of the package is registered
+// as command of a cli.StringArgsDispatcher with the wrapped function name
+// in kebab-case as command name and the first line of the
+// doc comment of the wrapped function as description.
+// Unexported wrappers, wrappers declared with function.WrapperTODO,
+// and interface wrappers can't be used by another package and are skipped.
+// An existing file is only overwritten if it was written by WriteCLI,
+// so remove the generated header to take over the file.
+// Files for which the optional ignored function returns true are skipped.
+func WriteCLI(pkgDir, appName string, verbose bool, printTo io.Writer, localImportPrefixes []string, ignored func(path string) bool) error {
+	if appName == "" || strings.ContainsAny(appName, `/\`) || appName == "." || appName == ".." {
+		return fmt.Errorf("invalid CLI app name %q", appName)
+	}
+	if ignored == nil {
+		ignored = func(string) bool { return false }
+	}
+	pkg, err := loadPackage(pkgDir)
+	if err != nil {
+		return err
+	}
+	if pkg.Name == "main" {
+		return fmt.Errorf("can't write a CLI for package main in %s because it can't be imported", pkgDir)
+	}
+	moduleDir, err := findModuleDir(pkgDir)
+	if err != nil {
+		return err
+	}
+	localImportPrefixes, err = localImportPrefixesFor(pkgDir, localImportPrefixes)
+	if err != nil {
+		return err
+	}
+
+	commands, err := packageCLICommands(newPackageCache(), pkg, pkgDir, verbose, ignored)
+	if err != nil {
+		return err
+	}
+	if len(commands) == 0 {
+		return fmt.Errorf("no exported function wrappers found for a CLI in %s", pkgDir)
+	}
+
+	filePath := filepath.Join(moduleDir, "cmd", appName, CLIFilename)
+	src, err := cliSource(pkg, appName, commands, localImportPrefixes)
+	if err != nil {
+		return err
+	}
+	if printTo != nil {
+		if verbose {
+			fmt.Println(filePath, "would be written as:")
+		}
+		_, err = printTo.Write(src)
+		return err
+	}
+
+	existing, err := os.ReadFile(filePath)
+	if err == nil && !bytes.HasPrefix(existing, []byte(generatedFileHeader)) {
+		return fmt.Errorf("not overwriting %s because it was not generated by gen-func-wrappers", filePath)
+	}
+	err = os.MkdirAll(filepath.Dir(filePath), 0755)
+	if err != nil {
+		return err
+	}
+	return writeFileIfChanged(filePath, src, "writing", verbose, nil)
+}
+
+// findModuleDir returns the directory of the go.mod file
+// of dir or one of its parents.
+func findModuleDir(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err = os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no go.mod found for %s", dir)
+		}
+		dir = parent
+	}
+}
+
+// packageCLICommands returns the commands for the exported
+// function wrappers of pkg sorted by name.
+func packageCLICommands(cache *packageCache, pkg *packages.Package, pkgDir string, verbose bool, ignored func(path string) bool) ([]cliCommand, error) {
+	files := packageFiles(pkg, pkgDir)
+	fileNames := make([]string, 0, len(files))
+	for fileName := range files {
+		if !isGeneratedFile(fileName) && !ignored(fileName) {
+			fileNames = append(fileNames, fileName)
+		}
+	}
+	sort.Strings(fileNames)
+
+	var (
+		commands     []cliCommand
+		commandIndex = make(map[string]int)
+		pkgGenerated = newGeneratedFile(GeneratedFilename, "")
+	)
+	for _, fileName := range fileNames {
+		file := files[fileName]
+		wrappers, err := findFunctionWrappers(pkg.Fset, file)
+		if err != nil {
+			return nil, err
+		}
+		if len(wrappers) == 0 {
+			continue
+		}
+
+		// The wrapped functions are looked up in the scope of the file
+		// and of the file with the generated wrapper types
+		// that has the imports of the wrapped functions
+		scopeFiles := []*ast.File{file}
+		genFileName := generatedFileFor(fileName, file, pkgGenerated).fileName
+		if generated, ok := files[filepath.Join(pkgDir, genFileName)]; ok {
+			scopeFiles = append(scopeFiles, generated)
+		}
+		functions, err := localAndImportedFunctions(cache, pkg, pkgDir, scopeFiles...)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, wrapper := range wrappers {
+			switch {
+			case !token.IsExported(wrapper.VarName):
+				if verbose {
+					fmt.Println("skipping unexported wrapper", wrapper.VarName, "for CLI")
+				}
+				continue
+			case wrapper.TODO:
+				if verbose {
+					fmt.Println("skipping wrapper", wrapper.VarName, "for CLI because it's not generated yet")
+				}
+				continue
+			}
+			wrappedFuncPackage, wrappedFuncName := wrapper.WrappedFuncPkgAndFuncName()
+			wrappedFunc, _, err := findFunc(functions, wrappedFuncPackage, wrappedFuncName)
+			if err != nil {
+				return nil, errorAt(pkg.Fset, wrapper.Pos, err)
+			}
+			command := cliCommand{
+				Name:    kebabCase(wrappedFuncName),
+				VarName: wrapper.VarName,
+			}
+			command.Description, _, _ = strings.Cut(strings.TrimSpace(wrappedFunc.Decl.Doc.Text()), "\n")
+			if i, exists := commandIndex[command.Name]; exists {
+				// Multiple wrappers of the same function
+				// or of functions with the same name in different packages
+				commands[i].Name = kebabCase(commands[i].VarName)
+				command.Name = kebabCase(wrapper.VarName)
+			}
+			commandIndex[command.Name] = len(commands)
+			commands = append(commands, command)
+		}
+	}
+	sort.Slice(commands, func(i, j int) bool { return commands[i].Name < commands[j].Name })
+	return commands, nil
+}
+
+// kebabCase returns name in kebab-case like "GetUserID" -> "get-user-id".
+func kebabCase(name string) string {
+	words := splitWords(name)
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	return strings.Join(words, "-")
+}
+
+// cliSource returns the formatted source of the main package
+// of the CLI appName with commands of the wrappers in pkg.
+func cliSource(pkg *packages.Package, appName string, commands []cliCommand, localImportPrefixes []string) ([]byte, error) {
+	pkgImport := fmt.Sprintf("%q", pkg.PkgPath)
+	if pkg.Name != path.Base(pkg.PkgPath) {
+		pkgImport = pkg.Name + " " + pkgImport
+	}
+	switch pkg.Name {
+	case "os", "cli", "function":
+		return nil, fmt.Errorf("can't write a CLI for package %s because its name conflicts with an import of the CLI", pkg.PkgPath)
+	}
+
+	var src bytes.Buffer
+	src.WriteString(generatedFileHeader)
+	fmt.Fprintf(&src, "\n// Command %s calls the function wrappers of package %s.\n", appName, pkg.PkgPath)
+	src.WriteString("package main\n\n")
+	src.WriteString("func main() {\n")
+	src.WriteString("\tdispatcher := cli.NewStringArgsDispatcher()\n")
+	for _, command := range commands {
+		fmt.Fprintf(&src, "\tdispatcher.MustAddCommand(%q, %q, %s.%s, function.Println)\n", command.Name, command.Description, pkg.Name, command.VarName)
+	}
+	src.WriteString("\n\tif len(os.Args) < 2 {\n")
+	fmt.Fprintf(&src, "\t\tdispatcher.PrintCommands(%q)\n", appName)
+	src.WriteString("\t\tos.Exit(cli.ExitCodeUsage)\n")
+	src.WriteString("\t}\n")
+	src.WriteString("\tcli.ExitOnError(cli.DispatchWithSignals(dispatcher, os.Args[1:]))\n")
+	src.WriteString("}\n")
+
+	importLines := map[string]struct{}{
+		`"os"`:                                 {},
+		`"github.com/domonda/go-function"`:     {},
+		`"github.com/domonda/go-function/cli"`: {},
+		pkgImport:                              {},
+	}
+	return astvisit.FormatFileWithImports(token.NewFileSet(), src.Bytes(), importLines, localImportPrefixes...)
+}
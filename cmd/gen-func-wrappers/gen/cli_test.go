@@ -0,0 +1,125 @@
+package gen
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_packageCLICommands(t *testing.T) {
+	root := writeTestModule(t, map[string]string{
+		"a/a.go": `package a
+
+import (
+	"github.com/domonda/go-function"
+
+	"example.com/testmod/b"
+)
+
+// GetUserID returns the ID of a user
+//
+// Only the first line is used as description.
+func GetUserID(name string) int64 { return 0 }
+
+func Split(s string) (string, string) { return s, s }
+
+// GetUserIDWrapper wraps GetUserID as function.Wrapper (generated code)
+var GetUserIDWrapper getUserIDWrapperT
+
+type getUserIDWrapperT struct{}
+
+// BGetUserIDWrapper wraps b.GetUserID as function.Wrapper (generated code)
+var BGetUserIDWrapper bGetUserIDWrapperT
+
+type bGetUserIDWrapperT struct{}
+
+// splitWrapper wraps Split as function.Wrapper (generated code)
+var splitWrapper splitWrapperT
+
+type splitWrapperT struct{}
+
+var TODOWrapper = function.WrapperTODO(Split)
+`,
+		"b/b.go": `package b
+
+// GetUserID of b
+func GetUserID() int64 { return 0 }
+`,
+	})
+	pkgDir := filepath.Join(root, "a")
+	pkg, err := loadPackage(pkgDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commands, err := packageCLICommands(newPackageCache(), pkg, pkgDir, false, func(string) bool { return false })
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []cliCommand{
+		{Name: "b-get-user-id-wrapper", Description: "GetUserID of b", VarName: "BGetUserIDWrapper"},
+		{Name: "get-user-id-wrapper", Description: "GetUserID returns the ID of a user", VarName: "GetUserIDWrapper"},
+	}
+	if !reflect.DeepEqual(commands, want) {
+		t.Errorf("packageCLICommands() = %+v, want %+v", commands, want)
+	}
+}
+
+func TestWriteCLI(t *testing.T) {
+	root := writeTestModule(t, map[string]string{
+		"a/a.go": `package a
+
+// Greet returns a greeting
+func Greet(name string) string { return "Hello " + name }
+
+// GreetWrapper wraps Greet as function.Wrapper (generated code)
+var GreetWrapper greetWrapperT
+
+type greetWrapperT struct{}
+`,
+	})
+	pkgDir := filepath.Join(root, "a")
+
+	var printed bytes.Buffer
+	err := WriteCLI(pkgDir, "greeter", false, &printed, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		generatedFileHeader,
+		"package main",
+		`"example.com/testmod/a"`,
+		`dispatcher.MustAddCommand("greet", "Greet returns a greeting", a.GreetWrapper, function.Println)`,
+		`dispatcher.PrintCommands("greeter")`,
+		"cli.ExitOnError(cli.DispatchWithSignals(dispatcher, os.Args[1:]))",
+	} {
+		if !strings.Contains(printed.String(), want) {
+			t.Errorf("printed CLI source does not contain %q:\n%s", want, printed.String())
+		}
+	}
+
+	err = WriteCLI(pkgDir, "greeter", false, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filePath := filepath.Join(root, "cmd", "greeter", CLIFilename)
+	written, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(written, printed.Bytes()) {
+		t.Errorf("written CLI source differs from printed:\n%s", written)
+	}
+
+	// Files without the generated header are not overwritten
+	err = os.WriteFile(filePath, []byte("package main\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = WriteCLI(pkgDir, "greeter", false, nil, nil, nil)
+	if err == nil {
+		t.Error("expected error for existing file without generated header")
+	}
+}
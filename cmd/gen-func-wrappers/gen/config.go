@@ -0,0 +1,134 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFilename is the name of the configuration file
+// searched by FindConfig.
+const ConfigFilename = "gen-func-wrappers.yaml"
+
+// Config is the content of a ConfigFilename file.
+//
+// Example:
+//
+//	replaceForJSON:
+//	  fs.FileReader: fs.File
+//	localImportPrefixes:
+//	  - github.com/domonda/
+//	jsonNaming: camelCase
+//	ignore:
+//	  - testdata
+//	  - internal/legacy/*
+type Config struct {
+	// ReplaceForJSON maps argument types to the types
+	// used for JSON unmarshalling, see JSONOptions.TypeReplacements
+	ReplaceForJSON map[string]string `yaml:"replaceForJSON"`
+	// LocalImportPrefixes are the import path prefixes
+	// of packages grouped after third party imports,
+	// if empty they are detected with DetectLocalImportPrefixes
+	LocalImportPrefixes []string `yaml:"localImportPrefixes"`
+	// JSONNaming is the JSONFieldNaming for CallWithJSON arguments
+	JSONNaming JSONFieldNaming `yaml:"jsonNaming"`
+	// Ignore are filepath.Match patterns of files and directories
+	// that will not be rewritten. Patterns without a slash
+	// match the base name of files and directories,
+	// patterns with a slash match paths relative to the directory
+	// of the configuration file.
+	Ignore []string `yaml:"ignore"`
+
+	// Dir is the directory of the loaded configuration file
+	Dir string `yaml:"-"`
+}
+
+// LoadConfig loads the configuration file at filePath.
+func LoadConfig(filePath string) (*Config, error) {
+	data, err := os.ReadFile(filePath) //#nosec G304
+	if err != nil {
+		return nil, err
+	}
+	config := new(Config)
+	err = yaml.Unmarshal(data, config)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse %s: %w", filePath, err)
+	}
+	_, err = ParseJSONFieldNaming(string(config.JSONNaming))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filePath, err)
+	}
+	for _, pattern := range config.Ignore {
+		if _, err = filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("%s: invalid ignore pattern %q: %w", filePath, pattern, err)
+		}
+	}
+	config.Dir, err = filepath.Abs(filepath.Dir(filePath))
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// FindConfig searches for a ConfigFilename file in dir
+// and its parent directories up to the root of the Go module
+// containing dir and returns the loaded configuration.
+// The result is nil without an error if no configuration file was found.
+func FindConfig(dir string) (*Config, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		config, err := LoadConfig(filepath.Join(dir, ConfigFilename))
+		if err == nil {
+			return config, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if _, err = os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return nil, nil // module root
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil // file system root
+		}
+		dir = parent
+	}
+}
+
+// IsIgnored returns true if path matches any of the Ignore patterns.
+// It is safe to call on a nil Config.
+func (c *Config) IsIgnored(path string) bool {
+	if c == nil || len(c.Ignore) == 0 {
+		return false
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	relPath, err := filepath.Rel(c.Dir, absPath)
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		relPath = ""
+	}
+	for _, pattern := range c.Ignore {
+		if !strings.Contains(pattern, "/") {
+			if match, _ := filepath.Match(pattern, filepath.Base(absPath)); match {
+				return true
+			}
+			continue
+		}
+		if relPath == "" {
+			continue
+		}
+		if match, _ := filepath.Match(filepath.FromSlash(strings.Trim(pattern, "/")), relPath); match {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package gen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindConfig(t *testing.T) {
+	moduleDir := t.TempDir()
+	pkgDir := filepath.Join(moduleDir, "pkg", "sub")
+	err := os.MkdirAll(pkgDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(moduleDir, "go.mod"), []byte("module example.com/test\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := FindConfig(pkgDir)
+	if err != nil || config != nil {
+		t.Fatalf("FindConfig() without config file = %v, %v", config, err)
+	}
+
+	configYAML := `
+replaceForJSON:
+  fs.FileReader: fs.File
+localImportPrefixes:
+  - example.com/
+jsonNaming: snake_case
+ignore:
+  - "*_legacy.go"
+  - pkg/sub
+`
+	err = os.WriteFile(filepath.Join(moduleDir, ConfigFilename), []byte(configYAML), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err = FindConfig(pkgDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config == nil {
+		t.Fatal("FindConfig() found no config")
+	}
+	if config.ReplaceForJSON["fs.FileReader"] != "fs.File" {
+		t.Errorf("ReplaceForJSON = %#v", config.ReplaceForJSON)
+	}
+	if len(config.LocalImportPrefixes) != 1 || config.LocalImportPrefixes[0] != "example.com/" {
+		t.Errorf("LocalImportPrefixes = %#v", config.LocalImportPrefixes)
+	}
+	if config.JSONNaming != JSONFieldNamingSnakeCase {
+		t.Errorf("JSONNaming = %q", config.JSONNaming)
+	}
+
+	ignored := map[string]bool{
+		filepath.Join(moduleDir, "pkg", "file.go"):        false,
+		filepath.Join(moduleDir, "pkg", "file_legacy.go"): true,
+		filepath.Join(moduleDir, "pkg", "sub"):            true,
+		filepath.Join(moduleDir, "other", "sub"):          false,
+	}
+	for path, want := range ignored {
+		if got := config.IsIgnored(path); got != want {
+			t.Errorf("IsIgnored(%q) = %t, want %t", path, got, want)
+		}
+	}
+	if (*Config)(nil).IsIgnored(pkgDir) {
+		t.Error("nil Config must not ignore anything")
+	}
+
+	err = os.WriteFile(filepath.Join(moduleDir, ConfigFilename), []byte("jsonNaming: kebab-case\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = FindConfig(pkgDir)
+	if err == nil {
+		t.Error("FindConfig() expected error for invalid jsonNaming")
+	}
+}
@@ -0,0 +1,181 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// WrapperDirective is the comment directive
+// with options for the wrapper declared below it
+// that override the options of the generator run.
+//
+// Example:
+//
+//	//gen:wrapper json=snake_case replace=fs.FileReader:fs.File impl=CallWithJSONWrapper
+//	var myFuncWrapper = function.WrapperTODO(MyFunc)
+//
+// Supported options:
+//
+//	impl=<Interface>           the function package interfaces to implement joined with "+"
+//	json=<naming>              JSONFieldNaming of the CallWithJSON arguments
+//	replace=<Type>:<JSONType>  comma separated JSONOptions.TypeReplacements
+//	results=<names>            comma separated names of the non-error results
+//	                           or "named" for the result names of the function
+//
+// The directive is kept in the doc comment of the generated
+// var declaration so that re-generating uses the same options.
+const WrapperDirective = "//gen:wrapper"
+
+type wrapperDirective struct {
+	Impl             Impl // zero if not set
+	FieldNaming      *JSONFieldNaming
+	TypeReplacements map[string]string
+	Results          string // "named" or comma separated result names
+}
+
+// parseWrapperDirective parses the WrapperDirective in comment.
+// The result is nil if comment has no WrapperDirective.
+func parseWrapperDirective(comment *ast.CommentGroup) (*wrapperDirective, error) {
+	if comment == nil {
+		return nil, nil
+	}
+	for _, c := range comment.List {
+		args, found := strings.CutPrefix(c.Text, WrapperDirective)
+		if !found || args != "" && args[0] != ' ' && args[0] != '\t' {
+			continue
+		}
+		directive := new(wrapperDirective)
+		for _, option := range strings.Fields(args) {
+			key, value, ok := strings.Cut(option, "=")
+			if !ok || value == "" {
+				return nil, fmt.Errorf("invalid %s option %q, expected key=value", WrapperDirective, option)
+			}
+			switch key {
+			case "impl":
+				names := strings.Split(value, "+")
+				for i, name := range names {
+					if !strings.HasPrefix(name, "function.") {
+						names[i] = "function." + name
+					}
+				}
+				impl, err := ImplFromString(strings.Join(names, "+"))
+				if err != nil {
+					return nil, fmt.Errorf("invalid %s option %q: %w", WrapperDirective, option, err)
+				}
+				directive.Impl = impl
+			case "json":
+				naming, err := ParseJSONFieldNaming(value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid %s option %q: %w", WrapperDirective, option, err)
+				}
+				directive.FieldNaming = &naming
+			case "replace":
+				if directive.TypeReplacements == nil {
+					directive.TypeReplacements = make(map[string]string)
+				}
+				for _, repl := range strings.Split(value, ",") {
+					typ, jsonType, ok := strings.Cut(repl, ":")
+					if !ok || typ == "" || jsonType == "" {
+						return nil, fmt.Errorf("invalid %s option %q, expected replace=Type:JSONType", WrapperDirective, option)
+					}
+					directive.TypeReplacements[typ] = jsonType
+				}
+			case "results":
+				if value != "named" && slices.Contains(strings.Split(value, ","), "") {
+					return nil, fmt.Errorf("invalid %s option %q, expected results=named or comma separated names", WrapperDirective, option)
+				}
+				directive.Results = value
+			default:
+				return nil, fmt.Errorf("unknown %s option %q", WrapperDirective, option)
+			}
+		}
+		return directive, nil
+	}
+	return nil, nil
+}
+
+// String returns the directive as comment line.
+func (d *wrapperDirective) String() string {
+	var b strings.Builder
+	b.WriteString(WrapperDirective)
+	if d.Impl != 0 {
+		fmt.Fprintf(&b, " impl=%s", strings.ReplaceAll(strings.TrimPrefix(d.Impl.String(), "function."), "+function.", "+"))
+	}
+	if d.FieldNaming != nil {
+		fmt.Fprintf(&b, " json=%s", *d.FieldNaming)
+	}
+	if len(d.TypeReplacements) > 0 {
+		repls := make([]string, 0, len(d.TypeReplacements))
+		for typ, jsonType := range d.TypeReplacements {
+			repls = append(repls, typ+":"+jsonType)
+		}
+		sort.Strings(repls)
+		fmt.Fprintf(&b, " replace=%s", strings.Join(repls, ","))
+	}
+	if d.Results != "" {
+		fmt.Fprintf(&b, " results=%s", d.Results)
+	}
+	return b.String()
+}
+
+// resultNames returns the names of the non-error results
+// of funcType for the results option of the directive
+// or nil if the option is not set.
+func (d *wrapperDirective) resultNames(funcType *ast.FuncType) ([]string, error) {
+	if d == nil || d.Results == "" {
+		return nil, nil
+	}
+	resultTypes := funcTypeResultTypes(funcType, "")
+	numResults := len(resultTypes)
+	if numResults > 0 && resultTypes[numResults-1] == "error" {
+		numResults--
+	}
+	if numResults == 0 {
+		return nil, fmt.Errorf("%s option results=%s used for function without non-error results", WrapperDirective, d.Results)
+	}
+	if d.Results != "named" {
+		names := strings.Split(d.Results, ",")
+		if len(names) != numResults {
+			return nil, fmt.Errorf("%s option results=%s has %d names but the function has %d non-error results", WrapperDirective, d.Results, len(names), numResults)
+		}
+		return names, nil
+	}
+	var names []string
+	for _, field := range funcType.Results.List {
+		if len(field.Names) == 0 {
+			return nil, fmt.Errorf("%s option results=named used for function with unnamed results", WrapperDirective)
+		}
+		for _, name := range field.Names {
+			if name.Name == "_" {
+				return nil, fmt.Errorf("%s option results=named used for function with blank result name", WrapperDirective)
+			}
+			names = append(names, name.Name)
+		}
+	}
+	return names[:numResults], nil
+}
+
+// jsonOptions returns the passed options
+// overridden by the options of the directive.
+func (d *wrapperDirective) jsonOptions(options JSONOptions) JSONOptions {
+	if d == nil {
+		return options
+	}
+	if d.FieldNaming != nil {
+		options.FieldNaming = *d.FieldNaming
+	}
+	if len(d.TypeReplacements) > 0 {
+		merged := make(map[string]string, len(options.TypeReplacements)+len(d.TypeReplacements))
+		for typ, jsonType := range options.TypeReplacements {
+			merged[typ] = jsonType
+		}
+		for typ, jsonType := range d.TypeReplacements {
+			merged[typ] = jsonType
+		}
+		options.TypeReplacements = merged
+	}
+	return options
+}
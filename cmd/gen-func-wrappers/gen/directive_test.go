@@ -0,0 +1,113 @@
+package gen
+
+import (
+	"go/ast"
+	"go/parser"
+	"reflect"
+	"testing"
+)
+
+func Test_parseWrapperDirective(t *testing.T) {
+	tests := []struct {
+		name    string
+		comment string
+		want    string // wrapperDirective.String() or empty for nil
+		wantErr bool
+	}{
+		{name: "no directive", comment: "// myFunc wraps MyFunc as function.Wrapper"},
+		{name: "other directive", comment: "//gen:wrappers-for-interface MyService"},
+		{name: "empty", comment: "//gen:wrapper", want: "//gen:wrapper"},
+		{name: "impl", comment: "//gen:wrapper impl=CallWithJSONWrapper", want: "//gen:wrapper impl=CallWithJSONWrapper"},
+		{name: "qualified impl", comment: "//gen:wrapper impl=function.Description", want: "//gen:wrapper impl=Description"},
+		{name: "combined impl", comment: "//gen:wrapper impl=Wrapper+function.CallWithURLValuesWrapper", want: "//gen:wrapper impl=Wrapper+CallWithURLValuesWrapper"},
+		{name: "results", comment: "//gen:wrapper results=head,tail", want: "//gen:wrapper results=head,tail"},
+		{name: "all", comment: "//gen:wrapper results=named replace=b.T:b.U,a.T:a.U json=snake_case impl=Wrapper", want: "//gen:wrapper impl=Wrapper json=snake_case replace=a.T:a.U,b.T:b.U results=named"},
+
+		// Invalid:
+		{name: "unknown option", comment: "//gen:wrapper color=red", wantErr: true},
+		{name: "missing value", comment: "//gen:wrapper json", wantErr: true},
+		{name: "invalid impl", comment: "//gen:wrapper impl=Unknown", wantErr: true},
+		{name: "invalid json", comment: "//gen:wrapper json=kebab", wantErr: true},
+		{name: "invalid replace", comment: "//gen:wrapper replace=a.T", wantErr: true},
+		{name: "empty result name", comment: "//gen:wrapper results=head,", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comment := &ast.CommentGroup{List: []*ast.Comment{{Text: tt.comment}}}
+			got, err := parseWrapperDirective(comment)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseWrapperDirective() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			gotStr := ""
+			if got != nil {
+				gotStr = got.String()
+			}
+			if gotStr != tt.want {
+				t.Errorf("parseWrapperDirective() = %q, want %q", gotStr, tt.want)
+			}
+		})
+	}
+}
+
+func Test_wrapperDirective_jsonOptions(t *testing.T) {
+	snakeCase := JSONFieldNamingSnakeCase
+	directive := &wrapperDirective{
+		FieldNaming:      &snakeCase,
+		TypeReplacements: map[string]string{"b.T": "b.V"},
+	}
+	global := JSONOptions{
+		TypeReplacements: map[string]string{"a.T": "a.U", "b.T": "b.U"},
+		FieldNaming:      JSONFieldNamingCamelCase,
+	}
+	got := directive.jsonOptions(global)
+	if got.FieldNaming != JSONFieldNamingSnakeCase {
+		t.Errorf("FieldNaming = %q", got.FieldNaming)
+	}
+	if got.TypeReplacements["a.T"] != "a.U" || got.TypeReplacements["b.T"] != "b.V" {
+		t.Errorf("TypeReplacements = %#v", got.TypeReplacements)
+	}
+	if global.TypeReplacements["b.T"] != "b.U" {
+		t.Error("global TypeReplacements modified")
+	}
+	var nilDirective *wrapperDirective
+	if nilDirective.jsonOptions(global).FieldNaming != JSONFieldNamingCamelCase {
+		t.Error("nil directive must not change options")
+	}
+}
+
+func Test_wrapperDirective_resultNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		results string
+		funcSrc string
+		want    []string
+		wantErr bool
+	}{
+		{name: "not set", funcSrc: "func(s string) (string, error)", want: nil},
+		{name: "explicit", results: "head,tail", funcSrc: "func(s string) (string, string, error)", want: []string{"head", "tail"}},
+		{name: "explicit without error", results: "head", funcSrc: "func(s string) string", want: []string{"head"}},
+		{name: "named", results: "named", funcSrc: "func(s string) (head, tail string, err error)", want: []string{"head", "tail"}},
+
+		// Invalid:
+		{name: "wrong number", results: "head", funcSrc: "func(s string) (string, string, error)", wantErr: true},
+		{name: "only error", results: "head", funcSrc: "func(s string) error", wantErr: true},
+		{name: "unnamed", results: "named", funcSrc: "func(s string) (string, string)", wantErr: true},
+		{name: "blank", results: "named", funcSrc: "func(s string) (head, _ string)", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := parser.ParseExpr(tt.funcSrc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			directive := &wrapperDirective{Results: tt.results}
+			got, err := directive.resultNames(expr.(*ast.FuncType))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resultNames() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resultNames() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// DocsFilename is the name of the markdown documentation file
+// written per package by WriteDocs.
+const DocsFilename = "wrappers.md"
+
+// docsFileHeader is the first line of files
+// written to DocsFilename.
+const docsFileHeader = "<!-- Code generated by gen-func-wrappers. DO NOT EDIT. -->\n"
+
+// docsFunc is a wrapped function or interface method
+// documented by WriteDocs.
+type docsFunc struct {
+	Name            string
+	Recv            string // interface type of a method
+	Doc             string
+	ArgNames        []string
+	ArgTypes        []string
+	ArgDescriptions []string
+	ResultTypes     []string
+	ResultNames     []string // of the non-error results, optional
+}
+
+// Signature returns the Go signature of the function.
+func (f *docsFunc) Signature() string {
+	var b strings.Builder
+	if f.Recv != "" {
+		b.WriteString("func (" + f.Recv + ") " + strings.TrimPrefix(f.Name, f.Recv+".") + "(")
+	} else {
+		b.WriteString("func " + f.Name + "(")
+	}
+	for i, argName := range f.ArgNames {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(argName + " " + f.ArgTypes[i])
+	}
+	b.WriteString(")")
+	switch len(f.ResultTypes) {
+	case 0:
+	case 1:
+		b.WriteString(" " + f.ResultTypes[0])
+	default:
+		b.WriteString(" (" + strings.Join(f.ResultTypes, ", ") + ")")
+	}
+	return b.String()
+}
+
+// WriteDocs writes a markdown documentation to the file DocsFilename
+// of the package in path, or recursively of all packages in sub-directories
+// if path ends with "...", listing every wrapped function and every method
+// of interface wrappers with its signature, a table of its arguments
+// with name, type and description, and its result types
+// named by the results option of a WrapperDirective.
+// The argument descriptions are parsed from the doc comments
+// like for the ArgDescriptions of the generated wrappers.
+// Packages without wrappers are skipped.
+// Files and directories for which the optional ignored function
+// returns true are skipped.
+func WriteDocs(path string, verbose bool, printTo io.Writer, ignored func(path string) bool) error {
+	if ignored == nil {
+		ignored = func(string) bool { return false }
+	}
+	return writePackageFiles(path, DocsFilename, verbose, printTo, ignored, func(cache *packageCache, pkg *packages.Package, pkgDir string) ([]byte, error) {
+		funcs, err := packageDocsFuncs(cache, pkg, pkgDir, ignored)
+		if err != nil || len(funcs) == 0 {
+			return nil, err
+		}
+		return docsMarkdown(pkg, funcs), nil
+	})
+}
+
+// packageDocsFuncs returns the functions wrapped in the files of pkg
+// sorted by name.
+func packageDocsFuncs(cache *packageCache, pkg *packages.Package, pkgDir string, ignored func(path string) bool) ([]*docsFunc, error) {
+	files := packageFiles(pkg, pkgDir)
+	fileNames := make([]string, 0, len(files))
+	for fileName := range files {
+		if !isGeneratedFile(fileName) && !ignored(fileName) {
+			fileNames = append(fileNames, fileName)
+		}
+	}
+	sort.Strings(fileNames)
+
+	var (
+		funcs        []*docsFunc
+		names        = make(map[string]bool)
+		pkgGenerated = newGeneratedFile(GeneratedFilename, "")
+	)
+	addFunc := func(name, recv string, funcDecl *ast.FuncDecl, funcPackage string, typeArgs []string, directive *wrapperDirective) error {
+		if names[name] {
+			// Multiple wrappers of the same function
+			return nil
+		}
+		names[name] = true
+		f := &docsFunc{
+			Name:            name,
+			Recv:            recv,
+			Doc:             strings.TrimSpace(funcDecl.Doc.Text()),
+			ArgNames:        funcTypeArgNames(funcDecl.Type),
+			ArgTypes:        funcTypeArgTypes(funcDecl.Type, funcPackage),
+			ArgDescriptions: funcDeclArgDescriptions(funcDecl),
+			ResultTypes:     funcTypeResultTypes(funcDecl.Type, funcPackage),
+		}
+		resultNames, err := directive.resultNames(funcDecl.Type)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		f.ResultNames = resultNames
+		typeParams := funcTypeParamNames(funcDecl.Type)
+		if len(typeArgs) != len(typeParams) {
+			return fmt.Errorf("function %s has %d type parameters but %d type arguments are given", name, len(typeParams), len(typeArgs))
+		}
+		if len(typeArgs) > 0 {
+			instances := make(map[string]string, len(typeParams))
+			for i, typeParam := range typeParams {
+				instances[typeParam] = typeArgs[i]
+			}
+			for i := range f.ArgTypes {
+				f.ArgTypes[i] = instantiateTypeParams(f.ArgTypes[i], funcPackage, instances)
+			}
+			for i := range f.ResultTypes {
+				f.ResultTypes[i] = instantiateTypeParams(f.ResultTypes[i], funcPackage, instances)
+			}
+		}
+		funcs = append(funcs, f)
+		return nil
+	}
+	for _, fileName := range fileNames {
+		file := files[fileName]
+		wrappers, err := findFunctionWrappers(pkg.Fset, file)
+		if err != nil {
+			return nil, err
+		}
+		ifaceWrappers := findInterfaceWrappers(file)
+		if len(wrappers) == 0 && len(ifaceWrappers) == 0 {
+			continue
+		}
+
+		// The wrapped functions are looked up in the scope of the file
+		// and of the file with the generated wrapper types
+		// that has the imports of the wrapped functions
+		scopeFiles := []*ast.File{file}
+		genFileName := generatedFileFor(fileName, file, pkgGenerated).fileName
+		if generated, ok := files[filepath.Join(pkgDir, genFileName)]; ok {
+			scopeFiles = append(scopeFiles, generated)
+		}
+		functions, err := localAndImportedFunctions(cache, pkg, pkgDir, scopeFiles...)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, wrapper := range wrappers {
+			wrappedFuncPackage, wrappedFuncName := wrapper.WrappedFuncPkgAndFuncName()
+			wrappedFunc, _, err := findFunc(functions, wrappedFuncPackage, wrappedFuncName)
+			if err != nil {
+				return nil, errorAt(pkg.Fset, wrapper.Pos, err)
+			}
+			typeArgs, err := wrapper.WrappedFuncTypeArgs()
+			if err != nil {
+				return nil, errorAt(pkg.Fset, wrapper.Pos, err)
+			}
+			err = addFunc(wrapper.WrappedFunc, "", wrappedFunc.Decl, wrappedFuncPackage, typeArgs, wrapper.Directive)
+			if err != nil {
+				return nil, errorAt(pkg.Fset, wrapper.Pos, err)
+			}
+		}
+
+		for _, iw := range ifaceWrappers {
+			ifacePackage, ifaceName := iw.PkgAndTypeName()
+			iface, referencedPkg, err := findInterface(functions, ifacePackage, ifaceName)
+			if err != nil {
+				return nil, errorAt(pkg.Fset, iw.Pos(), err)
+			}
+			methods, err := iw.methods(iface, referencedPkg.ImportLine != "")
+			if err != nil {
+				return nil, errorAt(pkg.Fset, iw.Pos(), err)
+			}
+			for _, method := range methods {
+				err = addFunc(iw.Interface+"."+method.Name.Name, iw.Interface, method, ifacePackage, nil, nil)
+				if err != nil {
+					return nil, errorAt(pkg.Fset, iw.Pos(), err)
+				}
+			}
+		}
+	}
+	sort.Slice(funcs, func(i, j int) bool { return funcs[i].Name < funcs[j].Name })
+	return funcs, nil
+}
+
+// docsMarkdown returns the markdown documentation of the funcs of pkg.
+func docsMarkdown(pkg *packages.Package, funcs []*docsFunc) []byte {
+	var b bytes.Buffer
+	b.WriteString(docsFileHeader)
+	fmt.Fprintf(&b, "\n# Package %s\n\n", pkg.Name)
+	fmt.Fprintf(&b, "`import %q`\n", pkg.PkgPath)
+
+	b.WriteString("\n## Functions\n\n")
+	for _, f := range funcs {
+		fmt.Fprintf(&b, "- [%s](#%s)\n", f.Name, markdownAnchor(f.Name))
+	}
+
+	for _, f := range funcs {
+		fmt.Fprintf(&b, "\n## %s\n\n", f.Name)
+		fmt.Fprintf(&b, "```go\n%s\n```\n", f.Signature())
+		if f.Doc != "" {
+			fmt.Fprintf(&b, "\n%s\n", f.Doc)
+		}
+		if len(f.ArgNames) > 0 {
+			b.WriteString("\n| Argument | Type | Description |\n")
+			b.WriteString("|----------|------|-------------|\n")
+			for i, argName := range f.ArgNames {
+				fmt.Fprintf(&b, "| %s | `%s` | %s |\n", argName, markdownTableCell(f.ArgTypes[i]), markdownTableCell(f.ArgDescriptions[i]))
+			}
+		}
+		if len(f.ResultTypes) > 0 {
+			b.WriteString("\nResults: ")
+			for i, resultType := range f.ResultTypes {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				if i < len(f.ResultNames) {
+					fmt.Fprintf(&b, "`%s %s`", f.ResultNames[i], resultType)
+				} else {
+					fmt.Fprintf(&b, "`%s`", resultType)
+				}
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.Bytes()
+}
+
+// markdownAnchor returns the anchor of a markdown heading
+// like generated by GitHub.
+func markdownAnchor(heading string) string {
+	return strings.Map(
+		func(r rune) rune {
+			switch {
+			case r >= 'A' && r <= 'Z':
+				return r - 'A' + 'a'
+			case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+				return r
+			case r == ' ':
+				return '-'
+			}
+			return -1
+		},
+		heading,
+	)
+}
+
+// markdownTableCell escapes the pipe characters of text
+// for use in a markdown table cell.
+func markdownTableCell(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
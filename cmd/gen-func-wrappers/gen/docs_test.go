@@ -0,0 +1,83 @@
+package gen
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_docsMarkdown(t *testing.T) {
+	root := writeTestModule(t, map[string]string{
+		"a/a.go": `package a
+
+import (
+	"context"
+
+	"github.com/domonda/go-function"
+)
+
+type User struct{ Name string }
+
+// GetUser returns a user
+//   id: the user ID
+func GetUser(ctx context.Context, id int64) (*User, error) { return nil, nil }
+
+func First[T any](items []T) (T, bool) { var t T; return t, false }
+
+// Service is a service
+type Service interface {
+	Count() int
+}
+
+var getUserWrapper = function.WrapperTODO(GetUser)
+
+//gen:wrapper results=first,found
+var firstWrapper = function.WrapperTODO(First[User])
+
+//gen:wrappers-for-interface Service
+`,
+	})
+	pkgDir := filepath.Join(root, "a")
+	pkg, err := loadPackage(pkgDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	funcs, err := packageDocsFuncs(newPackageCache(), pkg, pkgDir, func(string) bool { return false })
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(docsMarkdown(pkg, funcs))
+	for _, want := range []string{
+		docsFileHeader + "\n# Package a\n\n`import \"example.com/testmod/a\"`\n",
+		"- [First[User]](#firstuser)\n- [GetUser](#getuser)\n- [Service.Count](#servicecount)\n",
+		"## First[User]\n\n```go\nfunc First[User](items []User) (User, bool)\n```\n",
+		"\nResults: `first User`, `found bool`\n",
+		"## GetUser\n\n```go\nfunc GetUser(ctx context.Context, id int64) (*User, error)\n```\n",
+		"| id | `int64` | the user ID |\n",
+		"\nResults: `*User`, `error`\n",
+		"## Service.Count\n\n```go\nfunc (Service) Count() int\n```\n\nResults: `int`\n",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("markdown does not contain:\n%s\nmarkdown:\n%s", want, src)
+		}
+	}
+}
+
+func Test_markdownAnchor(t *testing.T) {
+	tests := []struct {
+		heading string
+		want    string
+	}{
+		{heading: "GetUser", want: "getuser"},
+		{heading: "Service.Get", want: "serviceget"},
+		{heading: "First[mypkg.T]", want: "firstmypkgt"},
+		{heading: "snake_case name", want: "snake_case-name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.heading, func(t *testing.T) {
+			if got := markdownAnchor(tt.heading); got != tt.want {
+				t.Errorf("markdownAnchor(%q) = %q, want %q", tt.heading, got, tt.want)
+			}
+		})
+	}
+}
@@ -18,63 +18,138 @@ const (
 	ImplCallWithStringsWrapper
 	ImplCallWithNamedStringsWrapper
 	ImplCallWithJSONWrapper
+	// ImplCallWithURLValuesWrapper is not part of ImplWrapper
+	// and only implemented when requested.
+	ImplCallWithURLValuesWrapper
 
 	ImplWrapper = ImplDescription | ImplCallWrapper | ImplCallWithStringsWrapper | ImplCallWithNamedStringsWrapper | ImplCallWithJSONWrapper
 )
 
+// implNames are the interfaces that can be implemented
+// with function.Wrapper first so that it is used
+// instead of its embedded interfaces by Impl.String.
+var implNames = []struct {
+	impl Impl
+	name string
+}{
+	{ImplWrapper, "function.Wrapper"},
+	{ImplDescription, "function.Description"},
+	{ImplCallWrapper, "function.CallWrapper"},
+	{ImplCallWithStringsWrapper, "function.CallWithStringsWrapper"},
+	{ImplCallWithNamedStringsWrapper, "function.CallWithNamedStringsWrapper"},
+	{ImplCallWithJSONWrapper, "function.CallWithJSONWrapper"},
+	{ImplCallWithURLValuesWrapper, "function.CallWithURLValuesWrapper"},
+}
+
+// ImplFromString parses an interface name like "function.Wrapper"
+// or multiple names joined with "+" like
+// "function.Wrapper+function.CallWithURLValuesWrapper".
 func ImplFromString(str string) (Impl, error) {
-	switch str {
-	case "function.Wrapper":
-		return ImplWrapper, nil
-	case "function.Description":
-		return ImplDescription, nil
-	case "function.CallWrapper":
-		return ImplCallWrapper, nil
-	case "function.CallWithStringsWrapper":
-		return ImplCallWithStringsWrapper, nil
-	case "function.CallWithNamedStringsWrapper":
-		return ImplCallWithNamedStringsWrapper, nil
-	case "function.ImplCallWithJSONWrapper":
-		return ImplCallWithJSONWrapper, nil
-	default:
-		return 0, fmt.Errorf("can't implement %q", str)
+	var impl Impl
+	for _, name := range strings.Split(str, "+") {
+		found := false
+		for _, n := range implNames {
+			if n.name == name {
+				impl |= n.impl
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("can't implement %q", str)
+		}
 	}
+	return impl, nil
 }
 
 func (impl Impl) String() string {
-	switch impl {
-	case ImplWrapper:
-		return "function.Wrapper"
-	case ImplDescription:
-		return "function.Description"
-	case ImplCallWrapper:
-		return "function.CallWrapper"
-	case ImplCallWithStringsWrapper:
-		return "function.CallWithStringsWrapper"
-	case ImplCallWithNamedStringsWrapper:
-		return "function.CallWithNamedStringsWrapper"
-	case ImplCallWithJSONWrapper:
-		return "function.CallWithJSONWrapper"
-	default:
+	var names []string
+	remaining := impl
+	for _, n := range implNames {
+		if remaining&n.impl == n.impl {
+			names = append(names, n.name)
+			remaining &^= n.impl
+		}
+	}
+	if len(names) == 0 || remaining != 0 {
 		return fmt.Sprintf("Impl(%d)", impl)
 	}
+	return strings.Join(names, "+")
+}
+
+func (impl Impl) WriteFunctionWrapper(w io.Writer, funcFile *ParsedFile, funcDecl *ast.FuncDecl, implType, funcPackage string, neededImportLines map[string]struct{}, jsonOptions JSONOptions, hooks MethodHooks) error {
+	return impl.writeWrapper(w, funcFile, funcDecl, nil, implType, funcPackage, "", "", neededImportLines, jsonOptions, hooks)
 }
 
-func (impl Impl) WriteFunctionWrapper(w io.Writer, funcFile *ast.File, funcDecl *ast.FuncDecl, implType, funcPackage string, neededImportLines map[string]struct{}, jsonTypeReplacements map[string]string) error {
+// WriteGenericFunctionWrapper writes a wrapper type implType for the
+// instantiation of the generic function funcDecl with typeArgs.
+// The type arguments are expected to be valid in the file of the wrapper.
+func (impl Impl) WriteGenericFunctionWrapper(w io.Writer, funcFile *ParsedFile, funcDecl *ast.FuncDecl, typeArgs []string, implType, funcPackage string, neededImportLines map[string]struct{}, jsonOptions JSONOptions, hooks MethodHooks) error {
+	return impl.writeWrapper(w, funcFile, funcDecl, typeArgs, implType, funcPackage, "", "", neededImportLines, jsonOptions, hooks)
+}
+
+// WriteMethodWrapper writes a wrapper type implType for the method methodDecl
+// of the interface type ifaceType that calls the method of its impl field.
+// The Recv of methodDecl is ignored.
+func (impl Impl) WriteMethodWrapper(w io.Writer, funcFile *ParsedFile, methodDecl *ast.FuncDecl, implType, funcPackage, ifaceType string, neededImportLines map[string]struct{}, jsonOptions JSONOptions, hooks MethodHooks) error {
+	return impl.writeWrapper(w, funcFile, methodDecl, nil, implType, funcPackage, ifaceType, "", neededImportLines, jsonOptions, hooks)
+}
+
+// writeWrapper writes a wrapper for a package function
+// if recvType is empty, else for a method of recvType.
+// typeArgs instantiate the type parameters of a generic function.
+// If sharedCalls is not empty, then the call methods are not generated
+// but delegate to the methods of the wrapper expression sharedCalls
+// of the same function that has to implement them.
+func (impl Impl) writeWrapper(w io.Writer, funcFile *ParsedFile, funcDecl *ast.FuncDecl, typeArgs []string, implType, funcPackage, recvType, sharedCalls string, neededImportLines map[string]struct{}, jsonOptions JSONOptions, hooks MethodHooks) error {
 	var (
 		argNames        = funcTypeArgNames(funcDecl.Type)
 		argDescriptions = funcDeclArgDescriptions(funcDecl)
 		argTypes        = funcTypeArgTypes(funcDecl.Type, funcPackage)
-		numArgs         = len(argTypes)
 		resultTypes     = funcTypeResultTypes(funcDecl.Type, funcPackage)
-		numResults      = len(resultTypes)
-		hasContextArg   = numArgs > 0 && argTypes[0] == "context.Context"
-		hasErrorResult  = numResults > 0 && resultTypes[numResults-1] == "error"
+		funcTypeString  = astvisit.FuncTypeString(funcDecl.Type)
 		funcPackageSel  = ""
 	)
 	if funcPackage != "" {
 		funcPackageSel = funcPackage + "."
 	}
+	var (
+		wrappedName = funcPackageSel + funcDecl.Name.Name
+		callee      = wrappedName
+		implStruct  = "struct{}"
+		callRecv    = "" // receiver name for methods calling the wrapped function
+	)
+	typeParams := funcTypeParamNames(funcDecl.Type)
+	if len(typeArgs) != len(typeParams) {
+		return fmt.Errorf("function %s has %d type parameters but %d type arguments are given", wrappedName, len(typeParams), len(typeArgs))
+	}
+	if len(typeArgs) > 0 {
+		instances := make(map[string]string, len(typeParams))
+		for i, typeParam := range typeParams {
+			instances[typeParam] = typeArgs[i]
+		}
+		for i := range argTypes {
+			argTypes[i] = instantiateTypeParams(argTypes[i], funcPackage, instances)
+		}
+		for i := range resultTypes {
+			resultTypes[i] = instantiateTypeParams(resultTypes[i], funcPackage, instances)
+		}
+		funcTypeString = instantiateTypeParams(funcTypeString, "", instances)
+		wrappedName += "[" + strings.Join(typeArgs, ", ") + "]"
+		callee = wrappedName
+	}
+	var (
+		numArgs        = len(argTypes)
+		numResults     = len(resultTypes)
+		hasContextArg  = numArgs > 0 && argTypes[0] == "context.Context"
+		hasErrorResult = numResults > 0 && resultTypes[numResults-1] == "error"
+	)
+	if recvType != "" {
+		wrappedName = recvType + "." + funcDecl.Name.Name
+		callee = "f.impl." + funcDecl.Name.Name
+		implStruct = "struct{ impl " + recvType + " }"
+		callRecv = "f "
+	}
 
 	writeFuncCall := func(args []string) {
 		numResultsWithoutErr := numResults
@@ -105,7 +180,7 @@ func (impl Impl) WriteFunctionWrapper(w io.Writer, funcFile *ast.File, funcDecl
 		if numArgs > 0 && strings.HasPrefix(argTypes[numArgs-1], "...") {
 			ellipsis = "..."
 		}
-		fmt.Fprintf(w, "%s%s(%s%s) // wrapped call\n", funcPackageSel, funcDecl.Name.Name, strings.Join(args, ", "), ellipsis)
+		fmt.Fprintf(w, "%s(%s%s) // wrapped call\n", callee, strings.Join(args, ", "), ellipsis)
 		if numResults > 0 {
 			fmt.Fprintf(w, "\treturn results, err\n")
 		} else {
@@ -113,12 +188,12 @@ func (impl Impl) WriteFunctionWrapper(w io.Writer, funcFile *ast.File, funcDecl
 		}
 	}
 
-	fmt.Fprintf(w, "// %s wraps %s%s as %s (generated code)\n", implType, funcPackageSel, funcDecl.Name.Name, impl)
-	fmt.Fprintf(w, "type %s struct{}\n\n", implType)
+	fmt.Fprintf(w, "// %s wraps %s as %s (generated code)\n", implType, wrappedName, impl)
+	fmt.Fprintf(w, "type %s %s\n\n", implType, implStruct)
 
 	// Always implement fmt.Stringer
 	fmt.Fprintf(w, "func (%s) String() string {\n", implType)
-	fmt.Fprintf(w, "\treturn \"%s%s%s\"\n", funcPackageSel, funcDecl.Name.Name, astvisit.FuncTypeString(funcDecl.Type))
+	fmt.Fprintf(w, "\treturn \"%s%s\"\n", wrappedName, funcTypeString)
 	fmt.Fprintf(w, "}\n\n")
 
 	// Always get imports of function arguments
@@ -182,13 +257,42 @@ func (impl Impl) WriteFunctionWrapper(w io.Writer, funcFile *ast.File, funcDecl
 		fmt.Fprintf(w, "}\n\n")
 	}
 
+	if sharedCalls != "" {
+		writeSharedCalls(w, impl, implType, sharedCalls, neededImportLines)
+		return nil
+	}
+
 	var ctxArgName string
-	if hasContextArg {
+	if hasContextArg || hooks != nil {
+		// Hooks can use the context even if the wrapped function has none
 		ctxArgName = "ctx "
 	} else if numArgs > 0 {
 		ctxArgName = "_ "
 	}
 
+	// hookMethod returns the HookMethod for the generated method name
+	hookMethod := func(name string) *HookMethod {
+		return &HookMethod{
+			WrapperType: implType,
+			WrappedFunc: wrappedName,
+			Name:        name,
+			CtxArg:      "ctx",
+			ImportLines: neededImportLines,
+		}
+	}
+	beginMethod := func(name string) error {
+		if hooks == nil {
+			return nil
+		}
+		return hooks.BeginMethod(w, hookMethod(name))
+	}
+	argError := func(name, argName, errExpr string) string {
+		if hooks == nil {
+			return errExpr
+		}
+		return hooks.ArgError(hookMethod(name), argName, errExpr)
+	}
+
 	resultsDecl := "(results []any, err error)"
 	if numResults == 0 {
 		resultsDecl = "([]any, error)"
@@ -200,11 +304,14 @@ func (impl Impl) WriteFunctionWrapper(w io.Writer, funcFile *ast.File, funcDecl
 		var argsArgName string
 		if !hasContextArg && numArgs > 0 || hasContextArg && numArgs > 1 {
 			argsArgName = "args "
-		} else if hasContextArg {
+		} else if ctxArgName != "" {
 			argsArgName = "_ "
 		}
 
-		fmt.Fprintf(w, "func (%s) Call(%scontext.Context, %s[]any) %s {\n", implType, ctxArgName, argsArgName, resultsDecl)
+		fmt.Fprintf(w, "func (%s%s) Call(%scontext.Context, %s[]any) %s {\n", callRecv, implType, ctxArgName, argsArgName, resultsDecl)
+		if err := beginMethod("Call"); err != nil {
+			return err
+		}
 		{
 			callParams := make([]string, numArgs)
 			for i, argType := range argTypes {
@@ -235,11 +342,11 @@ func (impl Impl) WriteFunctionWrapper(w io.Writer, funcFile *ast.File, funcDecl
 		var strsArgName string
 		if !hasContextArg && numArgs > 0 || hasContextArg && numArgs > 1 {
 			strsArgName = "strs "
-		} else if hasContextArg {
+		} else if ctxArgName != "" {
 			strsArgName = "_ "
 		}
 
-		receiver := ""
+		receiver := callRecv
 		for i, argName := range argNames {
 			if i == 0 && hasContextArg || argName == "_" {
 				continue
@@ -252,6 +359,9 @@ func (impl Impl) WriteFunctionWrapper(w io.Writer, funcFile *ast.File, funcDecl
 			}
 		}
 		fmt.Fprintf(w, "func (%s%s) CallWithStrings(%scontext.Context, %s...string) %s {\n", receiver, implType, ctxArgName, strsArgName, resultsDecl)
+		if err := beginMethod("CallWithStrings"); err != nil {
+			return err
+		}
 		{
 			var callParams []string
 			switch {
@@ -291,7 +401,7 @@ func (impl Impl) WriteFunctionWrapper(w io.Writer, funcFile *ast.File, funcDecl
 						fmt.Fprintf(w, "\t\terr := function.ScanString(strs[%d], &%s)\n", strsIndex, callParams[i])
 						fmt.Fprintf(w, "\t\tif err != nil {\n")
 						{
-							fmt.Fprintf(w, "\t\t\treturn nil, function.NewErrParseArgString(err, f, %q)\n", argName)
+							fmt.Fprintf(w, "\t\t\treturn nil, %s\n", argError("CallWithStrings", argName, fmt.Sprintf("function.NewErrParseArgString(err, f, %q)", argName)))
 						}
 						fmt.Fprintf(w, "\t\t}\n")
 					}
@@ -310,11 +420,11 @@ func (impl Impl) WriteFunctionWrapper(w io.Writer, funcFile *ast.File, funcDecl
 		var strsArgName string
 		if !hasContextArg && numArgs > 0 || hasContextArg && numArgs > 1 {
 			strsArgName = "strs "
-		} else if hasContextArg {
+		} else if ctxArgName != "" {
 			strsArgName = "_ "
 		}
 
-		receiver := ""
+		receiver := callRecv
 		for i, argName := range argNames {
 			if i == 0 && hasContextArg || argName == "_" {
 				continue
@@ -327,6 +437,9 @@ func (impl Impl) WriteFunctionWrapper(w io.Writer, funcFile *ast.File, funcDecl
 			}
 		}
 		fmt.Fprintf(w, "func (%s%s) CallWithNamedStrings(%scontext.Context, %smap[string]string) %s {\n", receiver, implType, ctxArgName, strsArgName, resultsDecl)
+		if err := beginMethod("CallWithNamedStrings"); err != nil {
+			return err
+		}
 		{
 			var callParams []string
 			switch {
@@ -362,7 +475,7 @@ func (impl Impl) WriteFunctionWrapper(w io.Writer, funcFile *ast.File, funcDecl
 						fmt.Fprintf(w, "\t\terr := function.ScanString(str, &%s)\n", callParams[i])
 						fmt.Fprintf(w, "\t\tif err != nil {\n")
 						{
-							fmt.Fprintf(w, "\t\t\treturn nil, function.NewErrParseArgString(err, f, %q)\n", argName)
+							fmt.Fprintf(w, "\t\t\treturn nil, %s\n", argError("CallWithNamedStrings", argName, fmt.Sprintf("function.NewErrParseArgString(err, f, %q)", argName)))
 						}
 						fmt.Fprintf(w, "\t\t}\n")
 					}
@@ -372,69 +485,218 @@ func (impl Impl) WriteFunctionWrapper(w io.Writer, funcFile *ast.File, funcDecl
 			writeFuncCall(callParams)
 		}
 		fmt.Fprintf(w, "}\n\n")
+	}
+
+	if impl&ImplCallWithJSONWrapper != 0 {
+		neededImportLines[`"context"`] = struct{}{}
+		neededImportLines[`"github.com/domonda/go-function"`] = struct{}{}
+
+		var argsJSONArgName string
+		if !hasContextArg && numArgs > 0 || hasContextArg && numArgs > 1 {
+			neededImportLines[`"encoding/json"`] = struct{}{}
+			argsJSONArgName = "argsJSON "
+		} else if ctxArgName != "" {
+			argsJSONArgName = "_ "
+		}
+
+		receiver := callRecv
+		if numArgs > 1 || numArgs == 1 && !hasContextArg {
+			receiver = "f "
+		}
+		fmt.Fprintf(w, "func (%s%s) CallWithJSON(%scontext.Context, %s[]byte) (results []any, err error) {\n", receiver, implType, ctxArgName, argsJSONArgName)
+		if err := beginMethod("CallWithJSON"); err != nil {
+			return err
+		}
+		{
+			var callParams []string
+			switch {
+			case numArgs == 1 && hasContextArg:
+				callParams = []string{"ctx"}
+
+			case numArgs > 0:
+				var variadicElemType, variadicField string
+				callParams = make([]string, len(argNames))
+				fmt.Fprintf(w, "\tvar a struct {\n")
+				for i, argName := range argNames {
+					if i == 0 && hasContextArg {
+						callParams[i] = "ctx"
+						continue
+					}
+					var fieldName, structTag string
+					if argName == "_" {
+						fieldName = "ignoredArg" + strconv.Itoa(i)
+					} else {
+						fieldName = exportedName(argName)
+						structTag = jsonOptions.FieldNaming.StructTag(argName)
+					}
+					argType := strings.Replace(argTypes[i], "...", "[]", 1)
+					if replacementType, ok := jsonOptions.TypeReplacements[argType]; ok {
+						argType = replacementType
+					} else if elemType, ok := strings.CutPrefix(argTypes[i], "..."); ok {
+						// The variadic arg is unmarshalled as slice of the replacement type
+						// and converted to a slice of the element type for the wrapped call
+						if replacementType, ok := jsonOptions.TypeReplacements[elemType]; ok {
+							argType = "[]" + replacementType
+							variadicElemType = elemType
+							variadicField = fieldName
+						}
+					}
+					if structTag != "" {
+						fmt.Fprintf(w, "\t\t%s %s %s\n", fieldName, argType, structTag)
+					} else {
+						fmt.Fprintf(w, "\t\t%s %s\n", fieldName, argType)
+					}
+
+					callParams[i] = "a." + fieldName
+				}
+				fmt.Fprintf(w, "\t}\n")
 
-		if impl&ImplCallWithJSONWrapper != 0 {
-			neededImportLines[`"context"`] = struct{}{}
-			neededImportLines[`"github.com/domonda/go-function"`] = struct{}{}
+				fmt.Fprintf(w, "\terr = json.Unmarshal(argsJSON, &a)\n")
+				fmt.Fprintf(w, "\tif err != nil {\n")
+				{
+					fmt.Fprintf(w, "\t\treturn nil, %s\n", argError("CallWithJSON", "", "function.NewErrParseArgsJSON(err, f, argsJSON)"))
+				}
+				fmt.Fprintf(w, "\t}\n")
 
-			var argsJSONArgName string
-			if !hasContextArg && numArgs > 0 || hasContextArg && numArgs > 1 {
-				neededImportLines[`"encoding/json"`] = struct{}{}
-				argsJSONArgName = "argsJSON "
-			} else if hasContextArg {
-				argsJSONArgName = "_ "
+				if variadicField != "" {
+					fmt.Fprintf(w, "\tvariadicArgs := make([]%s, len(a.%s))\n", variadicElemType, variadicField)
+					fmt.Fprintf(w, "\tfor i := range a.%s {\n", variadicField)
+					fmt.Fprintf(w, "\t\tvariadicArgs[i] = a.%s[i]\n", variadicField)
+					fmt.Fprintf(w, "\t}\n")
+					callParams[len(callParams)-1] = "variadicArgs"
+				}
 			}
+			writeFuncCall(callParams)
+		}
+		fmt.Fprintf(w, "}\n\n")
+	}
 
-			receiver := ""
-			if numArgs > 1 || numArgs == 1 && !hasContextArg {
+	if impl&ImplCallWithURLValuesWrapper != 0 {
+		neededImportLines[`"context"`] = struct{}{}
+		neededImportLines[`"net/url"`] = struct{}{}
+
+		var valsArgName string
+		if !hasContextArg && numArgs > 0 || hasContextArg && numArgs > 1 {
+			valsArgName = "vals "
+		} else if ctxArgName != "" {
+			valsArgName = "_ "
+		}
+
+		receiver := callRecv
+		for i, argName := range argNames {
+			if i == 0 && hasContextArg || argName == "_" {
+				continue
+			}
+			if argType := strings.Replace(argTypes[i], "...", "[]", 1); argType != "string" && argType != "[]string" {
+				// If there is any named argument that has to be scanned
+				// then the method code below needs a receiver
+				neededImportLines[`"github.com/domonda/go-function"`] = struct{}{}
 				receiver = "f "
+				break
 			}
-			fmt.Fprintf(w, "func (%s%s) CallWithJSON(%scontext.Context, %s[]byte) (results []any, err error) {\n", receiver, implType, ctxArgName, argsJSONArgName)
-			{
-				var callParams []string
-				switch {
-				case numArgs == 1 && hasContextArg:
-					callParams = []string{"ctx"}
-
-				case numArgs > 0:
-					callParams = make([]string, len(argNames))
-					fmt.Fprintf(w, "\tvar a struct {\n")
-					for i, argName := range argNames {
-						if i == 0 && hasContextArg {
-							callParams[i] = "ctx"
-							continue
-						}
-						if argName == "_" {
-							argName = "ignoredArg" + strconv.Itoa(i)
-						} else {
-							argName = exportedName(argName)
-						}
-						argType := strings.Replace(argTypes[i], "...", "[]", 1)
-						if replacementType, ok := jsonTypeReplacements[argType]; ok {
-							argType = replacementType
-						}
-						fmt.Fprintf(w, "\t\t%s %s\n", argName, argType)
+		}
+		fmt.Fprintf(w, "func (%s%s) CallWithURLValues(%scontext.Context, %surl.Values) %s {\n", receiver, implType, ctxArgName, valsArgName, resultsDecl)
+		if err := beginMethod("CallWithURLValues"); err != nil {
+			return err
+		}
+		{
+			var callParams []string
+			switch {
+			case numArgs == 1 && hasContextArg:
+				callParams = []string{"ctx"}
 
-						callParams[i] = "a." + argName
+			case numArgs > 0:
+				callParams = make([]string, len(argNames))
+				fmt.Fprintf(w, "\tvar a struct {\n")
+				for i, argName := range argNames {
+					if i == 0 && hasContextArg {
+						callParams[i] = "ctx"
+						continue
 					}
-					fmt.Fprintf(w, "\t}\n")
+					if argName == "_" {
+						argName = "ignoredArg" + strconv.Itoa(i)
+					}
+					argType := strings.Replace(argTypes[i], "...", "[]", 1)
+					fmt.Fprintf(w, "\t\t%s %s\n", argName, argType)
 
-					fmt.Fprintf(w, "\terr = json.Unmarshal(argsJSON, &a)\n")
-					fmt.Fprintf(w, "\tif err != nil {\n")
-					{
-						fmt.Fprintf(w, "\t\treturn nil, function.NewErrParseArgsJSON(err, f, argsJSON)\n")
+					callParams[i] = "a." + argName
+				}
+				fmt.Fprintf(w, "\t}\n")
+
+				for i, argName := range argNames {
+					if i == 0 && hasContextArg || argName == "_" {
+						continue
+					}
+					argType := strings.Replace(argTypes[i], "...", "[]", 1)
+					fmt.Fprintf(w, "\tif v := vals[%q]; len(v) > 0 {\n", argName)
+					switch {
+					case argType == "string" || argType == "[]string":
+						if argType == "string" {
+							fmt.Fprintf(w, "\t\t%s = v[0]\n", callParams[i])
+						} else {
+							fmt.Fprintf(w, "\t\t%s = v\n", callParams[i])
+						}
+					case strings.HasPrefix(argType, "[]") && argType != "[]byte" && argType != "[]uint8":
+						// All values of the key are used for slice arguments
+						fmt.Fprintf(w, "\t\t%s = make(%s, len(v))\n", callParams[i], argType)
+						fmt.Fprintf(w, "\t\tfor i := range v {\n")
+						fmt.Fprintf(w, "\t\t\terr := function.ScanString(v[i], &%s[i])\n", callParams[i])
+						fmt.Fprintf(w, "\t\t\tif err != nil {\n")
+						{
+							fmt.Fprintf(w, "\t\t\t\treturn nil, %s\n", argError("CallWithURLValues", argName, fmt.Sprintf("function.NewErrParseArgString(err, f, %q)", argName)))
+						}
+						fmt.Fprintf(w, "\t\t\t}\n")
+						fmt.Fprintf(w, "\t\t}\n")
+					default:
+						fmt.Fprintf(w, "\t\terr := function.ScanString(v[0], &%s)\n", callParams[i])
+						fmt.Fprintf(w, "\t\tif err != nil {\n")
+						{
+							fmt.Fprintf(w, "\t\t\treturn nil, %s\n", argError("CallWithURLValues", argName, fmt.Sprintf("function.NewErrParseArgString(err, f, %q)", argName)))
+						}
+						fmt.Fprintf(w, "\t\t}\n")
 					}
 					fmt.Fprintf(w, "\t}\n")
 				}
-				writeFuncCall(callParams)
 			}
-			fmt.Fprintf(w, "}\n\n")
+			writeFuncCall(callParams)
 		}
+		fmt.Fprintf(w, "}\n\n")
 	}
 
 	return nil
 }
 
+// sharedCallMethods are the call methods of the Impl interfaces
+// with the parameters and arguments used to delegate the calls.
+var sharedCallMethods = []struct {
+	impl         Impl
+	name, params string
+	args         string
+}{
+	{ImplCallWrapper, "Call", "args []any", "args"},
+	{ImplCallWithStringsWrapper, "CallWithStrings", "strs ...string", "strs..."},
+	{ImplCallWithNamedStringsWrapper, "CallWithNamedStrings", "strs map[string]string", "strs"},
+	{ImplCallWithJSONWrapper, "CallWithJSON", "argsJSON []byte", "argsJSON"},
+	{ImplCallWithURLValuesWrapper, "CallWithURLValues", "vals url.Values", "vals"},
+}
+
+// writeSharedCalls writes the call methods of impl for implType
+// that delegate to the same methods of the wrapper expression sharedCalls.
+func writeSharedCalls(w io.Writer, impl Impl, implType, sharedCalls string, neededImportLines map[string]struct{}) {
+	for _, m := range sharedCallMethods {
+		if impl&m.impl == 0 {
+			continue
+		}
+		neededImportLines[`"context"`] = struct{}{}
+		if m.impl == ImplCallWithURLValuesWrapper {
+			neededImportLines[`"net/url"`] = struct{}{}
+		}
+		fmt.Fprintf(w, "func (%s) %s(ctx context.Context, %s) ([]any, error) {\n", implType, m.name, m.params)
+		fmt.Fprintf(w, "\treturn %s.%s(ctx, %s) // shared call\n", sharedCalls, m.name, m.args)
+		fmt.Fprintf(w, "}\n\n")
+	}
+}
+
 // func (impl Impl) FunctionWrapperString(file *ast.File, funcDecl *ast.FuncDecl, implType, funcPackageSel string) (implSource string, err error) {
 // 	b := new(strings.Builder)
 // 	err = impl.WriteFunctionWrapper(b, file, funcDecl, implType, funcPackageSel)
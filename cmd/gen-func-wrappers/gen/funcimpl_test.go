@@ -1,6 +1,13 @@
 package gen
 
-import "testing"
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
 
 func Test_exportedName(t *testing.T) {
 	tests := map[string]string{
@@ -26,3 +33,174 @@ func Test_exportedName(t *testing.T) {
 		})
 	}
 }
+
+func TestImpl_WriteFunctionWrapper_variadicJSON(t *testing.T) {
+	const src = `package a
+
+import "io/fs"
+
+type Point struct{ X, Y int }
+
+func Join(sep string, parts ...string) string { return "" }
+
+func Path(name string, points ...Point) string { return name }
+
+func Open(files ...fs.FileReader) error { return nil }
+`
+	file, err := parser.ParseFile(token.NewFileSet(), "a.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	funcFile := &ParsedFile{File: file, ImportNames: map[string]string{"io/fs": "fs"}}
+	funcDecls := make(map[string]*ast.FuncDecl)
+	for _, decl := range file.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+			funcDecls[funcDecl.Name.Name] = funcDecl
+		}
+	}
+	jsonOptions := JSONOptions{TypeReplacements: map[string]string{"fs.FileReader": "fs.File"}}
+
+	tests := []struct {
+		funcName string
+		want     []string
+	}{
+		{
+			funcName: "Join",
+			want: []string{
+				"\t\tParts []string\n",
+				"results[0] = Join(a.Sep, a.Parts...) // wrapped call\n",
+			},
+		},
+		{
+			funcName: "Path",
+			want: []string{
+				"\t\tPoints []Point\n",
+				"results[0] = Path(a.Name, a.Points...) // wrapped call\n",
+			},
+		},
+		{
+			funcName: "Open",
+			want: []string{
+				"\t\tFiles []fs.File\n",
+				"\tvariadicArgs := make([]fs.FileReader, len(a.Files))\n" +
+					"\tfor i := range a.Files {\n" +
+					"\t\tvariadicArgs[i] = a.Files[i]\n" +
+					"\t}\n",
+				"err = Open(variadicArgs...) // wrapped call\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.funcName, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := ImplCallWithJSONWrapper.WriteFunctionWrapper(&buf, funcFile, funcDecls[tt.funcName], "wrapperT", "", make(map[string]struct{}), jsonOptions, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("generated CallWithJSON does not contain:\n%s\ngenerated:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestImplFromString(t *testing.T) {
+	tests := []struct {
+		str     string
+		want    Impl
+		wantErr bool
+	}{
+		{str: "function.Wrapper", want: ImplWrapper},
+		{str: "function.CallWithJSONWrapper", want: ImplCallWithJSONWrapper},
+		{str: "function.CallWithURLValuesWrapper", want: ImplCallWithURLValuesWrapper},
+		{str: "function.Wrapper+function.CallWithURLValuesWrapper", want: ImplWrapper | ImplCallWithURLValuesWrapper},
+		{str: "function.Description+function.CallWrapper", want: ImplDescription | ImplCallWrapper},
+
+		// Invalid:
+		{str: "function.Unknown", wantErr: true},
+		{str: "function.Wrapper+", wantErr: true},
+		{str: "Wrapper", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			got, err := ImplFromString(tt.str)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ImplFromString(%q) error = %v, wantErr %v", tt.str, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("ImplFromString(%q) = %d, want %d", tt.str, got, tt.want)
+			}
+			if !tt.wantErr && got.String() != tt.str {
+				t.Errorf("Impl(%d).String() = %q, want %q", got, got.String(), tt.str)
+			}
+		})
+	}
+}
+
+func TestImpl_WriteFunctionWrapper_urlValues(t *testing.T) {
+	const src = `package a
+
+import "context"
+
+func Find(ctx context.Context, name string, ids []int64, tags []string, limit int) error { return nil }
+
+func Sum(nums ...int) int { return 0 }
+`
+	file, err := parser.ParseFile(token.NewFileSet(), "a.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	funcFile := &ParsedFile{File: file, ImportNames: map[string]string{"context": "context"}}
+	funcDecls := make(map[string]*ast.FuncDecl)
+	for _, decl := range file.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+			funcDecls[funcDecl.Name.Name] = funcDecl
+		}
+	}
+
+	tests := []struct {
+		funcName string
+		want     []string
+	}{
+		{
+			funcName: "Find",
+			want: []string{
+				"func (f wrapperT) CallWithURLValues(ctx context.Context, vals url.Values) (results []any, err error) {\n",
+				"\tif v := vals[\"name\"]; len(v) > 0 {\n\t\ta.name = v[0]\n\t}\n",
+				"\tif v := vals[\"ids\"]; len(v) > 0 {\n" +
+					"\t\ta.ids = make([]int64, len(v))\n" +
+					"\t\tfor i := range v {\n" +
+					"\t\t\terr := function.ScanString(v[i], &a.ids[i])\n",
+				"\tif v := vals[\"tags\"]; len(v) > 0 {\n\t\ta.tags = v\n\t}\n",
+				"\t\terr := function.ScanString(v[0], &a.limit)\n",
+			},
+		},
+		{
+			funcName: "Sum",
+			want: []string{
+				"\t\tnums []int\n",
+				"results[0] = Sum(a.nums...) // wrapped call\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.funcName, func(t *testing.T) {
+			var buf bytes.Buffer
+			imports := make(map[string]struct{})
+			err := ImplCallWithURLValuesWrapper.WriteFunctionWrapper(&buf, funcFile, funcDecls[tt.funcName], "wrapperT", "", imports, JSONOptions{}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := imports[`"net/url"`]; !ok {
+				t.Errorf("net/url not imported: %v", imports)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("generated CallWithURLValues does not contain:\n%s\ngenerated:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
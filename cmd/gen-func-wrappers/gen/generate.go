@@ -3,25 +3,70 @@ package gen
 import (
 	"bytes"
 	"fmt"
-	"os"
+	"io"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"golang.org/x/tools/imports"
 )
 
-func PackageFunctions(pkgDir, genFilename, namePrefix string, printOnly bool, jsonTypeReplacements map[string]string, onlyFuncs ...string) error {
+const (
+	// GeneratedFilename is the name of the file
+	// with the generated wrapper types of a package
+	// written by RewriteDir and RewriteFile if genFile is true.
+	GeneratedFilename = "zz_generated_wrappers.go"
+
+	// ExportedFuncsFilename is the name of the file
+	// with the wrapper types for the exported functions
+	// of a package generated by PackageFunctions.
+	ExportedFuncsFilename = "zz_generated_exported_wrappers.go"
+)
+
+// isGeneratedFile returns true if filePath
+// is the path of a file completely generated by this package.
+func isGeneratedFile(filePath string) bool {
+	name := filepath.Base(filePath)
+	return name == GeneratedFilename || name == ExportedFuncsFilename || strings.HasPrefix(name, constrainedGeneratedFilePrefix)
+}
+
+// PackageFunctions generates the file genFilename in pkgDir
+// with a function.Wrapper implementation type for every exported
+// function of the package or only for the functions named in onlyFuncs.
+// The names of the generated types are the function names
+// prefixed with namePrefix.
+// Generic functions are skipped because they need type arguments.
+// If printTo is not nil then the generated source
+// is written to it instead of the file.
+func PackageFunctions(pkgDir, genFilename, namePrefix string, verbose bool, printTo io.Writer, jsonOptions JSONOptions, hooks MethodHooks, localImportPrefixes []string, onlyFuncs ...string) error {
 	pkg, funcs, err := parsePackage(pkgDir, genFilename, onlyFuncs...)
 	if err != nil {
 		return err
 	}
+	localImportPrefixes, err = localImportPrefixesFor(pkgDir, localImportPrefixes)
+	if err != nil {
+		return err
+	}
+
+	funcNames := make([]string, 0, len(funcs))
+	for funcName, fun := range funcs {
+		if fun.Decl.Type.TypeParams != nil {
+			if verbose {
+				fmt.Println("skipping generic function", funcName)
+			}
+			continue
+		}
+		funcNames = append(funcNames, funcName)
+	}
+	sort.Strings(funcNames)
 
 	importLines := map[string]struct{}{
-		`"reflect"`: {},
-		`"context"`: {},
-		`function "github.com/domonda/go-function"`: {},
+		`"reflect"`:                        {},
+		`"context"`:                        {},
+		`"github.com/domonda/go-function"`: {},
 	}
-	for _, fun := range funcs {
+	for _, funcName := range funcNames {
+		fun := funcs[funcName]
 		err = gatherFieldListImports(fun.File, fun.Decl.Type.Params, importLines)
 		if err != nil {
 			return err
@@ -31,6 +76,18 @@ func PackageFunctions(pkgDir, genFilename, namePrefix string, printOnly bool, js
 			return err
 		}
 	}
+
+	var body bytes.Buffer
+	for _, funcName := range funcNames {
+		fun := funcs[funcName]
+		err = ImplWrapper.WriteFunctionWrapper(&body, fun.File, fun.Decl, namePrefix+funcName, "", importLines, jsonOptions, hooks)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Write imports after the wrappers
+	// because they add their needed imports
 	var sortedImportLines []string
 	for l := range importLines {
 		sortedImportLines = append(sortedImportLines, l)
@@ -39,7 +96,7 @@ func PackageFunctions(pkgDir, genFilename, namePrefix string, printOnly bool, js
 
 	b := bytes.NewBuffer(nil)
 
-	fmt.Fprintf(b, "// This file has been AUTOGENERATED!\n\n")
+	fmt.Fprintf(b, "%s\n", generatedFileHeader)
 	fmt.Fprintf(b, "package %s\n\n", pkg.Name)
 	if len(sortedImportLines) > 0 {
 		fmt.Fprintf(b, "import (\n")
@@ -48,36 +105,23 @@ func PackageFunctions(pkgDir, genFilename, namePrefix string, printOnly bool, js
 		}
 		fmt.Fprintf(b, ")\n\n")
 	}
-
-	for funName, fun := range funcs {
-		err = ImplWrapper.WriteFunctionWrapper(b, fun.File, fun.Decl, namePrefix+funName, "", importLines, jsonTypeReplacements)
-		if err != nil {
-			return err
-		}
-	}
+	b.Write(body.Bytes())
 
 	genFileData := b.Bytes()
 	genFilePath := filepath.Join(pkgDir, genFilename)
 
-	imports.LocalPrefix = "github.com/domonda/"
+	imports.LocalPrefix = strings.Join(localImportPrefixes, ",")
 	genFileData, err = imports.Process(genFilePath, genFileData, &imports.Options{Comments: true, FormatOnly: true})
 	if err != nil {
 		return err
 	}
 
-	if printOnly {
-		fmt.Println(genFileData)
-	} else {
-		fmt.Println("Writing file", genFilePath)
-		err = os.WriteFile(genFilePath, genFileData, 0600)
-		if err != nil {
-			return err
+	if printTo != nil {
+		if verbose {
+			fmt.Println(genFilePath, "would be written as:")
 		}
+		_, err = printTo.Write(genFileData)
+		return err
 	}
-	// err = exec.Command("gofmt", "-s", "-w", genFile).Run()
-	// if err != nil {
-	// 	return err
-	// }
-
-	return nil
+	return writeFileIfChanged(genFilePath, genFileData, "writing", verbose, nil)
 }
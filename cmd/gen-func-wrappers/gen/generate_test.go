@@ -0,0 +1,88 @@
+package gen
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPackageFunctions(t *testing.T) {
+	root := writeTestModule(t, map[string]string{
+		"a/a.go": `package a
+
+import "context"
+
+type Point struct{ X, Y int }
+
+func Add(a, b int) int { return a + b }
+
+func Distance(ctx context.Context, p Point) (float64, error) { return 0, nil }
+
+func First[T any](items []T) T { return items[0] }
+
+func (p Point) Scale(f int) Point { return Point{p.X * f, p.Y * f} }
+
+func unexported(s string) string { return s }
+`,
+		"a/a_linux.go": `package a
+
+func Linux() {}
+`,
+	})
+	pkgDir := filepath.Join(root, "a")
+
+	err := PackageFunctions(pkgDir, ExportedFuncsFilename, "Func", false, nil, JSONOptions{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := os.ReadFile(filepath.Join(pkgDir, ExportedFuncsFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(source, []byte(generatedFileHeader)) {
+		t.Errorf("generated file does not start with header:\n%s", source)
+	}
+	for _, want := range []string{
+		"type FuncAdd struct{}",
+		"type FuncDistance struct{}",
+		`"context"`,
+	} {
+		if !bytes.Contains(source, []byte(want)) {
+			t.Errorf("generated file does not contain %q:\n%s", want, source)
+		}
+	}
+	for _, notWant := range []string{
+		"Funcunexported",
+		"FuncFirst", // generic
+		"FuncScale", // method
+		"FuncLinux", // build constraint
+		"FuncPoint", // type
+	} {
+		if bytes.Contains(source, []byte(notWant)) {
+			t.Errorf("generated file contains %q:\n%s", notWant, source)
+		}
+	}
+
+	// Generating again ignores the generated file
+	// instead of wrapping its exported types
+	var printed strings.Builder
+	err = PackageFunctions(pkgDir, ExportedFuncsFilename, "Func", false, &printed, JSONOptions{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if printed.String() != string(source) {
+		t.Errorf("printed source differs from written file:\n%s", printed.String())
+	}
+
+	// Only the named functions, also unexported ones
+	printed.Reset()
+	err = PackageFunctions(pkgDir, ExportedFuncsFilename, "Wrapped", false, &printed, JSONOptions{}, nil, nil, "unexported")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(printed.String(), "type Wrappedunexported struct{}") || strings.Contains(printed.String(), "WrappedAdd") {
+		t.Errorf("onlyFuncs not respected:\n%s", printed.String())
+	}
+}
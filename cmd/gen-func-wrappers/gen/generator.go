@@ -0,0 +1,166 @@
+package gen
+
+import (
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Generator generates the function wrappers of Go packages
+// like the gen-func-wrappers command so that build tools,
+// editor plugins and code generation pipelines
+// can embed the generation without running the command.
+// The zero value rewrites the wrappers in place
+// with default options.
+type Generator struct {
+	// GenFile writes the generated wrapper types
+	// to the file GeneratedFilename of each package
+	GenFile bool
+	// GenTests writes tests for the generated wrapper types
+	GenTests bool
+	// RemoveOrphans removes orphaned wrappers
+	// instead of returning ErrOrphanedWrapper
+	RemoveOrphans bool
+	// JSONOptions for the generated CallWithJSON methods
+	JSONOptions JSONOptions
+	// Hooks customize the generated call methods if not nil
+	Hooks MethodHooks
+	// LocalImportPrefixes of the imports grouped after the third party imports.
+	// If empty then DetectLocalImportPrefixes is used per package.
+	LocalImportPrefixes []string
+	// Ignored returns true for files and directories to skip if not nil
+	Ignored func(path string) bool
+	// Verbose prints what is generated to stdout
+	Verbose bool
+	// PrintOnly prints the generated files to the writer
+	// instead of writing them if not nil
+	PrintOnly io.Writer
+	// WatchOutput receives the verbose messages and the errors
+	// of Watch if not nil instead of os.Stdout and os.Stderr
+	WatchOutput io.Writer
+}
+
+// Result of Generator.Generate
+type Result struct {
+	// WrittenFiles are the sorted paths
+	// of the written or rewritten files.
+	// Unchanged files are not written.
+	WrittenFiles []string `json:"writtenFiles"`
+	// RemovedFiles are the sorted paths
+	// of the removed stale generated files.
+	RemovedFiles []string `json:"removedFiles"`
+	// Files are the reports of the processed Go files
+	// sorted by their path.
+	Files []*FileReport `json:"files"`
+	// Duration of the whole generation,
+	// marshalled to JSON as nanoseconds.
+	Duration time.Duration `json:"duration"`
+}
+
+// FileReport reports the wrappers of a processed Go file.
+type FileReport struct {
+	File     string           `json:"file"`
+	Wrappers []*WrapperReport `json:"wrappers"`
+	// Duration of processing the file,
+	// marshalled to JSON as nanoseconds.
+	Duration time.Duration `json:"duration"`
+	// Error of the file if it could not be processed
+	Error string `json:"error,omitempty"`
+}
+
+// WrapperReport reports a wrapper variable
+// or interface wrappers directive found in a file.
+type WrapperReport struct {
+	// Name of the wrapper variable or interface wrappers constructor
+	Name string `json:"name"`
+	// Wrapped function or interface
+	Wrapped string        `json:"wrapped"`
+	Status  WrapperStatus `json:"status"`
+	// Error of a failed or orphaned wrapper
+	Error string `json:"error,omitempty"`
+}
+
+// WrapperStatus is the result of generating a wrapper.
+type WrapperStatus string
+
+const (
+	// WrapperGenerated is the status of generated wrappers
+	WrapperGenerated WrapperStatus = "generated"
+	// WrapperOrphaned is the status of skipped wrappers
+	// of functions or interfaces that no longer exist
+	WrapperOrphaned WrapperStatus = "orphaned"
+	// WrapperRemoved is the status of removed orphaned wrappers
+	WrapperRemoved WrapperStatus = "removed"
+	// WrapperFailed is the status of wrappers
+	// that could not be generated
+	WrapperFailed WrapperStatus = "failed"
+)
+
+// Generate generates the wrappers for path
+// which can be a Go file, a package directory,
+// or a directory ending with "..." to include all sub-directories.
+// The returned Result lists the written and removed files
+// and the reports of the processed files.
+// It is also returned together with an error
+// for the files processed before the error.
+func (g *Generator) Generate(path string) (*Result, error) {
+	start := time.Now()
+	collector := new(resultCollector)
+	err := g.rewriteDir(path, collector)
+	result := collector.result()
+	result.Duration = time.Since(start)
+	return result, err
+}
+
+// resultCollector collects the results
+// of concurrently rewritten packages.
+// Methods of a nil *resultCollector do nothing.
+type resultCollector struct {
+	mtx     sync.Mutex
+	written []string
+	removed []string
+	files   []*FileReport
+}
+
+func (c *resultCollector) addWritten(filePath string) {
+	if c == nil {
+		return
+	}
+	c.mtx.Lock()
+	c.written = append(c.written, filePath)
+	c.mtx.Unlock()
+}
+
+func (c *resultCollector) addRemoved(filePath string) {
+	if c == nil {
+		return
+	}
+	c.mtx.Lock()
+	c.removed = append(c.removed, filePath)
+	c.mtx.Unlock()
+}
+
+func (c *resultCollector) addFileReport(report *FileReport) {
+	if c == nil {
+		return
+	}
+	c.mtx.Lock()
+	c.files = append(c.files, report)
+	c.mtx.Unlock()
+}
+
+func (c *resultCollector) result() *Result {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	r := &Result{
+		WrittenFiles: append([]string{}, c.written...),
+		RemovedFiles: append([]string{}, c.removed...),
+		Files:        append([]*FileReport{}, c.files...),
+	}
+	sort.Strings(r.WrittenFiles)
+	sort.Strings(r.RemovedFiles)
+	sort.SliceStable(r.Files, func(i, j int) bool { return r.Files[i].File < r.Files[j].File })
+	return r
+}
@@ -0,0 +1,109 @@
+package gen
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerator_Generate(t *testing.T) {
+	root := writeTestModule(t, map[string]string{
+		"a/a.go": `package a
+
+import "github.com/domonda/go-function"
+
+func Add(a, b int) int { return a + b }
+
+var addWrapper = function.WrapperTODO(Add)
+`,
+	})
+	g := &Generator{GenFile: true}
+
+	result, err := g.Generate(filepath.Join(root, "..."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join(root, "a", "a.go"),
+		filepath.Join(root, "a", GeneratedFilename),
+	}
+	if !reflect.DeepEqual(result.WrittenFiles, want) {
+		t.Errorf("WrittenFiles = %#v, want %#v", result.WrittenFiles, want)
+	}
+	if len(result.RemovedFiles) != 0 {
+		t.Errorf("RemovedFiles = %#v", result.RemovedFiles)
+	}
+
+	// Unchanged files are not written again
+	result, err = g.Generate(filepath.Join(root, "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.WrittenFiles) != 0 || len(result.RemovedFiles) != 0 {
+		t.Errorf("second Generate() = %#v", result)
+	}
+
+}
+
+func TestGenerator_Generate_report(t *testing.T) {
+	root := writeTestModule(t, map[string]string{
+		"a/a.go": `package a
+
+import "github.com/domonda/go-function"
+
+func Add(a, b int) int { return a + b }
+
+var addWrapper = function.WrapperTODO(Add)
+
+var subWrapper = function.WrapperTODO(Sub)
+`,
+		"a/b.go": `package a
+
+func Sub(a, b int) int { return a - b }
+`,
+	})
+	g := new(Generator)
+	_, err := g.Generate(filepath.Join(root, "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Remove the wrapped function of subWrapper
+	err = os.Remove(filepath.Join(root, "a", "b.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := g.Generate(filepath.Join(root, "a"))
+	if !errors.Is(err, ErrOrphanedWrapper) {
+		t.Fatalf("Generate() error = %v, want ErrOrphanedWrapper", err)
+	}
+	if len(result.Files) != 1 {
+		t.Fatalf("Files = %#v, want report of a.go", result.Files)
+	}
+	report := result.Files[0]
+	if report.File != filepath.Join(root, "a", "a.go") || report.Error == "" || report.Duration <= 0 {
+		t.Errorf("FileReport = %#v", report)
+	}
+	var statuses []WrapperStatus
+	for _, w := range report.Wrappers {
+		statuses = append(statuses, w.Status)
+	}
+	if !reflect.DeepEqual(statuses, []WrapperStatus{WrapperGenerated, WrapperOrphaned}) {
+		t.Errorf("wrapper statuses = %v", statuses)
+	}
+	if w := report.Wrappers[1]; w.Name != "subWrapper" || w.Wrapped != "Sub" || !strings.Contains(w.Error, "orphaned wrapper subWrapper of Sub") {
+		t.Errorf("orphaned WrapperReport = %#v", w)
+	}
+
+	g.RemoveOrphans = true
+	result, err = g.Generate(filepath.Join(root, "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.Files[0].Wrappers[1].Status; got != WrapperRemoved {
+		t.Errorf("status of removed orphan = %s", got)
+	}
+}
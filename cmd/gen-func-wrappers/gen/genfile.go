@@ -0,0 +1,203 @@
+package gen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/build/constraint"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ungerik/go-astvisit"
+)
+
+// generatedFileHeader is the first line of files
+// written to GeneratedFilename.
+const generatedFileHeader = "// Code generated by gen-func-wrappers. DO NOT EDIT.\n"
+
+// constrainedGeneratedFilePrefix is the file name prefix
+// of generated files for source files with build constraints.
+const constrainedGeneratedFilePrefix = "zz_generated_wrappers_"
+
+// generatedFile collects the wrapper types generated
+// for all files of a package to be written to GeneratedFilename,
+// or for a single file with build constraints to be written to
+// a file with the name of the source file prefixed
+// with constrainedGeneratedFilePrefix.
+type generatedFile struct {
+	fileName        string // without directory
+	buildConstraint string // //go:build line or empty
+	code            bytes.Buffer
+	importLines     map[string]struct{}
+}
+
+func newGeneratedFile(fileName, buildConstraint string) *generatedFile {
+	return &generatedFile{
+		fileName:        fileName,
+		buildConstraint: buildConstraint,
+		importLines:     make(map[string]struct{}),
+	}
+}
+
+// buildConstraint returns the //go:build line of file
+// or an empty string if file has none.
+func buildConstraint(file *ast.File) string {
+	for _, comment := range file.Comments {
+		if comment.Pos() > file.Package {
+			break
+		}
+		for _, c := range comment.List {
+			if constraint.IsGoBuild(c.Text) {
+				return c.Text
+			}
+		}
+	}
+	return ""
+}
+
+// hasFileNameConstraint returns true if the name of filePath
+// has a _GOOS or _GOARCH suffix that constrains the build.
+func hasFileNameConstraint(filePath string) bool {
+	// Match the file name against a build context
+	// that no GOOS or GOARCH suffix matches
+	// ignoring the content of the file
+	ctx := build.Default
+	ctx.GOOS = "nonexistentos"
+	ctx.GOARCH = "nonexistentarch"
+	ctx.OpenFile = func(string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("package p\n")), nil
+	}
+	match, err := ctx.MatchFile(filepath.Dir(filePath), filepath.Base(filePath))
+	return err == nil && !match
+}
+
+// generatedFileFor returns the generated file for the wrappers
+// of the source file filePath which is the generated file
+// of the whole package if the source file has no build constraints.
+func generatedFileFor(filePath string, file *ast.File, pkgFile *generatedFile) *generatedFile {
+	constraint := buildConstraint(file)
+	if constraint == "" && !hasFileNameConstraint(filePath) {
+		return pkgFile
+	}
+	// The generated file inherits file name constraints
+	// by having the same suffix as the source file
+	return newGeneratedFile(constrainedGeneratedFilePrefix+filepath.Base(filePath), constraint)
+}
+
+// write writes the generated code to the file in pkgDir
+// or removes an existing generated file if no code was generated.
+func (g *generatedFile) write(pkgDir, pkgName string, verbose bool, printTo io.Writer, localImportPrefixes []string, collector *resultCollector) error {
+	filePath := filepath.Join(pkgDir, g.fileName)
+	if g.code.Len() == 0 {
+		return removeGeneratedFile(filePath, verbose, printTo, collector)
+	}
+
+	var src bytes.Buffer
+	src.WriteString(generatedFileHeader)
+	if g.buildConstraint != "" {
+		fmt.Fprintf(&src, "\n%s\n", g.buildConstraint)
+	}
+	fmt.Fprintf(&src, "\npackage %s\n\n", pkgName)
+	src.Write(g.code.Bytes())
+	generated, err := astvisit.FormatFileWithImports(token.NewFileSet(), src.Bytes(), g.importLines, localImportPrefixes...)
+	if err != nil {
+		return err
+	}
+
+	if printTo != nil {
+		if verbose {
+			fmt.Println(filePath, "would be written as:")
+		}
+		_, err = printTo.Write(generated)
+		return err
+	}
+	return writeFileIfChanged(filePath, generated, "writing", verbose, collector)
+}
+
+// removeStaleGeneratedFiles removes the generated files and tests
+// for source files with build constraints in pkgDir
+// whose source file was deleted or renamed.
+func removeStaleGeneratedFiles(pkgDir string, verbose bool, printTo io.Writer, collector *resultCollector) error {
+	entries, err := os.ReadDir(pkgDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		sourceName, ok := strings.CutPrefix(entry.Name(), constrainedGeneratedFilePrefix)
+		if !ok || entry.IsDir() || entry.Name() == testFileName(GeneratedFilename) {
+			continue
+		}
+		if name, ok := strings.CutSuffix(sourceName, "_test.go"); ok {
+			sourceName = name + ".go"
+		}
+		_, err = os.Stat(filepath.Join(pkgDir, sourceName))
+		if !errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		err = removeGeneratedFile(filepath.Join(pkgDir, entry.Name()), verbose, printTo, collector)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeGeneratedFile removes the file filePath if it exists
+// and was generated by gen-func-wrappers
+// and adds it to the removed files of the optional collector.
+func removeGeneratedFile(filePath string, verbose bool, printTo io.Writer, collector *resultCollector) error {
+	existing, err := os.ReadFile(filePath) //#nosec G304
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !bytes.HasPrefix(existing, []byte(generatedFileHeader)) {
+		return fmt.Errorf("not removing %s because it was not generated by gen-func-wrappers", filePath)
+	}
+	if printTo != nil {
+		if verbose {
+			fmt.Println(filePath, "would be removed")
+		}
+		return nil
+	}
+	if verbose {
+		fmt.Println("removing", filePath)
+	}
+	err = os.Remove(filePath)
+	if err != nil {
+		return err
+	}
+	collector.addRemoved(filePath)
+	return nil
+}
+
+// writeFileIfChanged writes data to filePath
+// if the file does not already have the same content
+// so that file watchers and build caches
+// are not triggered by unchanged files.
+// A written file is added to the optional collector.
+func writeFileIfChanged(filePath string, data []byte, verb string, verbose bool, collector *resultCollector) error {
+	existing, err := os.ReadFile(filePath) //#nosec G304
+	if err == nil && bytes.Equal(existing, data) {
+		if verbose {
+			fmt.Println("unchanged", filePath)
+		}
+		return nil
+	}
+	if verbose {
+		fmt.Println(verb, filePath)
+	}
+	err = os.WriteFile(filePath, data, 0600)
+	if err != nil {
+		return err
+	}
+	collector.addWritten(filePath)
+	return nil
+}
@@ -0,0 +1,52 @@
+package gen
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func Test_buildConstraint(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{name: "none", source: "package pkg\n", want: ""},
+		{name: "go:build", source: "//go:build linux && !cgo\n\npackage pkg\n", want: "//go:build linux && !cgo"},
+		{name: "after header", source: "// Copyright\n\n//go:build ignore\n\n// Package pkg\npackage pkg\n", want: "//go:build ignore"},
+		{name: "after package clause", source: "package pkg\n\n//go:build linux\n", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := parser.ParseFile(token.NewFileSet(), "", tt.source, parser.ParseComments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := buildConstraint(file); got != tt.want {
+				t.Errorf("buildConstraint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_hasFileNameConstraint(t *testing.T) {
+	tests := []struct {
+		filePath string
+		want     bool
+	}{
+		{filePath: "pkg/file.go", want: false},
+		{filePath: "pkg/file_linux.go", want: true},
+		{filePath: "pkg/file_amd64.go", want: true},
+		{filePath: "pkg/file_windows_arm64.go", want: true},
+		{filePath: "pkg/file_unknown.go", want: false},
+		{filePath: "pkg/linux.go", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filePath, func(t *testing.T) {
+			if got := hasFileNameConstraint(tt.filePath); got != tt.want {
+				t.Errorf("hasFileNameConstraint(%q) = %v, want %v", tt.filePath, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,315 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/ungerik/go-astvisit"
+)
+
+// generatedTests collects the test cases of the function wrappers
+// generated for a generatedFile to be written to a test file
+// with the name of the generated file and a _test suffix.
+type generatedTests struct {
+	fileName        string // without directory
+	testName        string
+	buildConstraint string
+	cases           bytes.Buffer
+
+	// Imports of the rewritten files that may be used
+	// by the wrapped function expressions of the cases
+	importLines      map[string]struct{}
+	importNames      map[string]string
+	dotImportExports map[string]map[string]struct{}
+}
+
+func newGeneratedTests(g *generatedFile) *generatedTests {
+	baseName := strings.TrimSuffix(g.fileName, ".go")
+	testName := "TestGeneratedWrappers"
+	if suffix, ok := strings.CutPrefix(baseName, constrainedGeneratedFilePrefix); ok {
+		testName += "_" + strings.Map(
+			func(r rune) rune {
+				if unicode.IsLetter(r) || unicode.IsDigit(r) {
+					return r
+				}
+				return '_'
+			},
+			suffix,
+		)
+	}
+	return &generatedTests{
+		fileName:         testFileName(g.fileName),
+		testName:         testName,
+		buildConstraint:  g.buildConstraint,
+		importLines:      make(map[string]struct{}),
+		importNames:      make(map[string]string),
+		dotImportExports: make(map[string]map[string]struct{}),
+	}
+}
+
+// testFileName returns the name of the test file
+// for the Go file fileName.
+func testFileName(fileName string) string {
+	return strings.TrimSuffix(fileName, ".go") + "_test.go"
+}
+
+// addImports adds the imports of file to the candidate imports
+// of the test file.
+func (g *generatedTests) addImports(file *ParsedFile, functions map[string]packageFuncs) {
+	for _, imp := range file.Imports {
+		importLine := imp.Path.Value
+		if imp.Name != nil {
+			importLine = imp.Name.Name + " " + importLine
+		}
+		g.importLines[importLine] = struct{}{}
+	}
+	for path, name := range file.ImportNames {
+		g.importNames[path] = name
+	}
+	for key, pkg := range functions {
+		if strings.HasPrefix(key, ". ") {
+			g.dotImportExports[pkg.PkgPath] = pkg.ExportedNames
+		}
+	}
+}
+
+// addCase adds a test case for the wrapper variable varName
+// of the function expression wrappedFunc.
+func (g *generatedTests) addCase(varName, wrappedFunc string) {
+	fmt.Fprintf(&g.cases, "\t\t{name: %q, wrapper: %s, wrappedFunc: %s},\n", varName, varName, wrappedFunc)
+}
+
+// write writes the test file to pkgDir
+// or removes an existing generated test file if there are no cases.
+func (g *generatedTests) write(pkgDir, pkgName string, verbose bool, printTo io.Writer, localImportPrefixes []string, collector *resultCollector) error {
+	filePath := filepath.Join(pkgDir, g.fileName)
+	if g.cases.Len() == 0 {
+		return removeGeneratedFile(filePath, verbose, printTo, collector)
+	}
+
+	var src bytes.Buffer
+	src.WriteString(generatedFileHeader)
+	if g.buildConstraint != "" {
+		fmt.Fprintf(&src, "\n%s\n", g.buildConstraint)
+	}
+	fmt.Fprintf(&src, "\npackage %s\n\n", pkgName)
+	fmt.Fprintf(&src, generatedTestFunc, g.testName, g.cases.Bytes())
+
+	importLines := map[string]struct{}{
+		`"context"`:                        {},
+		`"encoding"`:                       {},
+		`"encoding/json"`:                  {},
+		`"fmt"`:                            {},
+		`"reflect"`:                        {},
+		`"strings"`:                        {},
+		`"testing"`:                        {},
+		`"github.com/domonda/go-function"`: {},
+	}
+	for importLine := range g.importLines {
+		importLines[importLine] = struct{}{}
+	}
+	generated, err := astvisit.FormatFileWithImports(token.NewFileSet(), src.Bytes(), importLines, localImportPrefixes...)
+	if err != nil {
+		return err
+	}
+	// Only the imports of the wrapped function expressions are needed
+	generated, err = removeUnusedImports(generated, g.importLines, g.importNames, g.dotImportExports)
+	if err != nil {
+		return err
+	}
+
+	if printTo != nil {
+		if verbose {
+			fmt.Println(filePath, "would be written as:")
+		}
+		_, err = printTo.Write(generated)
+		return err
+	}
+	return writeFileIfChanged(filePath, generated, "writing", verbose, collector)
+}
+
+// generatedTestFunc is the format of the generated test function
+// with the test name and the test cases as arguments.
+//
+// The metadata of the function.Description of a wrapper
+// is compared with the reflected type of the wrapped function
+// and the results of the calling conventions implemented by the wrapper
+// called with zero value arguments are compared with the results
+// of calling the wrapped function directly.
+const generatedTestFunc = `func %s(t *testing.T) {
+	tests := []struct {
+		name        string
+		wrapper     any
+		wrappedFunc any
+	}{
+%s	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				ctx       = context.Background()
+				funcType  = reflect.TypeOf(tt.wrappedFunc)
+				ctxType   = function.ReflectType[context.Context]()
+				errorType = function.ReflectType[error]()
+				ctxArg    = funcType.NumIn() > 0 && funcType.In(0) == ctxType
+				errResult = funcType.NumOut() > 0 && funcType.Out(funcType.NumOut()-1) == errorType
+			)
+			argTypes := make([]reflect.Type, funcType.NumIn())
+			for i := range argTypes {
+				argTypes[i] = funcType.In(i)
+			}
+			resultTypes := make([]reflect.Type, funcType.NumOut())
+			for i := range resultTypes {
+				resultTypes[i] = funcType.Out(i)
+			}
+
+			var argNames []string
+			if desc, ok := tt.wrapper.(function.Description); ok {
+				if desc.Name() == "" || !strings.Contains(desc.String(), desc.Name()) {
+					t.Errorf("Name() %%q is not part of String() %%q", desc.Name(), desc.String())
+				}
+				if desc.NumArgs() != funcType.NumIn() {
+					t.Errorf("NumArgs() = %%d, want %%d", desc.NumArgs(), funcType.NumIn())
+				}
+				if desc.ContextArg() != ctxArg {
+					t.Errorf("ContextArg() = %%t, want %%t", desc.ContextArg(), ctxArg)
+				}
+				if desc.NumResults() != funcType.NumOut() {
+					t.Errorf("NumResults() = %%d, want %%d", desc.NumResults(), funcType.NumOut())
+				}
+				if desc.ErrorResult() != errResult {
+					t.Errorf("ErrorResult() = %%t, want %%t", desc.ErrorResult(), errResult)
+				}
+				if len(desc.ArgNames()) != funcType.NumIn() {
+					t.Errorf("ArgNames() = %%v, want %%d names", desc.ArgNames(), funcType.NumIn())
+				}
+				if len(desc.ArgDescriptions()) != funcType.NumIn() {
+					t.Errorf("ArgDescriptions() = %%v, want %%d descriptions", desc.ArgDescriptions(), funcType.NumIn())
+				}
+				if fmt.Sprint(desc.ArgTypes()) != fmt.Sprint(argTypes) {
+					t.Errorf("ArgTypes() = %%v, want %%v", desc.ArgTypes(), argTypes)
+				}
+				if fmt.Sprint(desc.ResultTypes()) != fmt.Sprint(resultTypes) {
+					t.Errorf("ResultTypes() = %%v, want %%v", desc.ResultTypes(), resultTypes)
+				}
+				argNames = desc.ArgNames()
+			}
+
+			// Call the wrapped function directly with zero value arguments
+			// to get the results every calling convention must return
+			args := make([]reflect.Value, funcType.NumIn())
+			for i, argType := range argTypes {
+				args[i] = reflect.Zero(argType)
+			}
+			if ctxArg {
+				args[0] = reflect.ValueOf(ctx)
+			}
+			var (
+				wantResults []any
+				wantErr     error
+			)
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Skipf("wrapped function panics with zero value arguments: %%v", r)
+					}
+				}()
+				var out []reflect.Value
+				if funcType.IsVariadic() {
+					out = reflect.ValueOf(tt.wrappedFunc).CallSlice(args)
+				} else {
+					out = reflect.ValueOf(tt.wrappedFunc).Call(args)
+				}
+				if errResult {
+					wantErr, _ = out[len(out)-1].Interface().(error)
+					out = out[:len(out)-1]
+				}
+				for _, result := range out {
+					wantResults = append(wantResults, result.Interface())
+				}
+			}()
+			if ctxArg {
+				args = args[1:]
+				if argNames != nil {
+					argNames = argNames[1:]
+				}
+			}
+			checkResults := func(method string, results []any, err error) {
+				t.Helper()
+				if fmt.Sprint(err) != fmt.Sprint(wantErr) {
+					t.Errorf("%%s returned error %%v, want %%v", method, err, wantErr)
+				}
+				if len(results) != len(wantResults) || len(results) > 0 && !reflect.DeepEqual(results, wantResults) {
+					t.Errorf("%%s returned %%#v, want %%#v", method, results, wantResults)
+				}
+			}
+
+			hasInterfaceArg := false
+			callArgs := make([]any, len(args))
+			for i, arg := range args {
+				hasInterfaceArg = hasInterfaceArg || arg.Kind() == reflect.Interface
+				callArgs[i] = arg.Interface()
+			}
+			if w, ok := tt.wrapper.(function.CallWrapper); ok && !hasInterfaceArg {
+				// Nil interface arguments can't be passed to Call
+				results, err := w.Call(ctx, callArgs)
+				checkResults("Call", results, err)
+			}
+
+			strs := make([]string, len(args))
+			namedStrs := make(map[string]string, len(args))
+			canFormatStrs := true
+			for i, arg := range args {
+				switch {
+				case arg.Type() == errorType:
+				case arg.Kind() == reflect.Interface:
+					canFormatStrs = false
+				case arg.Kind() == reflect.Pointer || arg.Kind() == reflect.Slice || arg.Kind() == reflect.Map || arg.Kind() == reflect.Chan || arg.Kind() == reflect.Func:
+					// Empty strings are scanned as nil
+				case arg.Type().Implements(function.ReflectType[encoding.TextMarshaler]()):
+					text, err := arg.Interface().(encoding.TextMarshaler).MarshalText()
+					canFormatStrs = canFormatStrs && err == nil
+					strs[i] = string(text)
+				case arg.Kind() == reflect.Struct:
+					j, err := json.Marshal(arg.Interface())
+					canFormatStrs = canFormatStrs && err == nil
+					strs[i] = string(j)
+				case arg.Kind() == reflect.String:
+				default:
+					strs[i] = fmt.Sprint(arg.Interface())
+				}
+				if argNames != nil {
+					namedStrs[argNames[i]] = strs[i]
+				}
+			}
+			if w, ok := tt.wrapper.(function.CallWithStringsWrapper); ok && canFormatStrs {
+				results, err := w.CallWithStrings(ctx, strs...)
+				checkResults("CallWithStrings", results, err)
+			}
+			if w, ok := tt.wrapper.(function.CallWithNamedStringsWrapper); ok && canFormatStrs && argNames != nil {
+				results, err := w.CallWithNamedStrings(ctx, namedStrs)
+				checkResults("CallWithNamedStrings", results, err)
+			}
+
+			if w, ok := tt.wrapper.(function.CallWithJSONWrapper); ok {
+				jsonArgs := make(map[string]any, len(args))
+				for i, arg := range callArgs {
+					if argNames != nil {
+						jsonArgs[argNames[i]] = arg
+					}
+				}
+				argsJSON, err := json.Marshal(jsonArgs)
+				if err != nil {
+					t.Fatal(err)
+				}
+				results, err := w.CallWithJSON(ctx, argsJSON)
+				checkResults("CallWithJSON", results, err)
+			}
+		})
+	}
+}
+`
@@ -0,0 +1,29 @@
+package gen
+
+import "testing"
+
+func Test_newGeneratedTests(t *testing.T) {
+	tests := []struct {
+		genFile      *generatedFile
+		wantFileName string
+		wantTestName string
+	}{
+		{genFile: newGeneratedFile(GeneratedFilename, ""), wantFileName: "zz_generated_wrappers_test.go", wantTestName: "TestGeneratedWrappers"},
+		{genFile: newGeneratedFile(constrainedGeneratedFilePrefix+"file_linux.go", ""), wantFileName: "zz_generated_wrappers_file_linux_test.go", wantTestName: "TestGeneratedWrappers_file_linux"},
+		{genFile: newGeneratedFile(constrainedGeneratedFilePrefix+"my-file.go", "//go:build ignore"), wantFileName: "zz_generated_wrappers_my-file_test.go", wantTestName: "TestGeneratedWrappers_my_file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.genFile.fileName, func(t *testing.T) {
+			got := newGeneratedTests(tt.genFile)
+			if got.fileName != tt.wantFileName {
+				t.Errorf("newGeneratedTests().fileName = %q, want %q", got.fileName, tt.wantFileName)
+			}
+			if got.testName != tt.wantTestName {
+				t.Errorf("newGeneratedTests().testName = %q, want %q", got.testName, tt.wantTestName)
+			}
+			if got.buildConstraint != tt.genFile.buildConstraint {
+				t.Errorf("newGeneratedTests().buildConstraint = %q, want %q", got.buildConstraint, tt.genFile.buildConstraint)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package gen
+
+import "io"
+
+// MethodHooks customizes the bodies of the generated call methods
+// like Call, CallWithStrings or CallWithJSON,
+// for example to insert tracing calls or to wrap argument errors.
+// Embed NoMethodHooks to implement only some of the hooks.
+type MethodHooks interface {
+	// BeginMethod writes code at the beginning of the body
+	// of the generated method.
+	BeginMethod(w io.Writer, method *HookMethod) error
+
+	// ArgError returns the expression of the error returned
+	// by the generated method if the argument argName can't be parsed.
+	// argName is empty for errors of all arguments like from CallWithJSON.
+	// errExpr is the default expression like
+	// function.NewErrParseArgString(err, f, "argName")
+	// and the variable f is the wrapper.
+	ArgError(method *HookMethod, argName, errExpr string) string
+}
+
+// HookMethod describes a generated method passed to MethodHooks.
+type HookMethod struct {
+	// WrapperType is the name of the generated wrapper type
+	WrapperType string
+	// WrappedFunc is the wrapped function like "pkg.Func" or "Interface.Method"
+	WrappedFunc string
+	// Name of the method like "Call" or "CallWithJSON"
+	Name string
+	// CtxArg is the name of the context.Context argument of the method
+	CtxArg string
+	// ImportLines needed by the generated code,
+	// hooks add the imports of the code they write
+	ImportLines map[string]struct{}
+}
+
+// NoMethodHooks implements MethodHooks without changing
+// the generated code.
+type NoMethodHooks struct{}
+
+func (NoMethodHooks) BeginMethod(io.Writer, *HookMethod) error { return nil }
+
+func (NoMethodHooks) ArgError(_ *HookMethod, _, errExpr string) string { return errExpr }
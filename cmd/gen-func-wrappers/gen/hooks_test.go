@@ -0,0 +1,92 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"strings"
+	"testing"
+)
+
+type tracingHooks struct {
+	NoMethodHooks
+}
+
+func (tracingHooks) BeginMethod(w io.Writer, method *HookMethod) error {
+	method.ImportLines[`"example.com/trace"`] = struct{}{}
+	_, err := fmt.Fprintf(w, "\tdefer trace.Start(%s, %q)()\n", method.CtxArg, method.WrappedFunc+"."+method.Name)
+	return err
+}
+
+func (tracingHooks) ArgError(method *HookMethod, argName, errExpr string) string {
+	return fmt.Sprintf("trace.Error(%s, %s)", method.CtxArg, errExpr)
+}
+
+func TestImpl_WriteFunctionWrapper_hooks(t *testing.T) {
+	const src = `package a
+
+func Add(a, b int) int { return a + b }
+`
+	file, err := parser.ParseFile(token.NewFileSet(), "a.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	funcFile := &ParsedFile{File: file, ImportNames: map[string]string{}}
+	funcDecl := file.Decls[0].(*ast.FuncDecl)
+
+	var buf bytes.Buffer
+	imports := make(map[string]struct{})
+	err = ImplWrapper.WriteFunctionWrapper(&buf, funcFile, funcDecl, "addT", "", imports, JSONOptions{}, tracingHooks{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated := buf.String()
+	for _, want := range []string{
+		"func (addT) Call(ctx context.Context, args []any) (results []any, err error) {\n\tdefer trace.Start(ctx, \"Add.Call\")()\n",
+		"func (f addT) CallWithStrings(ctx context.Context, strs ...string) (results []any, err error) {\n\tdefer trace.Start(ctx, \"Add.CallWithStrings\")()\n",
+		"\t\t\treturn nil, trace.Error(ctx, function.NewErrParseArgString(err, f, \"a\"))\n",
+		"\t\treturn nil, trace.Error(ctx, function.NewErrParseArgsJSON(err, f, argsJSON))\n",
+	} {
+		if !strings.Contains(generated, want) {
+			t.Errorf("generated code does not contain:\n%s\ngenerated:\n%s", want, generated)
+		}
+	}
+	if _, ok := imports[`"example.com/trace"`]; !ok {
+		t.Errorf("import of hook code missing: %v", imports)
+	}
+	_, err = parser.ParseFile(token.NewFileSet(), "gen.go", "package a\n"+generated, 0)
+	if err != nil {
+		t.Errorf("invalid generated code: %s\n%s", err, generated)
+	}
+}
+
+func TestImpl_WriteFunctionWrapper_noArgsHooks(t *testing.T) {
+	const src = `package a
+
+func Reset() {}
+`
+	file, err := parser.ParseFile(token.NewFileSet(), "a.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	funcFile := &ParsedFile{File: file, ImportNames: map[string]string{}}
+	funcDecl := file.Decls[0].(*ast.FuncDecl)
+
+	var buf bytes.Buffer
+	err = ImplWrapper.WriteFunctionWrapper(&buf, funcFile, funcDecl, "resetT", "", make(map[string]struct{}), JSONOptions{}, NoMethodHooks{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The context argument is named for the hooks
+	// even if the wrapped function has no arguments
+	if want := "func (resetT) Call(ctx context.Context, _ []any) ([]any, error) {\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("generated code does not contain:\n%s\ngenerated:\n%s", want, buf.String())
+	}
+	_, err = parser.ParseFile(token.NewFileSet(), "gen.go", "package a\n"+buf.String(), 0)
+	if err != nil {
+		t.Errorf("invalid generated code: %s\n%s", err, buf.String())
+	}
+}
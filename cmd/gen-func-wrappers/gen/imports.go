@@ -1,23 +1,48 @@
 package gen
 
 import (
+	"bytes"
 	"fmt"
 	"go/ast"
+	"go/format"
+	"go/parser"
 	"go/token"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/ungerik/go-astvisit"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/packages"
 )
 
 type packageFuncs struct {
-	Location *astvisit.PackageLocation
-	Funcs    map[string]funcDeclInFile
+	PkgPath    string
+	PkgName    string
+	ImportLine string // empty for the local package
+	Funcs      map[string]funcDeclInFile
+	Interfaces map[string]interfaceInFile
+
+	// ExportedNames are the names of all exported
+	// package level declarations of imported packages
+	// used to detect if a dot import is used.
+	ExportedNames map[string]struct{}
 }
 
 // localAndImportedFunctions returns a map of packageFuncs with the package
-func localAndImportedFunctions(fset *token.FileSet, filePkg *ast.Package, file *ast.File, pkgDir string) (map[string]packageFuncs, error) {
+// import name as key and an empty string for the local package of file.
+// Dot imported packages use their import line as key
+// because a file can have multiple dot imports.
+// The imports of files are resolved with the type information of filePkg
+// and the imported packages are parsed using cache.
+// If multiple files import different packages with the same name,
+// then the import of the first file is used.
+func localAndImportedFunctions(cache *packageCache, filePkg *packages.Package, pkgDir string, files ...*ast.File) (map[string]packageFuncs, error) {
 	localFuncs := make(map[string]funcDeclInFile)
-	for _, f := range filePkg.Files {
+	localInterfaces := make(map[string]interfaceInFile)
+	for _, f := range filePkg.Syntax {
+		f := typedFile(filePkg, f)
+		addInterfaces(f, false, localInterfaces)
 		for _, decl := range f.Decls {
 			funcDecl, ok := decl.(*ast.FuncDecl)
 			if ok && funcDecl.Recv == nil {
@@ -30,48 +55,196 @@ func localAndImportedFunctions(fset *token.FileSet, filePkg *ast.Package, file *
 	}
 	functions := map[string]packageFuncs{
 		"": {
-			Location: &astvisit.PackageLocation{
-				PkgName:    filePkg.Name,
-				SourcePath: pkgDir,
-			},
-			Funcs: localFuncs,
+			PkgPath:    filePkg.PkgPath,
+			PkgName:    filePkg.Name,
+			Funcs:      localFuncs,
+			Interfaces: localInterfaces,
 		},
 	}
 
-	for _, imp := range file.Imports {
-		importName, pkgLocation, err := astvisit.LocatePackageOfImportSpec(pkgDir, imp)
-		if err != nil {
-			return nil, err
+	type importedPkg struct {
+		id         string
+		importLine string
+	}
+	importedPkgs := make(map[string]importedPkg) // by import name
+	for _, file := range files {
+		for _, imp := range file.Imports {
+			pkgName := filePkg.TypesInfo.PkgNameOf(imp)
+			if pkgName == nil {
+				return nil, fmt.Errorf("can't resolve import %s in package %s", imp.Path.Value, filePkg.PkgPath)
+			}
+			if pkgName.Name() == "_" {
+				continue
+			}
+			imported := filePkg.Imports[pkgName.Imported().Path()]
+			if imported == nil {
+				return nil, fmt.Errorf("can't find imported package %s of package %s", imp.Path.Value, filePkg.PkgPath)
+			}
+			importLine := imp.Path.Value
+			if imp.Name != nil {
+				importLine = imp.Name.Name + " " + importLine
+			}
+			key := pkgName.Name()
+			if key == "." {
+				key = importLine
+			}
+			// Packages imported by test files can be test variants
+			// with IDs like "pkg [pkg.test]" that can't be loaded
+			// by ID, their exported functions are loaded without tests
+			id, _, _ := strings.Cut(imported.ID, " ")
+			if _, exists := importedPkgs[key]; !exists {
+				importedPkgs[key] = importedPkg{id: id, importLine: importLine}
+			}
 		}
-		if pkgLocation.Std {
+	}
+	if len(importedPkgs) == 0 {
+		return functions, nil
+	}
+
+	ids := make([]string, 0, len(importedPkgs))
+	for _, imp := range importedPkgs {
+		ids = append(ids, imp.id)
+	}
+	imported, err := cache.imported(pkgDir, ids)
+	if err != nil {
+		return nil, err
+	}
+	for key, imp := range importedPkgs {
+		pkg, ok := imported[imp.id]
+		if !ok {
+			return nil, fmt.Errorf("can't load imported package %s of package %s", imp.id, filePkg.PkgPath)
+		}
+		if pkg.pkg.Module == nil {
+			// Standard library packages are not part of a module
 			continue
 		}
-		impPkg, err := astvisit.ParsePackage(fset, pkgLocation.SourcePath, filterOutTests)
+		funcs, err := pkg.exportedFuncs()
 		if err != nil {
 			return nil, err
 		}
-		exportedFuncs := make(map[string]funcDeclInFile)
-		for _, f := range impPkg.Files {
-			for _, decl := range f.Decls {
-				funcDecl, ok := decl.(*ast.FuncDecl)
-				if ok && funcDecl.Recv == nil && funcDecl.Name.IsExported() {
-					exportedFuncs[funcDecl.Name.Name] = funcDeclInFile{
-						Decl: funcDecl,
-						File: f,
-					}
+		funcs.ImportLine = imp.importLine
+		functions[key] = funcs
+	}
+
+	return functions, nil
+}
+
+// referencedPackages returns the packages of functions
+// that could declare a function or type referenced with pkgName.
+// An empty pkgName references the local package
+// or one of the dot imported packages.
+func referencedPackages(functions map[string]packageFuncs, pkgName string) []packageFuncs {
+	if pkgName != "" {
+		if pkg, ok := functions[pkgName]; ok {
+			return []packageFuncs{pkg}
+		}
+		return nil
+	}
+	pkgs := []packageFuncs{functions[""]}
+	dotImports := make([]string, 0)
+	for key := range functions {
+		if strings.HasPrefix(key, ". ") {
+			dotImports = append(dotImports, key)
+		}
+	}
+	sort.Strings(dotImports)
+	for _, key := range dotImports {
+		pkgs = append(pkgs, functions[key])
+	}
+	return pkgs
+}
+
+// findFunc returns the function funcName referenced with pkgName
+// and the package declaring it.
+func findFunc(functions map[string]packageFuncs, pkgName, funcName string) (funcDeclInFile, packageFuncs, error) {
+	pkgs := referencedPackages(functions, pkgName)
+	if len(pkgs) == 0 {
+		return funcDeclInFile{}, packageFuncs{}, fmt.Errorf("can't find package %s in imports", pkgName)
+	}
+	for _, pkg := range pkgs {
+		if fun, ok := pkg.Funcs[funcName]; ok {
+			return fun, pkg, nil
+		}
+	}
+	if pkgName == "" {
+		return funcDeclInFile{}, packageFuncs{}, fmt.Errorf("can't find function %s", funcName)
+	}
+	return funcDeclInFile{}, packageFuncs{}, fmt.Errorf("can't find function %s in package %s", funcName, pkgName)
+}
+
+// findInterface returns the interface typeName referenced with pkgName
+// and the package declaring it.
+func findInterface(functions map[string]packageFuncs, pkgName, typeName string) (interfaceInFile, packageFuncs, error) {
+	pkgs := referencedPackages(functions, pkgName)
+	if len(pkgs) == 0 {
+		return interfaceInFile{}, packageFuncs{}, fmt.Errorf("can't find package %s in imports", pkgName)
+	}
+	for _, pkg := range pkgs {
+		if iface, ok := pkg.Interfaces[typeName]; ok {
+			return iface, pkg, nil
+		}
+	}
+	if pkgName == "" {
+		return interfaceInFile{}, packageFuncs{}, fmt.Errorf("can't find interface %s", typeName)
+	}
+	return interfaceInFile{}, packageFuncs{}, fmt.Errorf("can't find interface %s in package %s", typeName, pkgName)
+}
+
+// addInterfaces adds all interface type declarations of file
+// to interfaces, or only the exported ones if onlyExported is true.
+func addInterfaces(file *ParsedFile, onlyExported bool, interfaces map[string]interfaceInFile) {
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok || onlyExported && !typeSpec.Name.IsExported() {
+				continue
+			}
+			if ifaceType, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+				interfaces[typeSpec.Name.Name] = interfaceInFile{
+					Type: ifaceType,
+					File: file,
 				}
 			}
 		}
-		functions[importName] = packageFuncs{
-			Location: pkgLocation,
-			Funcs:    exportedFuncs,
-		}
 	}
+}
 
-	return functions, nil
+// addExportedNames adds the names of all exported
+// package level declarations of file to names.
+func addExportedNames(file *ast.File, names map[string]struct{}) {
+	add := func(ident *ast.Ident) {
+		if ident.IsExported() {
+			names[ident.Name] = struct{}{}
+		}
+	}
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil {
+				add(decl.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					add(spec.Name)
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						add(name)
+					}
+				}
+			}
+		}
+	}
 }
 
-func gatherFieldListImports(funcFile *ast.File, fieldList *ast.FieldList, setImportLines map[string]struct{}) error {
+// gatherFieldListImports adds the import lines of funcFile
+// for the packages referenced by the types of fieldList to setImportLines.
+func gatherFieldListImports(funcFile *ParsedFile, fieldList *ast.FieldList, setImportLines map[string]struct{}) error {
 	if fieldList == nil {
 		return nil
 	}
@@ -87,12 +260,12 @@ func gatherFieldListImports(funcFile *ast.File, fieldList *ast.FieldList, setImp
 			}
 			continue
 		}
-		guessedName, err := guessPackageNameFromPath(imp.Path.Value)
+		name, err := importPackageName(imp, funcFile.ImportNames)
 		if err != nil {
 			return err
 		}
-		if _, ok := packageNames[guessedName]; ok {
-			if _, ok = setImportLines[guessedName+" "+imp.Path.Value]; !ok {
+		if _, ok := packageNames[name]; ok {
+			if _, ok = setImportLines[name+" "+imp.Path.Value]; !ok {
 				setImportLines[imp.Path.Value] = struct{}{}
 			}
 		}
@@ -100,6 +273,113 @@ func gatherFieldListImports(funcFile *ast.File, fieldList *ast.FieldList, setImp
 	return nil
 }
 
+// removeUnusedImports removes the imports of the candidateImportLines
+// from the Go source that are not used by any selector expression.
+// The package names of unnamed imports are looked up in importNames
+// by import path.
+// Dot imports are removed if none of their exported names
+// from dotImportExports by import path is used.
+func removeUnusedImports(source []byte, candidateImportLines map[string]struct{}, importNames map[string]string, dotImportExports map[string]map[string]struct{}) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", source, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		usedNames  = make(map[string]bool)
+		selected   = make(map[*ast.Ident]bool)
+		usedIdents = make(map[string]bool)
+	)
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.ImportSpec:
+			return false
+		case *ast.SelectorExpr:
+			selected[node.Sel] = true
+			if ident, ok := node.X.(*ast.Ident); ok {
+				usedNames[ident.Name] = true
+			}
+		case *ast.Ident:
+			if !selected[node] && node != file.Name {
+				usedIdents[node.Name] = true
+			}
+		}
+		return true
+	})
+	var unused []*ast.ImportSpec
+	for _, imp := range file.Imports {
+		name := ""
+		importLine := imp.Path.Value
+		if imp.Name != nil {
+			name = imp.Name.Name
+			importLine = name + " " + importLine
+		}
+		if _, ok := candidateImportLines[importLine]; !ok {
+			continue
+		}
+		if name == "" {
+			name, err = importPackageName(imp, importNames)
+			if err != nil {
+				continue
+			}
+		}
+		switch name {
+		case "_":
+		case ".":
+			if !usesAnyName(usedIdents, dotImportExports[strings.Trim(imp.Path.Value, `"`)]) {
+				unused = append(unused, imp)
+			}
+		default:
+			if !usedNames[name] {
+				unused = append(unused, imp)
+			}
+		}
+	}
+	if len(unused) == 0 {
+		return source, nil
+	}
+	for _, imp := range unused {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+		name := ""
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		astutil.DeleteNamedImport(fset, file, name, path)
+	}
+	var buf bytes.Buffer
+	err = format.Node(&buf, fset, file)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func usesAnyName(usedIdents map[string]bool, names map[string]struct{}) bool {
+	for name := range names {
+		if usedIdents[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// importPackageName returns the package name of the unnamed import imp
+// from importNames by import path or guessed from the import path
+// if the name of the imported package is not known.
+func importPackageName(imp *ast.ImportSpec, importNames map[string]string) (string, error) {
+	path, err := strconv.Unquote(imp.Path.Value)
+	if err != nil {
+		return "", err
+	}
+	if name, ok := importNames[path]; ok {
+		return name, nil
+	}
+	return guessPackageNameFromPath(path)
+}
+
 func guessPackageNameFromPath(path string) (string, error) {
 	pkg := path
 	if len(pkg) >= 2 && pkg[0] == '"' && pkg[len(pkg)-1] == '"' {
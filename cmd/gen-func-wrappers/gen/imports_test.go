@@ -0,0 +1,211 @@
+package gen
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"testing"
+)
+
+func Test_removeUnusedImports(t *testing.T) {
+	source := `package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/domonda/go-function"
+)
+
+var x = reflect.TypeOf(context.Background())
+`
+	candidates := map[string]struct{}{
+		`"encoding/json"`:                  {},
+		`"reflect"`:                        {},
+		`"github.com/domonda/go-function"`: {},
+	}
+	want := `package pkg
+
+import (
+	"context"
+	"reflect"
+)
+
+var x = reflect.TypeOf(context.Background())
+`
+	got, err := removeUnusedImports([]byte(source), candidates, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("removeUnusedImports() = \n%s\nwant:\n%s", got, want)
+	}
+}
+
+func Test_removeUnusedImports_importNames(t *testing.T) {
+	source := `package pkg
+
+import (
+	"example.com/lib/v2"
+	"example.com/my-pkg"
+)
+
+var x lib.T
+`
+	candidates := map[string]struct{}{
+		`"example.com/lib/v2"`: {},
+		`"example.com/my-pkg"`: {},
+	}
+	importNames := map[string]string{
+		"example.com/lib/v2": "lib",
+		"example.com/my-pkg": "mypkg",
+	}
+	want := `package pkg
+
+import (
+	"example.com/lib/v2"
+)
+
+var x lib.T
+`
+	got, err := removeUnusedImports([]byte(source), candidates, importNames, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("removeUnusedImports() = \n%s\nwant:\n%s", got, want)
+	}
+}
+
+func Test_gatherFieldListImports(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", `package pkg
+
+import (
+	"context"
+
+	"example.com/lib/v2"
+	"example.com/my-pkg"
+	"example.com/unused"
+	alias "example.com/aliased"
+)
+
+func F(ctx context.Context, a lib.A, b mypkg.B) (alias.C, error)
+`, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	funcFile := &ParsedFile{
+		File: file,
+		ImportNames: map[string]string{
+			"context":             "context",
+			"example.com/lib/v2":  "lib",
+			"example.com/my-pkg":  "mypkg",
+			"example.com/unused":  "unused",
+			"example.com/aliased": "aliased",
+		},
+	}
+	funcType := file.Decls[1].(*ast.FuncDecl).Type
+	got := make(map[string]struct{})
+	for _, fieldList := range []*ast.FieldList{funcType.Params, funcType.Results} {
+		err = gatherFieldListImports(funcFile, fieldList, got)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := map[string]struct{}{
+		`"context"`:                   {},
+		`"example.com/lib/v2"`:        {},
+		`"example.com/my-pkg"`:        {},
+		`alias "example.com/aliased"`: {},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("gatherFieldListImports() = %v, want %v", got, want)
+	}
+}
+
+func Test_removeUnusedImports_dotImports(t *testing.T) {
+	source := `package pkg
+
+import (
+	. "example.com/used"
+	. "example.com/unused"
+)
+
+var x = UsedFunc()
+`
+	candidates := map[string]struct{}{
+		`. "example.com/used"`:   {},
+		`. "example.com/unused"`: {},
+	}
+	dotImportExports := map[string]map[string]struct{}{
+		"example.com/used":   {"UsedFunc": {}},
+		"example.com/unused": {"UnusedFunc": {}},
+	}
+	want := `package pkg
+
+import (
+	. "example.com/used"
+)
+
+var x = UsedFunc()
+`
+	got, err := removeUnusedImports([]byte(source), candidates, nil, dotImportExports)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("removeUnusedImports() = \n%s\nwant:\n%s", got, want)
+	}
+}
+
+func Test_findFunc(t *testing.T) {
+	decl := func(name string) funcDeclInFile {
+		return funcDeclInFile{Decl: &ast.FuncDecl{Name: ast.NewIdent(name)}}
+	}
+	functions := map[string]packageFuncs{
+		"": {
+			Funcs: map[string]funcDeclInFile{"Local": decl("Local")},
+		},
+		"alias": {
+			PkgPath:    "example.com/pkg",
+			ImportLine: `alias "example.com/pkg"`,
+			Funcs:      map[string]funcDeclInFile{"Aliased": decl("Aliased")},
+		},
+		`. "example.com/dot"`: {
+			PkgPath:    "example.com/dot",
+			ImportLine: `. "example.com/dot"`,
+			Funcs:      map[string]funcDeclInFile{"Dotted": decl("Dotted")},
+		},
+	}
+	tests := []struct {
+		pkgName, funcName string
+		wantImportLine    string
+		wantErr           bool
+	}{
+		{pkgName: "", funcName: "Local", wantImportLine: ""},
+		{pkgName: "alias", funcName: "Aliased", wantImportLine: `alias "example.com/pkg"`},
+		{pkgName: "", funcName: "Dotted", wantImportLine: `. "example.com/dot"`},
+		{pkgName: "pkg", funcName: "Aliased", wantErr: true},
+		{pkgName: "alias", funcName: "Dotted", wantErr: true},
+		{pkgName: "", funcName: "Missing", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pkgName+"."+tt.funcName, func(t *testing.T) {
+			fun, pkg, err := findFunc(functions, tt.pkgName, tt.funcName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("findFunc() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if fun.Decl.Name.Name != tt.funcName {
+				t.Errorf("findFunc() returned function %s", fun.Decl.Name.Name)
+			}
+			if pkg.ImportLine != tt.wantImportLine {
+				t.Errorf("findFunc() import line = %q, want %q", pkg.ImportLine, tt.wantImportLine)
+			}
+		})
+	}
+}
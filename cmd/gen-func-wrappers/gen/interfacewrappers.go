@@ -0,0 +1,219 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ungerik/go-astvisit"
+)
+
+// InterfaceWrappersDirective is the comment directive
+// that requests function.Wrapper implementations for
+// all methods of the interface type named after the directive.
+//
+// Example:
+//
+//	//gen:wrappers-for-interface MyService
+//
+// The comment will be replaced with a constructor function
+// NewMyServiceWrappers(impl MyService) map[string]function.Wrapper
+// that keeps the directive in its doc comment
+// so that the wrappers can be re-generated.
+const InterfaceWrappersDirective = "//gen:wrappers-for-interface"
+
+type interfaceWrappers struct {
+	Interface string // optionally qualified with an import name
+	Nodes     []ast.Node
+}
+
+func (iw *interfaceWrappers) PkgAndTypeName() (pkgName, typeName string) {
+	dot := strings.IndexByte(iw.Interface, '.')
+	if dot == -1 {
+		return "", iw.Interface
+	}
+	return iw.Interface[:dot], iw.Interface[dot+1:]
+}
+
+// Pos returns the position of the InterfaceWrappersDirective.
+func (iw *interfaceWrappers) Pos() token.Pos {
+	return iw.Nodes[0].Pos()
+}
+
+// ConstructorName returns the name of the generated
+// function returning the wrappers for all interface methods.
+func (iw *interfaceWrappers) ConstructorName() string {
+	_, typeName := iw.PkgAndTypeName()
+	return "New" + exportedName(typeName) + "Wrappers"
+}
+
+// MethodImplType returns the name of the generated
+// wrapper type for the interface method methodName.
+func (iw *interfaceWrappers) MethodImplType(methodName string) string {
+	name := strings.ReplaceAll(iw.Interface, ".", "") + methodName + "T"
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(r)) + name[size:]
+}
+
+// parseInterfaceWrappersDirective returns the interface name
+// of an InterfaceWrappersDirective line in comment.
+func parseInterfaceWrappersDirective(comment *ast.CommentGroup) (iface string, ok bool) {
+	if comment == nil {
+		return "", false
+	}
+	for _, c := range comment.List {
+		args, found := strings.CutPrefix(c.Text, InterfaceWrappersDirective)
+		if !found {
+			continue
+		}
+		fields := strings.Fields(args)
+		if len(fields) != 1 || args[0] != ' ' && args[0] != '\t' {
+			return "", false
+		}
+		return fields[0], true
+	}
+	return "", false
+}
+
+// findInterfaceWrappers returns the InterfaceWrappersDirective comments of file.
+// If a directive is part of the doc comment of a previously generated
+// constructor function, then the nodes of the constructor and of
+// the generated wrapper types and their methods are also returned
+// so they can be replaced with newly generated code.
+func findInterfaceWrappers(file *ast.File) []*interfaceWrappers {
+	var (
+		constructors = make(map[*ast.CommentGroup]*ast.FuncDecl)
+		genTypes     = make(map[string][]ast.Node) // generated type name to nodes
+		genWrapped   = make(map[string]string)     // generated type name to wrapped method
+		genOrder     []string
+	)
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			if decl.Tok != token.TYPE || len(decl.Specs) != 1 {
+				continue
+			}
+			typeSpec, ok := decl.Specs[0].(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			if _, ok := typeSpec.Type.(*ast.StructType); !ok {
+				continue
+			}
+			wrappedMethod, _, err := parseImplementsComment(typeSpec.Name.Name, decl.Doc.Text())
+			if err != nil {
+				continue
+			}
+			genOrder = append(genOrder, typeSpec.Name.Name)
+			genWrapped[typeSpec.Name.Name] = wrappedMethod
+			genTypes[typeSpec.Name.Name] = append(genTypes[typeSpec.Name.Name], decl.Doc, decl)
+
+		case *ast.FuncDecl:
+			if decl.Recv == nil {
+				if decl.Doc != nil {
+					constructors[decl.Doc] = decl
+				}
+				continue
+			}
+			if decl.Recv.NumFields() != 1 {
+				continue
+			}
+			recvType := astvisit.ExprString(decl.Recv.List[0].Type)
+			if _, ok := genTypes[recvType]; !ok {
+				continue
+			}
+			if decl.Doc != nil {
+				genTypes[recvType] = append(genTypes[recvType], decl.Doc)
+			}
+			genTypes[recvType] = append(genTypes[recvType], decl)
+		}
+	}
+
+	var found []*interfaceWrappers
+	for _, comment := range file.Comments {
+		iface, ok := parseInterfaceWrappersDirective(comment)
+		if !ok {
+			continue
+		}
+		iw := &interfaceWrappers{Interface: iface}
+		iw.Nodes = append(iw.Nodes, comment)
+		if constructor := constructors[comment]; constructor != nil && constructor.Name.Name == iw.ConstructorName() {
+			iw.Nodes = append(iw.Nodes, constructor)
+			for _, typeName := range genOrder {
+				if strings.HasPrefix(genWrapped[typeName], iface+".") {
+					iw.Nodes = append(iw.Nodes, genTypes[typeName]...)
+				}
+			}
+		}
+		found = append(found, iw)
+	}
+	return found
+}
+
+// interfaceMethods returns the methods of iface as *ast.FuncDecl
+// without receiver so they can be passed to Impl.WriteMethodWrapper.
+// Embedded interfaces are not supported and return an error.
+// If onlyExported is true then unexported methods are skipped.
+func interfaceMethods(iface *ast.InterfaceType, onlyExported bool) ([]*ast.FuncDecl, error) {
+	var methods []*ast.FuncDecl
+	for _, field := range iface.Methods.List {
+		funcType, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) == 0 {
+			return nil, fmt.Errorf("embedded interface %s not supported", astvisit.ExprString(field.Type))
+		}
+		for _, name := range field.Names {
+			if onlyExported && !name.IsExported() {
+				continue
+			}
+			methods = append(methods, &ast.FuncDecl{
+				Doc:  field.Doc,
+				Name: name,
+				Type: funcType,
+			})
+		}
+	}
+	return methods, nil
+}
+
+// methods returns the methods of the interface to be wrapped.
+// Only exported methods of interfaces from other packages are returned.
+func (iw *interfaceWrappers) methods(iface interfaceInFile, imported bool) ([]*ast.FuncDecl, error) {
+	methods, err := interfaceMethods(iface.Type, imported)
+	if err != nil {
+		return nil, fmt.Errorf("interface %s: %w", iw.Interface, err)
+	}
+	return methods, nil
+}
+
+// writeConstructor writes the constructor function
+// returning the wrappers for all methods of the interface.
+func (iw *interfaceWrappers) writeConstructor(w io.Writer, methods []*ast.FuncDecl, neededImportLines map[string]struct{}) {
+	neededImportLines[`"github.com/domonda/go-function"`] = struct{}{}
+
+	fmt.Fprintf(w, "// %s returns a function.Wrapper for every method of %s calling impl (generated code)\n", iw.ConstructorName(), iw.Interface)
+	fmt.Fprintf(w, "//\n")
+	fmt.Fprintf(w, "%s %s\n", InterfaceWrappersDirective, iw.Interface)
+	fmt.Fprintf(w, "func %s(impl %s) map[string]function.Wrapper {\n", iw.ConstructorName(), iw.Interface)
+	fmt.Fprintf(w, "\treturn map[string]function.Wrapper{\n")
+	for _, method := range methods {
+		fmt.Fprintf(w, "\t\t%q: %s{impl},\n", method.Name.Name, iw.MethodImplType(method.Name.Name))
+	}
+	fmt.Fprintf(w, "\t}\n")
+	fmt.Fprintf(w, "}\n\n")
+}
+
+// writeMethodWrappers writes the wrapper types for the methods of the interface.
+func (iw *interfaceWrappers) writeMethodWrappers(w io.Writer, iface interfaceInFile, methods []*ast.FuncDecl, neededImportLines map[string]struct{}, jsonOptions JSONOptions, hooks MethodHooks) error {
+	pkgName, _ := iw.PkgAndTypeName()
+	for _, method := range methods {
+		err := ImplWrapper.WriteMethodWrapper(w, iface.File, method, iw.MethodImplType(method.Name.Name), pkgName, iw.Interface, neededImportLines, jsonOptions, hooks)
+		if err != nil {
+			return fmt.Errorf("interface %s method %s: %w", iw.Interface, method.Name.Name, err)
+		}
+	}
+	return nil
+}
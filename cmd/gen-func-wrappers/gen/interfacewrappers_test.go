@@ -0,0 +1,55 @@
+package gen
+
+import (
+	"go/ast"
+	"testing"
+)
+
+func Test_parseInterfaceWrappersDirective(t *testing.T) {
+	tests := []struct {
+		name      string
+		comments  []string
+		wantIface string
+		wantOk    bool
+	}{
+		{name: "local", comments: []string{"//gen:wrappers-for-interface MyService"}, wantIface: "MyService", wantOk: true},
+		{name: "imported", comments: []string{"//gen:wrappers-for-interface pkg.MyService "}, wantIface: "pkg.MyService", wantOk: true},
+		{name: "in doc", comments: []string{"// NewMyServiceWrappers returns...", "//", "//gen:wrappers-for-interface MyService"}, wantIface: "MyService", wantOk: true},
+
+		// Invalid:
+		{name: "no directive", comments: []string{"// MyService is a service"}},
+		{name: "missing interface", comments: []string{"//gen:wrappers-for-interface"}},
+		{name: "no space", comments: []string{"//gen:wrappers-for-interfaceMyService"}},
+		{name: "too many args", comments: []string{"//gen:wrappers-for-interface A B"}},
+		{name: "nil"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var comment *ast.CommentGroup
+			if tt.comments != nil {
+				comment = new(ast.CommentGroup)
+				for _, c := range tt.comments {
+					comment.List = append(comment.List, &ast.Comment{Text: c})
+				}
+			}
+			gotIface, gotOk := parseInterfaceWrappersDirective(comment)
+			if gotIface != tt.wantIface || gotOk != tt.wantOk {
+				t.Errorf("parseInterfaceWrappersDirective() = %q, %t, want %q, %t", gotIface, gotOk, tt.wantIface, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_interfaceWrappers_names(t *testing.T) {
+	iw := &interfaceWrappers{Interface: "pkg.MyService"}
+	if got := iw.ConstructorName(); got != "NewMyServiceWrappers" {
+		t.Errorf("ConstructorName() = %q", got)
+	}
+	if got := iw.MethodImplType("Get"); got != "pkgMyServiceGetT" {
+		t.Errorf("MethodImplType() = %q", got)
+	}
+	iw = &interfaceWrappers{Interface: "MyService"}
+	if got := iw.MethodImplType("Get"); got != "myServiceGetT" {
+		t.Errorf("MethodImplType() = %q", got)
+	}
+}
@@ -0,0 +1,113 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// JSONOptions configure the generated CallWithJSON methods.
+type JSONOptions struct {
+	// TypeReplacements maps argument types to the types
+	// used for JSON unmarshalling, like interface types
+	// to implementation types.
+	TypeReplacements map[string]string
+
+	// FieldNaming is the naming of the JSON object fields
+	// of the function arguments.
+	FieldNaming JSONFieldNaming
+}
+
+// JSONFieldNaming is the naming convention
+// for the JSON object fields of function arguments.
+type JSONFieldNaming string
+
+const (
+	// JSONFieldNamingDefault uses the exported argument names
+	// without json struct tags, so encoding/json matches
+	// the JSON object fields case insensitive.
+	JSONFieldNamingDefault JSONFieldNaming = ""
+	// JSONFieldNamingCamelCase uses camelCase names like "userID" -> "userId"
+	JSONFieldNamingCamelCase JSONFieldNaming = "camelCase"
+	// JSONFieldNamingSnakeCase uses snake_case names like "userID" -> "user_id"
+	JSONFieldNamingSnakeCase JSONFieldNaming = "snake_case"
+	// JSONFieldNamingVerbatim uses the argument names unchanged
+	JSONFieldNamingVerbatim JSONFieldNaming = "verbatim"
+)
+
+// ParseJSONFieldNaming parses one of the JSONFieldNaming constants.
+func ParseJSONFieldNaming(str string) (JSONFieldNaming, error) {
+	switch naming := JSONFieldNaming(str); naming {
+	case JSONFieldNamingDefault, JSONFieldNamingCamelCase, JSONFieldNamingSnakeCase, JSONFieldNamingVerbatim:
+		return naming, nil
+	}
+	return "", fmt.Errorf("invalid JSON field naming %q, valid are: %s, %s, %s", str, JSONFieldNamingCamelCase, JSONFieldNamingSnakeCase, JSONFieldNamingVerbatim)
+}
+
+// FieldName returns the JSON object field name for argName
+// or an empty string for JSONFieldNamingDefault.
+func (naming JSONFieldNaming) FieldName(argName string) string {
+	switch naming {
+	case JSONFieldNamingCamelCase:
+		words := splitWords(argName)
+		for i, word := range words {
+			if i == 0 {
+				words[i] = strings.ToLower(word)
+			} else {
+				words[i] = strings.ToUpper(word[:1]) + strings.ToLower(word[1:])
+			}
+		}
+		return strings.Join(words, "")
+	case JSONFieldNamingSnakeCase:
+		words := splitWords(argName)
+		for i, word := range words {
+			words[i] = strings.ToLower(word)
+		}
+		return strings.Join(words, "_")
+	case JSONFieldNamingVerbatim:
+		return argName
+	}
+	return ""
+}
+
+// StructTag returns the struct tag for the
+// JSON field of argName including the backticks
+// or an empty string for JSONFieldNamingDefault.
+func (naming JSONFieldNaming) StructTag(argName string) string {
+	fieldName := naming.FieldName(argName)
+	if fieldName == "" {
+		return ""
+	}
+	return "`json:\"" + fieldName + "\"`"
+}
+
+// splitWords splits a camelCase, PascalCase or snake_case
+// identifier into its words keeping acronyms together,
+// like "parseHTTPRequest" -> ["parse", "HTTP", "Request"].
+func splitWords(name string) (words []string) {
+	runes := []rune(name)
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == '_' {
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+			continue
+		}
+		if i == start || !unicode.IsUpper(r) {
+			continue
+		}
+		prev := runes[i-1]
+		nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if !unicode.IsUpper(prev) || nextIsLower {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
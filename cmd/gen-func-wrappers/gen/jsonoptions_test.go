@@ -0,0 +1,46 @@
+package gen
+
+import "testing"
+
+func TestJSONFieldNaming_FieldName(t *testing.T) {
+	tests := []struct {
+		argName   string
+		camelCase string
+		snakeCase string
+	}{
+		{argName: "name", camelCase: "name", snakeCase: "name"},
+		{argName: "userID", camelCase: "userId", snakeCase: "user_id"},
+		{argName: "ID", camelCase: "id", snakeCase: "id"},
+		{argName: "parseHTTPRequest", camelCase: "parseHttpRequest", snakeCase: "parse_http_request"},
+		{argName: "MaxCount", camelCase: "maxCount", snakeCase: "max_count"},
+		{argName: "max_count", camelCase: "maxCount", snakeCase: "max_count"},
+		{argName: "file2Path", camelCase: "file2Path", snakeCase: "file2_path"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.argName, func(t *testing.T) {
+			if got := JSONFieldNamingCamelCase.FieldName(tt.argName); got != tt.camelCase {
+				t.Errorf("camelCase FieldName(%q) = %q, want %q", tt.argName, got, tt.camelCase)
+			}
+			if got := JSONFieldNamingSnakeCase.FieldName(tt.argName); got != tt.snakeCase {
+				t.Errorf("snake_case FieldName(%q) = %q, want %q", tt.argName, got, tt.snakeCase)
+			}
+			if got := JSONFieldNamingVerbatim.FieldName(tt.argName); got != tt.argName {
+				t.Errorf("verbatim FieldName(%q) = %q, want %q", tt.argName, got, tt.argName)
+			}
+			if got := JSONFieldNamingDefault.StructTag(tt.argName); got != "" {
+				t.Errorf("default StructTag(%q) = %q, want empty", tt.argName, got)
+			}
+		})
+	}
+}
+
+func TestParseJSONFieldNaming(t *testing.T) {
+	for _, valid := range []string{"", "camelCase", "snake_case", "verbatim"} {
+		if _, err := ParseJSONFieldNaming(valid); err != nil {
+			t.Errorf("ParseJSONFieldNaming(%q) error: %s", valid, err)
+		}
+	}
+	if _, err := ParseJSONFieldNaming("kebab-case"); err == nil {
+		t.Error("ParseJSONFieldNaming(kebab-case) expected error")
+	}
+}
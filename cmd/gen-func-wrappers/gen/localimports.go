@@ -0,0 +1,135 @@
+package gen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/mod/modfile"
+)
+
+// DetectLocalImportPrefixes returns the import path prefixes
+// of the local imports for the Go module containing dir
+// that are grouped after the third party imports.
+// The prefixes are the module path of the module
+// and the module paths of all other modules of the Go workspace
+// containing the module, so that the imports of a monorepo
+// with several modules are grouped per module.
+// The workspace is the go.work file set with the GOWORK environment variable
+// or else the next go.work file in the module directory or its parents.
+// GOWORK=off disables the workspace detection.
+// Workspaces that don't use the module are ignored.
+func DetectLocalImportPrefixes(dir string) ([]string, error) {
+	moduleDir, err := findModuleDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	modulePath, err := readModulePath(moduleDir)
+	if err != nil {
+		return nil, err
+	}
+	prefixes := map[string]struct{}{modulePath: {}}
+
+	workFile, err := findWorkFile(moduleDir)
+	if err != nil {
+		return nil, err
+	}
+	if workFile != "" {
+		data, err := os.ReadFile(workFile) //#nosec G304
+		if err != nil {
+			return nil, err
+		}
+		work, err := modfile.ParseWork(workFile, data, nil)
+		if err != nil {
+			return nil, err
+		}
+		absModuleDir, err := filepath.Abs(moduleDir)
+		if err != nil {
+			return nil, err
+		}
+		var (
+			workspacePaths []string
+			inWorkspace    bool
+		)
+		for _, use := range work.Use {
+			useDir := use.Path
+			if !filepath.IsAbs(useDir) {
+				useDir = filepath.Join(filepath.Dir(workFile), useDir)
+			}
+			useDir, err = filepath.Abs(useDir)
+			if err != nil {
+				return nil, err
+			}
+			inWorkspace = inWorkspace || useDir == absModuleDir
+			usePath, err := readModulePath(useDir)
+			if err != nil {
+				return nil, err
+			}
+			workspacePaths = append(workspacePaths, usePath)
+		}
+		// A workspace that doesn't use the module is not its workspace
+		if inWorkspace {
+			for _, usePath := range workspacePaths {
+				prefixes[usePath] = struct{}{}
+			}
+		}
+	}
+
+	result := make([]string, 0, len(prefixes))
+	for prefix := range prefixes {
+		result = append(result, prefix)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// localImportPrefixesFor returns localImportPrefixes if not empty,
+// else the DetectLocalImportPrefixes for dir.
+func localImportPrefixesFor(dir string, localImportPrefixes []string) ([]string, error) {
+	if len(localImportPrefixes) > 0 {
+		return localImportPrefixes, nil
+	}
+	return DetectLocalImportPrefixes(dir)
+}
+
+// readModulePath returns the module path of the go.mod file in moduleDir.
+func readModulePath(moduleDir string) (string, error) {
+	goModFile := filepath.Join(moduleDir, "go.mod")
+	data, err := os.ReadFile(goModFile) //#nosec G304
+	if err != nil {
+		return "", err
+	}
+	modulePath := modfile.ModulePath(data)
+	if modulePath == "" {
+		return "", fmt.Errorf("no module path in %s", goModFile)
+	}
+	return modulePath, nil
+}
+
+// findWorkFile returns the go.work file of the workspace
+// containing moduleDir or an empty string if there is none.
+func findWorkFile(moduleDir string) (string, error) {
+	switch gowork := os.Getenv("GOWORK"); gowork {
+	case "off":
+		return "", nil
+	case "":
+	default:
+		return gowork, nil
+	}
+	dir, err := filepath.Abs(moduleDir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		workFile := filepath.Join(dir, "go.work")
+		if _, err = os.Stat(workFile); err == nil {
+			return workFile, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,82 @@
+package gen
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDetectLocalImportPrefixes(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"go.work":                 "go 1.25.0\n\nuse (\n\t./a\n\t./b\n)\n",
+		"a/go.mod":                "module example.com/a\n\ngo 1.25.0\n",
+		"a/pkg/pkg.go":            "package pkg\n",
+		"b/go.mod":                "module example.com/b\n\ngo 1.25.0\n",
+		"a/nested/go.mod":         "module example.com/a/nested\n\ngo 1.25.0\n",
+		"a/nested/pkg/pkg.go":     "package pkg\n",
+		"other/go.mod":            "module example.com/other\n\ngo 1.25.0\n",
+		"other/pkg/pkg.go":        "package pkg\n",
+		"outside/go.work":         "go 1.25.0\n\nuse ./x\n",
+		"outside/x/go.mod":        "module example.com/x\n\ngo 1.25.0\n",
+		"outside/y/go.mod":        "module example.com/y\n\ngo 1.25.0\n",
+		"outside/y/pkg/y.go":      "package pkg\n",
+		"outside/x/internal/x.go": "package internal\n",
+	} {
+		filePath := filepath.Join(root, filepath.FromSlash(name))
+		err := os.MkdirAll(filepath.Dir(filePath), 0700)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filePath, []byte(content), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("GOWORK", "")
+
+	tests := []struct {
+		name string
+		dir  string
+		want []string
+	}{
+		{name: "workspace module", dir: "a/pkg", want: []string{"example.com/a", "example.com/b"}},
+		{name: "nested module not in workspace", dir: "a/nested/pkg", want: []string{"example.com/a/nested"}},
+		{name: "module not in workspace", dir: "other/pkg", want: []string{"example.com/other"}},
+		{name: "other workspace module", dir: "outside/x/internal", want: []string{"example.com/x"}},
+		{name: "module beside workspace module", dir: "outside/y/pkg", want: []string{"example.com/y"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DetectLocalImportPrefixes(filepath.Join(root, filepath.FromSlash(tt.dir)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectLocalImportPrefixes() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("GOWORK=off", func(t *testing.T) {
+		t.Setenv("GOWORK", "off")
+		got, err := DetectLocalImportPrefixes(filepath.Join(root, "a", "pkg"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, []string{"example.com/a"}) {
+			t.Errorf("DetectLocalImportPrefixes() = %#v", got)
+		}
+	})
+
+	t.Run("configured prefixes", func(t *testing.T) {
+		got, err := localImportPrefixesFor(filepath.Join(root, "a", "pkg"), []string{"example.com/"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, []string{"example.com/"}) {
+			t.Errorf("localImportPrefixesFor() = %#v", got)
+		}
+	})
+}
@@ -0,0 +1,566 @@
+package gen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+	"gopkg.in/yaml.v3"
+)
+
+// OpenAPIFilename is the name of the OpenAPI specification file
+// written per package by WriteOpenAPI.
+const OpenAPIFilename = "openapi.yaml"
+
+// openAPIFileHeader is the first line of files
+// written to OpenAPIFilename.
+const openAPIFileHeader = "# Code generated by gen-func-wrappers. DO NOT EDIT.\n"
+
+// WriteOpenAPI writes an OpenAPI specification to the file OpenAPIFilename
+// of the package in path, or recursively of all packages in sub-directories
+// if path ends with "...", describing one POST operation per function wrapper
+// and per method of interface wrappers.
+// The request body of an operation is a JSON object with the arguments
+// of the wrapped function named like the JSON fields of CallWithJSON
+// and the response is the JSON encoded result, or an array of the results
+// if the function has multiple non-error results, like written by function.RespondJSON.
+// Packages without wrappers are skipped.
+// Files and directories for which the optional ignored function
+// returns true are skipped.
+func WriteOpenAPI(path string, verbose bool, printTo io.Writer, jsonOptions JSONOptions, ignored func(path string) bool) error {
+	if ignored == nil {
+		ignored = func(string) bool { return false }
+	}
+	return writePackageFiles(path, OpenAPIFilename, verbose, printTo, ignored, func(cache *packageCache, pkg *packages.Package, pkgDir string) ([]byte, error) {
+		spec, err := packageOpenAPI(cache, pkg, pkgDir, jsonOptions, ignored)
+		if err != nil || len(spec.Paths) == 0 {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		buf.WriteString(openAPIFileHeader)
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		err = enc.Encode(spec)
+		if err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	})
+}
+
+// writePackageFiles writes the file fileName with the data
+// returned by generate for the package in path,
+// or recursively for all packages in sub-directories if path ends with "...".
+// Packages for which generate returns no data are skipped.
+func writePackageFiles(path, fileName string, verbose bool, printTo io.Writer, ignored func(path string) bool, generate func(cache *packageCache, pkg *packages.Package, pkgDir string) ([]byte, error)) error {
+	recursive := strings.HasSuffix(path, "...")
+	path = filepath.Clean(strings.TrimSuffix(path, "..."))
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !fileInfo.IsDir() {
+		path = filepath.Dir(path)
+	}
+	if ignored == nil {
+		ignored = func(string) bool { return false }
+	}
+	pkgDirs, err := packageDirs(path, recursive, verbose, ignored)
+	if err != nil {
+		return err
+	}
+
+	cache := newPackageCache()
+	for _, pkgDir := range pkgDirs {
+		pkg, err := loadPackage(pkgDir)
+		if err != nil {
+			if recursive && errors.Is(err, errNoGoFiles) {
+				if verbose {
+					fmt.Println(err)
+				}
+				continue
+			}
+			return err
+		}
+		data, err := generate(cache, pkg, pkgDir)
+		if err != nil {
+			return err
+		}
+		if data == nil {
+			if verbose {
+				fmt.Println("no wrappers found for", fileName, "in", pkgDir)
+			}
+			continue
+		}
+
+		filePath := filepath.Join(pkgDir, fileName)
+		if printTo != nil {
+			if verbose {
+				fmt.Println(filePath, "would be written as:")
+			}
+			_, err = printTo.Write(data)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		err = writeFileIfChanged(filePath, data, "writing", verbose, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type openAPISpec struct {
+	OpenAPI    string                      `yaml:"openapi"`
+	Info       openAPIInfo                 `yaml:"info"`
+	Paths      map[string]*openAPIPathItem `yaml:"paths"`
+	Components *openAPIComponents          `yaml:"components,omitempty"`
+}
+
+type openAPIInfo struct {
+	Title       string `yaml:"title"`
+	Description string `yaml:"description,omitempty"`
+	Version     string `yaml:"version"`
+}
+
+type openAPIPathItem struct {
+	Post *openAPIOperation `yaml:"post"`
+}
+
+type openAPIOperation struct {
+	OperationID string                      `yaml:"operationId"`
+	Summary     string                      `yaml:"summary,omitempty"`
+	Description string                      `yaml:"description,omitempty"`
+	RequestBody *openAPIRequestBody         `yaml:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `yaml:"responses"`
+}
+
+type openAPIRequestBody struct {
+	Content map[string]*openAPIMediaType `yaml:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                       `yaml:"description"`
+	Content     map[string]*openAPIMediaType `yaml:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *jsonSchema `yaml:"schema"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*jsonSchema `yaml:"schemas"`
+}
+
+// jsonSchema is the subset of JSON Schema
+// used to describe Go types encoded with encoding/json.
+type jsonSchema struct {
+	Ref                  string                 `yaml:"$ref,omitempty"`
+	Type                 string                 `yaml:"type,omitempty"`
+	Format               string                 `yaml:"format,omitempty"`
+	Description          string                 `yaml:"description,omitempty"`
+	Minimum              *int                   `yaml:"minimum,omitempty"`
+	Items                *jsonSchema            `yaml:"items,omitempty"`
+	PrefixItems          []*jsonSchema          `yaml:"prefixItems,omitempty"`
+	MinItems             *int                   `yaml:"minItems,omitempty"`
+	MaxItems             *int                   `yaml:"maxItems,omitempty"`
+	Properties           map[string]*jsonSchema `yaml:"properties,omitempty"`
+	AdditionalProperties *jsonSchema            `yaml:"additionalProperties,omitempty"`
+}
+
+// packageOpenAPI returns the OpenAPI specification
+// for the wrappers in the files of pkg.
+func packageOpenAPI(cache *packageCache, pkg *packages.Package, pkgDir string, jsonOptions JSONOptions, ignored func(path string) bool) (*openAPISpec, error) {
+	files := packageFiles(pkg, pkgDir)
+	fileNames := make([]string, 0, len(files))
+	for fileName := range files {
+		if !isGeneratedFile(fileName) && !ignored(fileName) {
+			fileNames = append(fileNames, fileName)
+		}
+	}
+	sort.Strings(fileNames)
+
+	spec := &openAPISpec{
+		OpenAPI: "3.1.0",
+		Info: openAPIInfo{
+			Title:   pkg.Name,
+			Version: "0.0.0",
+		},
+		Paths: make(map[string]*openAPIPathItem),
+	}
+	pkgGenerated := newGeneratedFile(GeneratedFilename, "")
+	schemas := &openAPISchemas{
+		pkg:        pkg.Types,
+		components: make(map[string]*jsonSchema),
+	}
+	for _, fileName := range fileNames {
+		file := files[fileName]
+		if file.Doc != nil && spec.Info.Description == "" {
+			spec.Info.Description = strings.TrimSpace(file.Doc.Text())
+		}
+		wrappers, err := findFunctionWrappers(pkg.Fset, file)
+		if err != nil {
+			return nil, err
+		}
+		ifaceWrappers := findInterfaceWrappers(file)
+		if len(wrappers) == 0 && len(ifaceWrappers) == 0 {
+			continue
+		}
+
+		// Type expressions are evaluated in the scope of the file
+		// or of the file with the generated wrapper types
+		// that has the imports of the wrapped functions
+		scopeFiles := []*ast.File{file}
+		genFileName := generatedFileFor(fileName, file, pkgGenerated).fileName
+		if generated, ok := files[filepath.Join(pkgDir, genFileName)]; ok {
+			scopeFiles = append(scopeFiles, generated)
+		}
+		evalType := func(expr string) (types.Type, error) {
+			var err error
+			for _, f := range scopeFiles {
+				var tv types.TypeAndValue
+				tv, err = types.Eval(pkg.Fset, pkg.Types, f.Package, expr)
+				if err == nil {
+					return tv.Type, nil
+				}
+			}
+			return nil, fmt.Errorf("%s: can't evaluate type of %s: %w", fileName, expr, err)
+		}
+
+		functions, err := localAndImportedFunctions(cache, pkg, pkgDir, scopeFiles...)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, wrapper := range wrappers {
+			wrappedFuncPackage, wrappedFuncName := wrapper.WrappedFuncPkgAndFuncName()
+			wrappedFunc, _, err := findFunc(functions, wrappedFuncPackage, wrappedFuncName)
+			if err != nil {
+				return nil, errorAt(pkg.Fset, wrapper.Pos, err)
+			}
+			funcType, err := evalType(wrapper.WrappedFunc)
+			if err != nil {
+				return nil, err
+			}
+			signature, ok := funcType.(*types.Signature)
+			if !ok {
+				return nil, errorAt(pkg.Fset, wrapper.Pos, fmt.Errorf("%s is not a function", wrapper.WrappedFunc))
+			}
+			operation, err := schemas.operation(wrappedFuncName, wrappedFunc.Decl, signature, wrappedFuncPackage, wrapper.Directive.jsonOptions(jsonOptions), evalType)
+			if err != nil {
+				return nil, err
+			}
+			path := "/" + wrappedFuncName
+			if _, exists := spec.Paths[path]; exists {
+				path = "/" + wrapper.VarName
+				operation.OperationID = wrapper.VarName
+			}
+			spec.Paths[path] = &openAPIPathItem{Post: operation}
+		}
+
+		for _, iw := range ifaceWrappers {
+			ifacePackage, ifaceName := iw.PkgAndTypeName()
+			iface, referencedPkg, err := findInterface(functions, ifacePackage, ifaceName)
+			if err != nil {
+				return nil, errorAt(pkg.Fset, iw.Pos(), err)
+			}
+			methods, err := iw.methods(iface, referencedPkg.ImportLine != "")
+			if err != nil {
+				return nil, errorAt(pkg.Fset, iw.Pos(), err)
+			}
+			ifaceType, err := evalType(iw.Interface)
+			if err != nil {
+				return nil, err
+			}
+			for _, method := range methods {
+				obj, _, _ := types.LookupFieldOrMethod(ifaceType, false, pkg.Types, method.Name.Name)
+				methodFunc, ok := obj.(*types.Func)
+				if !ok {
+					return nil, fmt.Errorf("%s: can't find method %s of interface %s", fileName, method.Name.Name, iw.Interface)
+				}
+				operation, err := schemas.operation(ifaceName+"."+method.Name.Name, method, methodFunc.Type().(*types.Signature), ifacePackage, jsonOptions, evalType)
+				if err != nil {
+					return nil, err
+				}
+				spec.Paths["/"+ifaceName+"/"+method.Name.Name] = &openAPIPathItem{Post: operation}
+			}
+		}
+	}
+	if len(schemas.components) > 0 {
+		spec.Components = &openAPIComponents{Schemas: schemas.components}
+	}
+	return spec, nil
+}
+
+// openAPISchemas creates JSON schemas for Go types
+// and collects the schemas of named struct types
+// as components referenced by the other schemas.
+type openAPISchemas struct {
+	pkg        *types.Package
+	components map[string]*jsonSchema
+}
+
+// operation returns the OpenAPI operation for the function funcDecl
+// with the type signature that is qualified with funcPackage
+// if it is declared in another package.
+func (s *openAPISchemas) operation(operationID string, funcDecl *ast.FuncDecl, signature *types.Signature, funcPackage string, jsonOptions JSONOptions, evalType func(string) (types.Type, error)) (*openAPIOperation, error) {
+	operation := &openAPIOperation{
+		OperationID: operationID,
+		Responses:   make(map[string]*openAPIResponse),
+	}
+	if doc := strings.TrimSpace(funcDecl.Doc.Text()); doc != "" {
+		summary, description, _ := strings.Cut(doc, "\n")
+		operation.Summary = summary
+		operation.Description = strings.TrimSpace(description)
+	}
+
+	var (
+		argNames        = funcTypeArgNames(funcDecl.Type)
+		argDescriptions = funcDeclArgDescriptions(funcDecl)
+		argTypes        = funcTypeArgTypes(funcDecl.Type, funcPackage)
+		params          = signature.Params()
+	)
+	if params.Len() != len(argNames) {
+		return nil, fmt.Errorf("function %s is declared with %d arguments but its type has %d", operationID, len(argNames), params.Len())
+	}
+	argsSchema := &jsonSchema{
+		Type:       "object",
+		Properties: make(map[string]*jsonSchema),
+	}
+	for i, argName := range argNames {
+		argType := params.At(i).Type()
+		if i == 0 && isContextType(argType) {
+			continue
+		}
+		if argName == "_" {
+			continue
+		}
+		replacementType, ok := jsonOptions.TypeReplacements[strings.Replace(argTypes[i], "...", "[]", 1)]
+		if !ok && strings.HasPrefix(argTypes[i], "...") {
+			// Replacement of the element type of a variadic arg
+			replacementType, ok = jsonOptions.TypeReplacements[argTypes[i][3:]]
+			replacementType = "[]" + replacementType
+		}
+		if ok {
+			t, err := evalType(replacementType)
+			if err != nil {
+				return nil, err
+			}
+			argType = t
+		}
+		schema := s.schema(argType)
+		if argDescriptions[i] != "" {
+			if schema.Ref != "" {
+				// Siblings of $ref are allowed since OpenAPI 3.1
+				schema = &jsonSchema{Ref: schema.Ref}
+			}
+			schema.Description = argDescriptions[i]
+		}
+		fieldName := jsonOptions.FieldNaming.FieldName(argName)
+		if fieldName == "" {
+			fieldName = argName
+		}
+		argsSchema.Properties[fieldName] = schema
+	}
+	if len(argsSchema.Properties) > 0 {
+		operation.RequestBody = &openAPIRequestBody{
+			Content: map[string]*openAPIMediaType{
+				"application/json": {Schema: argsSchema},
+			},
+		}
+	}
+
+	results := signature.Results()
+	numResults := results.Len()
+	if numResults > 0 && isErrorType(results.At(numResults-1).Type()) {
+		numResults--
+		operation.Responses["default"] = &openAPIResponse{Description: "Error"}
+	}
+	response := &openAPIResponse{Description: "OK"}
+	switch numResults {
+	case 0:
+	case 1:
+		response.Content = map[string]*openAPIMediaType{
+			"application/json": {Schema: s.schema(results.At(0).Type())},
+		}
+	default:
+		schema := &jsonSchema{
+			Type:     "array",
+			MinItems: &numResults,
+			MaxItems: &numResults,
+		}
+		for i := 0; i < numResults; i++ {
+			schema.PrefixItems = append(schema.PrefixItems, s.schema(results.At(i).Type()))
+		}
+		response.Content = map[string]*openAPIMediaType{
+			"application/json": {Schema: schema},
+		}
+	}
+	operation.Responses["200"] = response
+	return operation, nil
+}
+
+func isContextType(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+func isErrorType(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// hasMethod returns true if t or a pointer to t has the method name.
+func hasMethod(t types.Type, name string) bool {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), false, nil, name)
+	_, ok := obj.(*types.Func)
+	return ok
+}
+
+// schema returns the JSON schema of values of type t
+// encoded with encoding/json.
+func (s *openAPISchemas) schema(t types.Type) *jsonSchema {
+	t = types.Unalias(t)
+	if named, ok := t.(*types.Named); ok {
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Time" {
+			return &jsonSchema{Type: "string", Format: "date-time"}
+		}
+		if hasMethod(t, "MarshalJSON") {
+			// Unknown custom JSON encoding
+			return &jsonSchema{}
+		}
+		if hasMethod(t, "MarshalText") {
+			return &jsonSchema{Type: "string"}
+		}
+		if _, ok := named.Underlying().(*types.Struct); ok {
+			return s.component(named)
+		}
+		return s.schema(named.Underlying())
+	}
+
+	switch t := t.(type) {
+	case *types.Basic:
+		return basicTypeSchema(t)
+
+	case *types.Pointer:
+		return s.schema(t.Elem())
+
+	case *types.Slice:
+		if elem, ok := t.Elem().Underlying().(*types.Basic); ok && elem.Kind() == types.Byte {
+			return &jsonSchema{Type: "string", Format: "byte"}
+		}
+		return &jsonSchema{Type: "array", Items: s.schema(t.Elem())}
+
+	case *types.Array:
+		length := int(t.Len())
+		return &jsonSchema{Type: "array", Items: s.schema(t.Elem()), MinItems: &length, MaxItems: &length}
+
+	case *types.Map:
+		return &jsonSchema{Type: "object", AdditionalProperties: s.schema(t.Elem())}
+
+	case *types.Struct:
+		schema := &jsonSchema{Type: "object", Properties: make(map[string]*jsonSchema)}
+		s.addStructFields(schema, t)
+		return schema
+	}
+
+	// Interfaces, channels and functions
+	return &jsonSchema{}
+}
+
+// component returns a reference to the component schema
+// of the named struct type which is added to the components
+// if it is not already added.
+func (s *openAPISchemas) component(named *types.Named) *jsonSchema {
+	name := componentName(named, s.pkg)
+	ref := &jsonSchema{Ref: "#/components/schemas/" + name}
+	if _, exists := s.components[name]; exists {
+		return ref
+	}
+	schema := &jsonSchema{Type: "object", Properties: make(map[string]*jsonSchema)}
+	// Add before the fields to stop the recursion of recursive types
+	s.components[name] = schema
+	s.addStructFields(schema, named.Underlying().(*types.Struct))
+	return ref
+}
+
+var invalidComponentNameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// componentName returns the name of the component schema for named
+// qualified with its package name if it is not declared in pkg.
+func componentName(named *types.Named, pkg *types.Package) string {
+	qualifier := func(p *types.Package) string {
+		if p == pkg {
+			return ""
+		}
+		return p.Name()
+	}
+	name := types.TypeString(named, qualifier)
+	return strings.Trim(invalidComponentNameChars.ReplaceAllString(name, "_"), "_")
+}
+
+// addStructFields adds the exported fields of structType
+// to the properties of schema like encoding/json
+// marshals them, including the fields of embedded structs.
+func (s *openAPISchemas) addStructFields(schema *jsonSchema, structType *types.Struct) {
+	for i := 0; i < structType.NumFields(); i++ {
+		field := structType.Field(i)
+		tag := reflect.StructTag(structType.Tag(i)).Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Embedded() && name == "" {
+			fieldType := types.Unalias(field.Type())
+			if ptr, ok := fieldType.(*types.Pointer); ok {
+				fieldType = types.Unalias(ptr.Elem())
+			}
+			if embedded, ok := fieldType.Underlying().(*types.Struct); ok {
+				s.addStructFields(schema, embedded)
+				continue
+			}
+		}
+		if !field.Exported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name()
+		}
+		schema.Properties[name] = s.schema(field.Type())
+	}
+}
+
+func basicTypeSchema(t *types.Basic) *jsonSchema {
+	zero := 0
+	switch t.Kind() {
+	case types.Bool:
+		return &jsonSchema{Type: "boolean"}
+	case types.Int8, types.Int16, types.Int32:
+		return &jsonSchema{Type: "integer", Format: "int32"}
+	case types.Int, types.Int64:
+		return &jsonSchema{Type: "integer", Format: "int64"}
+	case types.Uint8, types.Uint16, types.Uint32:
+		return &jsonSchema{Type: "integer", Format: "int32", Minimum: &zero}
+	case types.Uint, types.Uint64, types.Uintptr:
+		return &jsonSchema{Type: "integer", Format: "int64", Minimum: &zero}
+	case types.Float32:
+		return &jsonSchema{Type: "number", Format: "float"}
+	case types.Float64:
+		return &jsonSchema{Type: "number", Format: "double"}
+	case types.String:
+		return &jsonSchema{Type: "string"}
+	}
+	return &jsonSchema{}
+}
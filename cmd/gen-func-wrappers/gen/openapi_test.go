@@ -0,0 +1,111 @@
+package gen
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_packageOpenAPI(t *testing.T) {
+	root := writeTestModule(t, map[string]string{
+		"a/a.go": `package a
+
+import (
+	"context"
+	"time"
+
+	"github.com/domonda/go-function"
+)
+
+type Base struct {
+	ID int64 ` + "`json:\"id\"`" + `
+}
+
+type User struct {
+	Base
+	Name     string    ` + "`json:\"name,omitempty\"`" + `
+	Created  time.Time
+	Avatar   []byte
+	Manager  *User
+	Tags     map[string]bool
+	Password string ` + "`json:\"-\"`" + `
+	internal int
+}
+
+// GetUser returns a user
+//
+// Arguments:
+//   - id: the user ID
+func GetUser(ctx context.Context, id int64) (*User, error) { return nil, nil }
+
+func Split(s string) (string, uint8) { return s, 0 }
+
+var getUserWrapper = function.WrapperTODO(GetUser)
+
+var splitWrapper = function.WrapperTODO(Split)
+`,
+	})
+	pkgDir := filepath.Join(root, "a")
+	pkg, err := loadPackage(pkgDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec, err := packageOpenAPI(newPackageCache(), pkg, pkgDir, JSONOptions{FieldNaming: JSONFieldNamingSnakeCase}, func(string) bool { return false })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	getUser := spec.Paths["/GetUser"]
+	if getUser == nil {
+		t.Fatalf("no path /GetUser in %v", spec.Paths)
+	}
+	if getUser.Post.OperationID != "GetUser" || getUser.Post.Summary != "GetUser returns a user" {
+		t.Errorf("GetUser operation = %+v", getUser.Post)
+	}
+	wantArgs := &jsonSchema{
+		Type: "object",
+		Properties: map[string]*jsonSchema{
+			"id": {Type: "integer", Format: "int64", Description: "the user ID"},
+		},
+	}
+	if got := getUser.Post.RequestBody.Content["application/json"].Schema; !reflect.DeepEqual(got, wantArgs) {
+		t.Errorf("GetUser request body schema = %+v, want %+v", got, wantArgs)
+	}
+	if got := getUser.Post.Responses["200"].Content["application/json"].Schema.Ref; got != "#/components/schemas/User" {
+		t.Errorf("GetUser response schema $ref = %q", got)
+	}
+	if _, ok := getUser.Post.Responses["default"]; !ok {
+		t.Error("GetUser has no default error response")
+	}
+
+	zero := 0
+	wantUser := &jsonSchema{
+		Type: "object",
+		Properties: map[string]*jsonSchema{
+			"id":      {Type: "integer", Format: "int64"},
+			"name":    {Type: "string"},
+			"Created": {Type: "string", Format: "date-time"},
+			"Avatar":  {Type: "string", Format: "byte"},
+			"Manager": {Ref: "#/components/schemas/User"},
+			"Tags":    {Type: "object", AdditionalProperties: &jsonSchema{Type: "boolean"}},
+		},
+	}
+	if got := spec.Components.Schemas["User"]; !reflect.DeepEqual(got, wantUser) {
+		t.Errorf("User schema = %+v, want %+v", got, wantUser)
+	}
+
+	split := spec.Paths["/Split"]
+	if split == nil {
+		t.Fatalf("no path /Split in %v", spec.Paths)
+	}
+	two := 2
+	wantResults := &jsonSchema{
+		Type:        "array",
+		PrefixItems: []*jsonSchema{{Type: "string"}, {Type: "integer", Format: "int32", Minimum: &zero}},
+		MinItems:    &two,
+		MaxItems:    &two,
+	}
+	if got := split.Post.Responses["200"].Content["application/json"].Schema; !reflect.DeepEqual(got, wantResults) {
+		t.Errorf("Split response schema = %+v, want %+v", got, wantResults)
+	}
+}
@@ -1,130 +1,151 @@
 package gen
 
 import (
+	"errors"
 	"fmt"
 	"go/ast"
-	"go/parser"
-	"go/token"
-	"os"
+	"path/filepath"
 	"strings"
+
+	"golang.org/x/tools/go/packages"
 )
 
+// ParsedFile is a parsed Go file with the package names
+// of its imports that are needed to find the imports
+// referenced by the types of function signatures.
+type ParsedFile struct {
+	*ast.File
+
+	// ImportNames are the declared package names
+	// of the imported packages by import path.
+	ImportNames map[string]string
+}
+
+// typedFile returns the ParsedFile for a file of pkg
+// with the import names from the type information of pkg.
+func typedFile(pkg *packages.Package, file *ast.File) *ParsedFile {
+	parsed := &ParsedFile{File: file, ImportNames: make(map[string]string)}
+	for _, imp := range file.Imports {
+		if pkgName := pkg.TypesInfo.PkgNameOf(imp); pkgName != nil {
+			parsed.ImportNames[pkgName.Imported().Path()] = pkgName.Imported().Name()
+		}
+	}
+	return parsed
+}
+
 type funcDeclInFile struct {
 	Decl *ast.FuncDecl
-	File *ast.File
+	File *ParsedFile
 }
 
-func parsePackage(pkgDir, excludeFilename string, onlyFuncs ...string) (pkg *ast.Package, funcs map[string]funcDeclInFile, err error) {
-	fset := token.NewFileSet()
-	pkgs, err := parser.ParseDir(fset, pkgDir, filterGoFiles(excludeFilename), 0)
+type interfaceInFile struct {
+	Type *ast.InterfaceType
+	File *ParsedFile
+}
+
+// loadMode is the packages.LoadMode for packages with wrappers to generate.
+// The type information is used to resolve the imported packages of their files.
+const loadMode = packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports
+
+// errNoGoFiles is returned by loadPackage for directories
+// without Go files matching the build constraints.
+var errNoGoFiles = errors.New("no Go files")
+
+// loadPackage loads the package in pkgDir without test files.
+// Only parse errors are returned because code referencing
+// the wrappers to be generated does not compile before generation.
+func loadPackage(pkgDir string) (*packages.Package, error) {
+	config := &packages.Config{Mode: loadMode, Dir: pkgDir}
+	pkgs, err := packages.Load(config, ".")
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	if len(pkgs) != 1 {
-		return nil, nil, fmt.Errorf("%d packages found in %s", len(pkgs), pkgDir)
+		return nil, fmt.Errorf("%d packages found in %s", len(pkgs), pkgDir)
+	}
+	pkg := pkgs[0]
+	if len(pkg.GoFiles) == 0 {
+		return nil, fmt.Errorf("%w in %s", errNoGoFiles, pkgDir)
+	}
+	for _, err := range pkg.Errors {
+		if err.Kind == packages.ParseError {
+			return nil, err
+		}
+	}
+	return pkg, nil
+}
+
+// loadTestPackages loads the test variants of the package in pkgDir,
+// that is the package including its _test.go files
+// and the external _test package if the directory has test files.
+// Like with loadPackage only parse errors are returned.
+func loadTestPackages(pkgDir string) ([]*packages.Package, error) {
+	config := &packages.Config{Mode: loadMode, Dir: pkgDir, Tests: true}
+	pkgs, err := packages.Load(config, ".")
+	if err != nil {
+		return nil, err
+	}
+	var testPkgs []*packages.Package
+	for _, pkg := range pkgs {
+		// Test variants have IDs like "pkg [pkg.test]" and "pkg_test [pkg.test]",
+		// the package without tests has the ID "pkg"
+		// and the generated test main package "pkg.test"
+		if !strings.HasSuffix(pkg.ID, ".test]") {
+			continue
+		}
+		for _, err := range pkg.Errors {
+			if err.Kind == packages.ParseError {
+				return nil, err
+			}
+		}
+		testPkgs = append(testPkgs, pkg)
 	}
-	for _, p := range pkgs {
-		pkg = p
+	return testPkgs, nil
+}
+
+// packageFiles returns the parsed files of pkg by file name.
+// The file names are joined to pkgDir.
+func packageFiles(pkg *packages.Package, pkgDir string) map[string]*ast.File {
+	files := make(map[string]*ast.File, len(pkg.Syntax))
+	for _, file := range pkg.Syntax {
+		fileName := filepath.Base(pkg.Fset.Position(file.Package).Filename)
+		files[filepath.Join(pkgDir, fileName)] = file
 	}
+	return files
+}
 
-	// // typesInfo.Uses allows to lookup import paths for identifiers.
-	// typesInfo := &types.Info{Uses: make(map[*ast.Ident]types.Object)}
-	// // Type check the parsed code using the default importer.
-	// // Use golang.org/x/tools/go/loader to check a program
-	// // consisting of multiple packages.
-	// conf := types.Config{Importer: importer.Default()}
-	// _, err = conf.Check(pkgDir, fileSet, files, typesInfo)
-	// if err != nil {
-	// 	return nil, err
-	// }
+// parsePackage loads the package in pkgDir and returns its exported
+// functions or only the functions named in onlyFuncs.
+// Functions of files with build constraints are skipped
+// because the file generated for them has no build constraints.
+func parsePackage(pkgDir, excludeFilename string, onlyFuncs ...string) (pkg *packages.Package, funcs map[string]funcDeclInFile, err error) {
+	pkg, err = loadPackage(pkgDir)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	funcs = make(map[string]funcDeclInFile)
-	for _, file := range pkg.Files {
-		// ast.Print(fileSet, file.Imports)
-		for _, obj := range file.Scope.Objects {
-			if obj.Kind != ast.Fun {
-				// ast.Print(fileSet, obj)
-				continue
-			}
-			funcDecl := obj.Decl.(*ast.FuncDecl)
-			if funcDecl.Recv != nil {
+	for fileName, file := range packageFiles(pkg, pkgDir) {
+		if filepath.Base(fileName) == excludeFilename || buildConstraint(file) != "" || hasFileNameConstraint(fileName) {
+			continue
+		}
+		parsed := typedFile(pkg, file)
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv != nil {
 				continue
 			}
 			if len(onlyFuncs) > 0 {
 				for _, name := range onlyFuncs {
 					if funcDecl.Name.Name == name {
-						funcs[name] = funcDeclInFile{Decl: funcDecl, File: file}
+						funcs[name] = funcDeclInFile{Decl: funcDecl, File: parsed}
 						break
 					}
 				}
 			} else if funcDecl.Name.IsExported() {
-				funcs[funcDecl.Name.Name] = funcDeclInFile{Decl: funcDecl, File: file}
+				funcs[funcDecl.Name.Name] = funcDeclInFile{Decl: funcDecl, File: parsed}
 			}
 		}
 	}
 	return pkg, funcs, nil
 }
-
-func filterGoFiles(excludeFilenames ...string) func(info os.FileInfo) bool {
-	return func(info os.FileInfo) bool {
-		name := info.Name()
-		for _, exclude := range excludeFilenames {
-			if name == exclude {
-				return false
-			}
-		}
-		if strings.HasSuffix(name, "_test.go") {
-			return false
-		}
-		return true
-	}
-}
-
-func filterOutTests(info os.FileInfo) bool {
-	if strings.HasSuffix(info.Name(), "_test.go") {
-		return false
-	}
-	return true
-}
-
-// func parsePackage2(pkgDir, genFilename string, onlyFuncs ...string) (pkgName string, funcs map[*ast.FuncDecl]*ast.File, err error) {
-// 	config := &packages.Config{
-// 		Mode: packages.NeedName + packages.NeedImports + packages.NeedTypes + packages.NeedSyntax + packages.NeedTypesInfo,
-// 		Dir:  pkgDir,
-// 	}
-// 	pkgs, err := packages.Load(config, pkgDir)
-// 	if err != nil {
-// 		return "", nil, err
-// 	}
-// 	if err != nil {
-// 		return "", nil, err
-// 	}
-// 	if len(pkgs) != 1 {
-// 		return "", nil, fmt.Errorf("%d packages found in %s", len(pkgs), pkgDir)
-// 	}
-// 	pkgName = pkgs[0].Name
-// 	files := pkgs[0].Syntax
-
-// 	funcs = make(map[*ast.FuncDecl]*ast.File)
-// 	for _, file := range files {
-// 		// ast.Print(fileSet, file.Imports)
-// 		for _, obj := range file.Scope.Objects {
-// 			if obj.Kind != ast.Fun {
-// 				// ast.Print(fileSet, obj)
-// 				continue
-// 			}
-// 			funcDecl := obj.Decl.(*ast.FuncDecl)
-// 			if len(onlyFuncs) > 0 {
-// 				for _, name := range onlyFuncs {
-// 					if funcDecl.Name.Name == name {
-// 						funcs[funcDecl] = file
-// 						break
-// 					}
-// 				}
-// 			} else if funcDecl.Name.IsExported() {
-// 				funcs[funcDecl] = file
-// 			}
-// 		}
-// 	}
-// 	return pkgName, funcs, nil
-// }
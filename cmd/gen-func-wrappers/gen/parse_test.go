@@ -0,0 +1,94 @@
+package gen
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	t.Setenv("GOWORK", "off")
+	root := t.TempDir()
+	files["go.mod"] = "module example.com/testmod\n\ngo 1.23\n"
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		err := os.MkdirAll(filepath.Dir(path), 0700)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, []byte(content), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func Test_localAndImportedFunctions(t *testing.T) {
+	root := writeTestModule(t, map[string]string{
+		"a/a.go": `package a
+
+import (
+	"strings"
+
+	renamed "example.com/testmod/b"
+)
+
+func Local() string { return strings.ToUpper(renamed.B()) }
+
+var x = undefinedBeforeGeneration
+`,
+		"b/b.go": `package b
+
+type Service interface{ Get() string }
+
+func B() string { return "b" }
+
+func unexported() {}
+`,
+		"empty/README.md": "no Go files",
+	})
+
+	pkg, err := loadPackage(filepath.Join(root, "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := packageFiles(pkg, filepath.Join(root, "a"))[filepath.Join(root, "a", "a.go")]
+	if file == nil {
+		t.Fatal("a.go not found in package files")
+	}
+
+	functions, err := localAndImportedFunctions(newPackageCache(), pkg, filepath.Join(root, "a"), file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := functions[""].Funcs["Local"]; !ok {
+		t.Error("local function Local not found")
+	}
+	if _, ok := functions["strings"]; ok {
+		t.Error("standard library package strings should be skipped")
+	}
+	imported, ok := functions["renamed"]
+	if !ok {
+		t.Fatal("renamed import not found")
+	}
+	if imported.PkgName != "b" || imported.PkgPath != "example.com/testmod/b" {
+		t.Errorf("imported package = %s %s", imported.PkgName, imported.PkgPath)
+	}
+	if _, ok := imported.Funcs["B"]; !ok {
+		t.Error("imported function B not found")
+	}
+	if _, ok := imported.Funcs["unexported"]; ok {
+		t.Error("unexported function of imported package found")
+	}
+	if _, ok := imported.Interfaces["Service"]; !ok {
+		t.Error("imported interface Service not found")
+	}
+
+	_, err = loadPackage(filepath.Join(root, "empty"))
+	if !errors.Is(err, errNoGoFiles) {
+		t.Errorf("loadPackage() of directory without Go files returned %v", err)
+	}
+}
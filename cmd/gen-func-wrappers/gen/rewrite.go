@@ -4,16 +4,63 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
+	"go/parser"
 	"go/token"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/ungerik/go-astvisit"
+	"golang.org/x/tools/go/packages"
 )
 
+// ErrOrphanedWrapper is returned for generated wrappers
+// of functions or interfaces that no longer exist
+// unless the orphaned wrappers are removed
+// with Generator.RemoveOrphans.
+var ErrOrphanedWrapper = errors.New("orphaned wrapper")
+
+// RewriteDir rewrites the wrappers in place in all files of the package in path,
+// or recursively in all sub-directories if path ends with "...".
+// The imports of the written files are grouped with localImportPrefixes,
+// or if empty with the DetectLocalImportPrefixes of the module of each package.
+// Use Generator for further options.
 func RewriteDir(path string, verbose bool, printOnly io.Writer, jsonTypeReplacements map[string]string, localImportPrefixes []string) (err error) {
+	g := &Generator{
+		JSONOptions:         JSONOptions{TypeReplacements: jsonTypeReplacements},
+		LocalImportPrefixes: localImportPrefixes,
+		Verbose:             verbose,
+		PrintOnly:           printOnly,
+	}
+	return g.rewriteDir(path, nil)
+}
+
+// rewriteDir rewrites the wrappers in all files of the package in path,
+// or recursively in all sub-directories if path ends with "...",
+// adding the results to the optional collector.
+// If g.GenFile is true then the generated wrapper types of a package
+// are written to the file GeneratedFilename instead of the files
+// declaring the wrappers. Wrapper types of files with build constraints
+// are written to a separate generated file with the same constraints.
+// Only files matching the current build configuration are rewritten.
+// If g.GenTests is true then test cases for the generated function wrappers
+// of a package are written to a test file named like the generated file
+// with a _test suffix.
+// Wrappers declared in _test.go files are always generated
+// in place into their file without test cases.
+// If g.RemoveOrphans is true then generated wrappers of functions
+// or interfaces that no longer exist are removed,
+// else an ErrOrphanedWrapper error is returned for every one of them.
+// Packages are rewritten concurrently sharing the parsed imported packages,
+// except when g.PrintOnly is not nil to not mix up the printed files.
+func (g *Generator) rewriteDir(path string, collector *resultCollector) (err error) {
 	recursive := strings.HasSuffix(path, "...")
 	if recursive {
 		path = filepath.Clean(strings.TrimSuffix(path, "..."))
@@ -23,69 +70,369 @@ func RewriteDir(path string, verbose bool, printOnly io.Writer, jsonTypeReplacem
 		return err
 	}
 	if !fileInfo.IsDir() {
-		return RewriteFile(path, verbose, printOnly, jsonTypeReplacements, localImportPrefixes)
+		return g.rewriteFile(path, collector)
 	}
 
-	fset := token.NewFileSet()
-	pkg, err := astvisit.ParsePackage(fset, path, filterOutTests)
-	if err != nil && (!recursive || !errors.Is(err, astvisit.ErrPackageNotFound)) {
+	ignored := g.Ignored
+	if ignored == nil {
+		ignored = func(string) bool { return false }
+	}
+	pkgDirs, err := packageDirs(path, recursive, g.Verbose, ignored)
+	if err != nil {
 		return err
 	}
-	if err == nil {
-		for fileName, file := range pkg.Files {
-			err = RewriteAstFile(fset, pkg, file, fileName, verbose, printOnly, jsonTypeReplacements, localImportPrefixes)
-			if err != nil {
-				return err
+
+	concurrency := runtime.NumCPU()
+	if g.PrintOnly != nil {
+		concurrency = 1
+	}
+	var (
+		cache   = newPackageCache()
+		errs    = make([]error, len(pkgDirs))
+		indices = make(chan int)
+		wg      sync.WaitGroup
+	)
+	for w := 0; w < min(concurrency, len(pkgDirs)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				pkgDir := pkgDirs[i]
+				pkg, err := loadPackage(pkgDir)
+				if err != nil {
+					if recursive && errors.Is(err, errNoGoFiles) {
+						if g.Verbose {
+							fmt.Println(err)
+						}
+						continue
+					}
+					errs[i] = err
+					continue
+				}
+				errs[i] = g.rewritePackage(cache, pkg, pkgDir, ignored, collector)
 			}
+		}()
+	}
+	for i := range pkgDirs {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// skippedDirs are the names of directories that are always
+// skipped when walking directories recursively
+// because they don't contain packages of the module.
+var skippedDirs = map[string]bool{
+	"node_modules": true,
+	"testdata":     true,
+	"vendor":       true,
+}
+
+// packageDirs returns path and if recursive is true
+// all its sub-directories that are not hidden, skippedDirs or ignored.
+func packageDirs(path string, recursive, verbose bool, ignored func(path string) bool) ([]string, error) {
+	if ignored(path) {
+		if verbose {
+			fmt.Println("ignoring", path)
 		}
-	} else if verbose {
-		fmt.Println(err)
+		return nil, nil
 	}
 	if !recursive {
+		return []string{path}, nil
+	}
+	var dirs []string
+	err := filepath.WalkDir(path, func(dir string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		name := d.Name()
+		if dir != path && (name[0] == '.' || skippedDirs[name]) {
+			return filepath.SkipDir
+		}
+		if ignored(dir) {
+			if verbose {
+				fmt.Println("ignoring", dir)
+			}
+			return filepath.SkipDir
+		}
+		dirs = append(dirs, dir)
 		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dirs, nil
+}
+
+// RewriteFile rewrites the wrappers in place in the file filePath.
+// Use Generator for further options.
+func RewriteFile(filePath string, verbose bool, printOnly io.Writer, jsonTypeReplacements map[string]string, localImportPrefixes []string) (err error) {
+	g := &Generator{
+		JSONOptions:         JSONOptions{TypeReplacements: jsonTypeReplacements},
+		LocalImportPrefixes: localImportPrefixes,
+		Verbose:             verbose,
+		PrintOnly:           printOnly,
+	}
+	return g.rewriteFile(filePath, nil)
+}
+
+// rewriteFile rewrites the wrappers in the file filePath
+// adding the results to the optional collector.
+// If g.GenFile or g.GenTests is true then all files of the package are rewritten
+// because the file GeneratedFilename and its test file contain
+// the generated wrapper types and tests of the whole package.
+// A _test.go file is always rewritten in place on its own.
+func (g *Generator) rewriteFile(filePath string, collector *resultCollector) (err error) {
+	filePath = filepath.Clean(filePath)
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return err
 	}
+	if fileInfo.IsDir() {
+		return fmt.Errorf("file path is a directory: %s", filePath)
+	}
+	pkgDir := filepath.Dir(filePath)
+	localImportPrefixes, err := localImportPrefixesFor(pkgDir, g.LocalImportPrefixes)
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(filePath, "_test.go") {
+		return g.rewriteTestFiles(newPackageCache(), pkgDir, localImportPrefixes, func(path string) bool { return path != filePath }, collector)
+	}
+	pkg, err := loadPackage(pkgDir)
+	if err != nil {
+		return err
+	}
+	if g.GenFile || g.GenTests {
+		return g.rewritePackage(newPackageCache(), pkg, pkgDir, func(string) bool { return false }, collector)
+	}
+	astFile, ok := packageFiles(pkg, pkgDir)[filePath]
+	if !ok {
+		return fmt.Errorf("file %s is not part of package %s", filePath, pkg.PkgPath)
+	}
+	return g.rewriteAstFile(newPackageCache(), pkg, astFile, filePath, nil, nil, localImportPrefixes, collector)
+}
 
-	files, err := os.ReadDir(path)
+func (g *Generator) rewritePackage(cache *packageCache, pkg *packages.Package, pkgDir string, ignored func(path string) bool, collector *resultCollector) (err error) {
+	localImportPrefixes, err := localImportPrefixesFor(pkgDir, g.LocalImportPrefixes)
 	if err != nil {
 		return err
 	}
-	for _, file := range files {
-		fileName := file.Name()
-		if !file.IsDir() || fileName[0] == '.' || fileName == "node_modules" {
+	files := packageFiles(pkg, pkgDir)
+	if !g.GenFile && !g.GenTests {
+		for fileName, file := range files {
+			if isGeneratedFile(fileName) || ignored(fileName) {
+				continue
+			}
+			err := g.rewriteAstFile(cache, pkg, file, fileName, nil, nil, localImportPrefixes, collector)
+			if err != nil {
+				return err
+			}
+		}
+		return g.rewriteTestFiles(cache, pkgDir, localImportPrefixes, ignored, collector)
+	}
+
+	// Rewrite files in sorted order so that
+	// the generated files have a stable order
+	fileNames := make([]string, 0, len(files))
+	for fileName := range files {
+		if !isGeneratedFile(fileName) && !ignored(fileName) {
+			fileNames = append(fileNames, fileName)
+		}
+	}
+	sort.Strings(fileNames)
+
+	// Wrappers of files with build constraints are generated
+	// into separate files with the same constraints.
+	// Generated tests follow the same file layout,
+	// also if the wrapper types are written in place.
+	var (
+		generated = newGeneratedFile(GeneratedFilename, "")
+		tests     = newGeneratedTests(generated)
+	)
+	for _, fileName := range fileNames {
+		var (
+			fileGenerated = generatedFileFor(fileName, files[fileName], generated)
+			fileTests     = tests
+			genFileArg    *generatedFile
+			testsArg      *generatedTests
+		)
+		if fileGenerated != generated {
+			fileTests = newGeneratedTests(fileGenerated)
+		}
+		if g.GenFile {
+			genFileArg = fileGenerated
+		}
+		if g.GenTests {
+			testsArg = fileTests
+		}
+		err := g.rewriteAstFile(cache, pkg, files[fileName], fileName, genFileArg, testsArg, localImportPrefixes, collector)
+		if err != nil {
+			return err
+		}
+		if fileGenerated == generated {
 			continue
 		}
-		err = RewriteDir(filepath.Join(path, fileName, "..."), verbose, printOnly, jsonTypeReplacements, localImportPrefixes)
+		if g.GenFile {
+			err = fileGenerated.write(pkgDir, pkg.Name, g.Verbose, g.PrintOnly, localImportPrefixes, collector)
+			if err != nil {
+				return err
+			}
+		}
+		if g.GenTests {
+			err = fileTests.write(pkgDir, pkg.Name, g.Verbose, g.PrintOnly, localImportPrefixes, collector)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if g.GenFile {
+		err := generated.write(pkgDir, pkg.Name, g.Verbose, g.PrintOnly, localImportPrefixes, collector)
 		if err != nil {
 			return err
 		}
 	}
-	return nil
+	if g.GenTests {
+		err := tests.write(pkgDir, pkg.Name, g.Verbose, g.PrintOnly, localImportPrefixes, collector)
+		if err != nil {
+			return err
+		}
+	}
+	err = g.rewriteTestFiles(cache, pkgDir, localImportPrefixes, ignored, collector)
+	if err != nil {
+		return err
+	}
+	return removeStaleGeneratedFiles(pkgDir, g.Verbose, g.PrintOnly, collector)
 }
 
-func RewriteFile(filePath string, verbose bool, printOnly io.Writer, jsonTypeReplacements map[string]string, localImportPrefixes []string) (err error) {
-	filePath = filepath.Clean(filePath)
-	fileInfo, err := os.Stat(filePath)
+// rewriteTestFiles rewrites the wrappers declared in the _test.go files
+// of the package in pkgDir in place, also if the wrapper types
+// of the other files are written to a generated file,
+// so that test-only functions can be wrapped.
+// No test cases are generated for them.
+// The test variants of the package are only loaded
+// if a test file declares wrappers.
+func (g *Generator) rewriteTestFiles(cache *packageCache, pkgDir string, localImportPrefixes []string, ignored func(path string) bool, collector *resultCollector) error {
+	testFiles, err := testFilesWithWrappers(pkgDir, ignored)
+	if err != nil || len(testFiles) == 0 {
+		return err
+	}
+	testPkgs, err := loadTestPackages(pkgDir)
 	if err != nil {
 		return err
 	}
-	if fileInfo.IsDir() {
-		return fmt.Errorf("file path is a directory: %s", filePath)
+	for _, testPkg := range testPkgs {
+		files := packageFiles(testPkg, pkgDir)
+		for _, fileName := range testFiles {
+			file, ok := files[fileName]
+			if !ok {
+				continue
+			}
+			err = g.rewriteAstFile(cache, testPkg, file, fileName, nil, nil, localImportPrefixes, collector)
+			if err != nil {
+				return err
+			}
+		}
 	}
+	return nil
+}
+
+// testFilesWithWrappers returns the sorted paths of the
+// _test.go files in pkgDir that declare wrappers,
+// skipping generated and ignored files.
+// The files are only parsed without type information.
+func testFilesWithWrappers(pkgDir string, ignored func(path string) bool) ([]string, error) {
+	entries, err := os.ReadDir(pkgDir)
+	if err != nil {
+		return nil, err
+	}
+	var testFiles []string
 	fset := token.NewFileSet()
-	pkg, err := astvisit.ParsePackage(fset, filepath.Dir(filePath), filterOutTests)
+	for _, entry := range entries {
+		filePath := filepath.Join(pkgDir, entry.Name())
+		if entry.IsDir() || !strings.HasSuffix(filePath, "_test.go") || isGeneratedFile(filePath) || ignored(filePath) {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		wrappers, err := findFunctionWrappers(fset, file)
+		if err != nil {
+			return nil, err
+		}
+		if len(wrappers) > 0 || len(findInterfaceWrappers(file)) > 0 {
+			testFiles = append(testFiles, filePath)
+		}
+	}
+	return testFiles, nil
+}
+
+// RewriteAstFile rewrites the wrappers of astFile
+// that must be one of the parsed files of filePkg
+// loaded with syntax and type information.
+// Use Generator.RewriteAstFile for further options.
+func RewriteAstFile(filePkg *packages.Package, astFile *ast.File, filePath string, verbose bool, printTo io.Writer, jsonTypeReplacements map[string]string, localImportPrefixes []string) (err error) {
+	g := &Generator{
+		JSONOptions:         JSONOptions{TypeReplacements: jsonTypeReplacements},
+		LocalImportPrefixes: localImportPrefixes,
+		Verbose:             verbose,
+		PrintOnly:           printTo,
+	}
+	return g.RewriteAstFile(filePkg, astFile, filePath)
+}
+
+// RewriteAstFile rewrites the wrappers of astFile in place
+// that must be one of the parsed files of filePkg
+// loaded with syntax and type information.
+// The GenFile and GenTests options are not used
+// because they apply to whole packages.
+func (g *Generator) RewriteAstFile(filePkg *packages.Package, astFile *ast.File, filePath string) error {
+	localImportPrefixes, err := localImportPrefixesFor(filepath.Dir(filePath), g.LocalImportPrefixes)
 	if err != nil {
 		return err
 	}
-	return RewriteAstFile(fset, pkg, pkg.Files[filePath], filePath, verbose, printOnly, jsonTypeReplacements, localImportPrefixes)
+	return g.rewriteAstFile(newPackageCache(), filePkg, astFile, filePath, nil, nil, localImportPrefixes, nil)
 }
 
-func RewriteAstFile(fset *token.FileSet, filePkg *ast.Package, astFile *ast.File, filePath string, verbose bool, printTo io.Writer, jsonTypeReplacements map[string]string, localImportPrefixes []string) (err error) {
+// rewriteAstFile rewrites the wrappers of astFile in place
+// or writes the generated wrapper types to genFile if not nil
+// and only keeps the var declarations and interface wrapper
+// constructor functions in the file.
+// Test cases for the generated function wrappers
+// are added to tests if not nil.
+func (g *Generator) rewriteAstFile(cache *packageCache, filePkg *packages.Package, astFile *ast.File, filePath string, genFile *generatedFile, tests *generatedTests, localImportPrefixes []string, collector *resultCollector) (err error) {
 	filePath = filepath.Clean(filePath)
+	fset := filePkg.Fset
+
+	report := &FileReport{File: filePath, Wrappers: []*WrapperReport{}}
+	if collector != nil {
+		start := time.Now()
+		defer func() {
+			report.Duration = time.Since(start)
+			if err != nil {
+				report.Error = err.Error()
+				// A wrapper without status was being generated
+				if n := len(report.Wrappers); n > 0 && report.Wrappers[n-1].Status == "" {
+					report.Wrappers[n-1].Status = WrapperFailed
+					report.Wrappers[n-1].Error = err.Error()
+				}
+			}
+			collector.addFileReport(report)
+		}()
+	}
 
 	// ast.Print(fset, file)
-	wrappers := findFunctionWrappers(fset, astFile)
-	if len(wrappers) == 0 {
-		if verbose {
+	wrappers, err := findFunctionWrappers(fset, astFile)
+	if err != nil {
+		return err
+	}
+	ifaceWrappers := findInterfaceWrappers(astFile)
+	if len(wrappers) == 0 && len(ifaceWrappers) == 0 {
+		if g.Verbose {
 			fmt.Println("no wrappers found to rewrite in", filePath)
 		}
 		return nil
@@ -99,33 +446,114 @@ func RewriteAstFile(fset *token.FileSet, filePkg *ast.Package, astFile *ast.File
 	// Also parse all functions of the file's package
 	// because they could als be referenced with an empty import name.
 	// Added with empty string as package/import name.
-	functions, err := localAndImportedFunctions(fset, filePkg, astFile, pkgDir)
+	// The imports of the wrapped functions of files that were
+	// generated before to a separate file are only in the generated file.
+	files := []*ast.File{astFile}
+	if genFile != nil {
+		if generated, ok := packageFiles(filePkg, pkgDir)[filepath.Join(pkgDir, genFile.fileName)]; ok {
+			files = append(files, generated)
+		}
+	}
+	functions, err := localAndImportedFunctions(cache, filePkg, pkgDir, files...)
 	if err != nil {
 		return err
 	}
+	if tests != nil {
+		for _, file := range files {
+			tests.addImports(typedFile(filePkg, file), functions)
+		}
+	}
 
 	neededImportLines := make(map[string]struct{})
 
-	var replacements astvisit.NodeReplacements
+	// Generated types are written in place
+	// or to the separate generated file
+	var (
+		typesCode        io.Writer
+		typesImportLines = neededImportLines
+	)
+	if genFile != nil {
+		typesCode = &genFile.code
+		typesImportLines = genFile.importLines
+	}
+
+	var (
+		replacements astvisit.NodeReplacements
+		orphans      []error
+		// Wrappers with generated call methods per wrapped function
+		// that other wrappers of the same function can share
+		callWrappers = make(map[string][]*wrapper)
+	)
 	for _, wrapper := range wrappers {
+		wrapperReport := &WrapperReport{Name: wrapper.VarName, Wrapped: wrapper.WrappedFunc}
+		report.Wrappers = append(report.Wrappers, wrapperReport)
+
 		wrappedFuncPackage, wrappedFuncName := wrapper.WrappedFuncPkgAndFuncName()
-		referencedPkg, ok := functions[wrappedFuncPackage]
-		if !ok {
-			return fmt.Errorf("can't find package %s in imports of file %s", wrappedFuncPackage, filePath)
+		wrappedFunc, referencedPkg, err := findFunc(functions, wrappedFuncPackage, wrappedFuncName)
+		if err != nil {
+			if wrapper.TODO {
+				return errorAt(fset, wrapper.Pos, err)
+			}
+			if !g.RemoveOrphans {
+				orphan := fmt.Errorf("%s: %w %s of %s: %w", fset.Position(wrapper.Pos), ErrOrphanedWrapper, wrapper.VarName, wrapper.WrappedFunc, err)
+				orphans = append(orphans, orphan)
+				wrapperReport.Status, wrapperReport.Error = WrapperOrphaned, orphan.Error()
+				continue
+			}
+			if g.Verbose {
+				fmt.Println("removing orphaned wrapper", wrapper.VarName, "of", wrapper.WrappedFunc, "from", filePath)
+			}
+			for _, node := range wrapper.Nodes {
+				replacements.AddRemoval(node, "Orphaned wrapper for "+wrapper.WrappedFunc)
+			}
+			wrapperReport.Status = WrapperRemoved
+			continue
+		}
+		if referencedPkg.ImportLine != "" {
+			// The generated file needs the import
+			// exactly as in the file, including an alias or dot
+			typesImportLines[referencedPkg.ImportLine] = struct{}{}
 		}
-		wrappedFunc, ok := referencedPkg.Funcs[wrappedFuncName]
-		if !ok {
-			return fmt.Errorf("can't find function %s in package %s", wrappedFuncName, wrappedFuncPackage)
+
+		var typeArgs []string
+		typeArgs, err = wrapper.WrappedFuncTypeArgs()
+		if err != nil {
+			return errorAt(fset, wrapper.Pos, err)
 		}
 
 		var repl strings.Builder
 		// fmt.Fprintf(&newSrc, "////////////////////////////////////////\n")
 		// fmt.Fprintf(&newSrc, "// %s\n\n", impl.WrappedFunc)
 		fmt.Fprintf(&repl, "// %s wraps %s as %s (generated code)\n", wrapper.VarName, wrapper.WrappedFunc, wrapper.Impl)
+		if wrapper.Directive != nil {
+			fmt.Fprintf(&repl, "//\n%s\n", wrapper.Directive)
+		}
 		fmt.Fprintf(&repl, "var %[1]s %[1]sT\n\n", wrapper.VarName)
-		err = wrapper.Impl.WriteFunctionWrapper(&repl, wrappedFunc.File, wrappedFunc.Decl, wrapper.VarName+"T", wrappedFuncPackage, neededImportLines, jsonTypeReplacements)
+		if genFile == nil {
+			typesCode = &repl
+		}
+		// Hooks customize the call methods per wrapper type
+		// so the call methods are not shared with hooks
+		var sharedCalls string
+		if g.Hooks == nil {
+			sharedCalls = wrapper.sharedCallsOf(callWrappers[wrapper.WrappedFunc], g.JSONOptions)
+		}
+		err = wrapper.Impl.writeWrapper(typesCode, wrappedFunc.File, wrappedFunc.Decl, typeArgs, wrapper.VarName+"T", wrappedFuncPackage, "", sharedCalls, typesImportLines, wrapper.Directive.jsonOptions(g.JSONOptions), g.Hooks)
 		if err != nil {
-			return err
+			return errorAt(fset, wrapper.Pos, err)
+		}
+		if sharedCalls == "" && wrapper.VarName != wrapper.Type {
+			// Wrappers declared only as type have no variable to share
+			callWrappers[wrapper.WrappedFunc] = append(callWrappers[wrapper.WrappedFunc], wrapper)
+		}
+		resultNames, err := wrapper.Directive.resultNames(wrappedFunc.Decl.Type)
+		if err != nil {
+			return errorAt(fset, wrapper.Pos, fmt.Errorf("%s: %w", wrapper.VarName, err))
+		}
+		if resultNames != nil {
+			fmt.Fprintf(typesCode, "func (%sT) ResultNames() []string {\n", wrapper.VarName)
+			fmt.Fprintf(typesCode, "\treturn %#v\n", resultNames)
+			fmt.Fprintf(typesCode, "}\n\n")
 		}
 
 		var implReplacements astvisit.NodeReplacements
@@ -138,6 +566,73 @@ func RewriteAstFile(fset *token.FileSet, filePkg *ast.Package, astFile *ast.File
 			}
 		}
 		replacements.Add(implReplacements)
+
+		if tests != nil {
+			tests.addCase(wrapper.VarName, wrapper.WrappedFunc)
+		}
+		wrapperReport.Status = WrapperGenerated
+	}
+
+	for _, iw := range ifaceWrappers {
+		wrapperReport := &WrapperReport{Name: iw.ConstructorName(), Wrapped: iw.Interface}
+		report.Wrappers = append(report.Wrappers, wrapperReport)
+
+		ifacePackage, ifaceName := iw.PkgAndTypeName()
+		iface, referencedPkg, err := findInterface(functions, ifacePackage, ifaceName)
+		if err != nil {
+			if len(iw.Nodes) == 1 {
+				// Only the directive, nothing generated yet
+				return errorAt(fset, iw.Pos(), err)
+			}
+			if !g.RemoveOrphans {
+				orphan := fmt.Errorf("%s: %w %s of %s: %w", fset.Position(iw.Pos()), ErrOrphanedWrapper, iw.ConstructorName(), iw.Interface, err)
+				orphans = append(orphans, orphan)
+				wrapperReport.Status, wrapperReport.Error = WrapperOrphaned, orphan.Error()
+				continue
+			}
+			if g.Verbose {
+				fmt.Println("removing orphaned wrappers", iw.ConstructorName(), "of", iw.Interface, "from", filePath)
+			}
+			for _, node := range iw.Nodes {
+				replacements.AddRemoval(node, "Orphaned wrappers for interface "+iw.Interface)
+			}
+			wrapperReport.Status = WrapperRemoved
+			continue
+		}
+		if referencedPkg.ImportLine != "" {
+			typesImportLines[referencedPkg.ImportLine] = struct{}{}
+		}
+
+		var methods []*ast.FuncDecl
+		methods, err = iw.methods(iface, referencedPkg.ImportLine != "")
+		if err != nil {
+			return errorAt(fset, iw.Pos(), err)
+		}
+		var repl strings.Builder
+		iw.writeConstructor(&repl, methods, neededImportLines)
+		if genFile == nil {
+			typesCode = &repl
+		}
+		err = iw.writeMethodWrappers(typesCode, iface, methods, typesImportLines, g.JSONOptions, g.Hooks)
+		if err != nil {
+			return errorAt(fset, iw.Pos(), err)
+		}
+
+		var implReplacements astvisit.NodeReplacements
+		debugID := "Wrappers for interface " + iw.Interface
+		for i, node := range iw.Nodes {
+			if i == 0 {
+				implReplacements.AddReplacement(node, repl.String(), debugID)
+			} else {
+				implReplacements.AddRemoval(node, debugID)
+			}
+		}
+		replacements.Add(implReplacements)
+		wrapperReport.Status = WrapperGenerated
+	}
+
+	if len(orphans) > 0 {
+		return errors.Join(orphans...)
 	}
 
 	source, err := os.ReadFile(filePath) //#nosec G304
@@ -159,18 +654,29 @@ func RewriteAstFile(fset *token.FileSet, filePkg *ast.Package, astFile *ast.File
 	if err != nil {
 		return err
 	}
+	if genFile != nil {
+		// Imports of generated types that were
+		// moved to the generated file are not used anymore
+		dotImportExports := make(map[string]map[string]struct{})
+		for key, pkg := range functions {
+			if strings.HasPrefix(key, ". ") {
+				dotImportExports[pkg.PkgPath] = pkg.ExportedNames
+			}
+		}
+		rewritten, err = removeUnusedImports(rewritten, genFile.importLines, typedFile(filePkg, astFile).ImportNames, dotImportExports)
+		if err != nil {
+			return err
+		}
+	}
 
-	if printTo != nil {
-		if verbose {
+	if g.PrintOnly != nil {
+		if g.Verbose {
 			fmt.Println(filePath, "would be rewritten as:")
 		}
-		_, err = printTo.Write(rewritten)
+		_, err = g.PrintOnly.Write(rewritten)
 		return err
 	}
-	if verbose {
-		fmt.Println("rewriting", filePath)
-	}
-	return os.WriteFile(filePath, rewritten, 0600)
+	return writeFileIfChanged(filePath, rewritten, "rewriting", g.Verbose, collector)
 }
 
 type wrapper struct {
@@ -178,18 +684,95 @@ type wrapper struct {
 	WrappedFunc string
 	Type        string
 	Nodes       []ast.Node
+	Pos         token.Pos // of the declaration referencing the wrapped function
 	Impl        Impl
+	Directive   *wrapperDirective
+	TODO        bool // declared with a WrapperTODO call, not generated yet
+}
+
+// addDirective parses and adds the WrapperDirective from doc
+// if doc has one.
+func (impl *wrapper) addDirective(doc *ast.CommentGroup) error {
+	directive, err := parseWrapperDirective(doc)
+	if err != nil || directive == nil {
+		return err
+	}
+	impl.Directive = directive
+	if directive.Impl != 0 {
+		impl.Impl = directive.Impl
+	}
+	return nil
 }
 
+// sharedCallsOf returns the variable name of the first of the wrappers
+// of the same function that implements all call methods of impl
+// with the same JSON options, so that impl can delegate to its methods,
+// or an empty string if there is no such wrapper.
+func (impl *wrapper) sharedCallsOf(wrappers []*wrapper, jsonOptions JSONOptions) string {
+	calls := impl.Impl &^ ImplDescription
+	if calls == 0 {
+		return ""
+	}
+	for _, w := range wrappers {
+		if calls&^w.Impl == 0 && reflect.DeepEqual(w.Directive.jsonOptions(jsonOptions), impl.Directive.jsonOptions(jsonOptions)) {
+			return w.VarName
+		}
+	}
+	return ""
+}
+
+// setPos sets Pos if not already set
+// by a previous declaration of the wrapper.
+func (impl *wrapper) setPos(pos token.Pos) {
+	if impl.Pos == token.NoPos {
+		impl.Pos = pos
+	}
+}
+
+// errorAt returns err prefixed with the file, line and column of pos
+// so that errors of wrapper declarations can be located.
+func errorAt(fset *token.FileSet, pos token.Pos, err error) error {
+	return fmt.Errorf("%s: %w", fset.Position(pos), err)
+}
+
+// WrappedFuncPkgAndFuncName returns the package and function name
+// of WrappedFunc without the type arguments of a generic function instantiation.
 func (impl *wrapper) WrappedFuncPkgAndFuncName() (pkgName, funcName string) {
-	dot := strings.IndexByte(impl.WrappedFunc, '.')
+	wrappedFunc, _, _ := strings.Cut(impl.WrappedFunc, "[")
+	dot := strings.IndexByte(wrappedFunc, '.')
 	if dot == -1 {
-		return "", impl.WrappedFunc
+		return "", wrappedFunc
 	}
-	return impl.WrappedFunc[:dot], impl.WrappedFunc[dot+1:]
+	return wrappedFunc[:dot], wrappedFunc[dot+1:]
 }
 
-func findFunctionWrappers(fset *token.FileSet, file *ast.File) []*wrapper {
+// WrappedFuncTypeArgs returns the type arguments
+// if WrappedFunc is a generic function instantiation.
+func (impl *wrapper) WrappedFuncTypeArgs() ([]string, error) {
+	if !strings.Contains(impl.WrappedFunc, "[") {
+		return nil, nil
+	}
+	expr, err := parser.ParseExpr(impl.WrappedFunc)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse wrapped function %s: %w", impl.WrappedFunc, err)
+	}
+	var indices []ast.Expr
+	switch e := expr.(type) {
+	case *ast.IndexExpr:
+		indices = []ast.Expr{e.Index}
+	case *ast.IndexListExpr:
+		indices = e.Indices
+	default:
+		return nil, fmt.Errorf("invalid wrapped function %s", impl.WrappedFunc)
+	}
+	typeArgs := make([]string, len(indices))
+	for i, index := range indices {
+		typeArgs[i] = astvisit.ExprString(index)
+	}
+	return typeArgs, nil
+}
+
+func findFunctionWrappers(fset *token.FileSet, file *ast.File) ([]*wrapper, error) {
 	ordered := make([]*wrapper, 0)
 	named := make(map[string]*wrapper)
 	typed := make(map[string]*wrapper)
@@ -227,6 +810,10 @@ func findFunctionWrappers(fset *token.FileSet, file *ast.File) []*wrapper {
 					impl.WrappedFunc = wrappedFunc
 					impl.Impl |= implements
 					impl.Type = astvisit.ExprString(valueSpec.Type)
+					impl.setPos(decl.Pos())
+					if err = impl.addDirective(decl.Doc); err != nil {
+						return nil, fmt.Errorf("%s: %w", fset.Position(decl.Pos()), err)
+					}
 					if decl.Doc != nil {
 						impl.Nodes = append(impl.Nodes, decl.Doc)
 					}
@@ -258,8 +845,13 @@ func findFunctionWrappers(fset *token.FileSet, file *ast.File) []*wrapper {
 					named[implVarName] = impl
 				}
 				impl.VarName = implVarName
-				impl.WrappedFunc = astvisit.ExprString(callExpr.Args[0])
+				impl.WrappedFunc = wrappedFuncString(callExpr.Args[0])
+				impl.TODO = true
+				impl.setPos(callExpr.Args[0].Pos())
 				impl.Impl |= implements
+				if err = impl.addDirective(decl.Doc); err != nil {
+					return nil, fmt.Errorf("%s: %w", fset.Position(decl.Pos()), err)
+				}
 				if decl.Doc != nil {
 					impl.Nodes = append(impl.Nodes, decl.Doc)
 				}
@@ -293,6 +885,10 @@ func findFunctionWrappers(fset *token.FileSet, file *ast.File) []*wrapper {
 				}
 				impl.WrappedFunc = wrappedFunc
 				impl.Impl |= implements
+				impl.setPos(decl.Pos())
+				if err = impl.addDirective(decl.Doc); err != nil {
+					return nil, fmt.Errorf("%s: %w", fset.Position(decl.Pos()), err)
+				}
 				if decl.Doc != nil {
 					impl.Nodes = append(impl.Nodes, decl.Doc)
 				}
@@ -315,7 +911,7 @@ func findFunctionWrappers(fset *token.FileSet, file *ast.File) []*wrapper {
 		}
 	}
 
-	return ordered
+	return ordered, nil
 }
 
 // parseImplementsComment parses a comment that indicates the wrapped function
@@ -1,6 +1,14 @@
 package gen
 
-import "testing"
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
 
 func Test_parseImplementsComment(t *testing.T) {
 	type args struct {
@@ -61,3 +69,160 @@ func Test_parseImplementsComment(t *testing.T) {
 		})
 	}
 }
+
+func TestRewriteFile_orphanedWrapper(t *testing.T) {
+	root := writeTestModule(t, map[string]string{
+		"a/a.go": `package a
+
+import "github.com/domonda/go-function"
+
+func Kept() {}
+
+func Renamed() {}
+
+var keptWrapper = function.WrapperTODO(Kept)
+
+var renamedWrapper = function.WrapperTODO(Renamed)
+`,
+	})
+	filePath := filepath.Join(root, "a", "a.go")
+	err := RewriteFile(filePath, false, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rename the wrapped function after the wrapper was generated
+	source, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source = bytes.Replace(source, []byte("func Renamed()"), []byte("func NewName()"), 1)
+	err = os.WriteFile(filePath, source, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = RewriteFile(filePath, false, io.Discard, nil, nil)
+	if !errors.Is(err, ErrOrphanedWrapper) {
+		t.Fatalf("RewriteFile() without removing orphans returned %v, want ErrOrphanedWrapper", err)
+	}
+	if !strings.Contains(err.Error(), "renamedWrapper") {
+		t.Errorf("error %q does not name the orphaned wrapper", err)
+	}
+
+	_, err = (&Generator{RemoveOrphans: true}).Generate(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err = os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(source, []byte("renamedWrapper")) {
+		t.Errorf("orphaned wrapper not removed:\n%s", source)
+	}
+	if !bytes.Contains(source, []byte("var keptWrapper keptWrapperT")) {
+		t.Errorf("wrapper of existing function removed:\n%s", source)
+	}
+}
+
+func TestRewriteFile_errorPosition(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantPos string
+	}{
+		{
+			name: "unknown function",
+			source: `package a
+
+import "github.com/domonda/go-function"
+
+func Kept() {}
+
+var missingWrapper = function.WrapperTODO(Missing)
+`,
+			wantPos: "a.go:7:43: ",
+		},
+		{
+			name: "unknown package",
+			source: `package a
+
+import "github.com/domonda/go-function"
+
+var missingWrapper = function.WrapperTODO(
+	missing.Func,
+)
+`,
+			wantPos: "a.go:6:2: ",
+		},
+		{
+			name: "unknown interface",
+			source: `package a
+
+type Service interface{ Do() }
+
+//gen:wrappers-for-interface Missing
+`,
+			wantPos: "a.go:5:1: ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := writeTestModule(t, map[string]string{"a/a.go": tt.source})
+			filePath := filepath.Join(root, "a", "a.go")
+			err := RewriteFile(filePath, false, io.Discard, nil, nil)
+			if err == nil {
+				t.Fatal("RewriteFile() did not return an error")
+			}
+			if !strings.HasPrefix(err.Error(), filepath.Join(root, "a")+string(filepath.Separator)+tt.wantPos) {
+				t.Errorf("RewriteFile() error %q does not start with position %s", err, tt.wantPos)
+			}
+		})
+	}
+}
+
+func TestRewriteFile_sharedCalls(t *testing.T) {
+	root := writeTestModule(t, map[string]string{
+		"a/a.go": `package a
+
+import "github.com/domonda/go-function"
+
+func Add(a, b int) int { return a + b }
+
+var addWrapper = function.WrapperTODO(Add)
+
+var addJSON = function.CallWithJSONWrapperTODO(Add)
+
+//gen:wrapper json=snake_case
+var addSnakeCase = function.CallWithJSONWrapperTODO(Add)
+
+var addURLValues = function.CallWithURLValuesWrapperTODO(Add)
+`,
+	})
+	filePath := filepath.Join(root, "a", "a.go")
+	err := RewriteFile(filePath, false, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated := string(source)
+	for _, want := range []string{
+		"type addJSONT struct{}",
+		"func (addJSONT) CallWithJSON(ctx context.Context, argsJSON []byte) ([]any, error) {\n\treturn addWrapper.CallWithJSON(ctx, argsJSON) // shared call\n}",
+		// Different JSON options need their own call code
+		"func (f addSnakeCaseT) CallWithJSON(_ context.Context, argsJSON []byte) (results []any, err error) {\n\tvar a struct {",
+		// function.Wrapper does not implement CallWithURLValues
+		"func (f addURLValuesT) CallWithURLValues(",
+	} {
+		if !strings.Contains(generated, want) {
+			t.Errorf("generated code does not contain:\n%s\ngenerated:\n%s", want, generated)
+		}
+	}
+	if _, err = loadPackage(filepath.Join(root, "a")); err != nil {
+		t.Errorf("invalid generated code: %s\n%s", err, generated)
+	}
+}
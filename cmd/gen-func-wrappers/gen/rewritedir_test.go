@@ -0,0 +1,162 @@
+package gen
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_packageDirs(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"a/b", "a/.hidden", "a/vendor/v", "node_modules/x", "testdata/t", "ignored/y", "c"} {
+		err := os.MkdirAll(filepath.Join(root, filepath.FromSlash(dir)), 0700)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	ignored := func(path string) bool { return filepath.Base(path) == "ignored" }
+
+	got, err := packageDirs(root, true, false, ignored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		root,
+		filepath.Join(root, "a"),
+		filepath.Join(root, "a", "b"),
+		filepath.Join(root, "c"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("packageDirs() = %#v, want %#v", got, want)
+	}
+
+	got, err = packageDirs(root, false, false, ignored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, []string{root}) {
+		t.Errorf("packageDirs() not recursive = %#v", got)
+	}
+
+	got, err = packageDirs(filepath.Join(root, "ignored"), true, false, ignored)
+	if err != nil || len(got) != 0 {
+		t.Errorf("packageDirs() of ignored dir = %#v, %v", got, err)
+	}
+}
+
+func TestRewriteDir_idempotent(t *testing.T) {
+	for _, genFile := range []bool{false, true} {
+		root := writeTestModule(t, map[string]string{
+			"a/a.go": `package a
+
+import (
+	"context"
+
+	"github.com/domonda/go-function"
+)
+
+// Greet greets
+//   name: the name
+func Greet(ctx context.Context, name string, times int) (string, error) { return name, nil }
+
+var greetWrapper = function.WrapperTODO(Greet)
+`,
+		})
+		pkgDir := filepath.Join(root, "a")
+		readFiles := func() map[string][]byte {
+			t.Helper()
+			entries, err := os.ReadDir(pkgDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			files := make(map[string][]byte)
+			for _, entry := range entries {
+				data, err := os.ReadFile(filepath.Join(pkgDir, entry.Name()))
+				if err != nil {
+					t.Fatal(err)
+				}
+				files[entry.Name()] = data
+			}
+			return files
+		}
+
+		generator := &Generator{GenFile: genFile}
+		_, err := generator.Generate(pkgDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		first := readFiles()
+		_, err = generator.Generate(pkgDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		second := readFiles()
+		if !reflect.DeepEqual(first, second) {
+			t.Errorf("genFile=%t: second run changed the files:\n%s\nvs.\n%s", genFile, first, second)
+		}
+
+		generated := first["a.go"]
+		if genFile {
+			generated = first[GeneratedFilename]
+		}
+		if !bytes.Contains(generated, []byte(`"the name"`)) {
+			t.Errorf("genFile=%t: argument description of gofmt formatted doc comment is missing:\n%s", genFile, generated)
+		}
+	}
+}
+
+func TestRewriteDir_testFiles(t *testing.T) {
+	root := writeTestModule(t, map[string]string{
+		"a/a.go": `package a
+
+func Greet(name string) string { return name }
+`,
+		"a/a_test.go": `package a
+
+import "github.com/domonda/go-function"
+
+func double(x int) int { return x * 2 }
+
+var doubleWrapper = function.WrapperTODO(double)
+`,
+		"a/ext_test.go": `package a_test
+
+import (
+	"github.com/domonda/go-function"
+
+	"example.com/testmod/a"
+)
+
+var greetWrapper = function.WrapperTODO(a.Greet)
+`,
+	})
+	pkgDir := filepath.Join(root, "a")
+	_, err := (&Generator{GenFile: true, GenTests: true}).Generate(pkgDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for fileName, want := range map[string]string{
+		"a_test.go":   "var doubleWrapper doubleWrapperT\n",
+		"ext_test.go": "var greetWrapper greetWrapperT\n",
+	} {
+		data, err := os.ReadFile(filepath.Join(pkgDir, fileName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(data, []byte(want)) || !bytes.Contains(data, []byte("func (")) {
+			t.Errorf("wrapper not generated in place in %s:\n%s", fileName, data)
+		}
+	}
+	_, err = os.Stat(filepath.Join(pkgDir, GeneratedFilename))
+	if !os.IsNotExist(err) {
+		t.Errorf("wrappers of test files must not be written to %s", GeneratedFilename)
+	}
+
+	// Rewriting a single test file
+	err = RewriteFile(filepath.Join(pkgDir, "a_test.go"), false, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
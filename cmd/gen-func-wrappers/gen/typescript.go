@@ -0,0 +1,235 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// TypeScriptFilename is the name of the TypeScript client file
+// written per package by WriteTypeScript.
+const TypeScriptFilename = "wrappers.ts"
+
+// WriteTypeScript writes TypeScript client definitions to the file TypeScriptFilename
+// of the package in path, or recursively of all packages in sub-directories
+// if path ends with "...".
+// For every operation of the OpenAPI specification written by WriteOpenAPI
+// an interface type for the arguments, a type for the result
+// and a fetch based client function are written.
+// The client functions POST the JSON encoded arguments
+// to the path of the operation under a base URL
+// like expected by the CallWithJSON calling convention
+// and return the decoded JSON response.
+// Named struct types of the specification components
+// are written as interface types.
+// Packages without wrappers are skipped.
+// Files and directories for which the optional ignored function
+// returns true are skipped.
+func WriteTypeScript(path string, verbose bool, printTo io.Writer, jsonOptions JSONOptions, ignored func(path string) bool) error {
+	if ignored == nil {
+		ignored = func(string) bool { return false }
+	}
+	return writePackageFiles(path, TypeScriptFilename, verbose, printTo, ignored, func(cache *packageCache, pkg *packages.Package, pkgDir string) ([]byte, error) {
+		spec, err := packageOpenAPI(cache, pkg, pkgDir, jsonOptions, ignored)
+		if err != nil || len(spec.Paths) == 0 {
+			return nil, err
+		}
+		return typeScriptSource(spec), nil
+	})
+}
+
+// typeScriptSource returns the TypeScript client source
+// for the operations and components of spec.
+func typeScriptSource(spec *openAPISpec) []byte {
+	var b bytes.Buffer
+	b.WriteString(generatedFileHeader)
+	b.WriteString("\n")
+	if spec.Info.Description != "" {
+		for _, line := range strings.Split(spec.Info.Description, "\n") {
+			fmt.Fprintf(&b, "%s\n", strings.TrimRight("// "+line, " "))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(typeScriptCallWrapper)
+
+	componentNames := make([]string, 0)
+	if spec.Components != nil {
+		for name := range spec.Components.Schemas {
+			componentNames = append(componentNames, name)
+		}
+	}
+	sort.Strings(componentNames)
+	for _, name := range componentNames {
+		schema := spec.Components.Schemas[name]
+		fmt.Fprintf(&b, "\nexport interface %s %s\n", typeScriptComponentName(name), typeScriptType(schema, ""))
+	}
+
+	paths := make([]string, 0, len(spec.Paths))
+	for path := range spec.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		operation := spec.Paths[path].Post
+		name := typeScriptComponentName(operation.OperationID)
+
+		argsType := ""
+		if operation.RequestBody != nil {
+			argsType = name + "Args"
+			schema := operation.RequestBody.Content["application/json"].Schema
+			fmt.Fprintf(&b, "\nexport interface %s %s\n", argsType, typeScriptType(schema, ""))
+		}
+		resultType := "void"
+		if response := operation.Responses["200"]; response != nil && response.Content != nil {
+			resultType = name + "Result"
+			schema := response.Content["application/json"].Schema
+			fmt.Fprintf(&b, "\nexport type %s = %s;\n", resultType, typeScriptType(schema, ""))
+		}
+
+		b.WriteString("\n")
+		doc := operation.Summary
+		if operation.Description != "" {
+			doc += "\n\n" + operation.Description
+		}
+		if doc != "" {
+			writeTypeScriptDoc(&b, "", doc)
+		}
+		funcName := strings.ToLower(name[:1]) + name[1:]
+		if argsType != "" {
+			fmt.Fprintf(&b, "export function %s(baseURL: string, args: %s, init?: RequestInit): Promise<%s> {\n", funcName, argsType, resultType)
+			fmt.Fprintf(&b, "  return callWrapper(baseURL, %q, args, init);\n", path)
+		} else {
+			fmt.Fprintf(&b, "export function %s(baseURL: string, init?: RequestInit): Promise<%s> {\n", funcName, resultType)
+			fmt.Fprintf(&b, "  return callWrapper(baseURL, %q, {}, init);\n", path)
+		}
+		b.WriteString("}\n")
+	}
+	return b.Bytes()
+}
+
+// typeScriptCallWrapper is the helper function
+// called by the generated client functions.
+const typeScriptCallWrapper = `async function callWrapper<T>(baseURL: string, path: string, args: object, init?: RequestInit): Promise<T> {
+  const headers = new Headers(init?.headers);
+  headers.set("Content-Type", "application/json");
+  const response = await fetch(baseURL + path, {
+    ...init,
+    method: "POST",
+    headers,
+    body: JSON.stringify(args),
+  });
+  if (!response.ok) {
+    throw new Error(` + "`${path}: ${response.status} ${await response.text()}`" + `);
+  }
+  const text = await response.text();
+  return (text ? JSON.parse(text) : undefined) as T;
+}
+`
+
+// typeScriptType returns the TypeScript type for schema
+// with multi-line object types indented by indent.
+func typeScriptType(schema *jsonSchema, indent string) string {
+	if schema.Ref != "" {
+		return typeScriptComponentName(strings.TrimPrefix(schema.Ref, "#/components/schemas/"))
+	}
+	switch schema.Type {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		if schema.PrefixItems != nil {
+			items := make([]string, len(schema.PrefixItems))
+			for i, item := range schema.PrefixItems {
+				items[i] = typeScriptType(item, indent)
+			}
+			return "[" + strings.Join(items, ", ") + "]"
+		}
+		return typeScriptType(schema.Items, indent) + "[]"
+	case "object":
+		if schema.AdditionalProperties != nil {
+			return "Record<string, " + typeScriptType(schema.AdditionalProperties, indent) + ">"
+		}
+		names := make([]string, 0, len(schema.Properties))
+		for name := range schema.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return "{}"
+		}
+		var b bytes.Buffer
+		b.WriteString("{\n")
+		for _, name := range names {
+			property := schema.Properties[name]
+			if property.Description != "" {
+				writeTypeScriptDoc(&b, indent+"  ", property.Description)
+			}
+			if !typeScriptIdentifierRegexp.MatchString(name) {
+				name = fmt.Sprintf("%q", name)
+			}
+			fmt.Fprintf(&b, "%s  %s: %s;\n", indent, name, typeScriptType(property, indent+"  "))
+		}
+		b.WriteString(indent + "}")
+		return b.String()
+	}
+	// Unknown JSON encoding
+	return "unknown"
+}
+
+var typeScriptIdentifierRegexp = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// typeScriptIdentifier returns name with all characters
+// that are invalid in TypeScript identifiers removed
+// and the following character in upper case,
+// so "Service.Get" becomes "ServiceGet".
+func typeScriptIdentifier(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range name {
+		switch {
+		case r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r) && b.Len() > 0:
+			if upper {
+				r = unicode.ToUpper(r)
+				upper = false
+			}
+			b.WriteRune(r)
+		default:
+			upper = b.Len() > 0
+		}
+	}
+	return b.String()
+}
+
+// typeScriptComponentName returns the TypeScript type name
+// for a component schema or operation name,
+// so "mypkg.User" becomes "MypkgUser".
+func typeScriptComponentName(name string) string {
+	name = typeScriptIdentifier(name)
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(r)) + name[size:]
+}
+
+// writeTypeScriptDoc writes text as JSDoc comment
+// with every line indented by indent.
+func writeTypeScriptDoc(w *bytes.Buffer, indent, text string) {
+	lines := strings.Split(strings.ReplaceAll(text, "*/", `*\/`), "\n")
+	if len(lines) == 1 {
+		fmt.Fprintf(w, "%s/** %s */\n", indent, lines[0])
+		return
+	}
+	fmt.Fprintf(w, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(w, "%s *%s\n", indent, strings.TrimRight(" "+line, " "))
+	}
+	fmt.Fprintf(w, "%s */\n", indent)
+}
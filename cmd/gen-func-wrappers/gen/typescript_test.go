@@ -0,0 +1,86 @@
+package gen
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_typeScriptSource(t *testing.T) {
+	root := writeTestModule(t, map[string]string{
+		"a/a.go": `package a
+
+import (
+	"context"
+	"time"
+
+	"github.com/domonda/go-function"
+)
+
+type User struct {
+	ID      int64 ` + "`json:\"id\"`" + `
+	Name    string
+	Created time.Time
+	Tags    map[string]bool
+}
+
+// GetUser returns a user
+//
+// Arguments:
+//   - id: the user ID
+func GetUser(ctx context.Context, id int64) (*User, error) { return nil, nil }
+
+func Split(s string) (string, []uint8) { return s, nil }
+
+func Reset() error { return nil }
+
+var getUserWrapper = function.WrapperTODO(GetUser)
+
+var splitWrapper = function.WrapperTODO(Split)
+
+var resetWrapper = function.WrapperTODO(Reset)
+`,
+	})
+	pkgDir := filepath.Join(root, "a")
+	pkg, err := loadPackage(pkgDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec, err := packageOpenAPI(newPackageCache(), pkg, pkgDir, JSONOptions{}, func(string) bool { return false })
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(typeScriptSource(spec))
+	for _, want := range []string{
+		"export interface User {\n  Created: string;\n  Name: string;\n  Tags: Record<string, boolean>;\n  id: number;\n}\n",
+		"export interface GetUserArgs {\n  /** the user ID */\n  id: number;\n}\n",
+		"export type GetUserResult = User;\n",
+		"/**\n * GetUser returns a user\n *\n * Arguments:\n *   - id: the user ID\n */\nexport function getUser(baseURL: string, args: GetUserArgs, init?: RequestInit): Promise<GetUserResult> {\n  return callWrapper(baseURL, \"/GetUser\", args, init);\n}\n",
+		"export type SplitResult = [string, string];\n",
+		"export function reset(baseURL: string, init?: RequestInit): Promise<void> {\n  return callWrapper(baseURL, \"/Reset\", {}, init);\n}\n",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("TypeScript source does not contain:\n%s\nsource:\n%s", want, src)
+		}
+	}
+}
+
+func Test_typeScriptIdentifier(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "GetUser", want: "GetUser"},
+		{name: "Service.Get", want: "ServiceGet"},
+		{name: "mypkg.User", want: "mypkgUser"},
+		{name: "List_int_", want: "List_int_"},
+		{name: "9lives", want: "lives"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := typeScriptIdentifier(tt.name); got != tt.want {
+				t.Errorf("typeScriptIdentifier(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package gen
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchDebounce is the duration to wait after a file change
+// for further changes before regenerating the wrappers,
+// so that saving multiple files at once triggers only one run.
+var WatchDebounce = 200 * time.Millisecond
+
+// Watch watches the Go files in the directory path,
+// or recursively in all its sub-directories if path ends with "...",
+// and regenerates the wrappers of the packages with changed files
+// like Generate until ctx is canceled.
+//
+// Only the package directory of a changed file is rewritten,
+// wrappers in other packages referencing functions
+// of the changed package are not updated.
+// Errors of a rewrite are printed to os.Stderr or g.WatchOutput
+// and don't stop watching because they are expected
+// while files are being edited.
+func (g *Generator) Watch(ctx context.Context, path string) error {
+	recursive := strings.HasSuffix(path, "...")
+	path = filepath.Clean(strings.TrimSuffix(path, "..."))
+	ignored := g.Ignored
+	if ignored == nil {
+		ignored = func(string) bool { return false }
+	}
+	var stdout, stderr io.Writer = os.Stdout, os.Stderr
+	if g.WatchOutput != nil {
+		stdout, stderr = g.WatchOutput, g.WatchOutput
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	addDir := func(dir string) error {
+		dirs, err := packageDirs(dir, recursive, false, ignored)
+		if err != nil {
+			return err
+		}
+		for _, d := range dirs {
+			if g.Verbose {
+				fmt.Fprintln(stdout, "watching", d)
+			}
+			err = watcher.Add(d)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	err = addDir(path)
+	if err != nil {
+		return err
+	}
+
+	var (
+		changedDirs = make(map[string]struct{})
+		debounce    = time.NewTimer(WatchDebounce)
+	)
+	debounce.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case err := <-watcher.Errors:
+			fmt.Fprintln(stderr, "gen-func-wrappers watch error:", err)
+
+		case event := <-watcher.Events:
+			if event.Has(fsnotify.Create) && recursive {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					err = addDir(event.Name)
+					if err != nil {
+						fmt.Fprintln(stderr, "gen-func-wrappers watch error:", err)
+					}
+					continue
+				}
+			}
+			if !isWatchedGoFile(event.Name) || ignored(event.Name) || event.Op == fsnotify.Chmod {
+				continue
+			}
+			changedDirs[filepath.Dir(event.Name)] = struct{}{}
+			debounce.Reset(WatchDebounce)
+
+		case <-debounce.C:
+			dirs := make([]string, 0, len(changedDirs))
+			for dir := range changedDirs {
+				dirs = append(dirs, dir)
+			}
+			clear(changedDirs)
+			sort.Strings(dirs)
+			for _, dir := range dirs {
+				if g.Verbose {
+					fmt.Fprintln(stdout, "regenerating", dir)
+				}
+				err := g.rewriteDir(dir, nil)
+				if err != nil {
+					fmt.Fprintln(stderr, "gen-func-wrappers error:", err)
+				}
+			}
+		}
+	}
+}
+
+// isWatchedGoFile returns true for Go source files
+// including tests that are not completely generated files.
+func isWatchedGoFile(filePath string) bool {
+	return strings.HasSuffix(filePath, ".go") &&
+		!isGeneratedFile(filePath)
+}
@@ -0,0 +1,145 @@
+package gen
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use
+type syncBuffer struct {
+	mtx sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	return b.buf.String()
+}
+
+// waitFor calls cond until it returns true
+// or fails the test after a timeout.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// fileContains returns a condition for waitFor
+// that the file at filePath contains substr.
+func fileContains(filePath, substr string) func() bool {
+	return func() bool {
+		data, err := os.ReadFile(filePath)
+		return err == nil && strings.Contains(string(data), substr)
+	}
+}
+
+func TestGenerator_Watch(t *testing.T) {
+	root := writeTestModule(t, map[string]string{
+		"a/a.go": `package a
+
+import "github.com/domonda/go-function"
+
+func Add(a, b int) int { return a + b }
+
+var addWrapper = function.WrapperTODO(Add)
+`,
+		"a/ignored.go": `package a
+
+import "github.com/domonda/go-function"
+
+func Sub(a, b int) int { return a - b }
+
+var subWrapper = function.WrapperTODO(Sub)
+`,
+	})
+	var (
+		output  syncBuffer
+		aDir    = filepath.Join(root, "a")
+		bDir    = filepath.Join(root, "b")
+		aFile   = filepath.Join(aDir, "a.go")
+		ignFile = filepath.Join(aDir, "ignored.go")
+		g       = &Generator{
+			Ignored:     func(path string) bool { return filepath.Base(path) == "ignored.go" },
+			Verbose:     true,
+			WatchOutput: &output,
+		}
+		ctx, cancel = context.WithCancel(context.Background())
+		watchErr    = make(chan error)
+	)
+	defer cancel()
+	go func() { watchErr <- g.Watch(ctx, root+"/...") }()
+	waitFor(t, "watching "+aDir, func() bool { return strings.Contains(output.String(), "watching "+aDir+"\n") })
+
+	// Editing a file regenerates its package
+	source, err := os.ReadFile(aFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source = append(source, "\nfunc Mul(a, b int) int { return a * b }\n\nvar mulWrapper = function.WrapperTODO(Mul)\n"...)
+	err = os.WriteFile(aFile, source, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "regenerated "+aFile, fileContains(aFile, "var mulWrapper mulWrapperT\n"))
+	if !strings.Contains(output.String(), "regenerating "+aDir+"\n") {
+		t.Errorf("no regenerating message in output:\n%s", output.String())
+	}
+	if data, _ := os.ReadFile(aFile); !bytes.Contains(data, []byte("var addWrapper addWrapperT\n")) {
+		t.Errorf("addWrapper not regenerated:\n%s", data)
+	}
+	if data, _ := os.ReadFile(ignFile); !bytes.Contains(data, []byte("function.WrapperTODO(Sub)")) {
+		t.Errorf("ignored file was rewritten:\n%s", data)
+	}
+
+	// New directories are watched recursively
+	err = os.Mkdir(bDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "watching "+bDir, func() bool { return strings.Contains(output.String(), "watching "+bDir+"\n") })
+	bFile := filepath.Join(bDir, "b.go")
+	err = os.WriteFile(bFile, []byte("package b\n\nimport \"github.com/domonda/go-function\"\n\nfunc Neg(a int) int { return -a }\n\nvar negWrapper = function.WrapperTODO(Neg)\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "regenerated "+bFile, fileContains(bFile, "var negWrapper negWrapperT\n"))
+
+	cancel()
+	if err := <-watchErr; err != nil {
+		t.Errorf("Watch() returned error: %v", err)
+	}
+}
+
+func Test_isWatchedGoFile(t *testing.T) {
+	for filePath, want := range map[string]bool{
+		"a/a.go":                     true,
+		"a/a_test.go":                true,
+		"a/" + GeneratedFilename:     false,
+		"a/" + ExportedFuncsFilename: false,
+		"a/a.txt":                    false,
+	} {
+		if got := isWatchedGoFile(filePath); got != want {
+			t.Errorf("isWatchedGoFile(%q) = %v, want %v", filePath, got, want)
+		}
+	}
+}
@@ -1,15 +1,20 @@
 module github.com/domonda/go-function/cmd/gen-func-wrappers
 
-go 1.23
+// Go 1.25 is required by golang.org/x/tools v0.44.0,
+// earlier versions can't read the export data of current Go toolchains
+go 1.25.0
 
 require (
+	github.com/fsnotify/fsnotify v1.8.0
 	github.com/ungerik/go-astvisit v0.0.0-20231019122241-2d1ef5bbb4cf
-	golang.org/x/tools v0.27.0
+	golang.org/x/mod v0.35.0
+	golang.org/x/tools v0.44.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-	golang.org/x/mod v0.22.0 // indirect
-	golang.org/x/sync v0.9.0 // indirect
+	golang.org/x/sync v0.20.0 // indirect
+	golang.org/x/sys v0.43.0 // indirect
 )
 
 // replace github.com/ungerik/go-astvisit => ../../../../ungerik/go-astvisit
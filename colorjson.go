@@ -0,0 +1,101 @@
+package function
+
+import (
+	"io"
+	"os"
+)
+
+// ANSI escape sequences used to highlight JSON
+const (
+	colorJSONKey     = "\033[34;1m" // bold blue
+	colorJSONString  = "\033[32m"   // green
+	colorJSONNumber  = "\033[36m"   // cyan
+	colorJSONLiteral = "\033[35m"   // magenta for true, false, null
+	colorJSONReset   = "\033[0m"
+)
+
+// isColorTerminal returns true if w is a file connected to a terminal
+// and the NO_COLOR environment variable is not set.
+func isColorTerminal(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorizeJSON returns the valid JSON data
+// with ANSI escape sequences highlighting
+// object keys, strings, numbers and literals.
+func colorizeJSON(data []byte) []byte {
+	colored := make([]byte, 0, len(data)*2)
+	for i := 0; i < len(data); {
+		switch c := data[i]; {
+		case c == '"':
+			end := i + 1
+			for end < len(data) && data[end] != '"' {
+				if data[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(data))
+			color := colorJSONString
+			if isJSONObjectKey(data[end:]) {
+				color = colorJSONKey
+			}
+			colored = appendColored(colored, color, data[i:end])
+			i = end
+
+		case c == '-' || c >= '0' && c <= '9':
+			end := i + 1
+			for end < len(data) && isJSONNumberByte(data[end]) {
+				end++
+			}
+			colored = appendColored(colored, colorJSONNumber, data[i:end])
+			i = end
+
+		case c >= 'a' && c <= 'z':
+			end := i + 1
+			for end < len(data) && data[end] >= 'a' && data[end] <= 'z' {
+				end++
+			}
+			colored = appendColored(colored, colorJSONLiteral, data[i:end])
+			i = end
+
+		default:
+			colored = append(colored, c)
+			i++
+		}
+	}
+	return colored
+}
+
+// isJSONObjectKey returns true if the JSON after a string
+// starts with a colon after optional whitespace.
+func isJSONObjectKey(after []byte) bool {
+	for _, c := range after {
+		switch c {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case ':':
+			return true
+		}
+		return false
+	}
+	return false
+}
+
+func isJSONNumberByte(c byte) bool {
+	return c >= '0' && c <= '9' || c == '.' || c == 'e' || c == 'E' || c == '+' || c == '-'
+}
+
+func appendColored(dst []byte, color string, token []byte) []byte {
+	dst = append(dst, color...)
+	dst = append(dst, token...)
+	return append(dst, colorJSONReset...)
+}
@@ -0,0 +1,37 @@
+package function
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPrintColorJSONTo(t *testing.T) {
+	results := []any{map[string]any{"name": "a \"b\"", "count": -1.5e3, "ok": true, "none": nil}, []int{1}}
+
+	var b strings.Builder
+	err := PrintColorJSONTo(&b).HandleResults(context.Background(), results, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "{\n  \"count\": -1500,\n  \"name\": \"a \\\"b\\\"\",\n  \"none\": null,\n  \"ok\": true\n}\n[\n  1\n]\n"
+	if b.String() != want {
+		t.Errorf("PrintColorJSONTo() printed:\n%s\nwant plain JSON:\n%s", b.String(), want)
+	}
+
+	colored := string(colorizeJSON([]byte(`{"key": "value", "list": [-1.5e3, true, null], "s:": "x"}`)))
+	wantColored := `{` +
+		colorJSONKey + `"key"` + colorJSONReset + `: ` + colorJSONString + `"value"` + colorJSONReset + `, ` +
+		colorJSONKey + `"list"` + colorJSONReset + `: [` + colorJSONNumber + `-1.5e3` + colorJSONReset + `, ` +
+		colorJSONLiteral + `true` + colorJSONReset + `, ` + colorJSONLiteral + `null` + colorJSONReset + `], ` +
+		colorJSONKey + `"s:"` + colorJSONReset + `: ` + colorJSONString + `"x"` + colorJSONReset + `}`
+	if colored != wantColored {
+		t.Errorf("colorizeJSON() = %q, want %q", colored, wantColored)
+	}
+
+	err = PrintColorJSONTo(&b).HandleResults(context.Background(), nil, errors.New("failed"))
+	if err == nil || err.Error() != "failed" {
+		t.Errorf("expected result error, got %v", err)
+	}
+}
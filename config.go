@@ -17,6 +17,11 @@ var (
 	ArgNameTag        = "arg"
 	ArgDescriptionTag = "desc"
 
+	// TableColumnTag is the struct tag used by PrintTable
+	// for the column names of struct fields,
+	// fields tagged with "-" are not printed.
+	TableColumnTag = "table"
+
 	// TimeFormats used in that order to try parse time strings.
 	// If a time format has not time zone part,
 	// then the date is returned in the local time zone.
@@ -22,6 +22,30 @@ type Description interface {
 	ResultTypes() []reflect.Type
 }
 
+// ResultNamer is implemented by wrappers that name
+// the non-error results of the wrapped function.
+type ResultNamer interface {
+	// ResultNames returns the names of the results
+	// of the wrapped function without a last error result.
+	ResultNames() []string
+}
+
+// resultNames returns the ResultNames of wrapper if it implements
+// ResultNamer with numResults names, else the names
+// "result0", "result1", and so on are returned.
+func resultNames(wrapper any, numResults int) []string {
+	if namer, ok := wrapper.(ResultNamer); ok {
+		if names := namer.ResultNames(); len(names) == numResults {
+			return names
+		}
+	}
+	names := make([]string, numResults)
+	for i := range names {
+		names[i] = "result" + strconv.Itoa(i)
+	}
+	return names
+}
+
 func ReflectDescription(name string, f any) (Description, error) {
 	t := reflect.ValueOf(f).Type()
 	if t.Kind() != reflect.Func {
@@ -0,0 +1,74 @@
+package function
+
+import (
+	"context"
+	"reflect"
+)
+
+type dryRunCtxKey struct{}
+
+// ContextWithDryRun returns a context that marks
+// function calls with it as dry run.
+// Functions supporting dry runs check IsDryRun
+// and don't make any changes.
+func ContextWithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunCtxKey{}, true)
+}
+
+// IsDryRun returns true if ctx was returned by ContextWithDryRun
+// or is derived from such a context.
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunCtxKey{}).(bool)
+	return dryRun
+}
+
+// ValidateStrings checks if strs can be converted to the
+// arguments of f like by CallWithStrings without calling f.
+// An ErrParseArgString is returned for the first invalid argument.
+func ValidateStrings(f Description, strs ...string) error {
+	argNames, argTypes := nonContextArgs(f)
+	for i, str := range strs[:min(len(strs), len(argTypes))] {
+		err := validateString(f, argNames[i], argTypes[i], str)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateNamedStrings checks if strs can be converted to the
+// arguments of f like by CallWithNamedStrings without calling f.
+// An ErrParseArgString is returned for the first invalid argument.
+func ValidateNamedStrings(f Description, strs map[string]string) error {
+	argNames, argTypes := nonContextArgs(f)
+	for i, argName := range argNames {
+		str, ok := strs[argName]
+		if !ok {
+			continue
+		}
+		err := validateString(f, argName, argTypes[i], str)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func nonContextArgs(f Description) (argNames []string, argTypes []reflect.Type) {
+	argNames, argTypes = f.ArgNames(), f.ArgTypes()
+	if f.ContextArg() {
+		return argNames[1:], argTypes[1:]
+	}
+	return argNames, argTypes
+}
+
+func validateString(f Description, argName string, argType reflect.Type, str string) error {
+	if argType == typeOfAny {
+		return nil
+	}
+	err := ScanString(str, reflect.New(argType).Interface())
+	if err != nil {
+		return NewErrParseArgString(err, f, argName)
+	}
+	return nil
+}
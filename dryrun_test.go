@@ -0,0 +1,41 @@
+package function
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestIsDryRun(t *testing.T) {
+	ctx := context.Background()
+	if IsDryRun(ctx) {
+		t.Error("background context is dry run")
+	}
+	if !IsDryRun(ContextWithDryRun(ctx)) {
+		t.Error("context is not dry run")
+	}
+}
+
+func TestValidateStrings(t *testing.T) {
+	called := false
+	f := MustReflectWrapper(
+		func(ctx context.Context, count int, name string) { called = true },
+		"ctx", "count", "name",
+	)
+	if err := ValidateStrings(f, "3", "x", "ignored"); err != nil {
+		t.Errorf("ValidateStrings() error = %v", err)
+	}
+	var parseErr ErrParseArgString
+	if err := ValidateStrings(f, "three"); !errors.As(err, &parseErr) || parseErr.Arg != "count" {
+		t.Errorf("ValidateStrings() error = %v, want ErrParseArgString for count", err)
+	}
+	if err := ValidateNamedStrings(f, map[string]string{"name": "x"}); err != nil {
+		t.Errorf("ValidateNamedStrings() error = %v", err)
+	}
+	if err := ValidateNamedStrings(f, map[string]string{"count": "three"}); err == nil {
+		t.Error("ValidateNamedStrings() expected error")
+	}
+	if called {
+		t.Error("function was called")
+	}
+}
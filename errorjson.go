@@ -0,0 +1,126 @@
+package function
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Error codes of ErrorEnvelope
+const (
+	ErrorCodeParseArgString   = "parse_arg_string"
+	ErrorCodeParseArgJSON     = "parse_arg_json"
+	ErrorCodeParseArgsJSON    = "parse_args_json"
+	ErrorCodeUnknownArgs      = "unknown_args"
+	ErrorCodeTypeNotSupported = "type_not_supported"
+	ErrorCodeCanceled         = "canceled"
+	ErrorCodeDeadlineExceeded = "deadline_exceeded"
+	ErrorCodeError            = "error"
+)
+
+// ErrorEnvelope is the machine-parseable JSON object
+// describing an error written by PrintErrorJSONTo.
+type ErrorEnvelope struct {
+	// Error message
+	Error string `json:"error"`
+	// Code of the error, one of the ErrorCode constants
+	// or the result of the ErrorCode method of the error
+	Code string `json:"code"`
+	// Func is the name of the function of an argument error
+	Func string `json:"func,omitempty"`
+	// Arg is the name of the argument that could not be parsed
+	Arg string `json:"arg,omitempty"`
+	// Args are the unknown argument names of ErrUnknownArgs
+	Args []string `json:"args,omitempty"`
+}
+
+// NewErrorEnvelope returns an ErrorEnvelope for err
+// using the metadata of the typed errors of this package
+// found in the chain of err.
+// An error in the chain with an ErrorCode() string method
+// sets the code of the envelope.
+func NewErrorEnvelope(err error) *ErrorEnvelope {
+	envelope := &ErrorEnvelope{Error: err.Error(), Code: ErrorCodeError}
+	var (
+		coded        interface{ ErrorCode() string }
+		argString    ErrParseArgString
+		argJSON      ErrParseArgJSON
+		argsJSON     ErrParseArgsJSON
+		unknownArgs  ErrUnknownArgs
+		isCodedError = errors.As(err, &coded)
+	)
+	switch {
+	case errors.As(err, &argString):
+		envelope.Code = ErrorCodeParseArgString
+		envelope.Func = funcName(argString.Func)
+		envelope.Arg = argString.Arg
+	case errors.As(err, &argJSON):
+		envelope.Code = ErrorCodeParseArgJSON
+		envelope.Func = funcName(argJSON.Func)
+		envelope.Arg = argJSON.Arg
+	case errors.As(err, &argsJSON):
+		envelope.Code = ErrorCodeParseArgsJSON
+		envelope.Func = funcName(argsJSON.Func)
+	case errors.As(err, &unknownArgs):
+		envelope.Code = ErrorCodeUnknownArgs
+		envelope.Func = funcName(unknownArgs.Func)
+		envelope.Args = unknownArgs.Args
+	case errors.Is(err, ErrTypeNotSupported):
+		envelope.Code = ErrorCodeTypeNotSupported
+	case errors.Is(err, context.Canceled):
+		envelope.Code = ErrorCodeCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		envelope.Code = ErrorCodeDeadlineExceeded
+	}
+	if isCodedError {
+		envelope.Code = coded.ErrorCode()
+	}
+	return envelope
+}
+
+// funcName returns the Name of f if it has one,
+// else its String representation
+// or an empty string.
+func funcName(f any) string {
+	switch f := f.(type) {
+	case interface{ Name() string }:
+		return f.Name()
+	case fmt.Stringer:
+		return f.String()
+	default:
+		return ""
+	}
+}
+
+// PrintErrorJSONTo returns a ResultsHandler that writes
+// the ErrorEnvelope of a result error as a single line JSON object
+// to writer so that scripts can parse the error.
+// The result error is passed on so the command still fails,
+// results without an error are ignored.
+// Combine it with other handlers using ChainResultsHandlers.
+func PrintErrorJSONTo(writer io.Writer) ResultsHandlerFunc {
+	return func(ctx context.Context, results []any, resultErr error) error {
+		if resultErr == nil {
+			return nil
+		}
+		data, err := json.Marshal(NewErrorEnvelope(resultErr))
+		if err != nil {
+			return err
+		}
+		_, err = writer.Write(append(data, '\n'))
+		if err != nil {
+			return err
+		}
+		return resultErr
+	}
+}
+
+// PrintErrorJSON writes the ErrorEnvelope of a result error
+// as JSON object to os.Stderr.
+// See PrintErrorJSONTo.
+var PrintErrorJSON ResultsHandlerFunc = func(ctx context.Context, results []any, resultErr error) error {
+	return PrintErrorJSONTo(os.Stderr)(ctx, results, resultErr)
+}
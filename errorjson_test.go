@@ -0,0 +1,68 @@
+package function
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type codedTestError string
+
+func (e codedTestError) Error() string { return string(e) }
+
+func (e codedTestError) ErrorCode() string { return "coded" }
+
+func TestPrintErrorJSONTo(t *testing.T) {
+	f := MustReflectWrapper(func(a, b int) int { return a + b }, "a", "b").WithName("Sum")
+	tests := []struct {
+		name      string
+		resultErr error
+		want      string
+	}{
+		{name: "no error", resultErr: nil, want: ``},
+		{
+			name:      "error",
+			resultErr: errors.New("failed"),
+			want:      `{"error":"failed","code":"error"}` + "\n",
+		},
+		{
+			name:      "parse arg string",
+			resultErr: NewErrParseArgString(errors.New("invalid"), f, "b"),
+			want:      `{"error":"string conversion error for argument b of function Sum(a int, b int) int: invalid","code":"parse_arg_string","func":"Sum","arg":"b"}` + "\n",
+		},
+		{
+			name:      "wrapped unknown args",
+			resultErr: fmt.Errorf("call failed: %w", NewErrUnknownArgs(f, []string{"c", "d"})),
+			want:      `{"error":"call failed: ` + NewErrUnknownArgs(f, []string{"c", "d"}).Error() + `","code":"unknown_args","func":"Sum","args":["c","d"]}` + "\n",
+		},
+		{
+			name:      "type not supported",
+			resultErr: fmt.Errorf("%w: chan int", ErrTypeNotSupported),
+			want:      `{"error":"type not supported: chan int","code":"type_not_supported"}` + "\n",
+		},
+		{
+			name:      "canceled",
+			resultErr: context.Canceled,
+			want:      `{"error":"context canceled","code":"canceled"}` + "\n",
+		},
+		{
+			name:      "error code method",
+			resultErr: fmt.Errorf("wrapped: %w", codedTestError("custom")),
+			want:      `{"error":"wrapped: custom","code":"coded"}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			err := PrintErrorJSONTo(&b).HandleResults(context.Background(), []any{1}, tt.resultErr)
+			if !isSameError(err, tt.resultErr) {
+				t.Errorf("PrintErrorJSONTo() error = %v, want %v", err, tt.resultErr)
+			}
+			if b.String() != tt.want {
+				t.Errorf("PrintErrorJSONTo() wrote\n%s\nwant\n%s", b.String(), tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/ungerik/go-httpx/httperr"
 )
@@ -39,6 +40,11 @@ func (e ErrParseArgString) Unwrap() error {
 	return e.Err
 }
 
+// ServeHTTP responds with 400 Bad Request.
+func (e ErrParseArgString) ServeHTTP(response http.ResponseWriter, _ *http.Request) {
+	http.Error(response, e.Error(), http.StatusBadRequest)
+}
+
 type ErrParseArgJSON struct {
 	Err  error
 	Func fmt.Stringer
@@ -57,6 +63,11 @@ func (e ErrParseArgJSON) Unwrap() error {
 	return e.Err
 }
 
+// ServeHTTP responds with 400 Bad Request.
+func (e ErrParseArgJSON) ServeHTTP(response http.ResponseWriter, _ *http.Request) {
+	http.Error(response, e.Error(), http.StatusBadRequest)
+}
+
 type ErrParseArgsJSON struct {
 	Err  error
 	Func fmt.Stringer
@@ -74,3 +85,28 @@ func (e ErrParseArgsJSON) Error() string {
 func (e ErrParseArgsJSON) Unwrap() error {
 	return e.Err
 }
+
+// ServeHTTP responds with 400 Bad Request.
+func (e ErrParseArgsJSON) ServeHTTP(response http.ResponseWriter, _ *http.Request) {
+	http.Error(response, e.Error(), http.StatusBadRequest)
+}
+
+// ErrUnknownArgs is returned for argument names
+// that are not arguments of the function Func.
+type ErrUnknownArgs struct {
+	Func fmt.Stringer
+	Args []string
+}
+
+func NewErrUnknownArgs(f fmt.Stringer, args []string) ErrUnknownArgs {
+	return ErrUnknownArgs{Func: f, Args: args}
+}
+
+func (e ErrUnknownArgs) Error() string {
+	return fmt.Sprintf("unknown arguments %s for function %s", strings.Join(e.Args, ", "), e.Func)
+}
+
+// ServeHTTP responds with 400 Bad Request.
+func (e ErrUnknownArgs) ServeHTTP(response http.ResponseWriter, _ *http.Request) {
+	http.Error(response, e.Error(), http.StatusBadRequest)
+}
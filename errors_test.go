@@ -0,0 +1,49 @@
+package function
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrParseArgs_ServeHTTP(t *testing.T) {
+	f := MustReflectWrapper(func(a int) int { return a }, "a")
+	errs := []error{
+		NewErrParseArgString(errors.New("invalid"), f, "a"),
+		NewErrParseArgJSON(errors.New("invalid"), f, "a"),
+		NewErrParseArgsJSON(errors.New("invalid"), f, []byte(`{`)),
+		NewErrUnknownArgs(f, []string{"b"}),
+	}
+	for _, err := range errs {
+		t.Run(fmt.Sprintf("%T", err), func(t *testing.T) {
+			response := httptest.NewRecorder()
+			HandleErrorHTTP(fmt.Errorf("wrapped: %w", err), response, httptest.NewRequest(http.MethodGet, "/", nil))
+			if response.Code != http.StatusBadRequest {
+				t.Errorf("status %d, want %d", response.Code, http.StatusBadRequest)
+			}
+			if want := err.Error() + "\n"; response.Body.String() != want {
+				t.Errorf("body %q, want %q", response.Body.String(), want)
+			}
+		})
+	}
+}
+
+func TestHTTPHandler_badRequest(t *testing.T) {
+	f := MustReflectWrapper(func(ctx context.Context, a int) int { return a }, "ctx", "a").WithDisallowUnknownArgs()
+
+	for name, args := range map[string]map[string]string{
+		"unknown argument": {"a": "1", "b": "2"},
+		"invalid argument": {"a": "x"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			HTTPHandler(HTTPRequestArgs(args), f, RespondJSON)(response, httptest.NewRequest(http.MethodGet, "/", nil))
+			if response.Code != http.StatusBadRequest {
+				t.Errorf("status %d, want %d: %s", response.Code, http.StatusBadRequest, response.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package function
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// ResultFile is the data of the path pattern template
+// of WriteResultsToFile for a result.
+type ResultFile struct {
+	// Index of the result
+	Index int
+	// Count of the results
+	Count int
+	// Result value
+	Result any
+}
+
+// WriteResultsToFile returns a ResultsHandler that writes
+// every []byte, string or io.Reader result to a file.
+// An io.Reader that is also an io.Closer is closed after reading.
+// Untyped nil results are skipped.
+//
+// The path of the file is the text/template pathPattern
+// executed with a ResultFile as data, for example:
+//
+//	function.WriteResultsToFile("reports/report-{{.Index}}.csv")
+//
+// Missing directories of the path are created.
+// An error is returned for other result types
+// or if two results would be written to the same path.
+//
+// Panics if pathPattern can't be parsed.
+func WriteResultsToFile(pathPattern string) ResultsHandlerFunc {
+	t := template.Must(template.New("path").Parse(pathPattern))
+	return func(ctx context.Context, results []any, resultErr error) error {
+		if resultErr != nil {
+			return resultErr
+		}
+		written := make(map[string]bool, len(results))
+		for i, result := range results {
+			if result == nil {
+				continue
+			}
+			var path strings.Builder
+			err := t.Execute(&path, ResultFile{Index: i, Count: len(results), Result: result})
+			if err != nil {
+				return fmt.Errorf("can't execute path template for result %d because: %w", i, err)
+			}
+			filePath := filepath.Clean(path.String())
+			if written[filePath] {
+				return fmt.Errorf("result %d would overwrite the file %s written for a previous result", i, filePath)
+			}
+			written[filePath] = true
+			err = writeResultToFile(filePath, result)
+			if err != nil {
+				return fmt.Errorf("can't write result %d to file because: %w", i, err)
+			}
+		}
+		return nil
+	}
+}
+
+func writeResultToFile(filePath string, result any) (err error) {
+	var reader io.Reader
+	switch x := result.(type) {
+	case []byte:
+		reader = bytes.NewReader(x)
+	case string:
+		reader = strings.NewReader(x)
+	case io.Reader:
+		reader = x
+		if closer, ok := x.(io.Closer); ok {
+			defer func() {
+				if e := closer.Close(); e != nil && err == nil {
+					err = e
+				}
+			}()
+		}
+	default:
+		return fmt.Errorf("%w: %T", ErrTypeNotSupported, result)
+	}
+
+	err = os.MkdirAll(filepath.Dir(filePath), 0o750)
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(filePath) //#nosec G304
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, reader)
+	if e := file.Close(); e != nil && err == nil {
+		err = e
+	}
+	return err
+}
@@ -0,0 +1,59 @@
+package function
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestWriteResultsToFile(t *testing.T) {
+	dir := t.TempDir()
+	reader := &closeRecorder{Reader: strings.NewReader("from reader")}
+	handler := WriteResultsToFile(filepath.Join(dir, "out", "result-{{.Index}}-of-{{.Count}}.txt"))
+	err := handler.HandleResults(context.Background(), []any{[]byte("bytes"), "string", nil, reader}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"result-0-of-4.txt": "bytes",
+		"result-1-of-4.txt": "string",
+		"result-3-of-4.txt": "from reader",
+	} {
+		got, err := os.ReadFile(filepath.Join(dir, "out", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "out", "result-2-of-4.txt")); !os.IsNotExist(err) {
+		t.Errorf("file written for nil result: %v", err)
+	}
+	if !reader.closed {
+		t.Error("reader not closed")
+	}
+
+	err = WriteResultsToFile(filepath.Join(dir, "int.txt")).HandleResults(context.Background(), []any{1}, nil)
+	if !errors.Is(err, ErrTypeNotSupported) {
+		t.Errorf("expected ErrTypeNotSupported, got %v", err)
+	}
+	err = WriteResultsToFile(filepath.Join(dir, "same.txt")).HandleResults(context.Background(), []any{"a", "b"}, nil)
+	if err == nil {
+		t.Error("expected error for results written to the same file")
+	}
+}
@@ -0,0 +1,77 @@
+package functest
+
+import (
+	"reflect"
+	"testing"
+)
+
+// AssertNumCalls reports an error if w did not record want calls.
+func AssertNumCalls(t testing.TB, w *RecordingWrapper, want int) {
+	t.Helper()
+	if got := w.NumCalls(); got != want {
+		t.Errorf("%s called %d times, want %d", w.Name(), got, want)
+	}
+}
+
+// AssertCallArgs reports an error if the call with index i
+// recorded by w was not a Call method call with the arguments want.
+func AssertCallArgs(t testing.TB, w *RecordingWrapper, i int, want ...any) {
+	t.Helper()
+	call, ok := recordedCall(t, w, i, "Call")
+	if ok && !reflect.DeepEqual(call.Args, want) {
+		t.Errorf("call %d of %s got args %#v, want %#v", i, w.Name(), call.Args, want)
+	}
+}
+
+// AssertCallStringArgs reports an error if the call with index i
+// recorded by w was not a CallWithStrings method call with the arguments want.
+func AssertCallStringArgs(t testing.TB, w *RecordingWrapper, i int, want ...string) {
+	t.Helper()
+	call, ok := recordedCall(t, w, i, "CallWithStrings")
+	if ok && !reflect.DeepEqual(call.StringArgs, want) {
+		t.Errorf("call %d of %s got string args %#v, want %#v", i, w.Name(), call.StringArgs, want)
+	}
+}
+
+// AssertCallNamedStringArgs reports an error if the call with index i
+// recorded by w was not a CallWithNamedStrings method call with the arguments want.
+func AssertCallNamedStringArgs(t testing.TB, w *RecordingWrapper, i int, want map[string]string) {
+	t.Helper()
+	call, ok := recordedCall(t, w, i, "CallWithNamedStrings")
+	if ok && !reflect.DeepEqual(call.NamedStringArgs, want) {
+		t.Errorf("call %d of %s got named string args %#v, want %#v", i, w.Name(), call.NamedStringArgs, want)
+	}
+}
+
+// AssertResults reports an error if err is not nil
+// or results are not deeply equal to want.
+func AssertResults(t testing.TB, results []any, err error, want ...any) {
+	t.Helper()
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if len(results) != len(want) {
+		t.Errorf("got %d results %#v, want %d results %#v", len(results), results, len(want), want)
+		return
+	}
+	for i := range results {
+		if !reflect.DeepEqual(results[i], want[i]) {
+			t.Errorf("result %d is %#v, want %#v", i, results[i], want[i])
+		}
+	}
+}
+
+func recordedCall(t testing.TB, w *RecordingWrapper, i int, method string) (Call, bool) {
+	t.Helper()
+	calls := w.Calls()
+	if i < 0 || i >= len(calls) {
+		t.Errorf("no call %d of %s, got %d calls", i, w.Name(), len(calls))
+		return Call{}, false
+	}
+	if calls[i].Method != method {
+		t.Errorf("call %d of %s is a %s method call, want %s", i, w.Name(), calls[i].Method, method)
+		return Call{}, false
+	}
+	return calls[i], true
+}
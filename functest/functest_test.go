@@ -0,0 +1,82 @@
+package functest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/domonda/go-function"
+)
+
+var _ function.Wrapper = new(StubWrapper)
+
+func TestStubWrapper(t *testing.T) {
+	errNotFound := errors.New("not found")
+	stub := NewStub[func(ctx context.Context, id int) (string, error)]("GetName", "ctx", "id").
+		Returns("Alice").
+		ReturnsError(errNotFound)
+
+	if got, want := stub.String(), "GetName(ctx context.Context, id int) (string, error)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if !stub.ContextArg() || !stub.ErrorResult() || stub.NumArgs() != 2 || stub.NumResults() != 1 {
+		t.Errorf("unexpected description of %s", stub)
+	}
+
+	ctx := context.Background()
+	results, err := stub.Call(ctx, []any{ctx, 1})
+	AssertResults(t, results, err, "Alice")
+
+	for range 2 {
+		// The last scripted result is repeated
+		results, err = stub.CallWithStrings(ctx, "2")
+		if !errors.Is(err, errNotFound) {
+			t.Errorf("got error %v, want %v", err, errNotFound)
+		}
+		if len(results) != 1 || results[0] != "" {
+			t.Errorf("got results %#v, want zero value result", results)
+		}
+	}
+	if stub.NumCalls() != 3 {
+		t.Errorf("NumCalls() = %d, want 3", stub.NumCalls())
+	}
+}
+
+func TestNewStub_defaultArgNames(t *testing.T) {
+	stub := NewStub[func(context.Context, string, ...int)]("Default")
+	want := []string{"ctx", "arg1", "arg2"}
+	got := stub.ArgNames()
+	if !slices.Equal(got, want) {
+		t.Errorf("ArgNames() = %v, want %v", got, want)
+	}
+	results, err := stub.Call(context.Background(), nil)
+	AssertResults(t, results, err)
+}
+
+func TestRecordingWrapper(t *testing.T) {
+	sum := NewRecordingWrapper(function.MustReflectWrapper(func(a, b int) int { return a + b }, "a", "b").WithName("Sum"))
+	ctx := context.Background()
+
+	results, err := sum.Call(ctx, []any{1, 2})
+	AssertResults(t, results, err, 3)
+	results, err = sum.CallWithStrings(ctx, "3", "4")
+	AssertResults(t, results, err, 7)
+	results, err = sum.CallWithNamedStrings(ctx, map[string]string{"a": "5", "b": "6"})
+	AssertResults(t, results, err, 11)
+	_, err = sum.CallWithStrings(ctx, "x")
+	if err == nil {
+		t.Error("expected error for invalid argument")
+	}
+
+	AssertNumCalls(t, sum, 4)
+	AssertCallArgs(t, sum, 0, 1, 2)
+	AssertCallStringArgs(t, sum, 1, "3", "4")
+	AssertCallNamedStringArgs(t, sum, 2, map[string]string{"a": "5", "b": "6"})
+	if calls := sum.Calls(); calls[3].Err == nil || calls[3].Ctx != ctx {
+		t.Errorf("call 3 not recorded with error and context: %#v", calls[3])
+	}
+
+	sum.Reset()
+	AssertNumCalls(t, sum, 0)
+}
@@ -0,0 +1,105 @@
+// Package functest provides helpers for unit testing code
+// that depends on function.Wrapper implementations
+// without real functions or reflection setups.
+package functest
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/domonda/go-function"
+)
+
+// Call records a call of a RecordingWrapper.
+type Call struct {
+	// Ctx passed to the call
+	Ctx context.Context
+	// Method of the wrapper that was called:
+	// "Call", "CallWithStrings", "CallWithNamedStrings" or "CallWithJSON"
+	Method string
+	// Args of a Call method call
+	Args []any
+	// StringArgs of a CallWithStrings method call
+	StringArgs []string
+	// NamedStringArgs of a CallWithNamedStrings method call
+	NamedStringArgs map[string]string
+	// JSON of a CallWithJSON method call
+	JSON []byte
+	// Results returned by the wrapped function
+	Results []any
+	// Err returned by the wrapped function
+	Err error
+}
+
+// RecordingWrapper wraps a function.Wrapper
+// and records all calls with their arguments and results.
+// It is safe for concurrent use.
+type RecordingWrapper struct {
+	function.Wrapper
+
+	mtx   sync.Mutex
+	calls []Call
+}
+
+// NewRecordingWrapper returns a RecordingWrapper
+// that records the calls of wrapped.
+func NewRecordingWrapper(wrapped function.Wrapper) *RecordingWrapper {
+	return &RecordingWrapper{Wrapper: wrapped}
+}
+
+func (w *RecordingWrapper) Call(ctx context.Context, args []any) (results []any, err error) {
+	results, err = w.Wrapper.Call(ctx, args)
+	w.record(Call{Ctx: ctx, Method: "Call", Args: slices.Clone(args), Results: results, Err: err})
+	return results, err
+}
+
+func (w *RecordingWrapper) CallWithStrings(ctx context.Context, args ...string) (results []any, err error) {
+	results, err = w.Wrapper.CallWithStrings(ctx, args...)
+	w.record(Call{Ctx: ctx, Method: "CallWithStrings", StringArgs: slices.Clone(args), Results: results, Err: err})
+	return results, err
+}
+
+func (w *RecordingWrapper) CallWithNamedStrings(ctx context.Context, args map[string]string) (results []any, err error) {
+	results, err = w.Wrapper.CallWithNamedStrings(ctx, args)
+	w.record(Call{Ctx: ctx, Method: "CallWithNamedStrings", NamedStringArgs: maps.Clone(args), Results: results, Err: err})
+	return results, err
+}
+
+func (w *RecordingWrapper) CallWithJSON(ctx context.Context, argsJSON []byte) (results []any, err error) {
+	results, err = w.Wrapper.CallWithJSON(ctx, argsJSON)
+	w.record(Call{Ctx: ctx, Method: "CallWithJSON", JSON: slices.Clone(argsJSON), Results: results, Err: err})
+	return results, err
+}
+
+func (w *RecordingWrapper) record(call Call) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	w.calls = append(w.calls, call)
+}
+
+// Calls returns the recorded calls in call order.
+func (w *RecordingWrapper) Calls() []Call {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	return slices.Clone(w.calls)
+}
+
+// NumCalls returns the number of recorded calls.
+func (w *RecordingWrapper) NumCalls() int {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	return len(w.calls)
+}
+
+// Reset deletes all recorded calls.
+func (w *RecordingWrapper) Reset() {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	w.calls = nil
+}
@@ -0,0 +1,213 @@
+package functest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/domonda/go-function"
+)
+
+var (
+	typeOfContext = function.ReflectType[context.Context]()
+	typeOfError   = function.ReflectType[error]()
+)
+
+// StubResult is a scripted result of a StubWrapper call.
+type StubResult struct {
+	Results []any
+	Err     error
+}
+
+// StubWrapper is a function.Wrapper described by a function type
+// that returns scripted results instead of calling a function.
+// The arguments of the calls are not used,
+// wrap a StubWrapper with NewRecordingWrapper to record them.
+// It is safe for concurrent use.
+type StubWrapper struct {
+	name        string
+	argNames    []string
+	argTypes    []reflect.Type
+	resultTypes []reflect.Type // without error result
+	errorResult bool
+
+	mtx      sync.Mutex
+	script   []StubResult
+	numCalls int
+}
+
+// NewStub returns a StubWrapper described by the function type F
+// like a function.ReflectWrapper of a function of type F would be.
+// The argNames including a context argument name the arguments,
+// if no argNames are passed then a context argument is named "ctx"
+// and the other arguments "arg0", "arg1", and so on.
+//
+// Example:
+//
+//	stub := functest.NewStub[func(ctx context.Context, id int) (string, error)]("GetName", "ctx", "id").
+//		Returns("Alice").
+//		ReturnsError(errors.New("not found"))
+//
+// Panics if F is not a function type or the number
+// of argNames does not match the arguments of F.
+func NewStub[F any](name string, argNames ...string) *StubWrapper {
+	t := function.ReflectType[F]()
+	if t.Kind() != reflect.Func {
+		panic(fmt.Sprintf("functest.NewStub: expected function type but got %s", t))
+	}
+	if len(argNames) == 0 && t.NumIn() > 0 {
+		argNames = make([]string, t.NumIn())
+		for i := range argNames {
+			if i == 0 && t.In(0) == typeOfContext {
+				argNames[i] = "ctx"
+			} else {
+				argNames[i] = "arg" + strconv.Itoa(i)
+			}
+		}
+	}
+	if len(argNames) != t.NumIn() {
+		panic(fmt.Sprintf("functest.NewStub: %d argNames passed, but %s has %d arguments", len(argNames), t, t.NumIn()))
+	}
+	stub := &StubWrapper{name: name, argNames: argNames}
+	for i := range t.NumIn() {
+		stub.argTypes = append(stub.argTypes, t.In(i))
+	}
+	numOut := t.NumOut()
+	stub.errorResult = numOut > 0 && t.Out(numOut-1) == typeOfError
+	if stub.errorResult {
+		numOut--
+	}
+	for i := range numOut {
+		stub.resultTypes = append(stub.resultTypes, t.Out(i))
+	}
+	return stub
+}
+
+// Returns appends results to the script of the stub
+// that are returned by the next call that was not scripted yet.
+//
+// Panics if the number or types of the results
+// don't match the non error results of the function type.
+func (s *StubWrapper) Returns(results ...any) *StubWrapper {
+	if len(results) != len(s.resultTypes) {
+		panic(fmt.Sprintf("functest.StubWrapper.Returns: %d results passed, but %s has %d results", len(results), s.name, len(s.resultTypes)))
+	}
+	for i, result := range results {
+		if result != nil && !reflect.TypeOf(result).AssignableTo(s.resultTypes[i]) {
+			panic(fmt.Sprintf("functest.StubWrapper.Returns: result %d of type %T is not assignable to %s", i, result, s.resultTypes[i]))
+		}
+	}
+	return s.appendScript(StubResult{Results: results})
+}
+
+// ReturnsError appends err with zero value results to the script of the stub
+// that is returned by the next call that was not scripted yet.
+//
+// Panics if the function type has no error result.
+func (s *StubWrapper) ReturnsError(err error) *StubWrapper {
+	if !s.errorResult {
+		panic(fmt.Sprintf("functest.StubWrapper.ReturnsError: %s has no error result", s.name))
+	}
+	return s.appendScript(StubResult{Results: s.zeroResults(), Err: err})
+}
+
+func (s *StubWrapper) appendScript(result StubResult) *StubWrapper {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.script = append(s.script, result)
+	return s
+}
+
+// next returns the scripted result for the next call.
+// The last scripted result is repeated after the script is exhausted
+// and zero value results are returned if nothing was scripted.
+func (s *StubWrapper) next() ([]any, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.numCalls++
+	if len(s.script) == 0 {
+		return s.zeroResults(), nil
+	}
+	result := s.script[min(s.numCalls, len(s.script))-1]
+	return slices.Clone(result.Results), result.Err
+}
+
+func (s *StubWrapper) zeroResults() []any {
+	if len(s.resultTypes) == 0 {
+		return nil
+	}
+	results := make([]any, len(s.resultTypes))
+	for i, t := range s.resultTypes {
+		results[i] = reflect.Zero(t).Interface()
+	}
+	return results
+}
+
+// NumCalls returns how often the stub was called.
+func (s *StubWrapper) NumCalls() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s.numCalls
+}
+
+func (s *StubWrapper) Name() string { return s.name }
+
+func (s *StubWrapper) String() string {
+	var b strings.Builder
+	b.WriteString(s.name)
+	b.WriteByte('(')
+	for i, argType := range s.argTypes {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s %s", s.argNames[i], argType)
+	}
+	b.WriteByte(')')
+	results := make([]string, len(s.resultTypes), len(s.resultTypes)+1)
+	for i, resultType := range s.resultTypes {
+		results[i] = resultType.String()
+	}
+	if s.errorResult {
+		results = append(results, "error")
+	}
+	switch len(results) {
+	case 0:
+	case 1:
+		b.WriteString(" " + results[0])
+	default:
+		b.WriteString(" (" + strings.Join(results, ", ") + ")")
+	}
+	return b.String()
+}
+
+func (s *StubWrapper) NumArgs() int                { return len(s.argTypes) }
+func (s *StubWrapper) ContextArg() bool            { return len(s.argTypes) > 0 && s.argTypes[0] == typeOfContext }
+func (s *StubWrapper) NumResults() int             { return len(s.resultTypes) }
+func (s *StubWrapper) ErrorResult() bool           { return s.errorResult }
+func (s *StubWrapper) ArgNames() []string          { return slices.Clone(s.argNames) }
+func (s *StubWrapper) ArgDescriptions() []string   { return make([]string, len(s.argTypes)) }
+func (s *StubWrapper) ArgTypes() []reflect.Type    { return slices.Clone(s.argTypes) }
+func (s *StubWrapper) ResultTypes() []reflect.Type { return slices.Clone(s.resultTypes) }
+
+func (s *StubWrapper) Call(context.Context, []any) ([]any, error) {
+	return s.next()
+}
+
+func (s *StubWrapper) CallWithStrings(context.Context, ...string) ([]any, error) {
+	return s.next()
+}
+
+func (s *StubWrapper) CallWithNamedStrings(context.Context, map[string]string) ([]any, error) {
+	return s.next()
+}
+
+func (s *StubWrapper) CallWithJSON(context.Context, []byte) ([]any, error) {
+	return s.next()
+}
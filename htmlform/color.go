@@ -0,0 +1,87 @@
+package htmlform
+
+import (
+	"fmt"
+	"image/color"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/domonda/go-function"
+)
+
+var (
+	typeOfColor = function.ReflectType[color.Color]()
+	typeOfRGBA  = function.ReflectType[color.RGBA]()
+)
+
+// DefaultStringScanner is the default string scanner of a Handler
+// for converting submitted form values to argument types.
+// It uses ColorStringScanner for values of color input fields
+// submitted as #RRGGBB and function.ScanString for all other values.
+var DefaultStringScanner function.StringScanner = function.NewTypeStringScanners(function.StringScannerFunc(function.ScanString)).
+	WithInterfaceTypeScanner(typeOfColor, ColorStringScanner)
+
+// ColorHexString formats a color as #RRGGBB string
+// as used by the value of HTML color input elements.
+// The alpha channel is ignored.
+func ColorHexString(c color.Color) string {
+	if c == nil {
+		return "#000000"
+	}
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
+
+// ParseColorHexString parses a #RRGGBB or #RGB string
+// as opaque color.RGBA.
+func ParseColorHexString(str string) (color.RGBA, error) {
+	hex, ok := strings.CutPrefix(str, "#")
+	if !ok {
+		return color.RGBA{}, fmt.Errorf("color %q does not begin with '#'", str)
+	}
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return color.RGBA{}, fmt.Errorf("color %q is not in the format #RRGGBB", str)
+	}
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("color %q is not in the format #RRGGBB: %w", str, err)
+	}
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xff}, nil //#nosec G115 -- masked by uint8 conversion
+}
+
+// ColorStringScanner scans #RRGGBB strings into
+// color.Color interfaces, color.RGBA, color.NRGBA
+// and types that are convertible from color.RGBA.
+// Strings not beginning with '#' and other types
+// result in a function.ErrTypeNotSupported error
+// so the next configured scanner will be tried.
+var ColorStringScanner function.StringScannerFunc = func(sourceStr string, destPtr any) error {
+	if !strings.HasPrefix(sourceStr, "#") {
+		return function.ErrTypeNotSupported
+	}
+	c, err := ParseColorHexString(sourceStr)
+	if err != nil {
+		return err
+	}
+	switch dest := destPtr.(type) {
+	case *color.Color:
+		*dest = c
+		return nil
+	case *color.RGBA:
+		*dest = c
+		return nil
+	case *color.NRGBA:
+		*dest = color.NRGBA(c)
+		return nil
+	}
+	destVal := reflect.ValueOf(destPtr).Elem()
+	if !typeOfRGBA.ConvertibleTo(destVal.Type()) {
+		return fmt.Errorf("%w: %s", function.ErrTypeNotSupported, destVal.Type())
+	}
+	destVal.Set(reflect.ValueOf(c).Convert(destVal.Type()))
+	return nil
+}
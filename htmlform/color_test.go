@@ -0,0 +1,97 @@
+package htmlform
+
+import (
+	"context"
+	"image/color"
+	"net/http"
+	"testing"
+
+	"github.com/domonda/go-function"
+)
+
+type namedRGBA color.RGBA
+
+func TestParseColorHexString(t *testing.T) {
+	tests := []struct {
+		str     string
+		want    color.RGBA
+		wantErr bool
+	}{
+		{str: "#ff8000", want: color.RGBA{R: 0xff, G: 0x80, B: 0x00, A: 0xff}},
+		{str: "#FF8000", want: color.RGBA{R: 0xff, G: 0x80, B: 0x00, A: 0xff}},
+		{str: "#f80", want: color.RGBA{R: 0xff, G: 0x88, B: 0x00, A: 0xff}},
+		{str: "ff8000", wantErr: true},
+		{str: "#ff80", wantErr: true},
+		{str: "#gg8000", wantErr: true},
+		{str: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			got, err := ParseColorHexString(tt.str)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseColorHexString(%q) error = %v, wantErr %v", tt.str, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseColorHexString(%q) = %v, want %v", tt.str, got, tt.want)
+			}
+			if !tt.wantErr && len(tt.str) == 7 {
+				if str := ColorHexString(got); str != "#ff8000" {
+					t.Errorf("ColorHexString(%v) = %q, want %q", got, str, "#ff8000")
+				}
+			}
+		})
+	}
+	if str := ColorHexString(nil); str != "#000000" {
+		t.Errorf("ColorHexString(nil) = %q, want #000000", str)
+	}
+}
+
+func TestColorStringScanner(t *testing.T) {
+	want := color.RGBA{R: 0x12, G: 0x34, B: 0x56, A: 0xff}
+
+	var c color.Color
+	if err := ColorStringScanner.ScanString("#123456", &c); err != nil || c != want {
+		t.Errorf("scanned color.Color %v, %v", c, err)
+	}
+	var rgba color.RGBA
+	if err := ColorStringScanner.ScanString("#123456", &rgba); err != nil || rgba != want {
+		t.Errorf("scanned color.RGBA %v, %v", rgba, err)
+	}
+	var nrgba color.NRGBA
+	if err := ColorStringScanner.ScanString("#123456", &nrgba); err != nil || nrgba != color.NRGBA(want) {
+		t.Errorf("scanned color.NRGBA %v, %v", nrgba, err)
+	}
+	var named namedRGBA
+	if err := ColorStringScanner.ScanString("#123456", &named); err != nil || named != namedRGBA(want) {
+		t.Errorf("scanned namedRGBA %v, %v", named, err)
+	}
+	var str string
+	if err := ColorStringScanner.ScanString("#123456", &str); err == nil {
+		t.Error("expected error for scanning into string")
+	}
+	if err := ColorStringScanner.ScanString("red", &c); err != function.ErrTypeNotSupported {
+		t.Errorf("expected function.ErrTypeNotSupported for string without #, got %v", err)
+	}
+}
+
+func TestHandler_ColorArg(t *testing.T) {
+	var got color.Color
+	wrapper := function.MustReflectWrapper(func(ctx context.Context, c color.Color, name string) {
+		got = c
+	}, "ctx", "c", "name")
+	handler := MustNewHandler(wrapper, "Test", function.RespondStaticPlaintext("OK"))
+
+	response := postForm(t, handler, map[string]string{"c": "#102030", "name": "#not a color"})
+	if response.Code != http.StatusOK {
+		t.Fatalf("submit responded with status %d: %s", response.Code, response.Body.String())
+	}
+	if want := (color.RGBA{R: 0x10, G: 0x20, B: 0x30, A: 0xff}); got != want {
+		t.Errorf("got color %v, want %v", got, want)
+	}
+
+	// Importing htmlform does not change the global string scanners
+	var c color.Color
+	if err := function.ScanString("#102030", &c); err == nil {
+		t.Errorf("function.ScanString scanned color %v", c)
+	}
+}
@@ -9,35 +9,154 @@ var FormTemplate = `
 	<style>
 		* { font-family: "Lucida Console", Monaco, monospace; }
 		label { display: block; }
+		.help { display: block; color: #555; font-size: smaller; }
+		.error { display: block; color: #b00020; font-size: smaller; }
+		.error:empty { display: none; }
+		fieldset { margin-bottom: 10px; }
 		form { margin: 10px; }
 		form div { padding-bottom: 10px; }
+		textarea.json:invalid { border-color: red; }
+		.markdown-editor { display: flex; gap: 10px; }
+		.markdown-editor textarea, .markdown-preview { flex: 1; }
+		.markdown-preview { border: 1px solid #ccc; padding: 0 10px; overflow: auto; }
 	</style>
+	<script>
+		function showFieldError(input) {
+			const errorElem = document.getElementById(input.id + "-error");
+			if (errorElem) {
+				errorElem.textContent = input.validationMessage;
+			}
+			input.setAttribute("aria-invalid", input.validity.valid ? "false" : "true");
+		}
+		function validateJSON(textarea) {
+			try {
+				if (textarea.value.trim() !== "") {
+					JSON.parse(textarea.value);
+				}
+				textarea.setCustomValidity("");
+			} catch (e) {
+				textarea.setCustomValidity(e.message);
+			}
+			showFieldError(textarea);
+		}
+		function formatJSON(textarea) {
+			try {
+				textarea.value = JSON.stringify(JSON.parse(textarea.value), null, 2);
+			} catch (e) {}
+			validateJSON(textarea);
+		}
+		function renderMarkdown(md) {
+			const escape = s => s.replace(/&/g, "&amp;").replace(/</g, "&lt;").replace(/>/g, "&gt;").replace(/"/g, "&quot;");
+			const inline = s => escape(s)
+				.replace(/\x60([^\x60]+)\x60/g, "<code>$1</code>")
+				.replace(/\*\*([^*]+)\*\*/g, "<strong>$1</strong>")
+				.replace(/\*([^*]+)\*/g, "<em>$1</em>")
+				.replace(/\[([^\]]+)\]\((https?:[^)\s"<>]+)\)/g, '<a href="$2">$1</a>');
+			let html = "", list = false, code = false;
+			for (const line of md.split("\n")) {
+				if (line.startsWith("\x60\x60\x60")) {
+					if (list) {
+						html += "</ul>";
+						list = false;
+					}
+					html += code ? "</code></pre>" : "<pre><code>";
+					code = !code;
+					continue;
+				}
+				if (code) {
+					html += escape(line) + "\n";
+					continue;
+				}
+				const item = line.match(/^\s*[-*]\s+(.*)/);
+				if (list && !item) {
+					html += "</ul>";
+					list = false;
+				}
+				const heading = line.match(/^(#{1,6})\s+(.*)/);
+				if (heading) {
+					html += "<h" + heading[1].length + ">" + inline(heading[2]) + "</h" + heading[1].length + ">";
+				} else if (item) {
+					if (!list) {
+						html += "<ul>";
+						list = true;
+					}
+					html += "<li>" + inline(item[1]) + "</li>";
+				} else if (line.trim() !== "") {
+					html += "<p>" + inline(line) + "</p>";
+				}
+			}
+			return html + (list ? "</ul>" : "") + (code ? "</code></pre>" : "");
+		}
+		function previewMarkdown(textarea) {
+			textarea.nextElementSibling.innerHTML = renderMarkdown(textarea.value);
+		}
+		document.addEventListener("DOMContentLoaded", () => {
+			document.querySelectorAll("textarea.markdown").forEach(previewMarkdown);
+			document.querySelectorAll("form [name]").forEach(input => {
+				input.addEventListener("invalid", () => showFieldError(input));
+				input.addEventListener("change", () => showFieldError(input));
+			});
+		});
+	</script>
 </head>
 <body>
-<h1>{{.Title}}</h1>
-<form method="post" enctype="multipart/form-data">
-	{{range .Fields}}
-		<div>
-			{{if eq .Type "checkbox"}}
-				<input type="checkbox" id="{{.Name}}" name="{{.Name}}" value="true" {{if eq .Value "true"}}checked{{end}}/>
-				<label style="display: inline" for="{{.Name}}">{{.Label}}</label>
-			{{else if eq .Type "select"}}
-				<label for="{{.Name}}">{{.Label}}:</label>
-				<select id="{{.Name}}" name="{{.Name}}" {{if .Required}}required{{end}}>
-					{{$selectValue := .Value}}
-					{{range .Options}}
-						<option value="{{.Value}}" {{if eq (printf "%v" .Value) $selectValue}}selected{{end}}>{{.Label}}</option>
-					{{end}}
-				</select>
-			{{else if eq .Type "textarea"}}
-				<label for="{{.Name}}">{{.Label}}:</label>
-				<textarea id="{{.Name}}" name="{{.Name}}" cols="40" rows="5" {{if .Required}}required{{end}}>{{.Value}}</textarea>
-			{{else}}
-				<label for="{{.Name}}">{{.Label}}:</label>
-				<input type="{{.Type}}" id="{{.Name}}" name="{{.Name}}" value="{{.Value}}" size="40" {{if .Required}}required{{end}}/>
-			{{end}}
-		</div>
+{{define "field"}}
+	<div>
+		{{if .HTML}}
+			{{.HTML}}
+		{{else if eq .Type "checkbox"}}
+			<input type="checkbox" id="{{.Name}}" name="{{.Name}}" value="true" {{if eq .Value "true"}}checked{{end}} aria-describedby="{{if .Description}}{{.Name}}-help {{end}}{{.Name}}-error"/>
+			<label style="display: inline" for="{{.Name}}">{{.Label}}</label>
+		{{else if eq .Type "select"}}
+			<label for="{{.Name}}">{{.Label}}:</label>
+			<select id="{{.Name}}" name="{{.Name}}" {{if .Required}}required aria-required="true"{{end}} aria-describedby="{{if .Description}}{{.Name}}-help {{end}}{{.Name}}-error">
+				{{$selectValue := .Value}}
+				{{range .Options}}
+					<option value="{{.Value}}" {{if eq (printf "%v" .Value) $selectValue}}selected{{end}}>{{.Label}}</option>
+				{{end}}
+			</select>
+		{{else if eq .Type "textarea"}}
+			<label for="{{.Name}}">{{.Label}}:</label>
+			<textarea id="{{.Name}}" name="{{.Name}}" cols="40" rows="{{.Rows}}" {{if .Required}}required aria-required="true"{{end}} aria-describedby="{{if .Description}}{{.Name}}-help {{end}}{{.Name}}-error">{{.Value}}</textarea>
+		{{else if eq .Type "json"}}
+			<label for="{{.Name}}">{{.Label}} (JSON):</label>
+			<textarea class="json" id="{{.Name}}" name="{{.Name}}" cols="60" rows="{{.Rows}}" spellcheck="false" oninput="validateJSON(this)" {{if .Required}}required aria-required="true"{{end}} aria-describedby="{{if .Description}}{{.Name}}-help {{end}}{{.Name}}-error">{{.Value}}</textarea>
+			<button type="button" onclick="formatJSON(document.getElementById('{{.Name}}'))" aria-controls="{{.Name}}">Format JSON</button>
+		{{else if eq .Type "markdown"}}
+			<label for="{{.Name}}">{{.Label}}:</label>
+			<div class="markdown-editor">
+				<textarea class="markdown" id="{{.Name}}" name="{{.Name}}" cols="40" rows="{{.Rows}}" oninput="previewMarkdown(this)" {{if .Required}}required aria-required="true"{{end}} aria-describedby="{{if .Description}}{{.Name}}-help {{end}}{{.Name}}-error">{{.Value}}</textarea>
+				<div class="markdown-preview" role="region" aria-label="{{.Label}} preview" aria-live="polite"></div>
+			</div>
+		{{else if eq .Type "range"}}
+			<label for="{{.Name}}">{{.Label}}:</label>
+			<input type="range" id="{{.Name}}" name="{{.Name}}" value="{{.Value}}" {{if .Min}}min="{{.Min}}"{{end}} {{if .Max}}max="{{.Max}}"{{end}} {{if .Step}}step="{{.Step}}"{{end}} oninput="this.nextElementSibling.value = this.value" {{if .Required}}required aria-required="true"{{end}} aria-describedby="{{if .Description}}{{.Name}}-help {{end}}{{.Name}}-error"/>
+			<output for="{{.Name}}" aria-live="polite">{{.Value}}</output>
+		{{else}}
+			<label for="{{.Name}}">{{.Label}}:</label>
+			<input type="{{.Type}}" id="{{.Name}}" name="{{.Name}}" value="{{.Value}}" size="40" {{if .Min}}min="{{.Min}}"{{end}} {{if .Max}}max="{{.Max}}"{{end}} {{if .Step}}step="{{.Step}}"{{end}} {{if .Required}}required aria-required="true"{{end}} aria-describedby="{{if .Description}}{{.Name}}-help {{end}}{{.Name}}-error"/>
+		{{end}}
+		{{if .Description}}<span class="help" id="{{.Name}}-help">{{.Description}}</span>{{end}}
+		<span class="error" id="{{.Name}}-error" role="alert" aria-live="assertive"></span>
+	</div>
+{{end}}
+<main>
+<h1 id="form-title">{{.Title}}</h1>
+<form method="post" enctype="multipart/form-data" aria-labelledby="form-title">
+	{{if .SubmitToken}}<input type="hidden" name="{{.SubmitTokenFieldName}}" value="{{.SubmitToken}}"/>{{end}}
+	{{range .Groups}}
+		{{if .Legend}}
+			<fieldset>
+				<legend>{{.Legend}}</legend>
+				{{range .Fields}}{{template "field" .}}{{end}}
+			</fieldset>
+		{{else}}
+			{{range .Fields}}{{template "field" .}}{{end}}
+		{{end}}
 	{{end}}
-	<button>{{.SubmitButtonText}}</button>
+	<button type="submit">{{.SubmitButtonText}}</button>
 </form>
+</main>
+</body>
+</html>
 `
@@ -0,0 +1,56 @@
+package htmlform
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// renderMarkdownJS returns the source of the JavaScript
+// renderMarkdown function of FormTemplate.
+func renderMarkdownJS(t *testing.T) string {
+	t.Helper()
+	_, source, ok := strings.Cut(FormTemplate, "function renderMarkdown(md) {")
+	if !ok {
+		t.Fatal("FormTemplate has no renderMarkdown function")
+	}
+	source, _, ok = strings.Cut(source, "function previewMarkdown(")
+	if !ok {
+		t.Fatal("FormTemplate has no previewMarkdown function")
+	}
+	return "function renderMarkdown(md) {" + source
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node is needed to run the markdown preview JavaScript")
+	}
+	script := renderMarkdownJS(t) + "\nprocess.stdout.write(renderMarkdown(process.env.MARKDOWN));"
+
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{name: "link", markdown: "[x](https://example.com/?a=1&b=2)", want: `<p><a href="https://example.com/?a=1&amp;b=2">x</a></p>`},
+		{name: "quote in link", markdown: `[x](https://a"onmouseover="alert(1))`, want: `<p><a href="https://a&quot;onmouseover=&quot;alert(1">x</a>)</p>`},
+		{name: "tag in link", markdown: `[x](https://a<script>)`, want: `<p><a href="https://a&lt;script&gt;">x</a></p>`},
+		{name: "javascript link", markdown: `[x](javascript:alert(1))`, want: `<p>[x](javascript:alert(1))</p>`},
+		{name: "quote in text", markdown: `# "quoted" <b>`, want: `<h1>&quot;quoted&quot; &lt;b&gt;</h1>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(node, "-e", script)
+			cmd.Env = append(os.Environ(), "MARKDOWN="+tt.markdown)
+			got, err := cmd.Output()
+			if err != nil {
+				t.Fatalf("node error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("renderMarkdown(%q) = %s, want %s", tt.markdown, got, tt.want)
+			}
+		})
+	}
+}
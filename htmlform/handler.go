@@ -1,10 +1,15 @@
 package htmlform
 
 import (
+	"bytes"
+	"encoding"
+	"encoding/json"
 	"fmt"
 	"html/template"
+	"image/color"
 	"net/http"
 	"reflect"
+	"time"
 
 	"github.com/domonda/go-function"
 	"github.com/domonda/go-types"
@@ -14,7 +19,15 @@ import (
 	"github.com/ungerik/go-httpx/httperr"
 )
 
-var typeOfFileReader = function.ReflectType[fs.FileReader]()
+var (
+	typeOfAny             = function.ReflectType[any]()
+	typeOfFileReader      = function.ReflectType[fs.FileReader]()
+	typeOfTextUnmarshaler = function.ReflectType[encoding.TextUnmarshaler]()
+)
+
+// DefaultTextareaRows is the number of rows of a textarea
+// form field when no rows were set with SetArgMultiline.
+var DefaultTextareaRows = 5
 
 type Option struct {
 	Label string
@@ -22,12 +35,28 @@ type Option struct {
 }
 
 type formField struct {
-	Name     string
-	Label    string
-	Type     string
-	Value    string
-	Required bool
-	Options  []Option
+	Name        string
+	Label       string
+	Description string
+	Type        string
+	Value       string
+	Required    bool
+	Options     []Option
+	Rows        int
+	Min         string
+	Max         string
+	Step        string
+
+	// HTML is the pre-rendered output of a field template
+	// set with SetFieldTemplate or SetInputTypeTemplate
+	HTML template.HTML
+}
+
+// formGroup is a group of consecutive form fields
+// rendered as fieldset if it has a legend
+type formGroup struct {
+	Legend string
+	Fields []formField
 }
 
 type Handler struct {
@@ -37,13 +66,26 @@ type Handler struct {
 	argOptions      map[string][]Option
 	argDefaultValue map[string]any
 	argInputType    map[string]string
+	argLabel        map[string]string
+	argGroup        map[string]string
+	argRows         map[string]int
+	argMin          map[string]any
+	argMax          map[string]any
+	argStep         map[string]any
+	argTemplate     map[string]*template.Template
+	typeTemplate    map[string]*template.Template
 	form            struct {
-		Title            string
-		Fields           []formField
-		SubmitButtonText string
+		Title                string
+		Fields               []formField
+		Groups               []formGroup
+		SubmitButtonText     string
+		SubmitTokenFieldName string
+		SubmitToken          string
 	}
-	template     *template.Template
-	resultWriter function.HTTPResultsWriter
+	submitTokens  *submitTokens
+	stringScanner function.StringScanner
+	template      *template.Template
+	resultWriter  function.HTTPResultsWriter
 }
 
 func NewHandler(wrappedFunc function.Wrapper, title string, resultWriter function.HTTPResultsWriter) (handler *Handler, err error) {
@@ -54,10 +96,20 @@ func NewHandler(wrappedFunc function.Wrapper, title string, resultWriter functio
 		argOptions:      make(map[string][]Option),
 		argDefaultValue: make(map[string]any),
 		argInputType:    make(map[string]string),
+		argLabel:        make(map[string]string),
+		argGroup:        make(map[string]string),
+		argRows:         make(map[string]int),
+		argMin:          make(map[string]any),
+		argMax:          make(map[string]any),
+		argStep:         make(map[string]any),
+		argTemplate:     make(map[string]*template.Template),
+		typeTemplate:    make(map[string]*template.Template),
 		resultWriter:    resultWriter,
+		stringScanner:   DefaultStringScanner,
 	}
 	handler.form.Title = title
 	handler.form.SubmitButtonText = "Submit"
+	handler.form.SubmitTokenFieldName = SubmitTokenFieldName
 	handler.template, err = template.New("form").Parse(FormTemplate)
 	if err != nil {
 		return nil, err
@@ -93,10 +145,117 @@ func (handler *Handler) SetArgInputType(arg string, value string) {
 	handler.argInputType[arg] = value
 }
 
+// SetArgMarkdown renders the form field for arg as textarea
+// with the passed number of rows and a live markdown preview pane.
+// Passing zero or less rows uses DefaultTextareaRows.
+func (handler *Handler) SetArgMarkdown(arg string, rows int) {
+	handler.argInputType[arg] = "markdown"
+	if rows > 0 {
+		handler.argRows[arg] = rows
+	}
+}
+
+// SetArgMultiline renders the form field for arg
+// as textarea with the passed number of rows.
+// Passing zero or less rows resets the field
+// to the input type derived from the argument type.
+func (handler *Handler) SetArgMultiline(arg string, rows int) {
+	if rows <= 0 {
+		delete(handler.argRows, arg)
+		return
+	}
+	handler.argRows[arg] = rows
+}
+
+// SetArgMin sets the min attribute of the form field for arg.
+// Used by input types like number, range, and date.
+func (handler *Handler) SetArgMin(arg string, value any) {
+	handler.argMin[arg] = value
+}
+
+// SetArgMax sets the max attribute of the form field for arg.
+// Used by input types like number, range, and date.
+func (handler *Handler) SetArgMax(arg string, value any) {
+	handler.argMax[arg] = value
+}
+
+// SetArgStep sets the step attribute of the form field for arg.
+// Used by input types like number, range, and date.
+func (handler *Handler) SetArgStep(arg string, value any) {
+	handler.argStep[arg] = value
+}
+
+// SetArgRange renders the form field for arg as range slider
+// with the passed bounds and step.
+func (handler *Handler) SetArgRange(arg string, min, max, step any) {
+	handler.argInputType[arg] = "range"
+	handler.argMin[arg] = min
+	handler.argMax[arg] = max
+	handler.argStep[arg] = step
+}
+
+// SetArgLabel sets the visible label of the form field for arg.
+// Without a label the argument description from the
+// function's doc comment or else the argument name is used as label.
+// With a label set, the argument description
+// is rendered as help text below the form field.
+func (handler *Handler) SetArgLabel(arg, label string) {
+	handler.argLabel[arg] = label
+}
+
+// SetArgGroup puts the form field for arg into a group
+// that is rendered as fieldset with legend as caption.
+// Consecutive arguments with the same group legend
+// are rendered within the same fieldset.
+func (handler *Handler) SetArgGroup(arg, legend string) {
+	handler.argGroup[arg] = legend
+}
+
+// SetFieldTemplate sets a template that renders the form field
+// of the argument arg instead of the default markup of FormTemplate.
+// The template is executed with the field data having the
+// properties .Name, .Label, .Description, .Type, .Value,
+// .Required, and .Options.
+// A field template set for an argument has precedence
+// over one set for its input type with SetInputTypeTemplate.
+func (handler *Handler) SetFieldTemplate(arg string, tmpl *template.Template) {
+	handler.argTemplate[arg] = tmpl
+}
+
+// SetInputTypeTemplate sets a template that renders all form fields
+// of the passed input type instead of the default markup of FormTemplate.
+// See SetFieldTemplate for the data passed to the template.
+func (handler *Handler) SetInputTypeTemplate(inputType string, tmpl *template.Template) {
+	handler.typeTemplate[inputType] = tmpl
+}
+
 func (handler *Handler) SetSubmitButtonText(text string) {
 	handler.form.SubmitButtonText = text
 }
 
+// SetPreventDoubleSubmit enables a one-time submit token
+// that is rendered as hidden field with every form
+// and consumed by the first POST using it,
+// so that a double click on the submit button
+// does not call the wrapped function twice.
+// Tokens that were not used within tokenTTL expire.
+// A tokenTTL of zero or less disables the check.
+func (handler *Handler) SetPreventDoubleSubmit(tokenTTL time.Duration) {
+	if tokenTTL <= 0 {
+		handler.submitTokens = nil
+		return
+	}
+	handler.submitTokens = newSubmitTokens(tokenTTL)
+}
+
+// SetStringScanner sets the scanner used to convert
+// the submitted form values to the argument types
+// of the wrapped function.
+// The default is DefaultStringScanner.
+func (handler *Handler) SetStringScanner(scanner function.StringScanner) {
+	handler.stringScanner = scanner
+}
+
 func (handler *Handler) ServeHTTP(response http.ResponseWriter, request *http.Request) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -115,7 +274,10 @@ func (handler *Handler) ServeHTTP(response http.ResponseWriter, request *http.Re
 }
 
 func (handler *Handler) get(response http.ResponseWriter, _ *http.Request) {
-	handler.form.Fields = nil
+	// Copy form data so concurrent requests don't share rendered fields
+	form := handler.form
+	form.Fields = nil
+	form.Groups = nil
 	for i, argName := range handler.wrappedFunc.ArgNames() {
 		if i == 0 && handler.wrappedFunc.ContextArg() {
 			continue
@@ -128,11 +290,15 @@ func (handler *Handler) get(response http.ResponseWriter, _ *http.Request) {
 			Type:     "text",
 			Required: requiredBasedOnType(argType),
 		}
+		if label, ok := handler.argLabel[argName]; ok {
+			field.Label = label
+			field.Description = argDescription
+		}
 		if field.Label == "" {
 			field.Label = argName
 		}
 		if defaultValue, ok := handler.argDefaultValue[argName]; ok {
-			field.Value = fmt.Sprint(defaultValue)
+			field.Value = formatValue(defaultValue)
 		}
 		if required, ok := handler.argRequired[argName]; ok {
 			field.Required = required
@@ -146,6 +312,12 @@ func (handler *Handler) get(response http.ResponseWriter, _ *http.Request) {
 		case argType.Implements(typeOfFileReader):
 			field.Type = "file"
 
+		case argType.Implements(typeOfColor):
+			field.Type = "color"
+
+		case isJSONType(argType):
+			field.Type = "json"
+
 		// case argType == reflect.TypeOf(date.Date("")) || argType == reflect.TypeOf(date.NullableDate("")):
 		// 	field.Type = "date"
 
@@ -165,14 +337,78 @@ func (handler *Handler) get(response http.ResponseWriter, _ *http.Request) {
 			}
 		}
 
+		if rows, ok := handler.argRows[argName]; ok {
+			field.Type = "textarea"
+			field.Rows = rows
+		}
 		if inputType, ok := handler.argInputType[argName]; ok {
 			field.Type = inputType
 		}
+		if field.Type == "json" {
+			if defaultValue, ok := handler.argDefaultValue[argName]; ok {
+				j, err := json.MarshalIndent(defaultValue, "", "  ")
+				if err != nil {
+					http.Error(response, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				field.Value = string(j)
+			}
+		}
+		if (field.Type == "textarea" || field.Type == "json" || field.Type == "markdown") && field.Rows == 0 {
+			field.Rows = DefaultTextareaRows
+		}
+		if value, ok := handler.argMin[argName]; ok {
+			field.Min = formatValue(value)
+		}
+		if value, ok := handler.argMax[argName]; ok {
+			field.Max = formatValue(value)
+		}
+		if value, ok := handler.argStep[argName]; ok {
+			field.Step = formatValue(value)
+		}
+		if field.Type == "range" && field.Value == "" {
+			// Browsers default to the middle of the range
+			// which would not be the value displayed next to the slider
+			field.Value = field.Min
+			if field.Value == "" {
+				field.Value = "0"
+			}
+		}
 
-		handler.form.Fields = append(handler.form.Fields, field)
+		fieldTemplate, ok := handler.argTemplate[argName]
+		if !ok {
+			fieldTemplate, ok = handler.typeTemplate[field.Type]
+		}
+		if ok {
+			var buf bytes.Buffer
+			err := fieldTemplate.Execute(&buf, &field)
+			if err != nil {
+				http.Error(response, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			field.HTML = template.HTML(buf.String()) //#nosec G203 -- output of html/template is already escaped
+		}
+
+		form.Fields = append(form.Fields, field)
+
+		legend := handler.argGroup[argName]
+		if len(form.Groups) == 0 || form.Groups[len(form.Groups)-1].Legend != legend {
+			form.Groups = append(form.Groups, formGroup{Legend: legend})
+		}
+		group := &form.Groups[len(form.Groups)-1]
+		group.Fields = append(group.Fields, field)
 	}
 
-	err := handler.template.Execute(response, &handler.form)
+	if handler.submitTokens != nil {
+		token, err := handler.submitTokens.New()
+		if err != nil {
+			http.Error(response, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		form.SubmitToken = token
+	}
+
+	err := handler.template.Execute(response, &form)
 	if err != nil {
 		http.Error(response, err.Error(), http.StatusInternalServerError)
 	}
@@ -190,6 +426,8 @@ func (handler *Handler) post(response http.ResponseWriter, request *http.Request
 	for key, vals := range formfs.Form.Value {
 		argsMap[key] = vals[0]
 	}
+	submitToken := argsMap[SubmitTokenFieldName]
+	delete(argsMap, SubmitTokenFieldName)
 	for key := range formfs.Form.File {
 		file, err := formfs.FormFile(key)
 		if err != nil {
@@ -199,7 +437,20 @@ func (handler *Handler) post(response http.ResponseWriter, request *http.Request
 		argsMap[key] = string(file)
 	}
 
-	results, err := handler.wrappedFunc.CallWithNamedStrings(request.Context(), argsMap)
+	var results []any
+	// Consume the submit token only for valid arguments
+	// so that the form can be submitted again after
+	// fixing invalid values
+	args, err := handler.scanArgs(argsMap)
+	if err == nil {
+		if handler.submitTokens != nil && !handler.submitTokens.Consume(submitToken) {
+			http.Error(response, "form already submitted or expired, please reload the form", http.StatusConflict)
+			return
+		}
+		end := function.StartCallEvents(request.Context(), handler.wrappedFunc.Name(), function.TransportHTMLForm)
+		results, err = handler.wrappedFunc.Call(request.Context(), args)
+		end(err)
+	}
 
 	err = handler.resultWriter.WriteResults(results, err, response, request)
 	if err != nil {
@@ -207,6 +458,64 @@ func (handler *Handler) post(response http.ResponseWriter, request *http.Request
 	}
 }
 
+// scanArgs converts the submitted form values in argsMap
+// to the non context arguments of the wrapped function
+// using the string scanner of the handler.
+// Arguments without a form value get their zero value.
+func (handler *Handler) scanArgs(argsMap map[string]string) ([]any, error) {
+	argNames := handler.wrappedFunc.ArgNames()
+	argTypes := handler.wrappedFunc.ArgTypes()
+	if handler.wrappedFunc.ContextArg() {
+		argNames, argTypes = argNames[1:], argTypes[1:]
+	}
+	args := make([]any, len(argNames))
+	for i, argName := range argNames {
+		str, ok := argsMap[argName]
+		if argTypes[i] == typeOfAny {
+			// Pass string directly for argument of type any
+			if ok {
+				args[i] = str
+			}
+			continue
+		}
+		destPtr := reflect.New(argTypes[i])
+		if ok {
+			err := handler.stringScanner.ScanString(str, destPtr.Interface())
+			if err != nil {
+				return nil, function.NewErrParseArgString(err, handler.wrappedFunc, argName)
+			}
+		}
+		args[i] = destPtr.Elem().Interface()
+	}
+	return args, nil
+}
+
+// isJSONType returns true for types that can't be decomposed
+// into simple form fields and are edited as JSON instead.
+// Types that can be unmarshalled from text like time.Time are excluded.
+func isJSONType(t reflect.Type) bool {
+	if reflect.PointerTo(t).Implements(typeOfTextUnmarshaler) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Array:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() != reflect.Uint8
+	}
+	return false
+}
+
+// formatValue formats a value for the value attribute of a form field
+func formatValue(value any) string {
+	switch x := value.(type) {
+	case color.Color:
+		return ColorHexString(x)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
 func requiredBasedOnType(t reflect.Type) bool {
 	if t == reflect.TypeFor[string]() {
 		return false
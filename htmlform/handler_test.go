@@ -0,0 +1,131 @@
+package htmlform
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/domonda/go-function"
+)
+
+// postForm posts values as multipart form to handler
+// and returns the response.
+func postForm(t *testing.T, handler http.Handler, values map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range values {
+		if err := writer.WriteField(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	request := httptest.NewRequest(http.MethodPost, "/", &body)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	return response
+}
+
+var submitTokenRegexp = regexp.MustCompile(`name="` + SubmitTokenFieldName + `" value="([0-9a-f]+)"`)
+
+// getSubmitToken gets the form of handler
+// and returns the submit token rendered into it.
+func getSubmitToken(t *testing.T, handler http.Handler) string {
+	t.Helper()
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/", nil))
+	match := submitTokenRegexp.FindStringSubmatch(response.Body.String())
+	if match == nil {
+		t.Fatalf("no %s field in form:\n%s", SubmitTokenFieldName, response.Body.String())
+	}
+	return match[1]
+}
+
+func TestHandler_PreventDoubleSubmit(t *testing.T) {
+	var calls atomic.Int32
+	errFailed := errors.New("failed")
+	wrapper := function.MustReflectWrapper(func(ctx context.Context, count int, fail bool) error {
+		calls.Add(1)
+		if fail {
+			return errFailed
+		}
+		return nil
+	}, "ctx", "count", "fail")
+	handler := MustNewHandlerOpts(wrapper, "Test", function.RespondStaticPlaintext("OK"), WithPreventDoubleSubmit(time.Minute))
+
+	token := getSubmitToken(t, handler)
+
+	// Invalid arguments don't consume the token
+	response := postForm(t, handler, map[string]string{SubmitTokenFieldName: token, "count": "not a number"})
+	if response.Code == http.StatusOK || response.Code == http.StatusConflict {
+		t.Errorf("invalid argument responded with status %d", response.Code)
+	}
+	if calls.Load() != 0 {
+		t.Fatalf("function called %d times with invalid argument", calls.Load())
+	}
+
+	response = postForm(t, handler, map[string]string{SubmitTokenFieldName: token, "count": "1"})
+	if response.Code != http.StatusOK || response.Body.String() != "OK" {
+		t.Fatalf("valid submit responded with status %d: %s", response.Code, response.Body.String())
+	}
+
+	// Reusing the token is rejected
+	response = postForm(t, handler, map[string]string{SubmitTokenFieldName: token, "count": "1"})
+	if response.Code != http.StatusConflict {
+		t.Errorf("double submit responded with status %d, want %d", response.Code, http.StatusConflict)
+	}
+
+	// Unknown and missing tokens are rejected
+	for _, token := range []string{"unknown", ""} {
+		response = postForm(t, handler, map[string]string{SubmitTokenFieldName: token, "count": "1"})
+		if response.Code != http.StatusConflict {
+			t.Errorf("token %q responded with status %d, want %d", token, response.Code, http.StatusConflict)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("function called %d times, want 1", calls.Load())
+	}
+
+	// An error returned by the function is handled
+	// after the token was consumed by the call
+	token = getSubmitToken(t, handler)
+	response = postForm(t, handler, map[string]string{SubmitTokenFieldName: token, "count": "1", "fail": "true"})
+	if response.Code == http.StatusOK {
+		t.Errorf("function error responded with status %d", response.Code)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("function called %d times, want 2", calls.Load())
+	}
+}
+
+func TestHandler_WithoutSubmitToken(t *testing.T) {
+	var calls atomic.Int32
+	wrapper := function.MustReflectWrapper(func(text string) { calls.Add(1) }, "text")
+	handler := MustNewHandler(wrapper, "Test", function.RespondStaticPlaintext("OK"))
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/", nil))
+	if strings.Contains(response.Body.String(), SubmitTokenFieldName) {
+		t.Errorf("form has %s field without SetPreventDoubleSubmit", SubmitTokenFieldName)
+	}
+	for range 2 {
+		response = postForm(t, handler, map[string]string{"text": "x"})
+		if response.Code != http.StatusOK {
+			t.Errorf("submit responded with status %d", response.Code)
+		}
+	}
+	if calls.Load() != 2 {
+		t.Errorf("function called %d times, want 2", calls.Load())
+	}
+}
@@ -0,0 +1,125 @@
+package htmlform
+
+import (
+	"html/template"
+	"time"
+
+	"github.com/domonda/go-function"
+	"github.com/domonda/go-types"
+)
+
+// HandlerOption configures a Handler created with NewHandlerOpts.
+type HandlerOption func(*Handler)
+
+// NewHandlerOpts returns a new Handler configured with the passed options.
+// All options are applied before the handler is returned,
+// so it is safe to serve it without further synchronization.
+func NewHandlerOpts(wrappedFunc function.Wrapper, title string, resultWriter function.HTTPResultsWriter, opts ...HandlerOption) (*Handler, error) {
+	handler, err := NewHandler(wrappedFunc, title, resultWriter)
+	if err != nil {
+		return nil, err
+	}
+	for _, opt := range opts {
+		opt(handler)
+	}
+	return handler, nil
+}
+
+// MustNewHandlerOpts calls NewHandlerOpts and panics on error.
+func MustNewHandlerOpts(wrappedFunc function.Wrapper, title string, resultWriter function.HTTPResultsWriter, opts ...HandlerOption) *Handler {
+	handler, err := NewHandlerOpts(wrappedFunc, title, resultWriter, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return handler
+}
+
+// WithValidator is the HandlerOption for Handler.SetArgValidator
+func WithValidator(arg string, validator types.ValidatErr) HandlerOption {
+	return func(handler *Handler) { handler.SetArgValidator(arg, validator) }
+}
+
+// WithRequired is the HandlerOption for Handler.SetArgRequired
+func WithRequired(arg string, required bool) HandlerOption {
+	return func(handler *Handler) { handler.SetArgRequired(arg, required) }
+}
+
+// WithOptions is the HandlerOption for Handler.SetArgOptions
+func WithOptions(arg string, options ...Option) HandlerOption {
+	return func(handler *Handler) { handler.SetArgOptions(arg, options) }
+}
+
+// WithDefault is the HandlerOption for Handler.SetArgDefaultValue
+func WithDefault(arg string, value any) HandlerOption {
+	return func(handler *Handler) { handler.SetArgDefaultValue(arg, value) }
+}
+
+// WithInputType is the HandlerOption for Handler.SetArgInputType
+func WithInputType(arg, inputType string) HandlerOption {
+	return func(handler *Handler) { handler.SetArgInputType(arg, inputType) }
+}
+
+// WithMultiline is the HandlerOption for Handler.SetArgMultiline
+func WithMultiline(arg string, rows int) HandlerOption {
+	return func(handler *Handler) { handler.SetArgMultiline(arg, rows) }
+}
+
+// WithMin is the HandlerOption for Handler.SetArgMin
+func WithMin(arg string, value any) HandlerOption {
+	return func(handler *Handler) { handler.SetArgMin(arg, value) }
+}
+
+// WithMax is the HandlerOption for Handler.SetArgMax
+func WithMax(arg string, value any) HandlerOption {
+	return func(handler *Handler) { handler.SetArgMax(arg, value) }
+}
+
+// WithStep is the HandlerOption for Handler.SetArgStep
+func WithStep(arg string, value any) HandlerOption {
+	return func(handler *Handler) { handler.SetArgStep(arg, value) }
+}
+
+// WithRange is the HandlerOption for Handler.SetArgRange
+func WithRange(arg string, min, max, step any) HandlerOption {
+	return func(handler *Handler) { handler.SetArgRange(arg, min, max, step) }
+}
+
+// WithMarkdown is the HandlerOption for Handler.SetArgMarkdown
+func WithMarkdown(arg string, rows int) HandlerOption {
+	return func(handler *Handler) { handler.SetArgMarkdown(arg, rows) }
+}
+
+// WithLabel is the HandlerOption for Handler.SetArgLabel
+func WithLabel(arg, label string) HandlerOption {
+	return func(handler *Handler) { handler.SetArgLabel(arg, label) }
+}
+
+// WithGroup is the HandlerOption for Handler.SetArgGroup
+func WithGroup(arg, legend string) HandlerOption {
+	return func(handler *Handler) { handler.SetArgGroup(arg, legend) }
+}
+
+// WithFieldTemplate is the HandlerOption for Handler.SetFieldTemplate
+func WithFieldTemplate(arg string, tmpl *template.Template) HandlerOption {
+	return func(handler *Handler) { handler.SetFieldTemplate(arg, tmpl) }
+}
+
+// WithInputTypeTemplate is the HandlerOption for Handler.SetInputTypeTemplate
+func WithInputTypeTemplate(inputType string, tmpl *template.Template) HandlerOption {
+	return func(handler *Handler) { handler.SetInputTypeTemplate(inputType, tmpl) }
+}
+
+// WithSubmitButtonText is the HandlerOption for Handler.SetSubmitButtonText
+func WithSubmitButtonText(text string) HandlerOption {
+	return func(handler *Handler) { handler.SetSubmitButtonText(text) }
+}
+
+// WithPreventDoubleSubmit is the HandlerOption for Handler.SetPreventDoubleSubmit
+func WithPreventDoubleSubmit(tokenTTL time.Duration) HandlerOption {
+	return func(handler *Handler) { handler.SetPreventDoubleSubmit(tokenTTL) }
+}
+
+// WithStringScanner is the HandlerOption for Handler.SetStringScanner
+func WithStringScanner(scanner function.StringScanner) HandlerOption {
+	return func(handler *Handler) { handler.SetStringScanner(scanner) }
+}
@@ -0,0 +1,64 @@
+package htmlform
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// SubmitTokenFieldName is the name of the hidden form field
+// that transmits the double-submit prevention token.
+const SubmitTokenFieldName = "_submitToken"
+
+// submitTokens is a server side store of one-time
+// form submit tokens with an expiry time.
+type submitTokens struct {
+	ttl    time.Duration
+	mtx    sync.Mutex
+	tokens map[string]time.Time
+}
+
+func newSubmitTokens(ttl time.Duration) *submitTokens {
+	return &submitTokens{
+		ttl:    ttl,
+		tokens: make(map[string]time.Time),
+	}
+}
+
+// New returns a new random token that is valid
+// until it gets consumed or expires.
+func (s *submitTokens) New() (string, error) {
+	var b [16]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b[:])
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	now := time.Now()
+	for t, expires := range s.tokens {
+		if now.After(expires) {
+			delete(s.tokens, t)
+		}
+	}
+	s.tokens[token] = now.Add(s.ttl)
+	return token, nil
+}
+
+// Consume returns true if the token is valid
+// and removes it so that it can only be used once.
+func (s *submitTokens) Consume(token string) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	expires, ok := s.tokens[token]
+	if !ok {
+		return false
+	}
+	delete(s.tokens, token)
+	return time.Now().Before(expires)
+}
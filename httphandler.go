@@ -33,7 +33,9 @@ func HTTPHandler(getArgs HTTPRequestArgsGetter, function CallWithNamedStringsWra
 			args = a
 		}
 
+		end := StartCallEvents(request.Context(), funcName(function), TransportHTTP)
 		results, err := function.CallWithNamedStrings(request.Context(), args)
+		end(err)
 		if resultsWriter != nil {
 			err = resultsWriter.WriteResults(results, err, response, request)
 		}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -50,6 +51,52 @@ var RespondJSON HTTPResultsWriterFunc = func(results []any, resultErr error, res
 	return err
 }
 
+// RespondNDJSON responds with the items streamed from the results
+// by StreamResults as newline delimited JSON
+// with the content type "application/x-ndjson".
+// The response is flushed after every line so clients
+// receive the items of channel and iterator results as they arrive.
+//
+// An error that happens after the first line was written
+// can't change the response status anymore,
+// it is written as a last line {"error": "message"}.
+var RespondNDJSON HTTPResultsWriterFunc = func(results []any, resultErr error, response http.ResponseWriter, request *http.Request) error {
+	if resultErr != nil || request.Context().Err() != nil {
+		return resultErr
+	}
+	response.Header().Set("Content-Type", "application/x-ndjson")
+	writer := &ndjsonResponseWriter{response: response, controller: http.NewResponseController(response)}
+	err := StreamResults(request.Context(), results, func(result any) error {
+		return writeJSONLine(writer, result)
+	})
+	if err != nil && writer.written {
+		return writeJSONLine(writer, struct {
+			Error string `json:"error"`
+		}{err.Error()})
+	}
+	return err
+}
+
+type ndjsonResponseWriter struct {
+	response   http.ResponseWriter
+	controller *http.ResponseController
+	written    bool
+}
+
+func (w *ndjsonResponseWriter) Write(p []byte) (int, error) {
+	w.written = true
+	return w.response.Write(p)
+}
+
+func (w *ndjsonResponseWriter) Flush() error {
+	err := w.controller.Flush()
+	if errors.Is(err, http.ErrNotSupported) {
+		// Respond without streaming
+		return nil
+	}
+	return err
+}
+
 // RespondBinary responds with contentType using the binary data from results of type []byte, string, or io.Reader.
 func RespondBinary(contentType string) HTTPResultsWriterFunc {
 	return func(results []any, resultErr error, response http.ResponseWriter, request *http.Request) (err error) {
@@ -97,6 +144,30 @@ func RespondJSONField(fieldName string) HTTPResultsWriterFunc {
 	}
 }
 
+// RespondJSONObject responds with the results as JSON object
+// with the ResultNames of wrapper as field names
+// if it implements ResultNamer, else the fields are named
+// "result0", "result1", and so on.
+func RespondJSONObject(wrapper any) HTTPResultsWriterFunc {
+	return func(results []any, resultErr error, response http.ResponseWriter, request *http.Request) (err error) {
+		if resultErr != nil || request.Context().Err() != nil {
+			return resultErr
+		}
+		names := resultNames(wrapper, len(results))
+		m := make(map[string]any, len(results))
+		for i, result := range results {
+			m[names[i]] = result
+		}
+		j, err := encodeJSON(m)
+		if err != nil {
+			return err
+		}
+		response.Header().Set("Content-Type", contenttype.JSON)
+		_, err = response.Write(j)
+		return err
+	}
+}
+
 var RespondXML HTTPResultsWriterFunc = func(results []any, resultErr error, response http.ResponseWriter, request *http.Request) error {
 	if resultErr != nil || request.Context().Err() != nil {
 		return resultErr
@@ -0,0 +1,97 @@
+package function
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type resultNamerWrapper struct{}
+
+func (resultNamerWrapper) ResultNames() []string { return []string{"head", "tail"} }
+
+func TestRespondJSONObject(t *testing.T) {
+	tests := []struct {
+		name    string
+		wrapper any
+		results []any
+		want    string
+	}{
+		{name: "ResultNamer", wrapper: resultNamerWrapper{}, results: []any{"a", 1}, want: `{"head":"a","tail":1}`},
+		{name: "no ResultNamer", wrapper: nil, results: []any{"a", 1}, want: `{"result0":"a","result1":1}`},
+		{name: "wrong number of names", wrapper: resultNamerWrapper{}, results: []any{"a"}, want: `{"result0":"a"}`},
+		{name: "no results", wrapper: resultNamerWrapper{}, results: nil, want: `{}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			request := httptest.NewRequest("GET", "/", nil)
+			err := RespondJSONObject(tt.wrapper).WriteResults(tt.results, nil, response, request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got bytes.Buffer
+			if err := json.Compact(&got, response.Body.Bytes()); err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("RespondJSONObject() wrote %s, want %s", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRespondNDJSON(t *testing.T) {
+	errStop := errors.New("stop")
+	seq2Err := func(yield func(string, error) bool) {
+		_ = yield("a", nil) && yield("", errStop)
+	}
+	tests := []struct {
+		name    string
+		results []any
+		want    string
+		wantErr bool
+	}{
+		{name: "slice", results: []any{[]int{1, 2}}, want: "[1,2]\n"},
+		{name: "iterator error after first line", results: []any{seq2Err}, want: "\"a\"\n{\"error\":\"stop\"}\n"},
+		{name: "iterator error before first line", results: []any{func(yield func(int, error) bool) { yield(0, errStop) }}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			request := httptest.NewRequest("GET", "/", nil)
+			err := RespondNDJSON.WriteResults(tt.results, nil, response, request)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RespondNDJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := response.Body.String(); got != tt.want {
+				t.Errorf("RespondNDJSON() wrote %q, want %q", got, tt.want)
+			}
+			if !tt.wantErr && !response.Flushed {
+				t.Error("RespondNDJSON() did not flush the response")
+			}
+		})
+	}
+}
+
+// nonFlushingResponseWriter hides the Flush method
+// of the wrapped http.ResponseWriter
+type nonFlushingResponseWriter struct {
+	http.ResponseWriter
+}
+
+func TestRespondNDJSON_nonFlushing(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	response := nonFlushingResponseWriter{recorder}
+	request := httptest.NewRequest("GET", "/", nil)
+	err := RespondNDJSON.WriteResults([]any{func(yield func(int) bool) { _ = yield(1) && yield(2) }}, nil, response, request)
+	if err != nil {
+		t.Fatalf("RespondNDJSON() error = %v", err)
+	}
+	if got, want := recorder.Body.String(), "1\n2\n"; got != want {
+		t.Errorf("RespondNDJSON() wrote %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,125 @@
+// Package jobs defers calls of function wrappers
+// to background workers with retries.
+//
+// Calls are enqueued with the name of a wrapper
+// and the JSON of its arguments into a Store
+// and executed by a Worker that looks up
+// the wrapper by name in a Registry.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/domonda/go-function"
+)
+
+// Status of a Job
+type Status string
+
+const (
+	// StatusPending jobs are waiting for their next attempt
+	StatusPending Status = "pending"
+	// StatusRunning jobs are claimed by a worker
+	// until their LeaseUntil time
+	StatusRunning Status = "running"
+	// StatusSucceeded jobs were called without an error
+	StatusSucceeded Status = "succeeded"
+	// StatusFailed jobs failed and will not be retried
+	StatusFailed Status = "failed"
+)
+
+// Job is a deferred call of a function wrapper.
+type Job struct {
+	ID string `json:"id"`
+	// Wrapper is the name of the wrapper in the Registry of the Worker
+	Wrapper string `json:"wrapper"`
+	// Args is the JSON object or array of the arguments
+	// as accepted by function.CallWithJSONWrapper
+	Args json.RawMessage `json:"args"`
+	// Status of the job
+	Status Status `json:"status"`
+	// Attempts is the number of started calls
+	Attempts int `json:"attempts"`
+	// RunAt is the earliest time of the next attempt
+	RunAt time.Time `json:"runAt"`
+	// LeaseUntil is the time until a running job is claimed by a worker.
+	// A running job that was not updated until then is claimed again
+	// because its worker is assumed to have crashed.
+	LeaseUntil time.Time `json:"leaseUntil"`
+	// LastError is the error message of the last failed attempt
+	LastError string `json:"lastError,omitempty"`
+	// Created is the time the job was enqueued
+	Created time.Time `json:"created"`
+	// Updated is the time the job was last changed
+	Updated time.Time `json:"updated"`
+}
+
+func (job *Job) String() string {
+	return fmt.Sprintf("job %s %s(%s) %s", job.ID, job.Wrapper, job.Args, job.Status)
+}
+
+// Registry maps the wrapper names of jobs
+// to the function wrappers called by a Worker.
+type Registry map[string]function.Wrapper
+
+// DefaultStore is the Store used by Enqueue.
+var DefaultStore Store = NewMemoryStore()
+
+// Enqueue adds a job calling the wrapper registered as wrapperName
+// with argsJSON to DefaultStore and returns it.
+// See EnqueueTo.
+func Enqueue(ctx context.Context, wrapperName string, argsJSON []byte) (*Job, error) {
+	return EnqueueTo(ctx, DefaultStore, wrapperName, argsJSON)
+}
+
+// EnqueueTo adds a job calling the wrapper registered as wrapperName
+// with argsJSON to store and returns it.
+// The job is due immediately.
+// An error is returned if wrapperName is empty
+// or argsJSON is not valid JSON.
+// Empty argsJSON is enqueued as empty JSON object.
+func EnqueueTo(ctx context.Context, store Store, wrapperName string, argsJSON []byte) (*Job, error) {
+	if wrapperName == "" {
+		return nil, errors.New("jobs: empty wrapper name")
+	}
+	if len(argsJSON) == 0 {
+		argsJSON = []byte("{}")
+	}
+	if !json.Valid(argsJSON) {
+		return nil, fmt.Errorf("jobs: invalid arguments JSON for %s", wrapperName)
+	}
+	id, err := newJobID()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	job := &Job{
+		ID:      id,
+		Wrapper: wrapperName,
+		Args:    append(json.RawMessage(nil), argsJSON...),
+		Status:  StatusPending,
+		RunAt:   now,
+		Created: now,
+		Updated: now,
+	}
+	err = store.Add(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func newJobID() (string, error) {
+	var id [16]byte
+	_, err := rand.Read(id[:])
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id[:]), nil
+}
@@ -0,0 +1,275 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/domonda/go-function"
+)
+
+func TestWorker_RunNext(t *testing.T) {
+	var flakyCalls int
+	registry := Registry{
+		"Sum": function.MustReflectWrapper(func(a, b int) int { return a + b }, "a", "b"),
+		"Flaky": function.MustReflectWrapper(func() error {
+			flakyCalls++
+			if flakyCalls < 3 {
+				return errors.New("flaky")
+			}
+			return nil
+		}),
+		"Broken": function.MustReflectWrapper(func() error { return errors.New("broken") }),
+	}
+	store := NewMemoryStore()
+	worker := NewWorker(registry, store, 1)
+	worker.RetryPolicy = RetryPolicy{MaxAttempts: 3}
+	var sums []any
+	worker.ResultsHandler = function.ResultsHandlerFunc(func(ctx context.Context, results []any, resultErr error) error {
+		if info := function.CallInfoFromContext(ctx); info != nil && info.Name == "Sum" {
+			sums = append(sums, results...)
+		}
+		return resultErr
+	})
+
+	ctx := context.Background()
+	enqueue := func(wrapperName, argsJSON string) *Job {
+		t.Helper()
+		job, err := EnqueueTo(ctx, store, wrapperName, []byte(argsJSON))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return job
+	}
+	sum := enqueue("Sum", `{"a": 1, "b": 2}`)
+	sumArray := enqueue("Sum", `[3, 4]`)
+	badArgs := enqueue("Sum", `{"a": "x"}`)
+	flaky := enqueue("Flaky", ``)
+	broken := enqueue("Broken", `{}`)
+	unknown := enqueue("Unknown", `{}`)
+
+	for {
+		ok, err := worker.RunNext(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+	}
+
+	want := map[string]struct {
+		status   Status
+		attempts int
+	}{
+		sum.ID:      {StatusSucceeded, 1},
+		sumArray.ID: {StatusSucceeded, 1},
+		badArgs.ID:  {StatusFailed, 1},
+		flaky.ID:    {StatusSucceeded, 3},
+		broken.ID:   {StatusFailed, 3},
+		unknown.ID:  {StatusFailed, 1},
+	}
+	for _, job := range store.Jobs() {
+		if job.Status != want[job.ID].status || job.Attempts != want[job.ID].attempts {
+			t.Errorf("%s with %d attempts, want %s with %d attempts", job, job.Attempts, want[job.ID].status, want[job.ID].attempts)
+		}
+		if (job.Status == StatusFailed) != (job.LastError != "") {
+			t.Errorf("%s has LastError %q", job, job.LastError)
+		}
+	}
+	if len(sums) != 2 || sums[0] != 3 || sums[1] != 7 {
+		t.Errorf("got sums %v, want [3 7]", sums)
+	}
+}
+
+func TestWorker_Run(t *testing.T) {
+	var calls atomic.Int32
+	registry := Registry{
+		"Count": function.MustReflectWrapper(func() { calls.Add(1) }),
+	}
+	store := NewMemoryStore()
+	for range 10 {
+		_, err := EnqueueTo(context.Background(), store, "Count", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	worker := NewWorker(registry, store, 3)
+	worker.PollInterval = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() { worker.Run(ctx); close(done) }()
+	for calls.Load() < 10 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+	for _, job := range store.Jobs() {
+		if job.Status != StatusSucceeded {
+			t.Errorf("%s not succeeded", job)
+		}
+	}
+}
+
+// failingStore returns an error from Claim
+// for the first fails calls.
+type failingStore struct {
+	Store
+	fails atomic.Int32
+}
+
+func (s *failingStore) Claim(ctx context.Context, now time.Time, lease time.Duration) (*Job, error) {
+	if s.fails.Add(-1) >= 0 {
+		return nil, errors.New("claim failed")
+	}
+	return s.Store.Claim(ctx, now, lease)
+}
+
+func TestWorker_Run_storeError(t *testing.T) {
+	var calls atomic.Int32
+	registry := Registry{
+		"Count": function.MustReflectWrapper(func() { calls.Add(1) }),
+	}
+	memStore := NewMemoryStore()
+	for range 10 {
+		_, err := EnqueueTo(context.Background(), memStore, "Count", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	store := &failingStore{Store: memStore}
+	store.fails.Store(5)
+	var storeErrs atomic.Int32
+	worker := NewWorker(registry, store, 3)
+	worker.PollInterval = time.Millisecond
+	worker.StoreErrorHandler = func(ctx context.Context, err error) { storeErrs.Add(1) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() { worker.Run(ctx); close(done) }()
+	for calls.Load() < 10 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+	if storeErrs.Load() != 5 {
+		t.Errorf("StoreErrorHandler called %d times, want 5", storeErrs.Load())
+	}
+}
+
+func TestMemoryStore_Claim_lease(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	job, err := EnqueueTo(ctx, store, "Count", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := job.RunAt
+
+	first, err := store.Claim(ctx, now, time.Minute)
+	if err != nil || first == nil {
+		t.Fatalf("Claim() = %v, %v", first, err)
+	}
+	if first.Status != StatusRunning || first.Attempts != 1 || !first.LeaseUntil.Equal(now.Add(time.Minute)) {
+		t.Errorf("claimed %s with %d attempts until %s", first, first.Attempts, first.LeaseUntil)
+	}
+	if again, _ := store.Claim(ctx, now.Add(30*time.Second), time.Minute); again != nil {
+		t.Fatalf("claimed %s again before lease expired", again)
+	}
+
+	// The running job is claimed again after the lease expired
+	second, err := store.Claim(ctx, now.Add(2*time.Minute), time.Minute)
+	if err != nil || second == nil {
+		t.Fatalf("Claim() after lease = %v, %v", second, err)
+	}
+	if second.ID != job.ID || second.Attempts != 2 {
+		t.Errorf("claimed %s with %d attempts after lease", second, second.Attempts)
+	}
+
+	// The update of the first claim is rejected
+	first.Status = StatusSucceeded
+	err = store.Update(ctx, first)
+	if !errors.Is(err, ErrLeaseExpired) {
+		t.Errorf("Update() of expired claim error = %v, want ErrLeaseExpired", err)
+	}
+	second.Status = StatusSucceeded
+	err = store.Update(ctx, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := store.Claim(ctx, now.Add(time.Hour), time.Minute); again != nil {
+		t.Errorf("claimed finished %s", again)
+	}
+}
+
+func TestWorker_RunNext_expiredLease(t *testing.T) {
+	var calls atomic.Int32
+	registry := Registry{
+		"Count": function.MustReflectWrapper(func() { calls.Add(1) }),
+	}
+	ctx := context.Background()
+	store := NewMemoryStore()
+	worker := NewWorker(registry, store, 1)
+	worker.RetryPolicy = RetryPolicy{MaxAttempts: 2}
+
+	// Jobs of a worker that crashed during the first
+	// and the second attempt of the jobs
+	for attempts := range 2 {
+		job, err := EnqueueTo(ctx, store, "Count", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		job.Status = StatusRunning
+		job.Attempts = attempts + 1
+		job.LeaseUntil = time.Now().Add(-time.Minute)
+		err = store.Update(ctx, job)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for {
+		ok, err := worker.RunNext(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("%d calls, want 1", calls.Load())
+	}
+	jobs := store.Jobs()
+	if jobs[0].Status != StatusSucceeded || jobs[0].Attempts != 2 {
+		t.Errorf("%s with %d attempts, want succeeded with 2 attempts", jobs[0], jobs[0].Attempts)
+	}
+	if jobs[1].Status != StatusFailed || jobs[1].Attempts != 3 || jobs[1].LastError == "" {
+		t.Errorf("%s with %d attempts and error %q, want failed with 3 attempts", jobs[1], jobs[1].Attempts, jobs[1].LastError)
+	}
+}
+
+func TestEnqueueTo_invalid(t *testing.T) {
+	store := NewMemoryStore()
+	if _, err := EnqueueTo(context.Background(), store, "", nil); err == nil {
+		t.Error("expected error for empty wrapper name")
+	}
+	if _, err := EnqueueTo(context.Background(), store, "Sum", []byte(`{`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+	if len(store.Jobs()) != 0 {
+		t.Error("invalid jobs added to store")
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(time.Second, 5*time.Second)
+	for attempt, want := range []time.Duration{time.Second, time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		if got := backoff(attempt); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", attempt, got, want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrLeaseExpired is returned by Store.Update for a job
+// that was claimed again after its lease expired.
+var ErrLeaseExpired = errors.New("jobs: lease expired")
+
+// Store persists jobs.
+// Implementations must be safe for concurrent use
+// and must not return a job from Claim to more than one caller
+// before its lease expired.
+type Store interface {
+	// Add persists a new job.
+	Add(ctx context.Context, job *Job) error
+
+	// Claim returns the pending job with the earliest RunAt
+	// that is not after now, or a running job with an expired LeaseUntil,
+	// with its status changed to StatusRunning,
+	// its Attempts incremented and LeaseUntil set to now plus lease,
+	// or nil if no job is due.
+	Claim(ctx context.Context, now time.Time, lease time.Duration) (*Job, error)
+
+	// Update persists the changed status,
+	// RunAt and LastError of a claimed job.
+	// ErrLeaseExpired is returned if the job
+	// was claimed again after its lease expired.
+	Update(ctx context.Context, job *Job) error
+}
+
+// MemoryStore is a Store keeping jobs in memory
+// for tests and single process applications
+// where jobs don't have to survive a restart.
+type MemoryStore struct {
+	mtx  sync.Mutex
+	jobs []*Job // in order of adding
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (s *MemoryStore) Add(ctx context.Context, job *Job) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.index(job.ID) != -1 {
+		return fmt.Errorf("jobs: job %s already added", job.ID)
+	}
+	s.jobs = append(s.jobs, cloneJob(job))
+	return nil
+}
+
+func (s *MemoryStore) Claim(ctx context.Context, now time.Time, lease time.Duration) (*Job, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var (
+		next    *Job
+		nextDue time.Time
+	)
+	for _, job := range s.jobs {
+		var due time.Time
+		switch job.Status {
+		case StatusPending:
+			due = job.RunAt
+		case StatusRunning:
+			due = job.LeaseUntil
+		default:
+			continue
+		}
+		if !due.After(now) && (next == nil || due.Before(nextDue)) {
+			next, nextDue = job, due
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+	next.Status = StatusRunning
+	next.Attempts++
+	next.LeaseUntil = now.Add(lease)
+	next.Updated = now
+	return cloneJob(next), nil
+}
+
+func (s *MemoryStore) Update(ctx context.Context, job *Job) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	i := s.index(job.ID)
+	if i == -1 {
+		return fmt.Errorf("jobs: job %s not found", job.ID)
+	}
+	if stored := s.jobs[i]; stored.Status == StatusRunning && !stored.LeaseUntil.Equal(job.LeaseUntil) {
+		return fmt.Errorf("%w: %s", ErrLeaseExpired, job)
+	}
+	s.jobs[i] = cloneJob(job)
+	return nil
+}
+
+// Jobs returns copies of all jobs in the order they were added.
+func (s *MemoryStore) Jobs() []*Job {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	jobs := make([]*Job, len(s.jobs))
+	for i, job := range s.jobs {
+		jobs[i] = cloneJob(job)
+	}
+	return jobs
+}
+
+func (s *MemoryStore) index(id string) int {
+	return slices.IndexFunc(s.jobs, func(job *Job) bool { return job.ID == id })
+}
+
+func cloneJob(job *Job) *Job {
+	clone := *job
+	clone.Args = slices.Clone(job.Args)
+	return &clone
+}
@@ -0,0 +1,222 @@
+package jobs
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/domonda/go-function"
+)
+
+// RetryPolicy decides if and when failed jobs are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of calls of a job,
+	// jobs are not retried if it is less than two.
+	MaxAttempts int
+	// Backoff returns the delay before the next attempt
+	// after attempt number attempt failed.
+	// Jobs are retried immediately if nil.
+	Backoff func(attempt int) time.Duration
+}
+
+// DefaultRetryPolicy retries jobs up to 5 attempts
+// with exponential backoff starting at one second.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	Backoff:     ExponentialBackoff(time.Second, time.Hour),
+}
+
+// ExponentialBackoff returns a RetryPolicy.Backoff function
+// doubling the delay starting with initial for every attempt
+// up to a maximum delay.
+func ExponentialBackoff(initial, maximum time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		delay := initial
+		for i := 1; i < attempt && delay < maximum; i++ {
+			delay *= 2
+		}
+		return min(delay, maximum)
+	}
+}
+
+// DefaultLease is the Lease used by NewWorker
+// and by workers with a Lease of zero.
+const DefaultLease = 10 * time.Minute
+
+// Worker calls the jobs of a Store with the wrappers of a Registry.
+//
+// Jobs failing with an error are retried as configured
+// by the RetryPolicy except for argument errors
+// of the function package that would fail again,
+// and jobs with wrapper names that are not in the Registry.
+// Jobs that are not updated within the Lease because
+// their worker crashed are claimed again as new attempt.
+type Worker struct {
+	Registry    Registry
+	Store       Store
+	Concurrency int
+
+	// RetryPolicy for failed jobs
+	RetryPolicy RetryPolicy
+	// Lease is the duration a claimed job is reserved for the worker,
+	// it must be longer than the longest call of a job
+	Lease time.Duration
+	// PollInterval is the wait time before looking
+	// for new jobs if no job was due or after a Store error
+	PollInterval time.Duration
+	// StoreErrorHandler is called by Run with the errors of the Store
+	// if not nil, else the errors are printed to os.Stderr
+	StoreErrorHandler func(ctx context.Context, err error)
+	// ResultsHandler is called with the results of every job call
+	// if not nil, its context has a function.CallInfo
+	// with the wrapper name and arguments JSON.
+	// An error returned by the handler fails the attempt.
+	ResultsHandler function.ResultsHandler
+}
+
+// NewWorker returns a Worker calling the jobs of store
+// with the wrappers of registry from concurrency goroutines
+// using DefaultRetryPolicy, DefaultLease
+// and a PollInterval of one second.
+// Call Run to start the worker.
+func NewWorker(registry Registry, store Store, concurrency int) *Worker {
+	return &Worker{
+		Registry:     registry,
+		Store:        store,
+		Concurrency:  max(concurrency, 1),
+		RetryPolicy:  DefaultRetryPolicy,
+		Lease:        DefaultLease,
+		PollInterval: time.Second,
+	}
+}
+
+// Run calls due jobs until ctx is canceled
+// and returns after all running jobs have been updated.
+// Errors of the Store are passed to the StoreErrorHandler
+// and don't stop the worker, the goroutine that got
+// the error waits the PollInterval before claiming the next job.
+func (w *Worker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for range max(w.Concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				ok, err := w.RunNext(ctx)
+				if err != nil && ctx.Err() == nil {
+					w.handleStoreError(ctx, err)
+				}
+				if !ok || err != nil {
+					select {
+					case <-ctx.Done():
+					case <-time.After(w.PollInterval):
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (w *Worker) handleStoreError(ctx context.Context, err error) {
+	if w.StoreErrorHandler != nil {
+		w.StoreErrorHandler(ctx, err)
+		return
+	}
+	fmt.Fprintln(os.Stderr, "jobs worker error:", err)
+}
+
+// RunNext claims and calls the next due job of the Store.
+// It returns false if no job was due.
+// An error is only returned for Store errors,
+// errors of the job calls are recorded in the jobs.
+// A job claimed again after its lease expired
+// fails without a call if it has no attempts left.
+func (w *Worker) RunNext(ctx context.Context) (ok bool, err error) {
+	job, err := w.Store.Claim(ctx, time.Now(), cmp.Or(w.Lease, DefaultLease))
+	if err != nil || job == nil {
+		return false, err
+	}
+	var callErr error
+	if job.Attempts > max(w.RetryPolicy.MaxAttempts, 1) {
+		callErr = fmt.Errorf("jobs: lease of attempt %d expired", job.Attempts-1)
+	} else {
+		callErr = w.call(ctx, job)
+	}
+
+	job.Updated = time.Now()
+	switch {
+	case callErr == nil:
+		job.Status = StatusSucceeded
+		job.LastError = ""
+	case w.retry(job, callErr):
+		job.Status = StatusPending
+		job.LastError = callErr.Error()
+		if w.RetryPolicy.Backoff != nil {
+			job.RunAt = job.Updated.Add(w.RetryPolicy.Backoff(job.Attempts))
+		} else {
+			job.RunAt = job.Updated
+		}
+	default:
+		job.Status = StatusFailed
+		job.LastError = callErr.Error()
+	}
+	// Update the job even if ctx was canceled during the call
+	return true, w.Store.Update(context.WithoutCancel(ctx), job)
+}
+
+func (w *Worker) call(ctx context.Context, job *Job) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("jobs: panic in %s: %v", job.Wrapper, p)
+		}
+	}()
+
+	wrapper, ok := w.Registry[job.Wrapper]
+	if !ok {
+		return errUnknownWrapper{job.Wrapper}
+	}
+	end := function.StartCallEvents(ctx, job.Wrapper, function.TransportJobs)
+	results, resultErr := wrapper.CallWithJSON(ctx, job.Args)
+	end(resultErr)
+	if w.ResultsHandler == nil {
+		return resultErr
+	}
+	ctx = function.ContextWithCallInfo(ctx, function.CallInfo{Name: job.Wrapper, JSON: job.Args})
+	return w.ResultsHandler.HandleResults(ctx, results, resultErr)
+}
+
+func (w *Worker) retry(job *Job, err error) bool {
+	if job.Attempts >= w.RetryPolicy.MaxAttempts {
+		return false
+	}
+	var (
+		unknownWrapper errUnknownWrapper
+		argString      function.ErrParseArgString
+		argJSON        function.ErrParseArgJSON
+		argsJSON       function.ErrParseArgsJSON
+		unknownArgs    function.ErrUnknownArgs
+	)
+	switch {
+	case errors.As(err, &unknownWrapper),
+		errors.As(err, &argString),
+		errors.As(err, &argJSON),
+		errors.As(err, &argsJSON),
+		errors.As(err, &unknownArgs):
+		// Would fail the same way again
+		return false
+	}
+	return true
+}
+
+type errUnknownWrapper struct {
+	name string
+}
+
+func (e errUnknownWrapper) Error() string {
+	return fmt.Sprintf("jobs: no wrapper %q in registry", e.name)
+}
@@ -0,0 +1,28 @@
+package function
+
+import (
+	"context"
+	"io"
+	"os"
+)
+
+type outputCtxKey struct{}
+
+// ContextWithOutput returns a context with writer as output
+// for the results handlers that print to os.Stdout by default,
+// like Println, PrintColorJSON, PrintTable or PrintStream.
+// Command dispatchers use it to redirect the output
+// of a single call, for example through a pager,
+// without changing os.Stdout for the whole process.
+func ContextWithOutput(ctx context.Context, writer io.Writer) context.Context {
+	return context.WithValue(ctx, outputCtxKey{}, writer)
+}
+
+// OutputFromContext returns the writer added
+// with ContextWithOutput to ctx or os.Stdout.
+func OutputFromContext(ctx context.Context) io.Writer {
+	if writer, ok := ctx.Value(outputCtxKey{}).(io.Writer); ok {
+		return writer
+	}
+	return os.Stdout
+}
@@ -0,0 +1,25 @@
+package function
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestContextWithOutput(t *testing.T) {
+	if OutputFromContext(context.Background()) != os.Stdout {
+		t.Error("OutputFromContext() without output is not os.Stdout")
+	}
+	var output strings.Builder
+	ctx := ContextWithOutput(context.Background(), &output)
+	handlers := []ResultsHandler{Println, PrintlnWithPrefix("-"), PrintlnText("done"), PrintTemplate("{{first}}\n"), PrintStream}
+	for _, handler := range handlers {
+		if err := handler.HandleResults(ctx, []any{"a"}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := "a\n- a\ndone\na\na\n"; output.String() != want {
+		t.Errorf("handlers wrote %q, want %q", output.String(), want)
+	}
+}
@@ -0,0 +1,232 @@
+package function
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PrintableResult returns the readable representation of result
+// as printed by the Print and Println results handlers.
+// See AppendPrintableResult.
+func PrintableResult(result any) (string, error) {
+	buf := getPrintBuffer()
+	defer putPrintBuffer(buf)
+
+	b, err := AppendPrintableResult((*buf)[:0], result)
+	*buf = b
+	return string(b), err
+}
+
+// AppendPrintableResult appends the readable representation of result
+// as printed by the Print and Println results handlers to buf
+// and returns the extended buffer:
+//
+//   - Strings, integers and bools are formatted without reflection.
+//   - Floats are formatted with up to 12 decimals.
+//   - []string is printed as lines.
+//   - []byte is printed as string if it is valid printable UTF-8,
+//     else as hex number.
+//   - [][]string is printed as padded text table.
+//   - The GoString or String methods of fmt.GoStringer and fmt.Stringer
+//     implementations are used.
+//   - Structs, maps, slices and arrays are printed as indented JSON.
+//   - Functions and channels are printed in Go syntax.
+//   - Other types are formatted with fmt.Append.
+func AppendPrintableResult(buf []byte, result any) ([]byte, error) {
+	b, ok, err := appendPrintableResult(buf, result)
+	if !ok {
+		return fmt.Append(buf, result), nil
+	}
+	return b, err
+}
+
+// appendPrintableResult implements AppendPrintableResult
+// but returns false for ok if result has no special
+// representation and is formatted by the fmt package.
+func appendPrintableResult(buf []byte, result any) (b []byte, ok bool, err error) {
+	// Types without methods first,
+	// so no interface checks are needed for them
+	switch x := result.(type) {
+	case string:
+		return append(buf, x...), true, nil
+	case int:
+		return strconv.AppendInt(buf, int64(x), 10), true, nil
+	case int8:
+		return strconv.AppendInt(buf, int64(x), 10), true, nil
+	case int16:
+		return strconv.AppendInt(buf, int64(x), 10), true, nil
+	case int32:
+		return strconv.AppendInt(buf, int64(x), 10), true, nil
+	case int64:
+		return strconv.AppendInt(buf, x, 10), true, nil
+	case uint:
+		return strconv.AppendUint(buf, uint64(x), 10), true, nil
+	case uint8:
+		return strconv.AppendUint(buf, uint64(x), 10), true, nil
+	case uint16:
+		return strconv.AppendUint(buf, uint64(x), 10), true, nil
+	case uint32:
+		return strconv.AppendUint(buf, uint64(x), 10), true, nil
+	case uint64:
+		return strconv.AppendUint(buf, x, 10), true, nil
+	case bool:
+		return strconv.AppendBool(buf, x), true, nil
+	case float64:
+		return appendPrintableFloat(buf, x, 64), true, nil
+	case float32:
+		return appendPrintableFloat(buf, float64(x), 32), true, nil
+
+	case []string:
+		for i, s := range x {
+			if i > 0 {
+				buf = append(buf, '\n')
+			}
+			buf = append(buf, s...)
+		}
+		return buf, true, nil
+
+	case []byte:
+		if utf8.Valid(x) && bytes.IndexFunc(x, func(r rune) bool { return !unicode.IsPrint(r) }) == -1 {
+			return append(buf, x...), true, nil
+		}
+		return fmt.Appendf(buf, "%#x", x), true, nil
+
+	case []int:
+		return appendPrintableInts(buf, x), true, nil
+	case []int64:
+		return appendPrintableInts(buf, x), true, nil
+
+	case [][]string:
+		w := bytes.NewBuffer(buf)
+		err := writePaddedTextTable(w, x, "|")
+		if err != nil {
+			return buf, true, fmt.Errorf("can't print command result padded text table because: %w", err)
+		}
+		return w.Bytes(), true, nil
+
+	case fmt.GoStringer:
+		return append(buf, x.GoString()...), true, nil
+
+	case fmt.Stringer:
+		return append(buf, x.String()...), true, nil
+	}
+
+	switch v := derefValue(reflect.ValueOf(result)); v.Kind() {
+	case reflect.Float32:
+		return appendPrintableFloat(buf, v.Float(), 32), true, nil
+
+	case reflect.Float64:
+		return appendPrintableFloat(buf, v.Float(), 64), true, nil
+
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		b, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return buf, true, fmt.Errorf("can't print command result as JSON because: %w", err)
+		}
+		return append(buf, b...), true, nil
+
+	case reflect.Func, reflect.Chan:
+		// Use Go source representation for functional types
+		// that have no useful printable value
+		return fmt.Appendf(buf, "%#v", result), true, nil
+	}
+	return buf, false, nil
+}
+
+// appendPrintableFloat appends f with up to 12 decimals precission
+// and trailing zeros removed.
+func appendPrintableFloat(buf []byte, f float64, bitSize int) []byte {
+	buf = strconv.AppendFloat(buf, f, 'f', 12, bitSize)
+	return bytes.TrimRight(buf, "0")
+}
+
+// appendPrintableInts appends ints as indented JSON array
+// like json.MarshalIndent with two spaces indentation.
+func appendPrintableInts[T int | int64](buf []byte, ints []T) []byte {
+	if ints == nil {
+		return append(buf, "null"...)
+	}
+	if len(ints) == 0 {
+		return append(buf, "[]"...)
+	}
+	buf = append(buf, '[')
+	for i, n := range ints {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, "\n  "...)
+		buf = strconv.AppendInt(buf, int64(n), 10)
+	}
+	return append(buf, "\n]"...)
+}
+
+// makeResultsPrintable converts the elements of the results slice
+// to strings with a readable representation
+// if they are not already printed like that by the fmt package.
+// It modifies the results slice in place.
+func makeResultsPrintable(results []any) error {
+	for i, result := range results {
+		switch result.(type) {
+		case nil, string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, bool:
+			// Printed the same by the fmt package
+			continue
+		}
+		buf := getPrintBuffer()
+		b, ok, err := appendPrintableResult((*buf)[:0], result)
+		if ok && err == nil {
+			results[i] = string(b)
+		}
+		*buf = b
+		putPrintBuffer(buf)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var printBufferPool = sync.Pool{
+	New: func() any { return new([]byte) },
+}
+
+func getPrintBuffer() *[]byte {
+	return printBufferPool.Get().(*[]byte)
+}
+
+// putPrintBuffer returns buf to the pool
+// unless it grew too big to be kept.
+func putPrintBuffer(buf *[]byte) {
+	if cap(*buf) > 64*1024 {
+		return
+	}
+	*buf = (*buf)[:0]
+	printBufferPool.Put(buf)
+}
+
+// writePrintableLines writes every result in its printable representation
+// prefixed by linePrefix and followed by a newline to writer.
+func writePrintableLines(writer io.Writer, linePrefix string, results []any) error {
+	buf := getPrintBuffer()
+	defer putPrintBuffer(buf)
+
+	for _, result := range results {
+		b, err := AppendPrintableResult(append((*buf)[:0], linePrefix...), result)
+		if err != nil {
+			return err
+		}
+		b = append(b, '\n')
+		*buf = b
+		_, err = writer.Write(b)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
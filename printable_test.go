@@ -0,0 +1,87 @@
+package function
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestAppendPrintableResult(t *testing.T) {
+	type myInt int
+	tests := []struct {
+		name   string
+		result any
+		want   string
+	}{
+		{name: "nil", result: nil, want: "<nil>"},
+		{name: "string", result: "Hello", want: "Hello"},
+		{name: "int", result: -42, want: "-42"},
+		{name: "uint8", result: uint8(255), want: "255"},
+		{name: "bool", result: true, want: "true"},
+		{name: "named int", result: myInt(7), want: "7"},
+		{name: "float64", result: 1.5, want: "1.5"},
+		{name: "float32", result: float32(0.1), want: "0.10000000149"},
+		{name: "float pointer", result: new(float64), want: "0."},
+		{name: "strings", result: []string{"a", "b"}, want: "a\nb"},
+		{name: "ints", result: []int{1, -2}, want: "[\n  1,\n  -2\n]"},
+		{name: "empty ints", result: []int64{}, want: "[]"},
+		{name: "nil ints", result: []int(nil), want: "null"},
+		{name: "table", result: [][]string{{"a", "bb"}, {"ccc", "d"}}, want: "a  |bb\nccc|d \n"},
+		{name: "stringer", result: reflect.TypeOf(0), want: "int"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AppendPrintableResult([]byte("prefix "), tt.result)
+			if err != nil {
+				t.Fatalf("AppendPrintableResult() error = %v", err)
+			}
+			if want := "prefix " + tt.want; string(got) != want {
+				t.Errorf("AppendPrintableResult() = %q, want %q", got, want)
+			}
+		})
+	}
+
+	// The int slice fast path must match the JSON of other slices
+	for _, ints := range [][]int{nil, {}, {0}, {1, 2, 3}} {
+		want, _ := json.MarshalIndent(ints, "", "  ")
+		got, _ := PrintableResult(ints)
+		if got != string(want) {
+			t.Errorf("PrintableResult(%#v) = %q, want %q", ints, got, want)
+		}
+	}
+}
+
+func BenchmarkPrintlnTo(b *testing.B) {
+	type row struct {
+		ID   int
+		Name string
+	}
+	benchmarks := []struct {
+		name    string
+		results []any
+	}{
+		{name: "string", results: []any{"Hello World!"}},
+		{name: "int", results: []any{123456}},
+		{name: "float", results: []any{3.14159}},
+		{name: "strings", results: []any{[]string{"a", "b", "c"}}},
+		{name: "ints", results: []any{[]int{1, 2, 3, 4}}},
+		{name: "struct", results: []any{row{ID: 1, Name: "Alice"}}},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			handler := PrintlnTo(io.Discard)
+			ctx := context.Background()
+			results := make([]any, len(bm.results))
+			b.ReportAllocs()
+			for range b.N {
+				copy(results, bm.results)
+				err := handler(ctx, results, nil)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package function
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// sourceArgs are the arguments of a function
+// parsed from its source code.
+type sourceArgs struct {
+	names        []string
+	descriptions []string // nil if there are no descriptions
+}
+
+// sourceArgsCache caches the *sourceArgs
+// found by funcSourceArgs per function entry PC,
+// nil if the arguments could not be found.
+var sourceArgsCache sync.Map
+
+// sourceArgNames returns the argument names of the function funcVal
+// parsed from its source code or an error
+// if the source is not available.
+func sourceArgNames(funcVal reflect.Value) ([]string, error) {
+	args := funcSourceArgs(funcVal)
+	if args == nil {
+		return nil, fmt.Errorf("argument names of %s not found in its source", funcVal.Type())
+	}
+	return slices.Clone(args.names), nil
+}
+
+// reflectArgDescriptions returns the argument descriptions
+// of the function funcVal parsed from the doc comment
+// of its declaration in the source code if available
+// or nil if there are no descriptions.
+// Argument descriptions are formatted like
+//
+//	// Greet greets
+//	//   name: the name of the person
+//	func Greet(name string) string
+func reflectArgDescriptions(funcVal reflect.Value) []string {
+	args := funcSourceArgs(funcVal)
+	if args == nil || args.descriptions == nil {
+		return nil
+	}
+	return append([]string(nil), args.descriptions...)
+}
+
+// funcSourceArgs returns the arguments of the function funcVal
+// by parsing the declaration of the function in its source file
+// found via runtime.FuncForPC
+// or nil if the source is not available or has no usable names.
+// The returned sourceArgs must not be modified.
+func funcSourceArgs(funcVal reflect.Value) *sourceArgs {
+	fn := runtime.FuncForPC(funcVal.Pointer())
+	if fn == nil {
+		return nil
+	}
+	if cached, ok := sourceArgsCache.Load(fn.Entry()); ok {
+		return cached.(*sourceArgs)
+	}
+	var args *sourceArgs
+	// The compiler generates a wrapper function without source
+	// and with the suffix "-fm" for a method value that binds the receiver
+	if !strings.HasSuffix(fn.Name(), "-fm") {
+		fileName, line := fn.FileLine(fn.Entry())
+		args = parseSourceArgs(fileName, line, fn.Name(), funcVal.Type().NumIn())
+	}
+	sourceArgsCache.Store(fn.Entry(), args)
+	return args
+}
+
+// parseSourceArgs returns the arguments of the function
+// with the full runtime name funcName and numArgs arguments
+// declared at line in the file fileName
+// or nil if the declaration was not found.
+func parseSourceArgs(fileName string, line int, funcName string, numArgs int) *sourceArgs {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, fileName, nil, parser.SkipObjectResolution|parser.ParseComments)
+	if err != nil {
+		return nil
+	}
+	containsLine := func(node ast.Node) bool {
+		return fset.Position(node.Pos()).Line <= line && line <= fset.Position(node.End()).Line
+	}
+
+	// The name of the function without package path and type arguments
+	// like "Func", "Type.Method", "(*Type).Method", "Func.func1.func2"
+	// for a function literal nested in a function literal in Func
+	// that is named "Func.func1.1" if Func.func1 was not inlined,
+	// "init.0.func1" for a function literal in the first init function,
+	// or "init.func1" for a function literal of a package variable
+	// that older Go versions named "glob..func1".
+	name := funcName
+	name = name[strings.LastIndexByte(name, '/')+1:]
+	_, name, _ = strings.Cut(name, ".")
+	name = strings.ReplaceAll(name, "[...]", "")
+	var (
+		declName string
+		litDepth int // nesting depth of function literals
+	)
+	for _, part := range strings.Split(name, ".") {
+		isNum := part != "" && strings.Trim(part, "0123456789") == ""
+		if num, ok := strings.CutPrefix(part, "func"); ok && num != "" && strings.Trim(num, "0123456789") == "" || isNum && litDepth > 0 {
+			litDepth++
+		} else if litDepth == 0 && part != "" && strings.Trim(part, "0123456789") != "" {
+			declName = part
+		}
+	}
+
+	// Candidates are all functions with the name and nesting depth
+	// containing the line of the entry PC
+	var (
+		candidates []*ast.FuncType
+		recv       *ast.FieldList
+		doc        *ast.CommentGroup
+	)
+	var findLits func(node ast.Node, depth int)
+	findLits = func(node ast.Node, depth int) {
+		ast.Inspect(node, func(n ast.Node) bool {
+			lit, ok := n.(*ast.FuncLit)
+			if !ok || n == node {
+				return true
+			}
+			if containsLine(lit) {
+				if depth+1 == litDepth {
+					candidates = append(candidates, lit.Type)
+				} else {
+					findLits(lit, depth+1)
+				}
+			}
+			return false
+		})
+	}
+	for _, decl := range file.Decls {
+		if !containsLine(decl) {
+			continue
+		}
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Name.Name != declName {
+				continue
+			}
+			if litDepth == 0 {
+				candidates = append(candidates, decl.Type)
+				recv, doc = decl.Recv, decl.Doc
+			} else {
+				findLits(decl, 0)
+			}
+		case *ast.GenDecl:
+			if (declName == "init" || declName == "glob") && litDepth > 0 {
+				findLits(decl, 0)
+			}
+		}
+	}
+
+	for _, funcType := range candidates {
+		var fields []*ast.Field
+		if recv != nil {
+			// A method expression has the receiver as first argument
+			fields = append(fields, recv.List...)
+		}
+		fields = append(fields, funcType.Params.List...)
+		var names []string
+		for _, field := range fields {
+			if len(field.Names) == 0 {
+				names = append(names, "")
+			}
+			for _, ident := range field.Names {
+				names = append(names, ident.Name)
+			}
+		}
+		if len(names) != numArgs {
+			continue
+		}
+		for i, name := range names {
+			if name == "" || name == "_" {
+				names[i] = "arg" + strconv.Itoa(i)
+			}
+		}
+		return &sourceArgs{
+			names:        names,
+			descriptions: docArgDescriptions(doc, names),
+		}
+	}
+	return nil
+}
+
+// reflectFuncName returns a readable name of the function funcVal
+// like "Func", "Type.Method" for method expressions and values,
+// or "Func.func1" for function literals
+// without package path and type arguments
+// or an empty string if the function is not known to the runtime.
+func reflectFuncName(funcVal reflect.Value) string {
+	fn := runtime.FuncForPC(funcVal.Pointer())
+	if fn == nil {
+		return ""
+	}
+	name := fn.Name()
+	name = name[strings.LastIndexByte(name, '/')+1:]
+	_, name, _ = strings.Cut(name, ".")
+	name = strings.TrimSuffix(name, "-fm")
+	name = strings.ReplaceAll(name, "[...]", "")
+	if ptrRecv, ok := strings.CutPrefix(name, "(*"); ok {
+		// "(*Type).Method" to "Type.Method"
+		name = strings.Replace(ptrRecv, ")", "", 1)
+	}
+	return name
+}
+
+// docArgDescriptions returns the descriptions of the argument names
+// from lines formatted like "name: description" in doc
+// or nil if there are no descriptions.
+func docArgDescriptions(doc *ast.CommentGroup, names []string) []string {
+	if doc == nil {
+		return nil
+	}
+	var (
+		descriptions = make([]string, len(names))
+		found        bool
+	)
+	for i, name := range names {
+		label := " " + name + ": "
+		for _, comment := range doc.List {
+			// gofmt indents the lines of argument descriptions
+			// formatted as code block with a tab
+			text := strings.ReplaceAll(comment.Text, "\t", " ")
+			if labelPos := strings.Index(text, label); labelPos != -1 {
+				descriptions[i] = strings.TrimSpace(text[labelPos+len(label):])
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		return nil
+	}
+	return descriptions
+}
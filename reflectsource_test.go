@@ -0,0 +1,114 @@
+package function
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_parseSourceArgs(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "source.go")
+	err := os.WriteFile(fileName, []byte(`package source
+
+// Greet greets
+//
+//	name: the name of the person
+func Greet(ctx context.Context, name string, _ int) string {
+	return "Hello " + name
+}
+
+func (p *Person) Rename(name string) {}
+
+func Outer() {
+	_ = func(a int) func(b, c string) {
+		return func(b, c string) {}
+	}
+}
+
+var greeter = func(greeting string) {}
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		line     int
+		funcName string
+		numArgs  int
+		want     *sourceArgs
+	}{
+		{
+			name:     "func decl",
+			line:     6,
+			funcName: "example.com/source.Greet",
+			numArgs:  3,
+			want: &sourceArgs{
+				names:        []string{"ctx", "name", "arg2"},
+				descriptions: []string{"", "the name of the person", ""},
+			},
+		},
+		{
+			name:     "method expression",
+			line:     10,
+			funcName: "example.com/source.(*Person).Rename",
+			numArgs:  2,
+			want:     &sourceArgs{names: []string{"p", "name"}},
+		},
+		{
+			name:     "func lit",
+			line:     13,
+			funcName: "example.com/source.Outer.func1",
+			numArgs:  1,
+			want:     &sourceArgs{names: []string{"a"}},
+		},
+		{
+			name:     "nested func lit",
+			line:     14,
+			funcName: "example.com/source.Outer.func1.func2",
+			numArgs:  2,
+			want:     &sourceArgs{names: []string{"b", "c"}},
+		},
+		{
+			name:     "nested func lit in not inlined func lit",
+			line:     14,
+			funcName: "example.com/source.Outer.func1.1",
+			numArgs:  2,
+			want:     &sourceArgs{names: []string{"b", "c"}},
+		},
+		{
+			name:     "package var func lit",
+			line:     18,
+			funcName: "example.com/source.init.func1",
+			numArgs:  1,
+			want:     &sourceArgs{names: []string{"greeting"}},
+		},
+		{
+			name:     "wrong number of args",
+			line:     6,
+			funcName: "example.com/source.Greet",
+			numArgs:  2,
+			want:     nil,
+		},
+		{
+			name:     "wrong line",
+			line:     10,
+			funcName: "example.com/source.Greet",
+			numArgs:  3,
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseSourceArgs(fileName, tt.line, tt.funcName, tt.numArgs)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSourceArgs() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	if got := parseSourceArgs(filepath.Join(t.TempDir(), "missing.go"), 6, "example.com/source.Greet", 3); got != nil {
+		t.Errorf("parseSourceArgs() for missing file = %#v, want nil", got)
+	}
+}
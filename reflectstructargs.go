@@ -0,0 +1,118 @@
+package function
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ReflectStructArgsWrapper returns a Wrapper using reflection
+// for a function with a single struct argument
+// after an optional context.Context argument
+// that exposes the exported fields of the struct
+// as individual arguments.
+// The argument names are the names from the json struct tags
+// or the field names if there is no json tag name.
+// Fields tagged with `json:"-"` are not exposed
+// and the promoted fields of embedded structs
+// are exposed instead of the embedded structs.
+//
+// This way functions taking a data transfer object struct
+// can be called with named strings from forms or URL queries.
+func ReflectStructArgsWrapper(function any) (ReflectionWrapper, error) {
+	return newReflectStructArgsWrapper(function)
+}
+
+// MustReflectStructArgsWrapper calls ReflectStructArgsWrapper and panics any error.
+func MustReflectStructArgsWrapper(function any) ReflectionWrapper {
+	w, err := newReflectStructArgsWrapper(function)
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+func newReflectStructArgsWrapper(function any) (*reflectWrapper, error) {
+	var (
+		funcVal  = reflect.ValueOf(function)
+		funcType = funcVal.Type()
+	)
+	if funcType.Kind() != reflect.Func {
+		return nil, fmt.Errorf("expected function but got %s", funcType)
+	}
+	var argNames []string
+	switch {
+	case funcType.NumIn() == 2 && funcType.In(0) == typeOfContext:
+		argNames = []string{"ctx"}
+	case funcType.NumIn() != 1:
+		return nil, fmt.Errorf("expected function with a single struct argument after an optional context.Context but got %s", funcType)
+	}
+	structArg := funcType.In(funcType.NumIn() - 1)
+	if structArg.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected function with a single struct argument after an optional context.Context but got %s", funcType)
+	}
+	names, fields := structArgFields(structArg)
+	f := &reflectWrapper{
+		name:         reflectFuncName(funcVal),
+		funcVal:      funcVal,
+		funcType:     funcType,
+		argNames:     append(argNames, names...),
+		structArg:    structArg,
+		structFields: fields,
+	}
+	f.init()
+	return f, nil
+}
+
+// structArgFields returns the argument names
+// and field indices of the exported fields of structType.
+func structArgFields(structType reflect.Type) (names []string, indices [][]int) {
+	for _, field := range reflect.VisibleFields(structType) {
+		if !field.IsExported() || field.Anonymous || viaEmbeddedPointer(structType, field.Index) {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			if field.Tag.Get("json") == "-" {
+				continue
+			}
+		case "":
+			name = field.Name
+		}
+		names = append(names, name)
+		indices = append(indices, field.Index)
+	}
+	return names, indices
+}
+
+// viaEmbeddedPointer returns true if the field with index
+// is promoted from an embedded struct pointer
+// that would have to be allocated to set the field.
+func viaEmbeddedPointer(structType reflect.Type, index []int) bool {
+	for i := range len(index) - 1 {
+		if structType.FieldByIndex(index[:i+1]).Type.Kind() == reflect.Pointer {
+			return true
+		}
+	}
+	return false
+}
+
+// structArgIn returns in with the field argument values
+// replaced by the struct argument value.
+func (f *reflectWrapper) structArgIn(in []reflect.Value) []reflect.Value {
+	offs := 0
+	if f.contextArg {
+		offs = 1
+	}
+	structVal := reflect.New(f.structArg).Elem()
+	for i, index := range f.structFields {
+		structVal.FieldByIndex(index).Set(in[offs+i])
+	}
+	if len(in) == offs {
+		// Struct without fields
+		return append(in, structVal)
+	}
+	in[offs] = structVal
+	return in[:offs+1]
+}
@@ -0,0 +1,111 @@
+package function
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type structArgsPaging struct {
+	Limit int `json:"limit"`
+}
+
+type structArgsQuery struct {
+	structArgsPaging
+	Search   string `json:"search,omitempty"`
+	Archived bool
+	Internal string `json:"-"`
+	Dash     string `json:"-,"`
+	private  string
+}
+
+func structArgsFunc(ctx context.Context, q structArgsQuery) (string, error) {
+	if q.Limit < 0 {
+		return "", fmt.Errorf("negative limit %d", q.Limit)
+	}
+	return fmt.Sprintf("%s %d %t %s", q.Search, q.Limit, q.Archived, q.Dash), nil
+}
+
+func TestReflectStructArgsWrapper(t *testing.T) {
+	f, err := ReflectStructArgsWrapper(structArgsFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.ContextArg() || f.NumArgs() != 5 || f.NumResults() != 1 || !f.ErrorResult() {
+		t.Errorf("unexpected description: ContextArg %t, NumArgs %d, NumResults %d, ErrorResult %t", f.ContextArg(), f.NumArgs(), f.NumResults(), f.ErrorResult())
+	}
+	if want := []string{"ctx", "limit", "search", "Archived", "-"}; !reflect.DeepEqual(f.ArgNames(), want) {
+		t.Errorf("ArgNames() = %#v, want %#v", f.ArgNames(), want)
+	}
+	wantTypes := []reflect.Type{typeOfContext, ReflectType[int](), ReflectType[string](), ReflectType[bool](), ReflectType[string]()}
+	if !reflect.DeepEqual(f.ArgTypes(), wantTypes) {
+		t.Errorf("ArgTypes() = %v, want %v", f.ArgTypes(), wantTypes)
+	}
+
+	ctx := context.Background()
+	const want = "x 10 true d"
+	check := func(name string, results []any, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%s error: %s", name, err)
+		}
+		if !reflect.DeepEqual(results, []any{want}) {
+			t.Errorf("%s = %#v, want %q", name, results, want)
+		}
+	}
+	results, err := f.Call(ctx, []any{10, "x", true, "d"})
+	check("Call", results, err)
+	results, err = f.CallWithStrings(ctx, "10", "x", "true", "d")
+	check("CallWithStrings", results, err)
+	results, err = f.CallWithNamedStrings(ctx, map[string]string{"limit": "10", "search": "x", "Archived": "true", "-": "d"})
+	check("CallWithNamedStrings", results, err)
+	results, err = f.CallWithJSON(ctx, []byte(`{"limit":10,"search":"x","Archived":true,"-":"d"}`))
+	check("CallWithJSON", results, err)
+
+	// Missing arguments are zero values
+	results, err = f.CallWithNamedStrings(ctx, map[string]string{"search": "y"})
+	if err != nil || !reflect.DeepEqual(results, []any{"y 0 false "}) {
+		t.Errorf("CallWithNamedStrings() = %#v, %v", results, err)
+	}
+	_, err = f.CallWithStrings(ctx, "-1")
+	if err == nil {
+		t.Error("expected error from function")
+	}
+	_, err = f.CallWithStrings(ctx, "NaN")
+	if err == nil {
+		t.Error("expected ErrParseArgString")
+	}
+}
+
+func TestReflectStructArgsWrapper_noContext(t *testing.T) {
+	f := MustReflectStructArgsWrapper(func(p structArgsPaging) int { return p.Limit * 2 })
+	if f.ContextArg() || !reflect.DeepEqual(f.ArgNames(), []string{"limit"}) {
+		t.Errorf("ContextArg() = %t, ArgNames() = %#v", f.ContextArg(), f.ArgNames())
+	}
+	results, err := f.CallWithJSON(context.Background(), []byte(`{"limit":21}`))
+	if err != nil || !reflect.DeepEqual(results, []any{42}) {
+		t.Errorf("CallWithJSON() = %#v, %v", results, err)
+	}
+
+	empty := MustReflectStructArgsWrapper(func(struct{}) bool { return true })
+	results, err = empty.CallWithStrings(context.Background())
+	if err != nil || !reflect.DeepEqual(results, []any{true}) {
+		t.Errorf("CallWithStrings() = %#v, %v", results, err)
+	}
+}
+
+func TestReflectStructArgsWrapper_errors(t *testing.T) {
+	for _, function := range []any{
+		"not a function",
+		func() {},
+		func(int) {},
+		func(context.Context) {},
+		func(structArgsPaging, structArgsPaging) {},
+		func(context.Context, *structArgsPaging) {},
+	} {
+		if _, err := ReflectStructArgsWrapper(function); err == nil {
+			t.Errorf("expected error for %T", function)
+		}
+	}
+}
@@ -1,11 +1,15 @@
 package function
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
+	"strings"
+	"sync"
 )
 
 // ReflectWrapper returns a Wrapper for the passed function
@@ -15,12 +19,24 @@ import (
 // Except when the function only has one argument
 // of type context.Context then "ctx" is assumed
 // as argument name in case no name has been passed.
-func ReflectWrapper(function any, argNames ...string) (Wrapper, error) {
+// Use ReflectWrapperWithSourceArgNames to parse
+// the argument names from the source of the function.
+// The Name of the returned wrapper is derived from the runtime
+// symbol of the function like "Func", "Type.Method" or "Func.func1"
+// and can be changed with WithName,
+// String returns the signature with the argument names.
+// The argument descriptions are parsed from lines
+// formatted like "name: description" in the doc comment
+// of the function declaration if the source is available.
+// CallWithJSON of the returned wrapper accepts a JSON object
+// with the arguments by name or a JSON array
+// with the positional arguments after a context argument.
+func ReflectWrapper(function any, argNames ...string) (ReflectionWrapper, error) {
 	return newReflectWrapper(function, argNames)
 }
 
 // MustReflectWrapper calls ReflectWrapper and panics any error.
-func MustReflectWrapper(function any, argNames ...string) Wrapper {
+func MustReflectWrapper(function any, argNames ...string) ReflectionWrapper {
 	w, err := newReflectWrapper(function, argNames)
 	if err != nil {
 		panic(err)
@@ -28,6 +44,104 @@ func MustReflectWrapper(function any, argNames ...string) Wrapper {
 	return w
 }
 
+// ReflectWrapperWithSourceArgNames returns a Wrapper
+// for the passed function like ReflectWrapper
+// with the argument names parsed from the source file
+// of the function found via runtime.FuncForPC.
+// Unnamed arguments and arguments named "_" are named
+// "arg0", "arg1" and so on by their index.
+// Method values are not supported because the compiler
+// generates their functions without source,
+// pass their argument names to ReflectWrapper instead.
+// Returns an error if the source is not available,
+// like in binaries built with -trimpath or deployed
+// without their source, so the argument names
+// are never different from the ones found during development.
+func ReflectWrapperWithSourceArgNames(function any) (ReflectionWrapper, error) {
+	funcVal := reflect.ValueOf(function)
+	if funcVal.Kind() != reflect.Func {
+		return nil, fmt.Errorf("expected function but got %T", function)
+	}
+	var (
+		funcType = funcVal.Type()
+		argNames []string
+	)
+	if funcType.NumIn() > 1 || funcType.NumIn() == 1 && funcType.In(0) != typeOfContext {
+		var err error
+		argNames, err = sourceArgNames(funcVal)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return newReflectWrapper(function, argNames)
+}
+
+// MustReflectWrapperWithSourceArgNames calls
+// ReflectWrapperWithSourceArgNames and panics any error.
+func MustReflectWrapperWithSourceArgNames(function any) ReflectionWrapper {
+	w, err := ReflectWrapperWithSourceArgNames(function)
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+// ReflectWrapperOf returns a Wrapper with the passed name
+// for the function fn of type T using reflection
+// like ReflectWrapper.
+// The argNames are required like for ReflectWrapper
+// because they are not part of the type T.
+// The type parameter makes it possible to wrap an instantiation
+// of a generic function with explicit type arguments
+// and name it independently of the generic function, for example:
+//
+//	function.ReflectWrapperOf("MapIntString", Map[int, string], "m", "key")
+func ReflectWrapperOf[T any](name string, fn T, argNames ...string) (ReflectionWrapper, error) {
+	f, err := newReflectWrapper(fn, argNames)
+	if err != nil {
+		return nil, err
+	}
+	f.name = name
+	return f, nil
+}
+
+// MustReflectWrapperOf calls ReflectWrapperOf and panics any error.
+func MustReflectWrapperOf[T any](name string, fn T, argNames ...string) ReflectionWrapper {
+	w, err := ReflectWrapperOf(name, fn, argNames...)
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+// ReflectionWrapper is a Wrapper using reflection
+// returned by ReflectWrapper and ReflectStructArgsWrapper.
+type ReflectionWrapper interface {
+	Wrapper
+
+	// WithJSONTypeReplacement returns a copy of the wrapper
+	// that unmarshals arguments of the interface type ifaceType
+	// as concreteType in CallWithJSON like the -replaceForJSON
+	// option of gen-func-wrappers does for generated wrappers.
+	// A variadic argument with ifaceType elements
+	// unmarshals its elements as concreteType.
+	// Panics if ifaceType is not an interface type
+	// or concreteType does not implement it.
+	WithJSONTypeReplacement(ifaceType, concreteType reflect.Type) ReflectionWrapper
+
+	// WithName returns a copy of the wrapper
+	// with the passed name returned by Name
+	// instead of the name of the function
+	// that is derived from its runtime symbol.
+	WithName(name string) ReflectionWrapper
+
+	// WithDisallowUnknownArgs returns a copy of the wrapper
+	// whose CallWithNamedStrings and CallWithJSON return an ErrUnknownArgs
+	// for argument names or JSON object keys that are not arguments
+	// of the wrapped function instead of ignoring them.
+	WithDisallowUnknownArgs() ReflectionWrapper
+}
+
 // newReflectWrapper unexported function returns testable struct type
 func newReflectWrapper(function any, argNames []string) (*reflectWrapper, error) {
 	var (
@@ -44,42 +158,297 @@ func newReflectWrapper(function any, argNames []string) (*reflectWrapper, error)
 	case len(argNames) != funcType.NumIn():
 		return nil, fmt.Errorf("%d argNames passed, but %s has %d arguments", len(argNames), funcType, funcType.NumIn())
 	}
-	return &reflectWrapper{funcVal, funcType, argNames}, nil
+	f := &reflectWrapper{
+		name:            reflectFuncName(funcVal),
+		funcVal:         funcVal,
+		funcType:        funcType,
+		argNames:        argNames,
+		argDescriptions: reflectArgDescriptions(funcVal),
+	}
+	f.init()
+	return f, nil
 }
 
 type reflectWrapper struct {
-	funcVal  reflect.Value
-	funcType reflect.Type
-	argNames []string
+	name            string // empty if not known
+	funcVal         reflect.Value
+	funcType        reflect.Type
+	argNames        []string
+	argDescriptions []string // nil if there are no descriptions
+
+	// structArg is the struct type of the single non context argument
+	// if its fields are the arguments of the wrapper,
+	// structFields are the indices of the argument fields.
+	structArg    reflect.Type
+	structFields [][]int
+
+	// jsonTypes maps argument interface types
+	// to the concrete types used for unmarshalling JSON
+	jsonTypes map[reflect.Type]reflect.Type
+
+	// disallowUnknownArgs returns ErrUnknownArgs
+	// for unknown named arguments
+	disallowUnknownArgs bool
+
+	// Precomputed by init from funcType
+	// so that the call methods don't have to
+	// query funcType for every call
+	argTypes     []reflect.Type
+	resultTypes  []reflect.Type // without error result
+	contextArg   bool
+	errorResult  bool
+	variadic     bool
+	scanArgs     []scanArgFunc      // nil for context argument
+	unmarshalArg []unmarshalArgFunc // nil for context argument
+	inPool       sync.Pool          // *[]reflect.Value with len(argTypes)
+}
+
+// scanArgFunc parses str as argument value.
+type scanArgFunc func(str string) (reflect.Value, error)
+
+// unmarshalArgFunc unmarshals argJSON as argument value.
+type unmarshalArgFunc func(argJSON []byte) (reflect.Value, error)
+
+// init precomputes the argument and result types
+// and the argument conversion functions.
+func (f *reflectWrapper) init() {
+	numIn := f.funcType.NumIn()
+	if numIn > 0 {
+		f.argTypes = make([]reflect.Type, numIn)
+		for i := range f.argTypes {
+			f.argTypes[i] = f.funcType.In(i)
+		}
+		f.contextArg = f.argTypes[0] == typeOfContext
+	}
+	if f.structArg != nil {
+		// The struct fields replace the struct argument
+		f.argTypes = f.argTypes[:numIn-1]
+		for _, index := range f.structFields {
+			f.argTypes = append(f.argTypes, f.structArg.FieldByIndex(index).Type)
+		}
+		numIn = len(f.argTypes)
+	}
+	numOut := f.funcType.NumOut()
+	f.errorResult = numOut > 0 && f.funcType.Out(numOut-1) == typeOfError
+	if f.errorResult {
+		numOut--
+	}
+	if numOut > 0 {
+		f.resultTypes = make([]reflect.Type, numOut)
+		for i := range f.resultTypes {
+			f.resultTypes[i] = f.funcType.Out(i)
+		}
+	}
+	f.variadic = f.funcType.IsVariadic()
+
+	f.scanArgs = make([]scanArgFunc, numIn)
+	f.unmarshalArg = make([]unmarshalArgFunc, numIn)
+	for i, argType := range f.argTypes {
+		if i == 0 && f.contextArg {
+			continue
+		}
+		f.scanArgs[i] = newScanArgFunc(argType)
+		f.unmarshalArg[i] = f.newUnmarshalArgFunc(argType, f.variadic && i == numIn-1)
+	}
+
+	f.inPool.New = func() any {
+		in := make([]reflect.Value, numIn)
+		return &in
+	}
+}
+
+func newScanArgFunc(argType reflect.Type) scanArgFunc {
+	if argType == typeOfAny {
+		// Pass string directly for argument of type any
+		return func(str string) (reflect.Value, error) {
+			return reflect.ValueOf(str), nil
+		}
+	}
+	return func(str string) (reflect.Value, error) {
+		// StringScanners can be changed at any time,
+		// so ScanString has to be called for every argument
+		destPtr := reflect.New(argType)
+		err := ScanString(str, destPtr.Interface())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return destPtr.Elem(), nil
+	}
+}
+
+func (f *reflectWrapper) newUnmarshalArgFunc(argType reflect.Type, variadic bool) unmarshalArgFunc {
+	if jsonType, ok := f.jsonTypes[argType]; ok {
+		return func(argJSON []byte) (reflect.Value, error) {
+			destPtr := reflect.New(jsonType)
+			err := json.Unmarshal(argJSON, destPtr.Interface())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return destPtr.Elem().Convert(argType), nil
+		}
+	}
+	if variadic {
+		if jsonType, ok := f.jsonTypes[argType.Elem()]; ok {
+			return variadicJSONTypeUnmarshaller(argType, jsonType)
+		}
+	}
+	if argType == typeOfError {
+		// json.Unmarshal does not work for errors
+		// so unmarshal string and create error from it
+		return func(argJSON []byte) (reflect.Value, error) {
+			var errStr string
+			err := json.Unmarshal(argJSON, &errStr)
+			if err != nil || errStr == "" {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(errors.New(errStr)), nil
+		}
+	}
+	return func(argJSON []byte) (reflect.Value, error) {
+		destPtr := reflect.New(argType)
+		err := json.Unmarshal(argJSON, destPtr.Interface())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return destPtr.Elem(), nil
+	}
 }
 
+func (f *reflectWrapper) WithJSONTypeReplacement(ifaceType, concreteType reflect.Type) ReflectionWrapper {
+	if ifaceType.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("JSON replacement type %s is not an interface", ifaceType))
+	}
+	if !concreteType.Implements(ifaceType) {
+		panic(fmt.Sprintf("JSON replacement type %s does not implement %s", concreteType, ifaceType))
+	}
+	jsonTypes := make(map[reflect.Type]reflect.Type, len(f.jsonTypes)+1)
+	for iface, concrete := range f.jsonTypes {
+		jsonTypes[iface] = concrete
+	}
+	jsonTypes[ifaceType] = concreteType
+	c := f.clone()
+	c.jsonTypes = jsonTypes
+	c.init()
+	return c
+}
+
+func (f *reflectWrapper) WithName(name string) ReflectionWrapper {
+	c := f.clone()
+	c.name = name
+	c.init()
+	return c
+}
+
+func (f *reflectWrapper) WithDisallowUnknownArgs() ReflectionWrapper {
+	c := f.clone()
+	c.disallowUnknownArgs = true
+	c.init()
+	return c
+}
+
+// clone returns a copy of f without the
+// precomputed fields that have to be set by init.
+func (f *reflectWrapper) clone() *reflectWrapper {
+	return &reflectWrapper{
+		name:            f.name,
+		funcVal:         f.funcVal,
+		funcType:        f.funcType,
+		argNames:        f.argNames,
+		argDescriptions: f.argDescriptions,
+		structArg:       f.structArg,
+		structFields:    f.structFields,
+		jsonTypes:       f.jsonTypes,
+
+		disallowUnknownArgs: f.disallowUnknownArgs,
+	}
+}
+
+// variadicJSONTypeUnmarshaller returns an unmarshalArgFunc
+// for a variadic argument of sliceType that unmarshals the elements
+// as jsonType and converts them to the element type.
+func variadicJSONTypeUnmarshaller(sliceType, jsonType reflect.Type) unmarshalArgFunc {
+	return func(argJSON []byte) (reflect.Value, error) {
+		destPtr := reflect.New(reflect.SliceOf(jsonType))
+		err := json.Unmarshal(argJSON, destPtr.Interface())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		dest := destPtr.Elem()
+		if dest.IsNil() {
+			return reflect.Zero(sliceType), nil
+		}
+		slice := reflect.MakeSlice(sliceType, dest.Len(), dest.Len())
+		for i := range dest.Len() {
+			slice.Index(i).Set(dest.Index(i))
+		}
+		return slice, nil
+	}
+}
+
+// String returns the signature of the wrapped function
+// with the argument names like
+// "Name(ctx context.Context, a int) (string, error)".
 func (f *reflectWrapper) String() string {
-	return f.funcVal.String()
+	var b strings.Builder
+	if f.name != "" {
+		b.WriteString(f.name)
+	} else {
+		b.WriteString("func")
+	}
+	b.WriteByte('(')
+	for i, argType := range f.argTypes {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(f.argNames[i])
+		b.WriteByte(' ')
+		if f.variadic && i == len(f.argTypes)-1 {
+			b.WriteString("...")
+			argType = argType.Elem()
+		}
+		b.WriteString(argType.String())
+	}
+	b.WriteByte(')')
+	switch numOut := f.funcType.NumOut(); numOut {
+	case 0:
+	case 1:
+		b.WriteByte(' ')
+		b.WriteString(f.funcType.Out(0).String())
+	default:
+		b.WriteString(" (")
+		for i := range numOut {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(f.funcType.Out(i).String())
+		}
+		b.WriteByte(')')
+	}
+	// reflect formats the type any as "interface {}"
+	return strings.ReplaceAll(b.String(), "interface {}", "any")
 }
 
 func (f *reflectWrapper) Name() string {
-	return f.String()
+	if f.name == "" {
+		return f.funcVal.String()
+	}
+	return f.name
 }
 
 func (f *reflectWrapper) NumArgs() int {
-	return f.funcType.NumIn()
+	return len(f.argTypes)
 }
 
 func (f *reflectWrapper) ContextArg() bool {
-	return f.funcType.NumIn() > 0 && f.funcType.In(0) == typeOfContext
+	return f.contextArg
 }
 
 func (f *reflectWrapper) NumResults() int {
-	numResults := f.funcType.NumOut()
-	if numResults > 0 && f.funcType.Out(numResults-1) == typeOfError {
-		numResults--
-	}
-	return numResults
+	return len(f.resultTypes)
 }
 
 func (f *reflectWrapper) ErrorResult() bool {
-	numOut := f.funcType.NumOut()
-	return numOut > 0 && f.funcType.Out(numOut-1) == typeOfError
+	return f.errorResult
 }
 
 func (f *reflectWrapper) ArgNames() []string {
@@ -87,51 +456,65 @@ func (f *reflectWrapper) ArgNames() []string {
 }
 
 func (f *reflectWrapper) ArgDescriptions() []string {
-	numIn := f.funcType.NumIn()
-	if numIn == 0 {
+	if f.argDescriptions != nil {
+		return f.argDescriptions
+	}
+	if len(f.argTypes) == 0 {
 		return nil
 	}
-	return make([]string, numIn)
+	return make([]string, len(f.argTypes))
 }
 
 func (f *reflectWrapper) ArgTypes() []reflect.Type {
-	numIn := f.funcType.NumIn()
-	if numIn == 0 {
-		return nil
-	}
-	a := make([]reflect.Type, numIn)
-	for i := range a {
-		a[i] = f.funcType.In(i)
-	}
-	return a
+	return slices.Clone(f.argTypes)
 }
 
 func (f *reflectWrapper) ResultTypes() []reflect.Type {
-	numResults := f.NumResults()
-	if numResults == 0 {
-		return nil
-	}
-	r := make([]reflect.Type, numResults)
-	for i := range r {
-		r[i] = f.funcType.Out(i)
+	return slices.Clone(f.resultTypes)
+}
+
+// getIn returns a zeroed argument values buffer from the pool
+// with the context argument set if the function has one.
+// It has to be returned with putIn.
+func (f *reflectWrapper) getIn(ctx context.Context) *[]reflect.Value {
+	in := f.inPool.Get().(*[]reflect.Value)
+	if f.contextArg {
+		(*in)[0] = reflect.ValueOf(ctx)
 	}
-	return r
+	return in
+}
+
+// putIn zeroes in to not keep the argument values alive
+// and returns it to the pool.
+func (f *reflectWrapper) putIn(in *[]reflect.Value) {
+	clear(*in)
+	f.inPool.Put(in)
 }
 
-func (f *reflectWrapper) call(in []reflect.Value) (results []any, err error) {
+// call calls the function with in and returns in to the pool.
+func (f *reflectWrapper) call(inBuf *[]reflect.Value) (results []any, err error) {
+	in := *inBuf
 	// Replace untyped nil values with typed zero values
 	for i := range in {
 		if !in[i].IsValid() {
-			in[i] = reflect.Zero(f.funcType.In(i))
+			in[i] = reflect.Zero(f.argTypes[i])
 		}
 	}
-	out := f.funcVal.Call(in)
-	resultsLen := len(out)
-	if f.ErrorResult() {
-		resultsLen--
+	if f.structArg != nil {
+		in = f.structArgIn(in)
+	}
+	var out []reflect.Value
+	if f.variadic {
+		// The variadic arg is passed as slice
+		out = f.funcVal.CallSlice(in)
+	} else {
+		out = f.funcVal.Call(in)
+	}
+	f.putIn(inBuf)
+	if f.errorResult {
 		err, _ = out[len(out)-1].Interface().(error)
 	}
-	results = make([]any, resultsLen)
+	results = make([]any, len(f.resultTypes))
 	for i := range results {
 		results[i] = out[i].Interface()
 	}
@@ -139,115 +522,136 @@ func (f *reflectWrapper) call(in []reflect.Value) (results []any, err error) {
 }
 
 func (f *reflectWrapper) Call(ctx context.Context, args []any) (results []any, err error) {
-	in := make([]reflect.Value, f.NumArgs())
+	inBuf := f.getIn(ctx)
+	in := *inBuf
 	offs := 0
-	if f.ContextArg() {
+	if f.contextArg {
 		offs = 1
-		in[0] = reflect.ValueOf(ctx)
 	}
 	for i, arg := range args {
 		in[i+offs] = reflect.ValueOf(arg)
 	}
-	return f.call(in)
+	return f.call(inBuf)
 }
 
 func (f *reflectWrapper) CallWithStrings(ctx context.Context, strs ...string) (results []any, err error) {
-	in := make([]reflect.Value, f.NumArgs())
+	inBuf := f.getIn(ctx)
+	in := *inBuf
 	offs := 0
-	if f.ContextArg() {
+	if f.contextArg {
 		offs = 1
-		in[0] = reflect.ValueOf(ctx)
 	}
-	for i := offs; i < len(in); i++ {
-		argType := f.funcType.In(i)
-		if i-offs >= len(strs) {
-			// Pass default value if not enough strs
-			in[i] = reflect.Zero(argType)
-			continue
-		}
-		str := strs[i-offs]
-		if argType == typeOfAny {
-			// Pass string directly for argument of type any
-			in[i] = reflect.ValueOf(str)
-			continue
-		}
-		destPtr := reflect.New(argType)
-		err = ScanString(str, destPtr.Interface())
+	// Arguments without strs keep their default value
+	for i := offs; i < len(in) && i-offs < len(strs); i++ {
+		in[i], err = f.scanArgs[i](strs[i-offs])
 		if err != nil {
+			f.putIn(inBuf)
 			return nil, NewErrParseArgString(err, f, f.argNames[i])
 		}
-		in[i] = destPtr.Elem()
 	}
-	return f.call(in)
+	return f.call(inBuf)
+}
+
+// unknownArgs returns an ErrUnknownArgs for the sorted keys of args
+// that are not argument names.
+func unknownArgs[T any](f fmt.Stringer, argNames []string, contextArg bool, args map[string]T) error {
+	var unknown []string
+	for name := range args {
+		i := slices.Index(argNames, name)
+		if i == -1 || i == 0 && contextArg {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	slices.Sort(unknown)
+	return NewErrUnknownArgs(f, unknown)
 }
 
 func (f *reflectWrapper) CallWithNamedStrings(ctx context.Context, strs map[string]string) (results []any, err error) {
-	in := make([]reflect.Value, f.NumArgs())
+	if f.disallowUnknownArgs {
+		if err = unknownArgs(f, f.argNames, f.contextArg, strs); err != nil {
+			return nil, err
+		}
+	}
+	inBuf := f.getIn(ctx)
+	in := *inBuf
 	offs := 0
-	if f.ContextArg() {
+	if f.contextArg {
 		offs = 1
-		in[0] = reflect.ValueOf(ctx)
 	}
 	for i := offs; i < len(in); i++ {
-		argType := f.funcType.In(i)
-		argName := f.argNames[i]
-		if str, ok := strs[argName]; ok {
-			if argType == typeOfAny {
-				// Pass string directly for argument of type any
-				in[i] = reflect.ValueOf(str)
-				continue
-			}
-			destPtr := reflect.New(argType)
-			err = ScanString(str, destPtr.Interface())
+		if str, ok := strs[f.argNames[i]]; ok {
+			in[i], err = f.scanArgs[i](str)
 			if err != nil {
+				f.putIn(inBuf)
 				return nil, NewErrParseArgString(err, f, f.argNames[i])
 			}
-			in[i] = destPtr.Elem()
 		}
 	}
-	return f.call(in)
+	return f.call(inBuf)
 }
 
 func (f *reflectWrapper) CallWithJSON(ctx context.Context, argsJSON []byte) (results []any, err error) {
-	args := make(map[string]json.RawMessage)
+	if trimmed := bytes.TrimLeft(argsJSON, " \t\r\n"); len(trimmed) > 0 && trimmed[0] == '[' {
+		return f.callWithJSONArray(ctx, argsJSON)
+	}
+	args := make(map[string]json.RawMessage, len(f.argTypes))
 	err = json.Unmarshal(argsJSON, &args)
 	if err != nil {
 		return nil, NewErrParseArgsJSON(err, f, argsJSON)
 	}
-	in := make([]reflect.Value, f.NumArgs())
+	if f.disallowUnknownArgs {
+		if err = unknownArgs(f, f.argNames, f.contextArg, args); err != nil {
+			return nil, err
+		}
+	}
+	inBuf := f.getIn(ctx)
+	in := *inBuf
 	offs := 0
-	if f.ContextArg() {
+	if f.contextArg {
 		offs = 1
-		in[0] = reflect.ValueOf(ctx)
 	}
 	for i := offs; i < len(in); i++ {
-		argType := f.funcType.In(i)
-		destPtr := reflect.New(argType)
-		argName := f.argNames[i]
-		if arg, ok := args[argName]; ok {
-			if argType == typeOfError {
-				// json.Unmarshal does not work for errors
-				// so unmarshal string and create error from it
-				var errStr string
-				err = json.Unmarshal(arg, &errStr)
-				if err != nil {
-					return nil, NewErrParseArgsJSON(err, f, argsJSON)
-				}
-				var err error
-				if errStr != "" {
-					err = errors.New(errStr)
-				}
-				in[i] = reflect.ValueOf(err)
-				continue
-			}
-			err = json.Unmarshal(arg, destPtr.Interface())
+		if arg, ok := args[f.argNames[i]]; ok {
+			in[i], err = f.unmarshalArg[i](arg)
 			if err != nil {
+				f.putIn(inBuf)
 				return nil, NewErrParseArgsJSON(err, f, argsJSON)
 			}
 		}
-		in[i] = destPtr.Elem()
 	}
-	return f.call(in)
+	return f.call(inBuf)
+}
+
+// callWithJSONArray calls the function with the elements
+// of the JSON array argsJSON as positional arguments
+// not including a context argument.
+// Missing elements at the end are passed as zero values.
+func (f *reflectWrapper) callWithJSONArray(ctx context.Context, argsJSON []byte) (results []any, err error) {
+	var args []json.RawMessage
+	err = json.Unmarshal(argsJSON, &args)
+	if err != nil {
+		return nil, NewErrParseArgsJSON(err, f, argsJSON)
+	}
+	offs := 0
+	if f.contextArg {
+		offs = 1
+	}
+	if numArgs := len(f.argTypes) - offs; len(args) > numArgs {
+		return nil, NewErrParseArgsJSON(fmt.Errorf("%d positional arguments for %d arguments", len(args), numArgs), f, argsJSON)
+	}
+	inBuf := f.getIn(ctx)
+	in := *inBuf
+	for i, arg := range args {
+		in[i+offs], err = f.unmarshalArg[i+offs](arg)
+		if err != nil {
+			f.putIn(inBuf)
+			return nil, NewErrParseArgsJSON(err, f, argsJSON)
+		}
+	}
+	return f.call(inBuf)
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -3,7 +3,11 @@ package function
 import (
 	"context"
 	"errors"
+	"fmt"
+	"os"
 	"reflect"
+	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -62,6 +66,34 @@ func TestReflectWrapper(t *testing.T) {
 		ResultTypes: []reflect.Type{reflect.TypeOf(0)},
 	}
 
+	fvar := func(sep string, parts ...string) string { return strings.Join(parts, sep) }
+	fvardesc := description{
+		NumArgs:     2,
+		ContextArg:  false,
+		NumResults:  1,
+		ErrorResult: false,
+		ArgNames:    []string{"sep", "parts"},
+		ArgTypes:    []reflect.Type{reflect.TypeOf(""), reflect.TypeOf([]string(nil))},
+		ResultTypes: []reflect.Type{reflect.TypeOf("")},
+	}
+
+	type point struct{ X, Y int }
+	fvarStruct := func(points ...point) (sum int) {
+		for _, p := range points {
+			sum += p.X + p.Y
+		}
+		return sum
+	}
+	fvarStructdesc := description{
+		NumArgs:     1,
+		ContextArg:  false,
+		NumResults:  1,
+		ErrorResult: false,
+		ArgNames:    []string{"points"},
+		ArgTypes:    []reflect.Type{reflect.TypeOf([]point(nil))},
+		ResultTypes: []reflect.Type{reflect.TypeOf(0)},
+	}
+
 	type args struct {
 		function any
 		argNames []string
@@ -191,6 +223,50 @@ func TestReflectWrapper(t *testing.T) {
 			},
 			desc: ferrdesc,
 		},
+		{
+			name: "func(sep string, parts ...string) string",
+			args: args{
+				function: fvar,
+				argNames: []string{"sep", "parts"},
+			},
+			want: &reflectWrapper{
+				funcVal:  reflect.ValueOf(fvar),
+				funcType: reflect.TypeOf(fvar),
+				argNames: []string{"sep", "parts"},
+			},
+			wantErr: false,
+			call: call{
+				args:         []any{"-", []string{"a", "b"}},
+				argsStrings:  []string{"-", "[a,b]"},
+				argsNamedStr: map[string]string{"sep": "-", "parts": "[a,b]"},
+				argsJSON:     []byte(`{"sep":"-","parts":["a","b"]}`),
+				results:      []any{"a-b"},
+				wantErr:      false,
+			},
+			desc: fvardesc,
+		},
+		{
+			name: "func(points ...point) int",
+			args: args{
+				function: fvarStruct,
+				argNames: []string{"points"},
+			},
+			want: &reflectWrapper{
+				funcVal:  reflect.ValueOf(fvarStruct),
+				funcType: reflect.TypeOf(fvarStruct),
+				argNames: []string{"points"},
+			},
+			wantErr: false,
+			call: call{
+				args:         []any{[]point{{X: 1, Y: 2}, {X: 3, Y: 4}}},
+				argsStrings:  []string{`[{"X":1,"Y":2},{"X":3,"Y":4}]`},
+				argsNamedStr: map[string]string{"points": `[{"X":1,"Y":2},{"X":3,"Y":4}]`},
+				argsJSON:     []byte(`{"points":[{"X":1,"Y":2},{"X":3,"Y":4}]}`),
+				results:      []any{10},
+				wantErr:      false,
+			},
+			desc: fvarStructdesc,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -199,7 +275,8 @@ func TestReflectWrapper(t *testing.T) {
 				t.Errorf("newReflectWrapper() error = %#v, wantErr = %#v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(got, tt.want) {
+			// Compare the fields not precomputed from funcType
+			if got.funcVal != tt.want.funcVal || got.funcType != tt.want.funcType || !reflect.DeepEqual(got.argNames, tt.want.argNames) {
 				t.Errorf("newReflectWrapper() = %#v, want = %#v", got, tt.want)
 			}
 
@@ -263,3 +340,476 @@ func TestReflectWrapper(t *testing.T) {
 		})
 	}
 }
+
+type argNamesT struct{}
+
+func (argNamesT) Method(a int, _ string) {}
+
+func (*argNamesT) Sum(a, b int) int { return a + b }
+
+// argNamesFunc is used to test argument names and descriptions
+//
+//	name: the name
+//	count:   the count
+func argNamesFunc(
+	ctx context.Context,
+	name string,
+	count, _ int,
+) {
+}
+
+func argNamesGeneric[T any](value T, fallback T) {}
+
+var argNamesVarFunc = func(key string, val any) {}
+
+// skipWithoutSource skips the test if the source files
+// of the test binary are not available like with -trimpath
+func skipWithoutSource(t *testing.T) {
+	t.Helper()
+	_, file, _, _ := runtime.Caller(0)
+	if _, err := os.Stat(file); err != nil {
+		t.Skip("source not available:", err)
+	}
+}
+
+func TestReflectWrapper_missingArgNames(t *testing.T) {
+	_, err := ReflectWrapper(argNamesFunc)
+	if err == nil {
+		t.Error("expected error for missing argNames")
+	}
+}
+
+func TestReflectWrapperWithSourceArgNames(t *testing.T) {
+	skipWithoutSource(t)
+
+	inner := func(outer int) func(first, second string) {
+		return func(first, second string) {}
+	}
+	tests := []struct {
+		name     string
+		function any
+		want     []string
+	}{
+		{name: "func decl", function: argNamesFunc, want: []string{"ctx", "name", "count", "arg3"}},
+		{name: "func lit", function: inner, want: []string{"outer"}},
+		{name: "nested func lit", function: inner(0), want: []string{"first", "second"}},
+		{name: "package var func lit", function: argNamesVarFunc, want: []string{"key", "val"}},
+		{name: "generic func", function: argNamesGeneric[int], want: []string{"value", "fallback"}},
+		{name: "method expression", function: argNamesT.Method, want: []string{"arg0", "a", "arg2"}},
+		{name: "standard library", function: strings.Repeat, want: []string{"s", "count"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := ReflectWrapperWithSourceArgNames(tt.function)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := w.ArgNames(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ArgNames() = %#v, want %#v", got, tt.want)
+			}
+			// Cached names are not shared
+			w.ArgNames()[0] = "changed"
+			if got := MustReflectWrapperWithSourceArgNames(tt.function).ArgNames(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cached ArgNames() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	// Method values have no source
+	_, err := ReflectWrapperWithSourceArgNames((&argNamesT{}).Sum)
+	if err == nil {
+		t.Error("expected error for method value")
+	}
+}
+
+func TestReflectWrapper_NameString(t *testing.T) {
+	tests := []struct {
+		name       string
+		wrapper    ReflectionWrapper
+		wantName   string
+		wantString string
+	}{
+		{
+			name:       "func decl",
+			wrapper:    MustReflectWrapper(argNamesFunc, "ctx", "name", "count", "arg3"),
+			wantName:   "argNamesFunc",
+			wantString: "argNamesFunc(ctx context.Context, name string, count int, arg3 int)",
+		},
+		{
+			name:       "method value",
+			wrapper:    MustReflectWrapper((&argNamesT{}).Sum, "a", "b"),
+			wantName:   "argNamesT.Sum",
+			wantString: "argNamesT.Sum(a int, b int) int",
+		},
+		{
+			name:       "method expression",
+			wrapper:    MustReflectWrapper((*argNamesT).Sum, "t", "a", "b"),
+			wantName:   "argNamesT.Sum",
+			wantString: "argNamesT.Sum(t *function.argNamesT, a int, b int) int",
+		},
+		{
+			name:       "func lit",
+			wrapper:    MustReflectWrapper(func(vals ...any) (int, error) { return len(vals), nil }, "vals"),
+			wantName:   "TestReflectWrapper_NameString.func1",
+			wantString: "TestReflectWrapper_NameString.func1(vals ...any) (int, error)",
+		},
+		{
+			name:       "WithName",
+			wrapper:    MustReflectWrapper(argNamesGeneric[string], "value", "fallback").WithName("Choose"),
+			wantName:   "Choose",
+			wantString: "Choose(value string, fallback string)",
+		},
+		{
+			name:       "struct args",
+			wrapper:    MustReflectStructArgsWrapper(structArgsFunc),
+			wantName:   "structArgsFunc",
+			wantString: "structArgsFunc(ctx context.Context, limit int, search string, Archived bool, - string) (string, error)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.wrapper.Name(); got != tt.wantName {
+				t.Errorf("Name() = %q, want %q", got, tt.wantName)
+			}
+			if got := tt.wrapper.String(); got != tt.wantString {
+				t.Errorf("String() = %q, want %q", got, tt.wantString)
+			}
+		})
+	}
+}
+
+func TestReflectWrapper_argDescriptions(t *testing.T) {
+	skipWithoutSource(t)
+
+	tests := []struct {
+		name     string
+		function any
+		argNames []string
+		want     []string
+	}{
+		{name: "doc comment", function: argNamesFunc, argNames: []string{"ctx", "name", "count", "arg3"}, want: []string{"", "the name", "the count", ""}},
+		{name: "other names", function: argNamesFunc, argNames: []string{"c", "n", "x", "y"}, want: []string{"", "the name", "the count", ""}},
+		{name: "no doc comment", function: argNamesGeneric[int], argNames: []string{"value", "fallback"}, want: []string{"", ""}},
+		{name: "func lit", function: func(name string) {}, argNames: []string{"name"}, want: []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := ReflectWrapper(tt.function, tt.argNames...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := w.ArgDescriptions(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ArgDescriptions() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReflectWrapper_DisallowUnknownArgs(t *testing.T) {
+	w := MustReflectWrapper(func(ctx context.Context, a, b int) int { return a + b }, "ctx", "a", "b")
+	ctx := context.Background()
+
+	strict := w.WithDisallowUnknownArgs()
+
+	// The wrapper passed to WithDisallowUnknownArgs is not changed
+	_, err := w.CallWithJSON(ctx, []byte(`{"a":1,"c":2}`))
+	if err != nil {
+		t.Fatalf("unknown arguments must be ignored by default: %s", err)
+	}
+	_, err = w.CallWithNamedStrings(ctx, map[string]string{"a": "1", "c": "2"})
+	if err != nil {
+		t.Fatalf("unknown arguments must be ignored by default: %s", err)
+	}
+	// Other copies keep the option
+	strict = strict.WithName("Strict")
+
+	tests := []struct {
+		name string
+		call func() ([]any, error)
+		want []string
+	}{
+		{name: "CallWithJSON", call: func() ([]any, error) { return strict.CallWithJSON(ctx, []byte(`{"a":1,"d":2,"c":3}`)) }, want: []string{"c", "d"}},
+		{name: "CallWithNamedStrings", call: func() ([]any, error) {
+			return strict.CallWithNamedStrings(ctx, map[string]string{"a": "1", "ctx": "x"})
+		}, want: []string{"ctx"}},
+		{name: "known", call: func() ([]any, error) { return strict.CallWithJSON(ctx, []byte(`{"a":1,"b":2}`)) }, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.call()
+			var unknown ErrUnknownArgs
+			if tt.want == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if !errors.As(err, &unknown) {
+				t.Fatalf("error = %v, want ErrUnknownArgs", err)
+			}
+			if !reflect.DeepEqual(unknown.Args, tt.want) {
+				t.Errorf("unknown arguments = %v, want %v", unknown.Args, tt.want)
+			}
+		})
+	}
+}
+
+func TestReflectWrapper_CallWithJSONArray(t *testing.T) {
+	w := MustReflectWrapper(func(ctx context.Context, count int, name string) string {
+		return fmt.Sprint(count, name)
+	}, "ctx", "count", "name")
+	tests := []struct {
+		argsJSON string
+		want     []any
+		wantErr  bool
+	}{
+		{argsJSON: `[3,"x"]`, want: []any{"3x"}},
+		{argsJSON: " \n\t[3, \"x\"]", want: []any{"3x"}},
+		{argsJSON: `[3]`, want: []any{"3"}},
+		{argsJSON: `[]`, want: []any{"0"}},
+		{argsJSON: `[3,"x",true]`, wantErr: true},
+		{argsJSON: `["x"]`, wantErr: true},
+		{argsJSON: `[3,`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.argsJSON, func(t *testing.T) {
+			got, err := w.CallWithJSON(context.Background(), []byte(tt.argsJSON))
+			if tt.wantErr {
+				var parseErr ErrParseArgsJSON
+				if !errors.As(err, &parseErr) {
+					t.Errorf("CallWithJSON() error = %#v, want ErrParseArgsJSON", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CallWithJSON() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func genericLookup[K comparable, V any](m map[K]V, key K) V {
+	return m[key]
+}
+
+type genericList[T any] []T
+
+func (l genericList[T]) Get(index int) T {
+	return l[index]
+}
+
+func genericPrefixer[T any](prefix string) func(ctx context.Context, value T) string {
+	return func(ctx context.Context, value T) string {
+		return fmt.Sprint(prefix, value)
+	}
+}
+
+func TestReflectWrapperOf(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name        string
+		wrapper     ReflectionWrapper
+		funcName    string
+		argNames    []string
+		argTypes    []reflect.Type
+		strs        []string
+		argsJSON    string
+		wantResults []any
+	}{
+		{
+			name:        "genericLookup[int, string]",
+			wrapper:     MustReflectWrapperOf("LookupIntString", genericLookup[int, string], "m", "key"),
+			funcName:    "LookupIntString",
+			argNames:    []string{"m", "key"},
+			argTypes:    []reflect.Type{ReflectType[map[int]string](), ReflectType[int]()},
+			strs:        []string{`{"1":"one"}`, "1"},
+			argsJSON:    `{"m":{"1":"one"},"key":1}`,
+			wantResults: []any{"one"},
+		},
+		{
+			name:        "genericList[float64].Get",
+			wrapper:     MustReflectWrapperOf("ListGet", genericList[float64].Get, "l", "index"),
+			funcName:    "ListGet",
+			argNames:    []string{"l", "index"},
+			argTypes:    []reflect.Type{ReflectType[genericList[float64]](), ReflectType[int]()},
+			strs:        []string{"[0.5,1.5]", "1"},
+			argsJSON:    `{"l":[0.5,1.5],"index":1}`,
+			wantResults: []any{1.5},
+		},
+		{
+			name:        "genericPrefixer[bool]",
+			wrapper:     MustReflectWrapperOf("Prefix", genericPrefixer[bool]("is "), "ctx", "value"),
+			funcName:    "Prefix",
+			argNames:    []string{"ctx", "value"},
+			argTypes:    []reflect.Type{typeOfContext, ReflectType[bool]()},
+			strs:        []string{"true"},
+			argsJSON:    `{"value":true}`,
+			wantResults: []any{"is true"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := tt.wrapper
+			if w.Name() != tt.funcName || !strings.HasPrefix(w.String(), tt.funcName+"(") {
+				t.Errorf("Name() = %q, String() = %q, want %q", w.Name(), w.String(), tt.funcName)
+			}
+			if !reflect.DeepEqual(w.ArgNames(), tt.argNames) {
+				t.Errorf("ArgNames() = %#v, want %#v", w.ArgNames(), tt.argNames)
+			}
+			if !reflect.DeepEqual(w.ArgTypes(), tt.argTypes) {
+				t.Errorf("ArgTypes() = %v, want %v", w.ArgTypes(), tt.argTypes)
+			}
+			results, err := w.CallWithStrings(ctx, tt.strs...)
+			if err != nil || !reflect.DeepEqual(results, tt.wantResults) {
+				t.Errorf("CallWithStrings() = %#v, %v, want %#v", results, err, tt.wantResults)
+			}
+			results, err = w.CallWithJSON(ctx, []byte(tt.argsJSON))
+			if err != nil || !reflect.DeepEqual(results, tt.wantResults) {
+				t.Errorf("CallWithJSON() = %#v, %v, want %#v", results, err, tt.wantResults)
+			}
+		})
+	}
+
+	// The name is kept by WithJSONTypeReplacement
+	// and used in errors
+	w := MustReflectWrapperOf("LookupIntString", genericLookup[int, string], "m", "key").
+		WithJSONTypeReplacement(ReflectType[jsonShape](), ReflectType[jsonSquare]())
+	_, err := w.CallWithStrings(ctx, "{}", "NaN")
+	if err == nil || !strings.Contains(err.Error(), "LookupIntString") {
+		t.Errorf("CallWithStrings() error = %v, want name LookupIntString", err)
+	}
+
+	_, err = ReflectWrapperOf("NotAFunc", 1)
+	if err == nil {
+		t.Error("expected error for non function")
+	}
+}
+
+type jsonShape interface {
+	Area() float64
+}
+
+type jsonSquare struct {
+	Side float64 `json:"side"`
+}
+
+func (s jsonSquare) Area() float64 { return s.Side * s.Side }
+
+func totalArea(first jsonShape, more ...jsonShape) float64 {
+	area := first.Area()
+	for _, shape := range more {
+		area += shape.Area()
+	}
+	return area
+}
+
+func TestReflectWrapper_WithJSONTypeReplacement(t *testing.T) {
+	ctx := context.Background()
+	argsJSON := []byte(`{"first":{"side":2},"more":[{"side":1},{"side":3}]}`)
+
+	w := MustReflectWrapper(totalArea, "first", "more")
+	_, err := w.CallWithJSON(ctx, argsJSON)
+	if err == nil {
+		t.Fatal("expected error unmarshalling JSON into interface")
+	}
+
+	replaced := w.WithJSONTypeReplacement(ReflectType[jsonShape](), ReflectType[jsonSquare]())
+	results, err := replaced.CallWithJSON(ctx, argsJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(results, []any{14.0}) {
+		t.Errorf("CallWithJSON() = %#v, want 14", results)
+	}
+	results, err = replaced.CallWithJSON(ctx, []byte(`{"first":{"side":2}}`))
+	if err != nil || !reflect.DeepEqual(results, []any{4.0}) {
+		t.Errorf("CallWithJSON() without variadic = %#v, %v", results, err)
+	}
+	if _, err = w.CallWithJSON(ctx, argsJSON); err == nil {
+		t.Error("WithJSONTypeReplacement changed the original wrapper")
+	}
+
+	structArgs := MustReflectStructArgsWrapper(func(args struct{ Shape jsonShape }) float64 { return args.Shape.Area() }).
+		WithJSONTypeReplacement(ReflectType[jsonShape](), ReflectType[jsonSquare]())
+	results, err = structArgs.CallWithJSON(ctx, []byte(`{"Shape":{"side":5}}`))
+	if err != nil || !reflect.DeepEqual(results, []any{25.0}) {
+		t.Errorf("struct args CallWithJSON() = %#v, %v", results, err)
+	}
+
+	for name, replace := range map[string]func(){
+		"not interface":   func() { w.WithJSONTypeReplacement(ReflectType[jsonSquare](), ReflectType[jsonSquare]()) },
+		"not implemented": func() { w.WithJSONTypeReplacement(ReflectType[jsonShape](), ReflectType[string]()) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic")
+				}
+			}()
+			replace()
+		})
+	}
+}
+
+func benchmarkReflectWrapper() Wrapper {
+	return MustReflectWrapper(
+		func(ctx context.Context, name string, count int, ratio float64) (string, error) {
+			return name, nil
+		},
+		"ctx", "name", "count", "ratio",
+	)
+}
+
+func BenchmarkReflectWrapper_Call(b *testing.B) {
+	w := benchmarkReflectWrapper()
+	ctx := context.Background()
+	args := []any{"name", 3, 0.5}
+	b.ReportAllocs()
+	for range b.N {
+		_, err := w.Call(ctx, args)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReflectWrapper_CallWithStrings(b *testing.B) {
+	w := benchmarkReflectWrapper()
+	ctx := context.Background()
+	b.ReportAllocs()
+	for range b.N {
+		_, err := w.CallWithStrings(ctx, "name", "3", "0.5")
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReflectWrapper_CallWithNamedStrings(b *testing.B) {
+	w := benchmarkReflectWrapper()
+	ctx := context.Background()
+	strs := map[string]string{"name": "name", "count": "3", "ratio": "0.5"}
+	b.ReportAllocs()
+	for range b.N {
+		_, err := w.CallWithNamedStrings(ctx, strs)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReflectWrapper_CallWithJSON(b *testing.B) {
+	w := benchmarkReflectWrapper()
+	ctx := context.Background()
+	argsJSON := []byte(`{"name":"name","count":3,"ratio":0.5}`)
+	b.ReportAllocs()
+	for range b.N {
+		_, err := w.CallWithJSON(ctx, argsJSON)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -6,10 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"os"
 	"reflect"
-	"strings"
-	"unicode"
 	"unicode/utf8"
 )
 
@@ -23,6 +20,57 @@ func (f ResultsHandlerFunc) HandleResults(ctx context.Context, results []any, re
 	return f(ctx, results, resultErr)
 }
 
+// ChainResultsHandlers returns a ResultsHandler that calls
+// the passed handlers in order with the results
+// and the error passed down the chain:
+//
+//   - A handler returning the error it was called with
+//     passes the error on to the next handler.
+//   - A handler returning nil for an error consumes it,
+//     so the following handlers are called without an error.
+//   - A handler returning another error stops the chain
+//     and the chain returns that error.
+//
+// The chain returns the error passed on by the last handler.
+// Nil handlers are skipped.
+//
+// Example of a pipeline that logs errors without consuming them,
+// then prints and persists the results if there was no error:
+//
+//	function.ChainResultsHandlers(logErrors, function.Println, function.WriteResultsToFile("out.txt"))
+func ChainResultsHandlers(handlers ...ResultsHandler) ResultsHandlerFunc {
+	return func(ctx context.Context, results []any, resultErr error) error {
+		for _, handler := range handlers {
+			if handler == nil {
+				continue
+			}
+			err := handler.HandleResults(ctx, results, resultErr)
+			if err != nil && !isSameError(err, resultErr) {
+				return err
+			}
+			resultErr = err
+		}
+		return resultErr
+	}
+}
+
+// isSameError returns if a and b are the same error
+// without panicking for errors of uncomparable types
+// like ErrUnknownArgs.
+func isSameError(a, b error) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	t := reflect.TypeOf(a)
+	if t != reflect.TypeOf(b) {
+		return false
+	}
+	if !t.Comparable() {
+		return reflect.DeepEqual(a, b)
+	}
+	return a == b
+}
+
 // writePaddedTextTable prints a string table to an io.Writer
 // padding the table with spaces and using the passed
 // columnDelimiter between columns.
@@ -70,59 +118,6 @@ func writePaddedTextTable(w io.Writer, rows [][]string, columnDelimiter string)
 	return nil
 }
 
-// makeResultsPrintable converts the elements of the results slice
-// to strings with a readable representation.
-// It modifies the results slice in place.
-func makeResultsPrintable(results []any) error {
-	for i, result := range results {
-		switch x := result.(type) {
-		case fmt.GoStringer:
-			results[i] = x.GoString()
-
-		case fmt.Stringer:
-			results[i] = x.String()
-
-		case []string:
-			results[i] = strings.Join(x, "\n")
-
-		case []byte:
-			if utf8.Valid(x) && bytes.IndexFunc(x, func(r rune) bool { return !unicode.IsPrint(r) }) == -1 {
-				results[i] = string(x)
-			} else {
-				results[i] = fmt.Sprintf("%#x", x)
-			}
-
-		case [][]string:
-			var b strings.Builder
-			err := writePaddedTextTable(&b, x, "|")
-			if err != nil {
-				return fmt.Errorf("can't print command result padded text table because: %w", err)
-			}
-			results[i] = b.String()
-
-		default:
-			switch v := derefValue(reflect.ValueOf(result)); v.Kind() {
-			case reflect.Float32, reflect.Float64:
-				// Print with up to 12 decimals precission
-				results[i] = strings.TrimRight(fmt.Sprintf("%.12f", v.Interface()), "0")
-
-			case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
-				b, err := json.MarshalIndent(result, "", "  ")
-				if err != nil {
-					return fmt.Errorf("can't print command result as JSON because: %w", err)
-				}
-				results[i] = string(b)
-
-			case reflect.Func, reflect.Chan:
-				// Use Go source representation for functional types
-				// that have no useful printable value
-				results[i] = fmt.Sprintf("%#v", result)
-			}
-		}
-	}
-	return nil
-}
-
 // PrintTo calls fmt.Fprint on writer with the result values as varidic arguments
 func PrintTo(writer io.Writer) ResultsHandlerFunc {
 	return func(ctx context.Context, results []any, resultErr error) error {
@@ -144,36 +139,17 @@ func PrintlnTo(writer io.Writer) ResultsHandlerFunc {
 		if resultErr != nil {
 			return resultErr
 		}
-		err := makeResultsPrintable(results)
-		if err != nil {
-			return err
-		}
-		for _, r := range results {
-			_, err = fmt.Fprintln(writer, r)
-			if err != nil {
-				return err
-			}
-		}
-		return nil
+		return writePrintableLines(writer, "", results)
 	}
 }
 
 // Println calls fmt.Println for every result
+// or writes to the output of ContextWithOutput.
 var Println ResultsHandlerFunc = func(ctx context.Context, results []any, resultErr error) error {
 	if resultErr != nil {
 		return resultErr
 	}
-	err := makeResultsPrintable(results)
-	if err != nil {
-		return err
-	}
-	for _, r := range results {
-		_, err = fmt.Println(r)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+	return writePrintableLines(OutputFromContext(ctx), "", results)
 }
 
 // PrintlnWithPrefixTo calls fmt.Fprintln(writer, prefix, result) for every result value
@@ -182,32 +158,40 @@ func PrintlnWithPrefixTo(prefix string, writer io.Writer) ResultsHandlerFunc {
 		if resultErr != nil {
 			return resultErr
 		}
-		err := makeResultsPrintable(results)
-		if err != nil {
-			return err
-		}
-		for _, result := range results {
-			_, err = fmt.Fprintln(writer, prefix, result)
-			if err != nil {
-				return err
-			}
-		}
-		return nil
+		return writePrintableLines(writer, prefix+" ", results)
 	}
 }
 
 // PrintlnWithPrefix calls fmt.Println(prefix, result) for every result value
+// or writes to the output of ContextWithOutput.
 func PrintlnWithPrefix(prefix string) ResultsHandlerFunc {
 	return func(ctx context.Context, results []any, resultErr error) error {
 		if resultErr != nil {
 			return resultErr
 		}
-		err := makeResultsPrintable(results)
-		if err != nil {
-			return err
+		return writePrintableLines(OutputFromContext(ctx), prefix+" ", results)
+	}
+}
+
+// PrintColorJSONTo prints every result as JSON indented with PrettyPrintIndent
+// to writer with syntax highlighting if writer is a terminal
+// and the NO_COLOR environment variable is not set,
+// else as plain indented JSON.
+func PrintColorJSONTo(writer io.Writer) ResultsHandlerFunc {
+	return func(ctx context.Context, results []any, resultErr error) error {
+		if resultErr != nil {
+			return resultErr
 		}
+		color := isColorTerminal(writer)
 		for _, result := range results {
-			_, err = fmt.Println(prefix, result)
+			data, err := json.MarshalIndent(result, "", PrettyPrintIndent)
+			if err != nil {
+				return fmt.Errorf("can't print result as JSON because: %w", err)
+			}
+			if color {
+				data = colorizeJSON(data)
+			}
+			_, err = writer.Write(append(data, '\n'))
 			if err != nil {
 				return err
 			}
@@ -216,6 +200,14 @@ func PrintlnWithPrefix(prefix string) ResultsHandlerFunc {
 	}
 }
 
+// PrintColorJSON prints every result as indented JSON to os.Stdout
+// or the output of ContextWithOutput
+// with syntax highlighting if it is a terminal.
+// See PrintColorJSONTo.
+var PrintColorJSON ResultsHandlerFunc = func(ctx context.Context, results []any, resultErr error) error {
+	return PrintColorJSONTo(OutputFromContext(ctx))(ctx, results, resultErr)
+}
+
 // Logger interface
 type Logger interface {
 	Printf(format string, args ...any)
@@ -253,13 +245,14 @@ func LogWithPrefixTo(prefix string, logger Logger) ResultsHandlerFunc {
 }
 
 // PrintlnText prints a fixed string if a command returns without an error
+// to os.Stdout or the output of ContextWithOutput.
 type PrintlnText string
 
 func (t PrintlnText) HandleResults(ctx context.Context, results []any, resultErr error) error {
 	if resultErr != nil {
 		return resultErr
 	}
-	_, err := fmt.Println(t)
+	_, err := fmt.Fprintln(OutputFromContext(ctx), t)
 	return err
 }
 
@@ -270,98 +263,3 @@ func derefValue(v reflect.Value) reflect.Value {
 	}
 	return v
 }
-
-// PrintStructSliceAsTable prints a slice of structs or struct pointers as a padded table
-// to os.Stdout using fmt.Sprint to format field values.
-var PrintStructSliceAsTable ResultsHandlerFunc = func(ctx context.Context, results []any, resultErr error) error {
-	if resultErr != nil {
-		return resultErr
-	}
-	if len(results) == 0 {
-		return nil
-	}
-	for i, result := range results {
-		// Check if all results are slices of the same type
-		resultType := reflect.TypeOf(result)
-		if resultType.Kind() != reflect.Slice && resultType.Kind() != reflect.Array {
-			return fmt.Errorf("expected slice or array, got %T for result %d", result, i)
-		}
-		if resultType.Elem().Kind() != reflect.Struct && resultType.Elem().Kind() != reflect.Ptr {
-			return fmt.Errorf("expected slice/array of structs or struct pointers, got %T for result %d", result, i)
-		}
-		isPtr := resultType.Elem().Kind() == reflect.Ptr
-		structType := resultType.Elem()
-		if isPtr {
-			structType = structType.Elem()
-		}
-		var header []string
-		for col := 0; col < structType.NumField(); col++ {
-			header = append(header, structType.Field(col).Name)
-		}
-		rows := [][]string{header}
-
-		sliceVal := reflect.ValueOf(result)
-		for row := 0; row < sliceVal.Len(); row++ {
-			structVal := sliceVal.Index(row)
-			if isPtr {
-				if structVal.IsNil() {
-					continue
-				}
-				structVal = structVal.Elem()
-			}
-			fieldStrings := make([]string, structType.NumField())
-			for col := 0; col < structType.NumField(); col++ {
-				fieldStrings[col] = fmt.Sprint(structVal.Field(col).Interface())
-			}
-			rows = append(rows, fieldStrings)
-		}
-
-		colWidths := make([]int, structType.NumField())
-		for row := range rows {
-			for col := 0; col < structType.NumField() && col < len(rows[row]); col++ {
-				numRunes := utf8.RuneCountInString(rows[row][col])
-				colWidths[col] = max(colWidths[col], numRunes)
-			}
-		}
-
-		// Write table to os.Stdout
-		if i > 0 {
-			fmt.Println() // Add line between tables
-		}
-		w := os.Stdout
-		var err error
-		for _, rowStrs := range rows {
-			for col, colWidth := range colWidths {
-				switch {
-				case col == 0:
-					_, err = w.Write([]byte("| "))
-				case col < len(colWidths):
-					_, err = w.Write([]byte(" | "))
-				}
-				if err != nil {
-					return err
-				}
-				str := ""
-				if col < len(rowStrs) {
-					str = rowStrs[col]
-				}
-				_, err = io.WriteString(w, str)
-				if err != nil {
-					return err
-				}
-				strLen := utf8.RuneCountInString(str)
-				for i := strLen; i < colWidth; i++ {
-					_, err = w.Write([]byte{' '})
-					if err != nil {
-						return err
-					}
-				}
-			}
-			_, err = w.Write([]byte(" |\n"))
-			if err != nil {
-				return err
-			}
-		}
-	}
-	return nil
-}
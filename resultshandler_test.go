@@ -2,6 +2,9 @@ package function
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -84,3 +87,84 @@ func TestPrintlnTo(t *testing.T) {
 		})
 	}
 }
+
+func TestChainResultsHandlers(t *testing.T) {
+	resultErr := NewErrUnknownArgs(nil, []string{"x"})
+	handlerErr := errors.New("handler failed")
+	var called []string
+	record := func(name string, returnErr func(resultErr error) error) ResultsHandler {
+		return ResultsHandlerFunc(func(ctx context.Context, results []any, resultErr error) error {
+			called = append(called, fmt.Sprintf("%s:%v", name, resultErr != nil))
+			return returnErr(resultErr)
+		})
+	}
+	passOn := func(resultErr error) error { return resultErr }
+	consume := func(error) error { return nil }
+	fail := func(error) error { return handlerErr }
+
+	tests := []struct {
+		name       string
+		handlers   []ResultsHandler
+		resultErr  error
+		wantErr    error
+		wantCalled []string
+	}{
+		{
+			name:       "pass on",
+			handlers:   []ResultsHandler{record("a", passOn), nil, record("b", passOn)},
+			resultErr:  resultErr,
+			wantErr:    resultErr,
+			wantCalled: []string{"a:true", "b:true"},
+		},
+		{
+			name:       "consume",
+			handlers:   []ResultsHandler{record("a", consume), record("b", passOn)},
+			resultErr:  resultErr,
+			wantErr:    nil,
+			wantCalled: []string{"a:true", "b:false"},
+		},
+		{
+			name:       "fail",
+			handlers:   []ResultsHandler{record("a", fail), record("b", passOn)},
+			resultErr:  nil,
+			wantErr:    handlerErr,
+			wantCalled: []string{"a:false"},
+		},
+		{
+			name:       "fail with error",
+			handlers:   []ResultsHandler{record("a", passOn), record("b", fail), record("c", passOn)},
+			resultErr:  resultErr,
+			wantErr:    handlerErr,
+			wantCalled: []string{"a:true", "b:true"},
+		},
+		{
+			name:      "empty",
+			resultErr: resultErr,
+			wantErr:   resultErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = nil
+			err := ChainResultsHandlers(tt.handlers...).HandleResults(context.Background(), nil, tt.resultErr)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("ChainResultsHandlers() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(called, tt.wantCalled) {
+				t.Errorf("called %v, want %v", called, tt.wantCalled)
+			}
+		})
+	}
+}
+
+func TestPrintlnWithPrefixTo(t *testing.T) {
+	var b strings.Builder
+	err := PrintlnWithPrefixTo("", &b)(context.Background(), []any{1, []string{"a", "b"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Like fmt.Println(prefix, result) with an empty prefix
+	if want := " 1\n a\nb\n"; b.String() != want {
+		t.Errorf("PrintlnWithPrefixTo() printed %q, want %q", b.String(), want)
+	}
+}
@@ -0,0 +1,84 @@
+package function
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// KeyValue is a key value pair of an iter.Seq2 result
+// streamed by StreamResults.
+type KeyValue struct {
+	Key   any `json:"key"`
+	Value any `json:"value"`
+}
+
+// StreamResults calls yield for every item of channel
+// and iter.Seq results as they arrive
+// and with a KeyValue for every pair of iter.Seq2 results.
+// Wrapped functions producing large result sets
+// stream their results by returning a channel or an iterator
+// instead of a slice, so the results don't have to be buffered.
+// An iter.Seq2 with error values yields only the values
+// and the streaming stops with the first non nil error.
+// Other results are passed to yield as they are.
+//
+// The streaming stops with the error of ctx if it is canceled
+// or with the first error returned by yield.
+func StreamResults(ctx context.Context, results []any, yield func(result any) error) error {
+	for _, result := range results {
+		err := streamResult(ctx, result, func(items ...any) error {
+			if len(items) == 2 {
+				return yield(KeyValue{Key: items[0], Value: items[1]})
+			}
+			return yield(items[0])
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintNDJSONTo returns a ResultsHandler that writes every item
+// streamed from the results by StreamResults
+// as a line of JSON to writer as it arrives.
+// The writer is flushed after every line if it has a Flush method.
+func PrintNDJSONTo(writer io.Writer) ResultsHandlerFunc {
+	return func(ctx context.Context, results []any, resultErr error) error {
+		if resultErr != nil {
+			return resultErr
+		}
+		return StreamResults(ctx, results, func(result any) error {
+			return writeJSONLine(writer, result)
+		})
+	}
+}
+
+// PrintNDJSON writes every item streamed from the results
+// as a line of JSON to os.Stdout or the output of ContextWithOutput.
+// See PrintNDJSONTo.
+var PrintNDJSON ResultsHandlerFunc = func(ctx context.Context, results []any, resultErr error) error {
+	return PrintNDJSONTo(OutputFromContext(ctx))(ctx, results, resultErr)
+}
+
+// writeJSONLine writes value as JSON followed by a newline
+// with a single Write call and flushes writer.
+func writeJSONLine(writer io.Writer, value any) error {
+	line, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("can't stream result as JSON because: %w", err)
+	}
+	_, err = writer.Write(append(line, '\n'))
+	if err != nil {
+		return err
+	}
+	switch f := writer.(type) {
+	case interface{ Flush() error }:
+		return f.Flush()
+	case interface{ Flush() }:
+		f.Flush()
+	}
+	return nil
+}
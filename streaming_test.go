@@ -0,0 +1,77 @@
+package function
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPrintNDJSONTo(t *testing.T) {
+	errStop := errors.New("stop")
+	seq2Err := func(yield func(int, error) bool) {
+		_ = yield(1, nil) && yield(0, errStop) && yield(2, nil)
+	}
+	seq2 := func(yield func(string, int) bool) {
+		yield("a", 1)
+	}
+
+	var w flushRecorder
+	err := PrintNDJSONTo(&w).HandleResults(context.Background(), []any{[]int{1, 2}, seq2}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "[1,2]\n{\"key\":\"a\",\"value\":1}\n"; w.String() != want {
+		t.Errorf("PrintNDJSONTo() printed %q, want %q", w.String(), want)
+	}
+	if len(w.flushed) != 2 {
+		t.Errorf("expected a flush per line, got %q", w.flushed)
+	}
+
+	w = flushRecorder{}
+	err = PrintNDJSONTo(&w).HandleResults(context.Background(), []any{seq2Err}, nil)
+	if !errors.Is(err, errStop) {
+		t.Errorf("expected error of iterator, got %v", err)
+	}
+	if w.String() != "1\n" {
+		t.Errorf("PrintNDJSONTo() printed %q before error, want %q", w.String(), "1\n")
+	}
+}
+
+func TestStreamResults(t *testing.T) {
+	ch := make(chan string, 2)
+	ch <- "a"
+	ch <- "b"
+	close(ch)
+	seq2 := func(yield func(string, int) bool) {
+		_ = yield("c", 1) && yield("d", 2)
+	}
+	results, err := MustReflectWrapper(func() (<-chan string, int) { return ch, 3 }).Call(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []any
+	err = StreamResults(context.Background(), append(results, seq2), func(result any) error {
+		got = append(got, result)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []any{"a", "b", 3, KeyValue{"c", 1}, KeyValue{"d", 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("streamed %#v, want %#v", got, want)
+	}
+
+	// The error of yield stops the streaming
+	errStop := errors.New("stop")
+	got = nil
+	err = StreamResults(context.Background(), []any{seq2, 4}, func(result any) error {
+		got = append(got, result)
+		return errStop
+	})
+	if !errors.Is(err, errStop) || len(got) != 1 {
+		t.Errorf("streamed %#v, %v after error", got, err)
+	}
+}
@@ -0,0 +1,153 @@
+package function
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// PrintStreamTo returns a ResultsHandler that prints the items
+// of channel and iterator results to writer as they arrive,
+// so long running functions streaming their results show progress.
+//
+// Every item of a channel result is printed as a line until the channel
+// is closed, iter.Seq results are printed per item and iter.Seq2
+// results per key value pair separated by a space.
+// An iter.Seq2 with error values is printed per value
+// until it yields an error that is returned.
+// Items are formatted like Println formats results.
+// Other results are printed like Println prints them.
+//
+// Every line is written with a single Write call and writer
+// is flushed after every line if it has a Flush method
+// like bufio.Writer or http.ResponseWriter.
+// The streaming stops with the error of ctx if it is canceled.
+func PrintStreamTo(writer io.Writer) ResultsHandlerFunc {
+	return func(ctx context.Context, results []any, resultErr error) error {
+		if resultErr != nil {
+			return resultErr
+		}
+		for _, result := range results {
+			err := printStreamResult(ctx, writer, result)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// PrintStream prints the items of channel and iterator results
+// to os.Stdout or the output of ContextWithOutput as they arrive.
+// See PrintStreamTo.
+var PrintStream ResultsHandlerFunc = func(ctx context.Context, results []any, resultErr error) error {
+	return PrintStreamTo(OutputFromContext(ctx))(ctx, results, resultErr)
+}
+
+func printStreamResult(ctx context.Context, writer io.Writer, result any) error {
+	return streamResult(ctx, result, func(items ...any) error {
+		return printStreamLine(writer, items...)
+	})
+}
+
+// streamResult calls yield with every item of a channel
+// or iter.Seq result and with every key and value
+// of an iter.Seq2 result as they arrive,
+// or once with result if it is not a channel or iterator.
+// An iter.Seq2 with error values yields only the values
+// and the streaming stops with the first non nil error.
+// The streaming stops with the error of ctx if it is canceled
+// or with the first error returned by yield.
+func streamResult(ctx context.Context, result any, yield func(items ...any) error) error {
+	v := reflect.ValueOf(result)
+	switch {
+	case v.Kind() == reflect.Chan && v.Type().ChanDir()&reflect.RecvDir != 0 && !v.IsNil():
+		cases := []reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+			{Dir: reflect.SelectRecv, Chan: v},
+		}
+		for {
+			chosen, item, ok := reflect.Select(cases)
+			if chosen == 0 {
+				return ctx.Err()
+			}
+			if !ok {
+				return nil
+			}
+			err := yield(item.Interface())
+			if err != nil {
+				return err
+			}
+		}
+
+	case v.IsValid() && isIterSeq(v.Type()):
+		var (
+			err      error
+			yieldT   = v.Type().In(0)
+			errorSeq = yieldT.NumIn() == 2 && yieldT.In(1) == typeOfError
+		)
+		yieldFunc := reflect.MakeFunc(yieldT, func(args []reflect.Value) []reflect.Value {
+			// Keep the first error if the iterator
+			// ignores that yield returned false
+			if err == nil {
+				err = ctx.Err()
+			}
+			if err == nil && errorSeq {
+				// iter.Seq2[V, error] yields values or an error
+				if e := args[1]; !e.IsNil() {
+					err = e.Interface().(error)
+				}
+				args = args[:1]
+			}
+			if err == nil {
+				items := make([]any, len(args))
+				for i, arg := range args {
+					items[i] = arg.Interface()
+				}
+				err = yield(items...)
+			}
+			return []reflect.Value{reflect.ValueOf(err == nil)}
+		})
+		if !v.IsNil() {
+			v.Call([]reflect.Value{yieldFunc})
+		}
+		return err
+
+	default:
+		return yield(result)
+	}
+}
+
+// isIterSeq returns true if t is a function type
+// like iter.Seq or iter.Seq2.
+func isIterSeq(t reflect.Type) bool {
+	if t == nil || t.Kind() != reflect.Func || t.NumIn() != 1 || t.NumOut() != 0 {
+		return false
+	}
+	yield := t.In(0)
+	return yield.Kind() == reflect.Func &&
+		(yield.NumIn() == 1 || yield.NumIn() == 2) &&
+		yield.NumOut() == 1 && yield.Out(0).Kind() == reflect.Bool
+}
+
+// printStreamLine writes the printable items as line
+// with a single Write call and flushes writer.
+func printStreamLine(writer io.Writer, items ...any) error {
+	err := makeResultsPrintable(items)
+	if err != nil {
+		return err
+	}
+	line := fmt.Sprintln(items...)
+	_, err = io.WriteString(writer, line)
+	if err != nil {
+		return err
+	}
+	switch f := writer.(type) {
+	case interface{ Flush() error }:
+		return f.Flush()
+	case interface{ Flush() }:
+		f.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package function
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+type flushRecorder struct {
+	strings.Builder
+	flushed []string
+}
+
+func (w *flushRecorder) Flush() error {
+	w.flushed = append(w.flushed, w.String())
+	return nil
+}
+
+func TestPrintStreamTo(t *testing.T) {
+	ch := make(chan int)
+	go func() {
+		for i := range 3 {
+			ch <- i
+		}
+		close(ch)
+	}()
+	seq := func(yield func(string) bool) {
+		_ = yield("a") && yield("b")
+	}
+	seq2 := func(yield func(string, float64) bool) {
+		yield("pi", 3.14)
+	}
+
+	var w flushRecorder
+	err := PrintStreamTo(&w).HandleResults(context.Background(), []any{ch, seq, seq2, "done"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "0\n1\n2\na\nb\npi 3.14\ndone\n"; w.String() != want {
+		t.Errorf("PrintStreamTo() printed %q, want %q", w.String(), want)
+	}
+	if len(w.flushed) != 7 || w.flushed[0] != "0\n" {
+		t.Errorf("expected a flush per line, got %q", w.flushed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = PrintStreamTo(io.Discard).HandleResults(ctx, []any{make(chan int)}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled for blocking channel, got %v", err)
+	}
+	var yielded int
+	endless := func(yield func(int) bool) {
+		for yield(yielded) {
+			yielded++
+		}
+	}
+	err = PrintStreamTo(io.Discard).HandleResults(ctx, []any{endless}, nil)
+	if !errors.Is(err, context.Canceled) || yielded != 0 {
+		t.Errorf("expected context.Canceled for iterator, got %v after %d items", err, yielded)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -224,7 +225,16 @@ func scanString(sourceStr string, destVal reflect.Value) (err error) {
 		}
 		return nil
 
-	case reflect.Map, reflect.Chan, reflect.Func:
+	case reflect.Map:
+		if sourceStrNil {
+			destVal.SetZero()
+			return nil
+		}
+		// Maps of any key and value type like map[int]string
+		// of instantiated generic functions are scanned as JSON
+		return json.Unmarshal([]byte(sourceStr), destPtr)
+
+	case reflect.Chan, reflect.Func:
 		if sourceStrNil {
 			destVal.SetZero()
 			return nil
@@ -232,6 +242,13 @@ func scanString(sourceStr string, destVal reflect.Value) (err error) {
 		return fmt.Errorf("%w: %s", ErrTypeNotSupported, destVal.Type())
 	}
 
+	// Parse the common number and bool formats with strconv
+	// which does not allocate like fmt.Sscan.
+	// Other formats and fmt.Scanner implementations use fmt.Sscan.
+	if _, ok := destPtr.(fmt.Scanner); !ok && scanStrconv(sourceStr, destVal) {
+		return nil
+	}
+
 	// If all else fails, use fmt scanning
 	// for generic type conversation from string
 	_, err = fmt.Sscan(sourceStr, destPtr)
@@ -242,6 +259,46 @@ func scanString(sourceStr string, destVal reflect.Value) (err error) {
 	return nil
 }
 
+// scanStrconv sets destVal of a number or bool kind
+// to sourceStr parsed with strconv and returns true
+// or returns false if that was not possible.
+func scanStrconv(sourceStr string, destVal reflect.Value) bool {
+	switch destVal.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(sourceStr, 0, destVal.Type().Bits())
+		if err != nil {
+			return false
+		}
+		destVal.SetInt(i)
+		return true
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(sourceStr, 0, destVal.Type().Bits())
+		if err != nil {
+			return false
+		}
+		destVal.SetUint(u)
+		return true
+
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(sourceStr, destVal.Type().Bits())
+		if err != nil {
+			return false
+		}
+		destVal.SetFloat(f)
+		return true
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(sourceStr)
+		if err != nil {
+			return false
+		}
+		destVal.SetBool(b)
+		return true
+	}
+	return false
+}
+
 func sliceLiteralFields(sourceStr string) (fields []string, err error) {
 	if !strings.HasPrefix(sourceStr, "[") {
 		return nil, fmt.Errorf("slice value %q does not begin with '['", sourceStr)
@@ -85,6 +85,31 @@ func TestScanString(t *testing.T) {
 			args:     args{sourceStr: "666", destPtr: new(int)},
 			wantDest: int(666),
 		},
+		{
+			name:     "hex int(16)",
+			args:     args{sourceStr: "0x10", destPtr: new(int)},
+			wantDest: int(16),
+		},
+		{
+			name:     "int with leading space scanned by fmt",
+			args:     args{sourceStr: " 7", destPtr: new(int)},
+			wantDest: int(7),
+		},
+		{
+			name:     "uint8(255)",
+			args:     args{sourceStr: "255", destPtr: new(uint8)},
+			wantDest: uint8(255),
+		},
+		{
+			name:     "float32(0.5)",
+			args:     args{sourceStr: "0.5", destPtr: new(float32)},
+			wantDest: float32(0.5),
+		},
+		{
+			name:     "bool(true)",
+			args:     args{sourceStr: "true", destPtr: new(bool)},
+			wantDest: true,
+		},
 		{
 			name:     "empty string map[int]int",
 			args:     args{sourceStr: "", destPtr: &intMap},
@@ -100,6 +125,11 @@ func TestScanString(t *testing.T) {
 			args:     args{sourceStr: "null", destPtr: &intMap},
 			wantDest: map[int]int(nil),
 		},
+		{
+			name:     "JSON map[int]int",
+			args:     args{sourceStr: `{"1":2}`, destPtr: &intMap},
+			wantDest: map[int]int{1: 2},
+		},
 		{
 			name:     "nil bool",
 			args:     args{sourceStr: "false", destPtr: &boolPtr},
@@ -112,6 +142,11 @@ func TestScanString(t *testing.T) {
 		},
 
 		// // wantErr
+		{
+			name:    "uint8 overflow",
+			args:    args{sourceStr: "256", destPtr: new(uint8)},
+			wantErr: true,
+		},
 		{
 			name:    "nil destPtr",
 			args:    args{sourceStr: "nil", destPtr: nil},
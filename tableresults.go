@@ -0,0 +1,269 @@
+package function
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// TableOptions configure the tables printed by PrintTable.
+type TableOptions struct {
+	// Writer of the tables, if nil os.Stdout
+	// or the output of ContextWithOutput
+	Writer io.Writer
+	// Columns selects the printed columns by name in that order.
+	// All columns are printed if empty.
+	Columns []string
+	// SortBy is the name of the column the rows are sorted by.
+	// The rows are not sorted if empty.
+	SortBy string
+	// SortDescending sorts the rows in descending order
+	SortDescending bool
+	// Limit is the maximum number of printed rows,
+	// zero prints all rows
+	Limit int
+}
+
+// PrintStructSliceAsTable prints a slice of structs or struct pointers as a padded table
+// to os.Stdout using fmt.Sprint to format field values.
+// It is PrintTable with default TableOptions.
+var PrintStructSliceAsTable ResultsHandlerFunc = PrintTable(TableOptions{})
+
+// PrintTable returns a ResultsHandler that prints every result as padded table
+// using fmt.Sprint to format the cell values.
+// A slice or array of structs or struct pointers is printed
+// with a column per exported struct field named like the field
+// or by the TableColumnTag struct tag,
+// fields tagged with "-" are not printed.
+// A slice or array of other types is printed
+// with a single column named "Value"
+// and a map with the columns "Key" and "Value" sorted by key.
+//
+// An error is returned for other result types
+// or if the options name columns that don't exist.
+func PrintTable(options TableOptions) ResultsHandlerFunc {
+	return func(ctx context.Context, results []any, resultErr error) error {
+		if resultErr != nil {
+			return resultErr
+		}
+		w := options.Writer
+		if w == nil {
+			w = OutputFromContext(ctx)
+		}
+		for i, result := range results {
+			table, err := newResultTable(result)
+			if err != nil {
+				return fmt.Errorf("can't print result %d as table because: %w", i, err)
+			}
+			err = table.apply(&options)
+			if err != nil {
+				return fmt.Errorf("can't print result %d as table because: %w", i, err)
+			}
+			if i > 0 {
+				// Add line between tables
+				_, err = io.WriteString(w, "\n")
+				if err != nil {
+					return err
+				}
+			}
+			err = table.write(w)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// resultTable is a result converted to table rows
+// of the cell values.
+type resultTable struct {
+	columns []string
+	rows    [][]reflect.Value
+}
+
+func newResultTable(result any) (*resultTable, error) {
+	v := derefValue(reflect.ValueOf(result))
+	switch v.Kind() {
+	case reflect.Map:
+		t := &resultTable{columns: []string{"Key", "Value"}}
+		iter := v.MapRange()
+		for iter.Next() {
+			t.rows = append(t.rows, []reflect.Value{iter.Key(), iter.Value()})
+		}
+		sort.SliceStable(t.rows, func(i, j int) bool {
+			return compareTableValues(t.rows[i][0], t.rows[j][0]) < 0
+		})
+		return t, nil
+
+	case reflect.Slice, reflect.Array:
+		elemType := v.Type().Elem()
+		isPtr := elemType.Kind() == reflect.Pointer
+		if isPtr {
+			elemType = elemType.Elem()
+		}
+		if elemType.Kind() != reflect.Struct {
+			t := &resultTable{columns: []string{"Value"}}
+			for i := range v.Len() {
+				t.rows = append(t.rows, []reflect.Value{v.Index(i)})
+			}
+			return t, nil
+		}
+		t := new(resultTable)
+		var fields []int
+		for i := range elemType.NumField() {
+			field := elemType.Field(i)
+			name := field.Tag.Get(TableColumnTag)
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			t.columns = append(t.columns, name)
+			fields = append(fields, i)
+		}
+		for i := range v.Len() {
+			structVal := v.Index(i)
+			if isPtr {
+				if structVal.IsNil() {
+					continue
+				}
+				structVal = structVal.Elem()
+			}
+			row := make([]reflect.Value, len(fields))
+			for col, field := range fields {
+				row[col] = structVal.Field(field)
+			}
+			t.rows = append(t.rows, row)
+		}
+		return t, nil
+	}
+	return nil, fmt.Errorf("expected slice, array or map, got %T", result)
+}
+
+// apply sorts, limits and selects the columns
+// of the table as configured by options.
+func (t *resultTable) apply(options *TableOptions) error {
+	if options.SortBy != "" {
+		col := slices.Index(t.columns, options.SortBy)
+		if col == -1 {
+			return fmt.Errorf("no column %q to sort by in columns %s", options.SortBy, strings.Join(t.columns, ", "))
+		}
+		sort.SliceStable(t.rows, func(i, j int) bool {
+			c := compareTableValues(t.rows[i][col], t.rows[j][col])
+			if options.SortDescending {
+				return c > 0
+			}
+			return c < 0
+		})
+	}
+	if options.Limit > 0 && len(t.rows) > options.Limit {
+		t.rows = t.rows[:options.Limit]
+	}
+	if len(options.Columns) > 0 {
+		indices := make([]int, len(options.Columns))
+		for i, name := range options.Columns {
+			indices[i] = slices.Index(t.columns, name)
+			if indices[i] == -1 {
+				return fmt.Errorf("no column %q in columns %s", name, strings.Join(t.columns, ", "))
+			}
+		}
+		for r, row := range t.rows {
+			selected := make([]reflect.Value, len(indices))
+			for i, col := range indices {
+				selected[i] = row[col]
+			}
+			t.rows[r] = selected
+		}
+		t.columns = options.Columns
+	}
+	return nil
+}
+
+// write writes the table with a header row
+// and padded cells to w.
+func (t *resultTable) write(w io.Writer) error {
+	rows := make([][]string, 0, len(t.rows)+1)
+	rows = append(rows, t.columns)
+	for _, row := range t.rows {
+		cells := make([]string, len(row))
+		for col, val := range row {
+			cells[col] = fmt.Sprint(val.Interface())
+		}
+		rows = append(rows, cells)
+	}
+
+	colWidths := make([]int, len(t.columns))
+	for _, row := range rows {
+		for col, str := range row {
+			colWidths[col] = max(colWidths[col], utf8.RuneCountInString(str))
+		}
+	}
+	var line strings.Builder
+	for _, row := range rows {
+		line.Reset()
+		for col, str := range row {
+			if col == 0 {
+				line.WriteString("| ")
+			} else {
+				line.WriteString(" | ")
+			}
+			line.WriteString(str)
+			line.WriteString(strings.Repeat(" ", colWidths[col]-utf8.RuneCountInString(str)))
+		}
+		line.WriteString(" |\n")
+		_, err := io.WriteString(w, line.String())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compareTableValues compares numbers, strings and bools
+// by their values and other types by their fmt.Sprint representation.
+// Nil values are less than non nil values.
+func compareTableValues(a, b reflect.Value) int {
+	a, b = derefTableValue(a), derefTableValue(b)
+	switch {
+	case !a.IsValid() || !b.IsValid():
+		return cmp.Compare(boolToInt(a.IsValid()), boolToInt(b.IsValid()))
+	case a.CanInt() && b.CanInt():
+		return cmp.Compare(a.Int(), b.Int())
+	case a.CanUint() && b.CanUint():
+		return cmp.Compare(a.Uint(), b.Uint())
+	case a.CanFloat() && b.CanFloat():
+		return cmp.Compare(a.Float(), b.Float())
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return strings.Compare(a.String(), b.String())
+	case a.Kind() == reflect.Bool && b.Kind() == reflect.Bool:
+		return cmp.Compare(boolToInt(a.Bool()), boolToInt(b.Bool()))
+	}
+	return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+}
+
+// derefTableValue dereferences pointers and interfaces
+// and returns an invalid reflect.Value for nil.
+func derefTableValue(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,89 @@
+package function
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestPrintTable(t *testing.T) {
+	type user struct {
+		Name     string
+		Age      int    `table:"Years"`
+		Password string `table:"-"`
+		internal bool
+	}
+	users := []*user{{Name: "Bob", Age: 42}, nil, {Name: "Alice", Age: 7}, {Name: "Carol", Age: 30}}
+	tests := []struct {
+		name    string
+		options TableOptions
+		results []any
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "struct pointers",
+			results: []any{users},
+			want: "" +
+				"| Name  | Years |\n" +
+				"| Bob   | 42    |\n" +
+				"| Alice | 7     |\n" +
+				"| Carol | 30    |\n",
+		},
+		{
+			name:    "sorted limited columns",
+			options: TableOptions{Columns: []string{"Years", "Name"}, SortBy: "Years", SortDescending: true, Limit: 2},
+			results: []any{users},
+			want: "" +
+				"| Years | Name  |\n" +
+				"| 42    | Bob   |\n" +
+				"| 30    | Carol |\n",
+		},
+		{
+			name:    "sort by string",
+			options: TableOptions{SortBy: "Name", Limit: 1},
+			results: []any{users},
+			want: "" +
+				"| Name  | Years |\n" +
+				"| Alice | 7     |\n",
+		},
+		{
+			name:    "map and primitives",
+			results: []any{map[string]int{"b": 2, "a": 1}, []float64{1.5, 0.25}},
+			want: "" +
+				"| Key | Value |\n" +
+				"| a   | 1     |\n" +
+				"| b   | 2     |\n" +
+				"\n" +
+				"| Value |\n" +
+				"| 1.5   |\n" +
+				"| 0.25  |\n",
+		},
+		{
+			name:    "sorted any values",
+			options: TableOptions{SortBy: "Value"},
+			results: []any{[]any{10, nil, 9}},
+			want: "" +
+				"| Value |\n" +
+				"| <nil> |\n" +
+				"| 9     |\n" +
+				"| 10    |\n",
+		},
+		{name: "not a table", results: []any{1}, wantErr: true},
+		{name: "unknown column", options: TableOptions{Columns: []string{"Password"}}, results: []any{users}, wantErr: true},
+		{name: "unknown sort column", options: TableOptions{SortBy: "x"}, results: []any{users}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			tt.options.Writer = &b
+			err := PrintTable(tt.options).HandleResults(context.Background(), tt.results, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PrintTable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && b.String() != tt.want {
+				t.Errorf("PrintTable() printed:\n%s\nwant:\n%s", b.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package function
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// PrintTemplateTo executes the text/template tmpl
+// with the results slice as data and writes the output to writer.
+// In addition to the predefined functions of text/template
+// the following functions are available in the template:
+//
+//	result i     the result with index i or nil if there is none
+//	first        the first result or nil if there are no results
+//	json x       x marshalled as compact JSON
+//	indentJSON x x marshalled as JSON indented with PrettyPrintIndent
+//	printable x  x formatted like it is printed by Println
+//	join s sep   the strings s joined with sep
+//
+// Example:
+//
+//	function.PrintTemplateTo(os.Stderr, "{{len .}} results, first: {{first | json}}\n")
+//
+// Panics if tmpl can't be parsed.
+func PrintTemplateTo(writer io.Writer, tmpl string) ResultsHandlerFunc {
+	return printTemplate(func(context.Context) io.Writer { return writer }, tmpl)
+}
+
+// PrintTemplate executes the text/template tmpl
+// with the results slice as data and writes the output
+// to os.Stdout or the output of ContextWithOutput.
+// See PrintTemplateTo for the available functions.
+//
+// Panics if tmpl can't be parsed.
+func PrintTemplate(tmpl string) ResultsHandlerFunc {
+	return printTemplate(OutputFromContext, tmpl)
+}
+
+func printTemplate(writer func(context.Context) io.Writer, tmpl string) ResultsHandlerFunc {
+	// The result functions are defined for parsing
+	// and bound to the results for every execution
+	t := template.Must(template.New("results").Funcs(templateResultsFuncs(nil)).Parse(tmpl))
+	return func(ctx context.Context, results []any, resultErr error) error {
+		if resultErr != nil {
+			return resultErr
+		}
+		exec, err := t.Clone()
+		if err != nil {
+			return err
+		}
+		return exec.Funcs(templateResultsFuncs(results)).Execute(writer(ctx), results)
+	}
+}
+
+// templateResultsFuncs returns the functions
+// of the PrintTemplateTo templates for results.
+func templateResultsFuncs(results []any) template.FuncMap {
+	result := func(i int) any {
+		if i < 0 || i >= len(results) {
+			return nil
+		}
+		return results[i]
+	}
+	return template.FuncMap{
+		"result": result,
+		"first":  func() any { return result(0) },
+		"json": func(x any) (string, error) {
+			b, err := json.Marshal(x)
+			return string(b), err
+		},
+		"indentJSON": func(x any) (string, error) {
+			b, err := json.MarshalIndent(x, "", PrettyPrintIndent)
+			return string(b), err
+		},
+		"printable": PrintableResult,
+		"join":      strings.Join,
+	}
+}
@@ -0,0 +1,53 @@
+package function
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestPrintTemplateTo(t *testing.T) {
+	type point struct{ X, Y int }
+	tests := []struct {
+		name    string
+		tmpl    string
+		results []any
+		want    string
+		wantErr bool
+	}{
+		{name: "index", tmpl: `{{index . 1}} of {{len .}}`, results: []any{"a", "b"}, want: "b of 2"},
+		{name: "result", tmpl: `{{result 0}}|{{result 5}}`, results: []any{1}, want: "1|<no value>"},
+		{name: "first json", tmpl: `{{first | json}}`, results: []any{point{1, 2}}, want: `{"X":1,"Y":2}`},
+		{name: "no results", tmpl: `{{if first}}some{{else}}none{{end}}`, results: nil, want: "none"},
+		{name: "indentJSON", tmpl: `{{indentJSON (result 0)}}`, results: []any{[]int{1}}, want: "[\n  1\n]"},
+		{name: "printable", tmpl: `{{printable (result 0)}}`, results: []any{0.25}, want: "0.25"},
+		{name: "join", tmpl: `{{join (result 0) ", "}}`, results: []any{[]string{"a", "b"}}, want: "a, b"},
+		{name: "range fields", tmpl: `{{range .}}{{.X}},{{.Y}};{{end}}`, results: []any{point{1, 2}, point{3, 4}}, want: "1,2;3,4;"},
+		{name: "execution error", tmpl: `{{index . 3}}`, results: []any{1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			err := PrintTemplateTo(&b, tt.tmpl).HandleResults(context.Background(), tt.results, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PrintTemplateTo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && b.String() != tt.want {
+				t.Errorf("PrintTemplateTo() printed %q, want %q", b.String(), tt.want)
+			}
+		})
+	}
+
+	resultErr := errors.New("failed")
+	if err := PrintTemplateTo(io.Discard, "x").HandleResults(context.Background(), nil, resultErr); err != resultErr {
+		t.Errorf("expected result error, got %v", err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for invalid template")
+		}
+	}()
+	PrintTemplate("{{")
+}
@@ -0,0 +1,334 @@
+package function
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Wrap0 returns a Wrapper for a function without arguments
+// besides the context. See Wrap2.
+func Wrap0[R any](name string, fn func(context.Context) (R, error)) Wrapper {
+	f := newTypedDescription[R](name, fn, nil)
+	return &typedWrapper{f, typedCall0(fn)}
+}
+
+// Wrap1 returns a Wrapper for a function with one argument
+// besides the context. See Wrap2.
+func Wrap1[A, R any](name string, fn func(context.Context, A) (R, error), argNames ...string) Wrapper {
+	f := newTypedDescription[R](name, fn, argNames, ReflectType[A]())
+	return &typedWrapper{f, typedCall1(f, fn)}
+}
+
+// Wrap2 returns a Wrapper for a function with two arguments
+// besides the context using generics instead of reflection
+// to call the function, so no code generation with gen-func-wrappers
+// is needed to avoid the overhead of ReflectWrapper.
+//
+// The argNames without the context argument name the arguments
+// and are required because argument names are not available
+// via reflection.
+//
+// Functions with other signatures can be adapted with a closure:
+//
+//	wrapper := function.Wrap2("Add", func(_ context.Context, a, b int) (int, error) {
+//		return Add(a, b), nil
+//	}, "a", "b")
+//
+// Panics if the number of argNames does not match the arguments.
+func Wrap2[A, B, R any](name string, fn func(context.Context, A, B) (R, error), argNames ...string) Wrapper {
+	f := newTypedDescription[R](name, fn, argNames, ReflectType[A](), ReflectType[B]())
+	return &typedWrapper{f, typedCall2(f, fn)}
+}
+
+// Wrap3 returns a Wrapper for a function with three arguments
+// besides the context. See Wrap2.
+func Wrap3[A, B, C, R any](name string, fn func(context.Context, A, B, C) (R, error), argNames ...string) Wrapper {
+	f := newTypedDescription[R](name, fn, argNames, ReflectType[A](), ReflectType[B](), ReflectType[C]())
+	return &typedWrapper{f, typedCall3(f, fn)}
+}
+
+// Wrap4 returns a Wrapper for a function with four arguments
+// besides the context. See Wrap2.
+func Wrap4[A, B, C, D, R any](name string, fn func(context.Context, A, B, C, D) (R, error), argNames ...string) Wrapper {
+	f := newTypedDescription[R](name, fn, argNames, ReflectType[A](), ReflectType[B](), ReflectType[C](), ReflectType[D]())
+	return &typedWrapper{f, typedCall4(f, fn)}
+}
+
+// Wrap5 returns a Wrapper for a function with five arguments
+// besides the context. See Wrap2.
+func Wrap5[A, B, C, D, E, R any](name string, fn func(context.Context, A, B, C, D, E) (R, error), argNames ...string) Wrapper {
+	f := newTypedDescription[R](name, fn, argNames, ReflectType[A](), ReflectType[B](), ReflectType[C](), ReflectType[D](), ReflectType[E]())
+	return &typedWrapper{f, typedCall5(f, fn)}
+}
+
+// typedDescription implements Description
+// for the typed wrappers of functions with a context as first argument,
+// a single result and an error result.
+type typedDescription struct {
+	name            string
+	signature       string
+	argNames        []string // including "ctx"
+	argDescriptions []string // nil if there are no descriptions
+	argTypes        []reflect.Type
+	resultType      reflect.Type
+}
+
+func newTypedDescription[R any](name string, fn any, argNames []string, argTypes ...reflect.Type) *typedDescription {
+	if len(argNames) != len(argTypes) {
+		panic(fmt.Sprintf("%s: %d argNames passed for %d arguments", name, len(argNames), len(argTypes)))
+	}
+	f := &typedDescription{
+		name:            name,
+		argNames:        append([]string{"ctx"}, argNames...),
+		argDescriptions: reflectArgDescriptions(reflect.ValueOf(fn)),
+		argTypes:        append([]reflect.Type{typeOfContext}, argTypes...),
+		resultType:      ReflectType[R](),
+	}
+	var b strings.Builder
+	b.WriteString(name)
+	b.WriteByte('(')
+	for i, argType := range f.argTypes {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s %s", f.argNames[i], argType)
+	}
+	fmt.Fprintf(&b, ") (%s, error)", f.resultType)
+	// reflect formats the type any as "interface {}"
+	f.signature = strings.ReplaceAll(b.String(), "interface {}", "any")
+	return f
+}
+
+func (f *typedDescription) String() string { return f.signature }
+func (f *typedDescription) Name() string   { return f.name }
+
+func (f *typedDescription) NumArgs() int      { return len(f.argTypes) }
+func (f *typedDescription) ContextArg() bool  { return true }
+func (f *typedDescription) NumResults() int   { return 1 }
+func (f *typedDescription) ErrorResult() bool { return true }
+
+func (f *typedDescription) ArgNames() []string { return slices.Clone(f.argNames) }
+
+func (f *typedDescription) ArgDescriptions() []string {
+	if f.argDescriptions != nil {
+		return slices.Clone(f.argDescriptions)
+	}
+	return make([]string, len(f.argNames))
+}
+
+func (f *typedDescription) ArgTypes() []reflect.Type    { return slices.Clone(f.argTypes) }
+func (f *typedDescription) ResultTypes() []reflect.Type { return []reflect.Type{f.resultType} }
+
+// typedArgs is the source of the arguments
+// of a call of a typed wrapper,
+// only one of its fields is set.
+type typedArgs struct {
+	args      []any                      // Call
+	strs      []string                   // CallWithStrings
+	namedStrs map[string]string          // CallWithNamedStrings
+	jsonArgs  map[string]json.RawMessage // CallWithJSON with object
+	jsonArray []json.RawMessage          // CallWithJSON with array
+}
+
+// typedWrapper implements the call methods of the typed wrappers
+// by passing the arguments to a call function
+// that converts them to the argument types of the wrapped function.
+type typedWrapper struct {
+	*typedDescription
+	call func(ctx context.Context, args *typedArgs) ([]any, error)
+}
+
+func (f *typedWrapper) Call(ctx context.Context, args []any) ([]any, error) {
+	return f.call(ctx, &typedArgs{args: args})
+}
+
+func (f *typedWrapper) CallWithStrings(ctx context.Context, strs ...string) ([]any, error) {
+	return f.call(ctx, &typedArgs{strs: strs})
+}
+
+func (f *typedWrapper) CallWithNamedStrings(ctx context.Context, strs map[string]string) ([]any, error) {
+	return f.call(ctx, &typedArgs{namedStrs: strs})
+}
+
+func (f *typedWrapper) CallWithJSON(ctx context.Context, argsJSON []byte) ([]any, error) {
+	var a typedArgs
+	if trimmed := bytes.TrimLeft(argsJSON, " \t\r\n"); len(trimmed) > 0 && trimmed[0] == '[' {
+		err := json.Unmarshal(argsJSON, &a.jsonArray)
+		if err != nil {
+			return nil, NewErrParseArgsJSON(err, f, argsJSON)
+		}
+		if numArgs := len(f.argTypes) - 1; len(a.jsonArray) > numArgs {
+			return nil, NewErrParseArgsJSON(fmt.Errorf("%d positional arguments for %d arguments", len(a.jsonArray), numArgs), f, argsJSON)
+		}
+		return f.call(ctx, &a)
+	}
+	err := json.Unmarshal(argsJSON, &a.jsonArgs)
+	if err != nil {
+		return nil, NewErrParseArgsJSON(err, f, argsJSON)
+	}
+	return f.call(ctx, &a)
+}
+
+// typedArg returns the argument with index i not counting the context
+// from args or the zero value of T if the argument was not passed.
+func typedArg[T any](f *typedDescription, args *typedArgs, i int) (val T, err error) {
+	name := f.argNames[i+1]
+	switch {
+	case args.args != nil:
+		if i < len(args.args) && args.args[i] != nil {
+			v, ok := args.args[i].(T)
+			if !ok {
+				return val, fmt.Errorf("argument %s of %s must be of type %s, but got %T", name, f, f.argTypes[i+1], args.args[i])
+			}
+			val = v
+		}
+	case args.strs != nil:
+		if i < len(args.strs) {
+			err = scanTypedArg(args.strs[i], &val)
+			if err != nil {
+				return val, NewErrParseArgString(err, f, name)
+			}
+		}
+	case args.namedStrs != nil:
+		if str, ok := args.namedStrs[name]; ok {
+			err = scanTypedArg(str, &val)
+			if err != nil {
+				return val, NewErrParseArgString(err, f, name)
+			}
+		}
+	case args.jsonArgs != nil || args.jsonArray != nil:
+		var argJSON json.RawMessage
+		if args.jsonArgs != nil {
+			argJSON = args.jsonArgs[name]
+		} else if i < len(args.jsonArray) {
+			argJSON = args.jsonArray[i]
+		}
+		if argJSON != nil {
+			err = json.Unmarshal(argJSON, &val)
+			if err != nil {
+				return val, NewErrParseArgJSON(err, f, name)
+			}
+		}
+	}
+	return val, nil
+}
+
+// scanTypedArg scans str into dest
+// or assigns str to dest of type *any.
+func scanTypedArg(str string, dest any) error {
+	if a, ok := dest.(*any); ok {
+		*a = str
+		return nil
+	}
+	return ScanString(str, dest)
+}
+
+// typedResults returns the result also together with an error
+// like ReflectWrapper and the generated wrappers.
+func typedResults[R any](result R, err error) ([]any, error) {
+	return []any{result}, err
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Call functions per number of arguments
+
+func typedCall0[R any](fn func(context.Context) (R, error)) func(context.Context, *typedArgs) ([]any, error) {
+	return func(ctx context.Context, _ *typedArgs) ([]any, error) {
+		return typedResults(fn(ctx))
+	}
+}
+
+func typedCall1[A, R any](f *typedDescription, fn func(context.Context, A) (R, error)) func(context.Context, *typedArgs) ([]any, error) {
+	return func(ctx context.Context, args *typedArgs) ([]any, error) {
+		a, err := typedArg[A](f, args, 0)
+		if err != nil {
+			return nil, err
+		}
+		return typedResults(fn(ctx, a))
+	}
+}
+
+func typedCall2[A, B, R any](f *typedDescription, fn func(context.Context, A, B) (R, error)) func(context.Context, *typedArgs) ([]any, error) {
+	return func(ctx context.Context, args *typedArgs) ([]any, error) {
+		a, err := typedArg[A](f, args, 0)
+		if err != nil {
+			return nil, err
+		}
+		b, err := typedArg[B](f, args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return typedResults(fn(ctx, a, b))
+	}
+}
+
+func typedCall3[A, B, C, R any](f *typedDescription, fn func(context.Context, A, B, C) (R, error)) func(context.Context, *typedArgs) ([]any, error) {
+	return func(ctx context.Context, args *typedArgs) ([]any, error) {
+		a, err := typedArg[A](f, args, 0)
+		if err != nil {
+			return nil, err
+		}
+		b, err := typedArg[B](f, args, 1)
+		if err != nil {
+			return nil, err
+		}
+		c, err := typedArg[C](f, args, 2)
+		if err != nil {
+			return nil, err
+		}
+		return typedResults(fn(ctx, a, b, c))
+	}
+}
+
+func typedCall4[A, B, C, D, R any](f *typedDescription, fn func(context.Context, A, B, C, D) (R, error)) func(context.Context, *typedArgs) ([]any, error) {
+	return func(ctx context.Context, args *typedArgs) ([]any, error) {
+		a, err := typedArg[A](f, args, 0)
+		if err != nil {
+			return nil, err
+		}
+		b, err := typedArg[B](f, args, 1)
+		if err != nil {
+			return nil, err
+		}
+		c, err := typedArg[C](f, args, 2)
+		if err != nil {
+			return nil, err
+		}
+		d, err := typedArg[D](f, args, 3)
+		if err != nil {
+			return nil, err
+		}
+		return typedResults(fn(ctx, a, b, c, d))
+	}
+}
+
+func typedCall5[A, B, C, D, E, R any](f *typedDescription, fn func(context.Context, A, B, C, D, E) (R, error)) func(context.Context, *typedArgs) ([]any, error) {
+	return func(ctx context.Context, args *typedArgs) ([]any, error) {
+		a, err := typedArg[A](f, args, 0)
+		if err != nil {
+			return nil, err
+		}
+		b, err := typedArg[B](f, args, 1)
+		if err != nil {
+			return nil, err
+		}
+		c, err := typedArg[C](f, args, 2)
+		if err != nil {
+			return nil, err
+		}
+		d, err := typedArg[D](f, args, 3)
+		if err != nil {
+			return nil, err
+		}
+		e, err := typedArg[E](f, args, 4)
+		if err != nil {
+			return nil, err
+		}
+		return typedResults(fn(ctx, a, b, c, d, e))
+	}
+}
@@ -0,0 +1,153 @@
+package function
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func typedDivide(ctx context.Context, dividend, divisor int) (int, error) {
+	if divisor == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return dividend / divisor, nil
+}
+
+func TestWrap2(t *testing.T) {
+	f := Wrap2("Divide", typedDivide, "dividend", "divisor")
+	if got, want := f.String(), "Divide(ctx context.Context, dividend int, divisor int) (int, error)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got, want := f.ArgNames(), []string{"ctx", "dividend", "divisor"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ArgNames() = %v, want %v", got, want)
+	}
+	if f.NumArgs() != 3 || !f.ContextArg() || f.NumResults() != 1 || !f.ErrorResult() {
+		t.Errorf("unexpected description of %s", f)
+	}
+
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		call    func() ([]any, error)
+		want    []any
+		wantErr bool
+	}{
+		{name: "Call", call: func() ([]any, error) { return f.Call(ctx, []any{6, 3}) }, want: []any{2}},
+		{name: "Call wrong type", call: func() ([]any, error) { return f.Call(ctx, []any{"6", 3}) }, wantErr: true},
+		{name: "CallWithStrings", call: func() ([]any, error) { return f.CallWithStrings(ctx, "9", "3") }, want: []any{3}},
+		{name: "CallWithStrings invalid", call: func() ([]any, error) { return f.CallWithStrings(ctx, "x", "3") }, wantErr: true},
+		{name: "CallWithStrings missing", call: func() ([]any, error) { return f.CallWithStrings(ctx, "9") }, want: []any{0}, wantErr: true},
+		{name: "CallWithNamedStrings", call: func() ([]any, error) {
+			return f.CallWithNamedStrings(ctx, map[string]string{"divisor": "2", "dividend": "8"})
+		}, want: []any{4}},
+		{name: "CallWithJSON", call: func() ([]any, error) { return f.CallWithJSON(ctx, []byte(`{"dividend": 10, "divisor": 5}`)) }, want: []any{2}},
+		{name: "CallWithJSON array", call: func() ([]any, error) { return f.CallWithJSON(ctx, []byte(` [12, 4]`)) }, want: []any{3}},
+		{name: "CallWithJSON array too long", call: func() ([]any, error) { return f.CallWithJSON(ctx, []byte(`[1, 2, 3]`)) }, wantErr: true},
+		{name: "CallWithJSON invalid", call: func() ([]any, error) { return f.CallWithJSON(ctx, []byte(`{"divisor": "x"}`)) }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.call()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("results = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrap2_errors(t *testing.T) {
+	ctx := context.Background()
+	errPartial := errors.New("partial")
+	f := Wrap1("Partial", func(_ context.Context, n int) (int, error) { return n, errPartial }, "n")
+
+	// Results are returned together with the error of the function
+	results, err := f.CallWithStrings(ctx, "7")
+	if !errors.Is(err, errPartial) || !reflect.DeepEqual(results, []any{7}) {
+		t.Errorf("got %v, %v, want [7], %v", results, err, errPartial)
+	}
+
+	// Invalid JSON of an argument is an ErrParseArgJSON naming the argument
+	_, err = Wrap2("Divide", typedDivide, "dividend", "divisor").CallWithJSON(ctx, []byte(`{"dividend": 1, "divisor": "x"}`))
+	var argErr ErrParseArgJSON
+	if !errors.As(err, &argErr) || argErr.Arg != "divisor" {
+		t.Errorf("error = %#v, want ErrParseArgJSON for divisor", err)
+	}
+	// Invalid JSON of the arguments object is an ErrParseArgsJSON
+	_, err = Wrap2("Divide", typedDivide, "dividend", "divisor").CallWithJSON(ctx, []byte(`{"dividend": 1`))
+	if !errors.As(err, new(ErrParseArgsJSON)) {
+		t.Errorf("error = %#v, want ErrParseArgsJSON", err)
+	}
+}
+
+func TestWrap2_missingArgNames(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for missing argNames")
+		}
+	}()
+	Wrap2("Divide", typedDivide)
+}
+
+func TestWrapN(t *testing.T) {
+	ctx := context.Background()
+	wrappers := []Wrapper{
+		Wrap0("Zero", func(context.Context) (string, error) { return "", nil }),
+		Wrap1("One", func(_ context.Context, a string) (string, error) { return a, nil }, "a"),
+		Wrap3("Three", func(_ context.Context, a, b, c string) (string, error) { return a + b + c, nil }, "a", "b", "c"),
+		Wrap4("Four", func(_ context.Context, a, b, c, d string) (string, error) { return a + b + c + d, nil }, "a", "b", "c", "d"),
+		Wrap5("Five", func(_ context.Context, a, b, c, d, e string) (string, error) { return a + b + c + d + e, nil }, "a", "b", "c", "d", "e"),
+	}
+	args := []string{"a", "b", "c", "d", "e"}
+	for _, f := range wrappers {
+		t.Run(f.Name(), func(t *testing.T) {
+			numArgs := f.NumArgs() - 1
+			results, err := f.CallWithStrings(ctx, args[:numArgs]...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := ""
+			for _, arg := range args[:numArgs] {
+				want += arg
+			}
+			if results[0] != want {
+				t.Errorf("got %v, want %q", results[0], want)
+			}
+		})
+	}
+	// Variable of type any gets the string
+	f := Wrap1("Any", func(_ context.Context, a any) (any, error) { return a, nil }, "a")
+	results, err := f.CallWithStrings(ctx, "str")
+	if err != nil || results[0] != "str" {
+		t.Errorf("got %v, %v, want str", results, err)
+	}
+}
+
+func BenchmarkWrap2(b *testing.B) {
+	ctx := context.Background()
+	benchmarks := []struct {
+		name string
+		f    Wrapper
+	}{
+		{name: "Wrap2", f: Wrap2("Divide", typedDivide, "dividend", "divisor")},
+		{name: "ReflectWrapper", f: MustReflectWrapper(typedDivide)},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name+"/Call", func(b *testing.B) {
+			b.ReportAllocs()
+			args := []any{6, 3}
+			for range b.N {
+				_, _ = bm.f.Call(ctx, args)
+			}
+		})
+		b.Run(bm.name+"/CallWithStrings", func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				_, _ = bm.f.CallWithStrings(ctx, "6", "3")
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package function
 
 import (
 	"context"
+	"net/url"
 	"reflect"
 )
 
@@ -64,12 +65,29 @@ func CallWithJSONWrapperTODO(function any) CallWithJSONWrapper {
 	panic("function.CallWithJSONWrapperTODO: run gen-func-wrappers")
 }
 
+// CallWithURLValuesWrapper calls a function with the arguments
+// from URL query or form values where all values of a key
+// are used for slice arguments and the first value for other arguments.
+// It is not part of Wrapper and is only generated
+// by gen-func-wrappers when requested.
+type CallWithURLValuesWrapper interface {
+	CallWithURLValues(ctx context.Context, args url.Values) (results []any, err error)
+}
+
+func CallWithURLValuesWrapperTODO(function any) CallWithURLValuesWrapper {
+	if reflect.ValueOf(function).Kind() != reflect.Func {
+		panic("function.CallWithURLValuesWrapperTODO must be used with a function as argument, then run gen-func-wrappers to replace it with generated code")
+	}
+	panic("function.CallWithURLValuesWrapperTODO: run gen-func-wrappers")
+}
+
 // Implementations of the call interfaces as higher order functions
 var (
 	_ CallWrapper                 = CallWrapperFunc(nil)
 	_ CallWithStringsWrapper      = CallWithStringsWrapperFunc(nil)
 	_ CallWithNamedStringsWrapper = CallWithNamedStringsWrapperFunc(nil)
 	_ CallWithJSONWrapper         = CallWithJSONWrapperFunc(nil)
+	_ CallWithURLValuesWrapper    = CallWithURLValuesWrapperFunc(nil)
 )
 
 type CallWrapperFunc func(ctx context.Context, args []any) (results []any, err error)
@@ -96,6 +114,12 @@ func (f CallWithJSONWrapperFunc) CallWithJSON(ctx context.Context, argsJSON []by
 	return f(ctx, argsJSON)
 }
 
+type CallWithURLValuesWrapperFunc func(ctx context.Context, args url.Values) (results []any, err error)
+
+func (f CallWithURLValuesWrapperFunc) CallWithURLValues(ctx context.Context, args url.Values) (results []any, err error) {
+	return f(ctx, args)
+}
+
 // NoArgNoResultWrapper returns a Wrapper for a function call
 // without arguments and without results.
 func NoArgNoResultWrapper(name string, call func()) Wrapper {
@@ -9,9 +9,10 @@ type StringArgsFunc func(ctx context.Context, args ...string) error
 func NewStringArgsFunc(f CallWithStringsWrapper, resultsHandlers ...ResultsHandler) StringArgsFunc {
 	return func(ctx context.Context, args ...string) error {
 		results, resultErr := f.CallWithStrings(ctx, args...)
+		ctx = contextWithCall(ctx, f, resultsHandlers, CallInfo{Args: args})
 		for _, resultsHandler := range resultsHandlers {
 			err := resultsHandler.HandleResults(ctx, results, resultErr)
-			if err != nil && err != resultErr {
+			if err != nil && !isSameError(err, resultErr) {
 				return err
 			}
 		}
@@ -24,9 +25,10 @@ type NamedStringArgsFunc func(ctx context.Context, args map[string]string) error
 func NewNamedStringArgsFunc(f CallWithNamedStringsWrapper, resultsHandlers ...ResultsHandler) NamedStringArgsFunc {
 	return func(ctx context.Context, args map[string]string) error {
 		results, resultErr := f.CallWithNamedStrings(ctx, args)
+		ctx = contextWithCall(ctx, f, resultsHandlers, CallInfo{NamedArgs: args})
 		for _, resultsHandler := range resultsHandlers {
 			err := resultsHandler.HandleResults(ctx, results, resultErr)
-			if err != nil && err != resultErr {
+			if err != nil && !isSameError(err, resultErr) {
 				return err
 			}
 		}
@@ -39,9 +41,10 @@ type JSONArgsFunc func(ctx context.Context, jsonObject []byte) error
 func NewJSONArgsFunc(f Wrapper, resultsHandlers ...ResultsHandler) JSONArgsFunc {
 	return func(ctx context.Context, jsonObject []byte) error {
 		results, resultErr := CallFunctionWithJSONArgs(ctx, f, jsonObject)
+		ctx = contextWithCall(ctx, f, resultsHandlers, CallInfo{JSON: jsonObject})
 		for _, resultsHandler := range resultsHandlers {
 			err := resultsHandler.HandleResults(ctx, results, resultErr)
-			if err != nil && err != resultErr {
+			if err != nil && !isSameError(err, resultErr) {
 				return err
 			}
 		}